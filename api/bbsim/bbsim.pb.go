@@ -7,6 +7,7 @@ import (
 	context "context"
 	fmt "fmt"
 	proto "github.com/golang/protobuf/proto"
+	openolt "github.com/opencord/voltha-protos/v2/go/openolt"
 	grpc "google.golang.org/grpc"
 	codes "google.golang.org/grpc/codes"
 	status "google.golang.org/grpc/status"
@@ -197,6 +198,45 @@ func (m *Olt) GetPONPorts() []*PONPort {
 	return nil
 }
 
+type Olts struct {
+	Items                []*Olt   `protobuf:"bytes,1,rep,name=items,proto3" json:"items,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Olts) Reset()         { *m = Olts{} }
+func (m *Olts) String() string { return proto.CompactTextString(m) }
+func (*Olts) ProtoMessage()    {}
+func (*Olts) Descriptor() ([]byte, []int) {
+	return fileDescriptor_ef7750073d18011b, []int{3}
+}
+
+func (m *Olts) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Olts.Unmarshal(m, b)
+}
+func (m *Olts) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Olts.Marshal(b, m, deterministic)
+}
+func (m *Olts) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Olts.Merge(m, src)
+}
+func (m *Olts) XXX_Size() int {
+	return xxx_messageInfo_Olts.Size(m)
+}
+func (m *Olts) XXX_DiscardUnknown() {
+	xxx_messageInfo_Olts.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Olts proto.InternalMessageInfo
+
+func (m *Olts) GetItems() []*Olt {
+	if m != nil {
+		return m.Items
+	}
+	return nil
+}
+
 type ONU struct {
 	ID                   int32    `protobuf:"varint,1,opt,name=ID,proto3" json:"ID,omitempty"`
 	SerialNumber         string   `protobuf:"bytes,2,opt,name=SerialNumber,proto3" json:"SerialNumber,omitempty"`
@@ -207,6 +247,7 @@ type ONU struct {
 	CTag                 int32    `protobuf:"varint,7,opt,name=CTag,proto3" json:"CTag,omitempty"`
 	HwAddress            string   `protobuf:"bytes,8,opt,name=HwAddress,proto3" json:"HwAddress,omitempty"`
 	PortNo               int32    `protobuf:"varint,9,opt,name=PortNo,proto3" json:"PortNo,omitempty"`
+	Unis                 []*UNI   `protobuf:"bytes,10,rep,name=Unis,proto3" json:"Unis,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -216,7 +257,7 @@ func (m *ONU) Reset()         { *m = ONU{} }
 func (m *ONU) String() string { return proto.CompactTextString(m) }
 func (*ONU) ProtoMessage()    {}
 func (*ONU) Descriptor() ([]byte, []int) {
-	return fileDescriptor_ef7750073d18011b, []int{3}
+	return fileDescriptor_ef7750073d18011b, []int{4}
 }
 
 func (m *ONU) XXX_Unmarshal(b []byte) error {
@@ -300,6 +341,100 @@ func (m *ONU) GetPortNo() int32 {
 	return 0
 }
 
+func (m *ONU) GetUnis() []*UNI {
+	if m != nil {
+		return m.Unis
+	}
+	return nil
+}
+
+type UNI struct {
+	ID                   int32    `protobuf:"varint,1,opt,name=ID,proto3" json:"ID,omitempty"`
+	OnuID                int32    `protobuf:"varint,2,opt,name=OnuID,proto3" json:"OnuID,omitempty"`
+	OnuSn                string   `protobuf:"bytes,3,opt,name=OnuSn,proto3" json:"OnuSn,omitempty"`
+	PortNo               int32    `protobuf:"varint,4,opt,name=PortNo,proto3" json:"PortNo,omitempty"`
+	HwAddress            string   `protobuf:"bytes,5,opt,name=HwAddress,proto3" json:"HwAddress,omitempty"`
+	CTag                 int32    `protobuf:"varint,6,opt,name=CTag,proto3" json:"CTag,omitempty"`
+	InternalState        string   `protobuf:"bytes,7,opt,name=InternalState,proto3" json:"InternalState,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *UNI) Reset()         { *m = UNI{} }
+func (m *UNI) String() string { return proto.CompactTextString(m) }
+func (*UNI) ProtoMessage()    {}
+func (*UNI) Descriptor() ([]byte, []int) {
+	return fileDescriptor_ef7750073d18011b, []int{5}
+}
+
+func (m *UNI) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_UNI.Unmarshal(m, b)
+}
+func (m *UNI) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_UNI.Marshal(b, m, deterministic)
+}
+func (m *UNI) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_UNI.Merge(m, src)
+}
+func (m *UNI) XXX_Size() int {
+	return xxx_messageInfo_UNI.Size(m)
+}
+func (m *UNI) XXX_DiscardUnknown() {
+	xxx_messageInfo_UNI.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_UNI proto.InternalMessageInfo
+
+func (m *UNI) GetID() int32 {
+	if m != nil {
+		return m.ID
+	}
+	return 0
+}
+
+func (m *UNI) GetOnuID() int32 {
+	if m != nil {
+		return m.OnuID
+	}
+	return 0
+}
+
+func (m *UNI) GetOnuSn() string {
+	if m != nil {
+		return m.OnuSn
+	}
+	return ""
+}
+
+func (m *UNI) GetPortNo() int32 {
+	if m != nil {
+		return m.PortNo
+	}
+	return 0
+}
+
+func (m *UNI) GetHwAddress() string {
+	if m != nil {
+		return m.HwAddress
+	}
+	return ""
+}
+
+func (m *UNI) GetCTag() int32 {
+	if m != nil {
+		return m.CTag
+	}
+	return 0
+}
+
+func (m *UNI) GetInternalState() string {
+	if m != nil {
+		return m.InternalState
+	}
+	return ""
+}
+
 type ONUs struct {
 	Items                []*ONU   `protobuf:"bytes,1,rep,name=items,proto3" json:"items,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
@@ -311,7 +446,7 @@ func (m *ONUs) Reset()         { *m = ONUs{} }
 func (m *ONUs) String() string { return proto.CompactTextString(m) }
 func (*ONUs) ProtoMessage()    {}
 func (*ONUs) Descriptor() ([]byte, []int) {
-	return fileDescriptor_ef7750073d18011b, []int{4}
+	return fileDescriptor_ef7750073d18011b, []int{6}
 }
 
 func (m *ONUs) XXX_Unmarshal(b []byte) error {
@@ -339,706 +474,3224 @@ func (m *ONUs) GetItems() []*ONU {
 	return nil
 }
 
-type ONURequest struct {
-	SerialNumber         string   `protobuf:"bytes,1,opt,name=SerialNumber,proto3" json:"SerialNumber,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+type Flows struct {
+	FlowCount            uint32          `protobuf:"varint,1,opt,name=flow_count,json=flowCount,proto3" json:"flow_count,omitempty"`
+	Flows                []*openolt.Flow `protobuf:"bytes,2,rep,name=flows,proto3" json:"flows,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}        `json:"-"`
+	XXX_unrecognized     []byte          `json:"-"`
+	XXX_sizecache        int32           `json:"-"`
 }
 
-func (m *ONURequest) Reset()         { *m = ONURequest{} }
-func (m *ONURequest) String() string { return proto.CompactTextString(m) }
-func (*ONURequest) ProtoMessage()    {}
-func (*ONURequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_ef7750073d18011b, []int{5}
+func (m *Flows) Reset()         { *m = Flows{} }
+func (m *Flows) String() string { return proto.CompactTextString(m) }
+func (*Flows) ProtoMessage()    {}
+func (*Flows) Descriptor() ([]byte, []int) {
+	return fileDescriptor_ef7750073d18011b, []int{7}
 }
 
-func (m *ONURequest) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_ONURequest.Unmarshal(m, b)
+func (m *Flows) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Flows.Unmarshal(m, b)
 }
-func (m *ONURequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_ONURequest.Marshal(b, m, deterministic)
+func (m *Flows) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Flows.Marshal(b, m, deterministic)
 }
-func (m *ONURequest) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_ONURequest.Merge(m, src)
+func (m *Flows) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Flows.Merge(m, src)
 }
-func (m *ONURequest) XXX_Size() int {
-	return xxx_messageInfo_ONURequest.Size(m)
+func (m *Flows) XXX_Size() int {
+	return xxx_messageInfo_Flows.Size(m)
 }
-func (m *ONURequest) XXX_DiscardUnknown() {
-	xxx_messageInfo_ONURequest.DiscardUnknown(m)
+func (m *Flows) XXX_DiscardUnknown() {
+	xxx_messageInfo_Flows.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_ONURequest proto.InternalMessageInfo
+var xxx_messageInfo_Flows proto.InternalMessageInfo
 
-func (m *ONURequest) GetSerialNumber() string {
+func (m *Flows) GetFlowCount() uint32 {
 	if m != nil {
-		return m.SerialNumber
+		return m.FlowCount
 	}
-	return ""
+	return 0
 }
 
-type VersionNumber struct {
-	Version              string   `protobuf:"bytes,1,opt,name=version,proto3" json:"version,omitempty"`
-	BuildTime            string   `protobuf:"bytes,2,opt,name=buildTime,proto3" json:"buildTime,omitempty"`
-	CommitHash           string   `protobuf:"bytes,3,opt,name=commitHash,proto3" json:"commitHash,omitempty"`
-	GitStatus            string   `protobuf:"bytes,4,opt,name=gitStatus,proto3" json:"gitStatus,omitempty"`
+func (m *Flows) GetFlows() []*openolt.Flow {
+	if m != nil {
+		return m.Flows
+	}
+	return nil
+}
+
+type TCont struct {
+	UniID                uint32   `protobuf:"varint,1,opt,name=UniID,proto3" json:"UniID,omitempty"`
+	Direction            string   `protobuf:"bytes,2,opt,name=Direction,proto3" json:"Direction,omitempty"`
+	AllocID              uint32   `protobuf:"varint,3,opt,name=AllocID,proto3" json:"AllocID,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *VersionNumber) Reset()         { *m = VersionNumber{} }
-func (m *VersionNumber) String() string { return proto.CompactTextString(m) }
-func (*VersionNumber) ProtoMessage()    {}
-func (*VersionNumber) Descriptor() ([]byte, []int) {
-	return fileDescriptor_ef7750073d18011b, []int{6}
+func (m *TCont) Reset()         { *m = TCont{} }
+func (m *TCont) String() string { return proto.CompactTextString(m) }
+func (*TCont) ProtoMessage()    {}
+func (*TCont) Descriptor() ([]byte, []int) {
+	return fileDescriptor_ef7750073d18011b, []int{8}
 }
 
-func (m *VersionNumber) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_VersionNumber.Unmarshal(m, b)
+func (m *TCont) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TCont.Unmarshal(m, b)
 }
-func (m *VersionNumber) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_VersionNumber.Marshal(b, m, deterministic)
+func (m *TCont) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TCont.Marshal(b, m, deterministic)
 }
-func (m *VersionNumber) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_VersionNumber.Merge(m, src)
+func (m *TCont) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TCont.Merge(m, src)
 }
-func (m *VersionNumber) XXX_Size() int {
-	return xxx_messageInfo_VersionNumber.Size(m)
+func (m *TCont) XXX_Size() int {
+	return xxx_messageInfo_TCont.Size(m)
 }
-func (m *VersionNumber) XXX_DiscardUnknown() {
-	xxx_messageInfo_VersionNumber.DiscardUnknown(m)
+func (m *TCont) XXX_DiscardUnknown() {
+	xxx_messageInfo_TCont.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_VersionNumber proto.InternalMessageInfo
-
-func (m *VersionNumber) GetVersion() string {
-	if m != nil {
-		return m.Version
-	}
-	return ""
-}
+var xxx_messageInfo_TCont proto.InternalMessageInfo
 
-func (m *VersionNumber) GetBuildTime() string {
+func (m *TCont) GetUniID() uint32 {
 	if m != nil {
-		return m.BuildTime
+		return m.UniID
 	}
-	return ""
+	return 0
 }
 
-func (m *VersionNumber) GetCommitHash() string {
+func (m *TCont) GetDirection() string {
 	if m != nil {
-		return m.CommitHash
+		return m.Direction
 	}
 	return ""
 }
 
-func (m *VersionNumber) GetGitStatus() string {
+func (m *TCont) GetAllocID() uint32 {
 	if m != nil {
-		return m.GitStatus
+		return m.AllocID
 	}
-	return ""
+	return 0
 }
 
-type LogLevel struct {
-	Level                string   `protobuf:"bytes,1,opt,name=level,proto3" json:"level,omitempty"`
-	Caller               bool     `protobuf:"varint,2,opt,name=caller,proto3" json:"caller,omitempty"`
+type GemPort struct {
+	UniID                uint32   `protobuf:"varint,1,opt,name=UniID,proto3" json:"UniID,omitempty"`
+	Direction            string   `protobuf:"bytes,2,opt,name=Direction,proto3" json:"Direction,omitempty"`
+	GemportID            uint32   `protobuf:"varint,3,opt,name=GemportID,proto3" json:"GemportID,omitempty"`
+	PbitMap              string   `protobuf:"bytes,4,opt,name=PbitMap,proto3" json:"PbitMap,omitempty"`
+	Priority             uint32   `protobuf:"varint,5,opt,name=Priority,proto3" json:"Priority,omitempty"`
+	Weight               uint32   `protobuf:"varint,6,opt,name=Weight,proto3" json:"Weight,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *LogLevel) Reset()         { *m = LogLevel{} }
-func (m *LogLevel) String() string { return proto.CompactTextString(m) }
-func (*LogLevel) ProtoMessage()    {}
-func (*LogLevel) Descriptor() ([]byte, []int) {
-	return fileDescriptor_ef7750073d18011b, []int{7}
+func (m *GemPort) Reset()         { *m = GemPort{} }
+func (m *GemPort) String() string { return proto.CompactTextString(m) }
+func (*GemPort) ProtoMessage()    {}
+func (*GemPort) Descriptor() ([]byte, []int) {
+	return fileDescriptor_ef7750073d18011b, []int{9}
 }
 
-func (m *LogLevel) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_LogLevel.Unmarshal(m, b)
+func (m *GemPort) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GemPort.Unmarshal(m, b)
 }
-func (m *LogLevel) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_LogLevel.Marshal(b, m, deterministic)
+func (m *GemPort) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GemPort.Marshal(b, m, deterministic)
 }
-func (m *LogLevel) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_LogLevel.Merge(m, src)
+func (m *GemPort) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GemPort.Merge(m, src)
 }
-func (m *LogLevel) XXX_Size() int {
-	return xxx_messageInfo_LogLevel.Size(m)
+func (m *GemPort) XXX_Size() int {
+	return xxx_messageInfo_GemPort.Size(m)
 }
-func (m *LogLevel) XXX_DiscardUnknown() {
-	xxx_messageInfo_LogLevel.DiscardUnknown(m)
+func (m *GemPort) XXX_DiscardUnknown() {
+	xxx_messageInfo_GemPort.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_LogLevel proto.InternalMessageInfo
+var xxx_messageInfo_GemPort proto.InternalMessageInfo
 
-func (m *LogLevel) GetLevel() string {
+func (m *GemPort) GetUniID() uint32 {
 	if m != nil {
-		return m.Level
+		return m.UniID
+	}
+	return 0
+}
+
+func (m *GemPort) GetDirection() string {
+	if m != nil {
+		return m.Direction
 	}
 	return ""
 }
 
-func (m *LogLevel) GetCaller() bool {
+func (m *GemPort) GetGemportID() uint32 {
 	if m != nil {
-		return m.Caller
+		return m.GemportID
 	}
-	return false
+	return 0
 }
 
-type Response struct {
-	StatusCode           int32    `protobuf:"varint,1,opt,name=status_code,json=statusCode,proto3" json:"status_code,omitempty"`
-	Message              string   `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+func (m *GemPort) GetPbitMap() string {
+	if m != nil {
+		return m.PbitMap
+	}
+	return ""
 }
 
-func (m *Response) Reset()         { *m = Response{} }
-func (m *Response) String() string { return proto.CompactTextString(m) }
-func (*Response) ProtoMessage()    {}
-func (*Response) Descriptor() ([]byte, []int) {
-	return fileDescriptor_ef7750073d18011b, []int{8}
+func (m *GemPort) GetPriority() uint32 {
+	if m != nil {
+		return m.Priority
+	}
+	return 0
 }
 
-func (m *Response) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_Response.Unmarshal(m, b)
+func (m *GemPort) GetWeight() uint32 {
+	if m != nil {
+		return m.Weight
+	}
+	return 0
 }
-func (m *Response) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_Response.Marshal(b, m, deterministic)
+
+type TConts struct {
+	TConts               []*TCont   `protobuf:"bytes,1,rep,name=TConts,proto3" json:"TConts,omitempty"`
+	GemPorts             []*GemPort `protobuf:"bytes,2,rep,name=GemPorts,proto3" json:"GemPorts,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}   `json:"-"`
+	XXX_unrecognized     []byte     `json:"-"`
+	XXX_sizecache        int32      `json:"-"`
 }
-func (m *Response) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_Response.Merge(m, src)
+
+func (m *TConts) Reset()         { *m = TConts{} }
+func (m *TConts) String() string { return proto.CompactTextString(m) }
+func (*TConts) ProtoMessage()    {}
+func (*TConts) Descriptor() ([]byte, []int) {
+	return fileDescriptor_ef7750073d18011b, []int{10}
 }
-func (m *Response) XXX_Size() int {
-	return xxx_messageInfo_Response.Size(m)
+
+func (m *TConts) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TConts.Unmarshal(m, b)
 }
-func (m *Response) XXX_DiscardUnknown() {
-	xxx_messageInfo_Response.DiscardUnknown(m)
+func (m *TConts) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TConts.Marshal(b, m, deterministic)
+}
+func (m *TConts) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TConts.Merge(m, src)
+}
+func (m *TConts) XXX_Size() int {
+	return xxx_messageInfo_TConts.Size(m)
+}
+func (m *TConts) XXX_DiscardUnknown() {
+	xxx_messageInfo_TConts.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_Response proto.InternalMessageInfo
+var xxx_messageInfo_TConts proto.InternalMessageInfo
 
-func (m *Response) GetStatusCode() int32 {
+func (m *TConts) GetTConts() []*TCont {
 	if m != nil {
-		return m.StatusCode
+		return m.TConts
 	}
-	return 0
+	return nil
 }
 
-func (m *Response) GetMessage() string {
+func (m *TConts) GetGemPorts() []*GemPort {
 	if m != nil {
-		return m.Message
+		return m.GemPorts
 	}
-	return ""
+	return nil
 }
 
-type Empty struct {
+type OmciHistoryEntry struct {
+	Time                 string   `protobuf:"bytes,1,opt,name=Time,proto3" json:"Time,omitempty"`
+	Direction            string   `protobuf:"bytes,2,opt,name=Direction,proto3" json:"Direction,omitempty"`
+	TransactionId        uint32   `protobuf:"varint,3,opt,name=TransactionId,proto3" json:"TransactionId,omitempty"`
+	MessageType          string   `protobuf:"bytes,4,opt,name=MessageType,proto3" json:"MessageType,omitempty"`
+	MeClass              uint32   `protobuf:"varint,5,opt,name=MeClass,proto3" json:"MeClass,omitempty"`
+	MeInstance           uint32   `protobuf:"varint,6,opt,name=MeInstance,proto3" json:"MeInstance,omitempty"`
+	Result               string   `protobuf:"bytes,7,opt,name=Result,proto3" json:"Result,omitempty"`
+	Dropped              bool     `protobuf:"varint,8,opt,name=Dropped,proto3" json:"Dropped,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *Empty) Reset()         { *m = Empty{} }
-func (m *Empty) String() string { return proto.CompactTextString(m) }
-func (*Empty) ProtoMessage()    {}
-func (*Empty) Descriptor() ([]byte, []int) {
-	return fileDescriptor_ef7750073d18011b, []int{9}
+func (m *OmciHistoryEntry) Reset()         { *m = OmciHistoryEntry{} }
+func (m *OmciHistoryEntry) String() string { return proto.CompactTextString(m) }
+func (*OmciHistoryEntry) ProtoMessage()    {}
+func (*OmciHistoryEntry) Descriptor() ([]byte, []int) {
+	return fileDescriptor_ef7750073d18011b, []int{11}
 }
 
-func (m *Empty) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_Empty.Unmarshal(m, b)
+func (m *OmciHistoryEntry) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_OmciHistoryEntry.Unmarshal(m, b)
 }
-func (m *Empty) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_Empty.Marshal(b, m, deterministic)
+func (m *OmciHistoryEntry) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_OmciHistoryEntry.Marshal(b, m, deterministic)
 }
-func (m *Empty) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_Empty.Merge(m, src)
+func (m *OmciHistoryEntry) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_OmciHistoryEntry.Merge(m, src)
 }
-func (m *Empty) XXX_Size() int {
-	return xxx_messageInfo_Empty.Size(m)
+func (m *OmciHistoryEntry) XXX_Size() int {
+	return xxx_messageInfo_OmciHistoryEntry.Size(m)
 }
-func (m *Empty) XXX_DiscardUnknown() {
-	xxx_messageInfo_Empty.DiscardUnknown(m)
+func (m *OmciHistoryEntry) XXX_DiscardUnknown() {
+	xxx_messageInfo_OmciHistoryEntry.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_Empty proto.InternalMessageInfo
+var xxx_messageInfo_OmciHistoryEntry proto.InternalMessageInfo
 
-func init() {
-	proto.RegisterType((*PONPort)(nil), "bbsim.PONPort")
-	proto.RegisterType((*NNIPort)(nil), "bbsim.NNIPort")
-	proto.RegisterType((*Olt)(nil), "bbsim.Olt")
+func (m *OmciHistoryEntry) GetTime() string {
+	if m != nil {
+		return m.Time
+	}
+	return ""
+}
+
+func (m *OmciHistoryEntry) GetDirection() string {
+	if m != nil {
+		return m.Direction
+	}
+	return ""
+}
+
+func (m *OmciHistoryEntry) GetTransactionId() uint32 {
+	if m != nil {
+		return m.TransactionId
+	}
+	return 0
+}
+
+func (m *OmciHistoryEntry) GetMessageType() string {
+	if m != nil {
+		return m.MessageType
+	}
+	return ""
+}
+
+func (m *OmciHistoryEntry) GetMeClass() uint32 {
+	if m != nil {
+		return m.MeClass
+	}
+	return 0
+}
+
+func (m *OmciHistoryEntry) GetMeInstance() uint32 {
+	if m != nil {
+		return m.MeInstance
+	}
+	return 0
+}
+
+func (m *OmciHistoryEntry) GetResult() string {
+	if m != nil {
+		return m.Result
+	}
+	return ""
+}
+
+func (m *OmciHistoryEntry) GetDropped() bool {
+	if m != nil {
+		return m.Dropped
+	}
+	return false
+}
+
+type OmciHistory struct {
+	Entries              []*OmciHistoryEntry `protobuf:"bytes,1,rep,name=Entries,proto3" json:"Entries,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}            `json:"-"`
+	XXX_unrecognized     []byte              `json:"-"`
+	XXX_sizecache        int32               `json:"-"`
+}
+
+func (m *OmciHistory) Reset()         { *m = OmciHistory{} }
+func (m *OmciHistory) String() string { return proto.CompactTextString(m) }
+func (*OmciHistory) ProtoMessage()    {}
+func (*OmciHistory) Descriptor() ([]byte, []int) {
+	return fileDescriptor_ef7750073d18011b, []int{12}
+}
+
+func (m *OmciHistory) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_OmciHistory.Unmarshal(m, b)
+}
+func (m *OmciHistory) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_OmciHistory.Marshal(b, m, deterministic)
+}
+func (m *OmciHistory) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_OmciHistory.Merge(m, src)
+}
+func (m *OmciHistory) XXX_Size() int {
+	return xxx_messageInfo_OmciHistory.Size(m)
+}
+func (m *OmciHistory) XXX_DiscardUnknown() {
+	xxx_messageInfo_OmciHistory.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_OmciHistory proto.InternalMessageInfo
+
+func (m *OmciHistory) GetEntries() []*OmciHistoryEntry {
+	if m != nil {
+		return m.Entries
+	}
+	return nil
+}
+
+// a ME instance in the MIB of an ONU
+type OmciMeInstance struct {
+	MeClass              uint32   `protobuf:"varint,1,opt,name=MeClass,proto3" json:"MeClass,omitempty"`
+	MeInstance           uint32   `protobuf:"varint,2,opt,name=MeInstance,proto3" json:"MeInstance,omitempty"`
+	Name                 string   `protobuf:"bytes,3,opt,name=Name,proto3" json:"Name,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *OmciMeInstance) Reset()         { *m = OmciMeInstance{} }
+func (m *OmciMeInstance) String() string { return proto.CompactTextString(m) }
+func (*OmciMeInstance) ProtoMessage()    {}
+func (*OmciMeInstance) Descriptor() ([]byte, []int) {
+	return fileDescriptor_ef7750073d18011b, []int{13}
+}
+
+func (m *OmciMeInstance) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_OmciMeInstance.Unmarshal(m, b)
+}
+func (m *OmciMeInstance) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_OmciMeInstance.Marshal(b, m, deterministic)
+}
+func (m *OmciMeInstance) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_OmciMeInstance.Merge(m, src)
+}
+func (m *OmciMeInstance) XXX_Size() int {
+	return xxx_messageInfo_OmciMeInstance.Size(m)
+}
+func (m *OmciMeInstance) XXX_DiscardUnknown() {
+	xxx_messageInfo_OmciMeInstance.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_OmciMeInstance proto.InternalMessageInfo
+
+func (m *OmciMeInstance) GetMeClass() uint32 {
+	if m != nil {
+		return m.MeClass
+	}
+	return 0
+}
+
+func (m *OmciMeInstance) GetMeInstance() uint32 {
+	if m != nil {
+		return m.MeInstance
+	}
+	return 0
+}
+
+func (m *OmciMeInstance) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+type OnuMib struct {
+	MibDataSync          uint32            `protobuf:"varint,1,opt,name=MibDataSync,proto3" json:"MibDataSync,omitempty"`
+	Instances            []*OmciMeInstance `protobuf:"bytes,2,rep,name=Instances,proto3" json:"Instances,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
+}
+
+func (m *OnuMib) Reset()         { *m = OnuMib{} }
+func (m *OnuMib) String() string { return proto.CompactTextString(m) }
+func (*OnuMib) ProtoMessage()    {}
+func (*OnuMib) Descriptor() ([]byte, []int) {
+	return fileDescriptor_ef7750073d18011b, []int{14}
+}
+
+func (m *OnuMib) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_OnuMib.Unmarshal(m, b)
+}
+func (m *OnuMib) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_OnuMib.Marshal(b, m, deterministic)
+}
+func (m *OnuMib) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_OnuMib.Merge(m, src)
+}
+func (m *OnuMib) XXX_Size() int {
+	return xxx_messageInfo_OnuMib.Size(m)
+}
+func (m *OnuMib) XXX_DiscardUnknown() {
+	xxx_messageInfo_OnuMib.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_OnuMib proto.InternalMessageInfo
+
+func (m *OnuMib) GetMibDataSync() uint32 {
+	if m != nil {
+		return m.MibDataSync
+	}
+	return 0
+}
+
+func (m *OnuMib) GetInstances() []*OmciMeInstance {
+	if m != nil {
+		return m.Instances
+	}
+	return nil
+}
+
+// an instance of the Software Image ME of an ONU
+type SoftwareImage struct {
+	Instance             uint32   `protobuf:"varint,1,opt,name=Instance,proto3" json:"Instance,omitempty"`
+	Version              string   `protobuf:"bytes,2,opt,name=Version,proto3" json:"Version,omitempty"`
+	IsCommitted          bool     `protobuf:"varint,3,opt,name=IsCommitted,proto3" json:"IsCommitted,omitempty"`
+	IsActive             bool     `protobuf:"varint,4,opt,name=IsActive,proto3" json:"IsActive,omitempty"`
+	IsValid              bool     `protobuf:"varint,5,opt,name=IsValid,proto3" json:"IsValid,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SoftwareImage) Reset()         { *m = SoftwareImage{} }
+func (m *SoftwareImage) String() string { return proto.CompactTextString(m) }
+func (*SoftwareImage) ProtoMessage()    {}
+func (*SoftwareImage) Descriptor() ([]byte, []int) {
+	return fileDescriptor_ef7750073d18011b, []int{15}
+}
+
+func (m *SoftwareImage) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SoftwareImage.Unmarshal(m, b)
+}
+func (m *SoftwareImage) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SoftwareImage.Marshal(b, m, deterministic)
+}
+func (m *SoftwareImage) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SoftwareImage.Merge(m, src)
+}
+func (m *SoftwareImage) XXX_Size() int {
+	return xxx_messageInfo_SoftwareImage.Size(m)
+}
+func (m *SoftwareImage) XXX_DiscardUnknown() {
+	xxx_messageInfo_SoftwareImage.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SoftwareImage proto.InternalMessageInfo
+
+func (m *SoftwareImage) GetInstance() uint32 {
+	if m != nil {
+		return m.Instance
+	}
+	return 0
+}
+
+func (m *SoftwareImage) GetVersion() string {
+	if m != nil {
+		return m.Version
+	}
+	return ""
+}
+
+func (m *SoftwareImage) GetIsCommitted() bool {
+	if m != nil {
+		return m.IsCommitted
+	}
+	return false
+}
+
+func (m *SoftwareImage) GetIsActive() bool {
+	if m != nil {
+		return m.IsActive
+	}
+	return false
+}
+
+func (m *SoftwareImage) GetIsValid() bool {
+	if m != nil {
+		return m.IsValid
+	}
+	return false
+}
+
+type SoftwareImages struct {
+	Images               []*SoftwareImage `protobuf:"bytes,1,rep,name=Images,proto3" json:"Images,omitempty"`
+	DownloadedBytes      uint32           `protobuf:"varint,2,opt,name=DownloadedBytes,proto3" json:"DownloadedBytes,omitempty"`
+	DownloadSize         uint32           `protobuf:"varint,3,opt,name=DownloadSize,proto3" json:"DownloadSize,omitempty"`
+	Fault                string           `protobuf:"bytes,4,opt,name=Fault,proto3" json:"Fault,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}         `json:"-"`
+	XXX_unrecognized     []byte           `json:"-"`
+	XXX_sizecache        int32            `json:"-"`
+}
+
+func (m *SoftwareImages) Reset()         { *m = SoftwareImages{} }
+func (m *SoftwareImages) String() string { return proto.CompactTextString(m) }
+func (*SoftwareImages) ProtoMessage()    {}
+func (*SoftwareImages) Descriptor() ([]byte, []int) {
+	return fileDescriptor_ef7750073d18011b, []int{16}
+}
+
+func (m *SoftwareImages) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SoftwareImages.Unmarshal(m, b)
+}
+func (m *SoftwareImages) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SoftwareImages.Marshal(b, m, deterministic)
+}
+func (m *SoftwareImages) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SoftwareImages.Merge(m, src)
+}
+func (m *SoftwareImages) XXX_Size() int {
+	return xxx_messageInfo_SoftwareImages.Size(m)
+}
+func (m *SoftwareImages) XXX_DiscardUnknown() {
+	xxx_messageInfo_SoftwareImages.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SoftwareImages proto.InternalMessageInfo
+
+func (m *SoftwareImages) GetImages() []*SoftwareImage {
+	if m != nil {
+		return m.Images
+	}
+	return nil
+}
+
+func (m *SoftwareImages) GetDownloadedBytes() uint32 {
+	if m != nil {
+		return m.DownloadedBytes
+	}
+	return 0
+}
+
+func (m *SoftwareImages) GetDownloadSize() uint32 {
+	if m != nil {
+		return m.DownloadSize
+	}
+	return 0
+}
+
+func (m *SoftwareImages) GetFault() string {
+	if m != nil {
+		return m.Fault
+	}
+	return ""
+}
+
+type PortStats struct {
+	PortType             string   `protobuf:"bytes,1,opt,name=PortType,proto3" json:"PortType,omitempty"`
+	ID                   uint32   `protobuf:"varint,2,opt,name=ID,proto3" json:"ID,omitempty"`
+	RxPackets            uint64   `protobuf:"varint,3,opt,name=RxPackets,proto3" json:"RxPackets,omitempty"`
+	RxBytes              uint64   `protobuf:"varint,4,opt,name=RxBytes,proto3" json:"RxBytes,omitempty"`
+	TxPackets            uint64   `protobuf:"varint,5,opt,name=TxPackets,proto3" json:"TxPackets,omitempty"`
+	TxBytes              uint64   `protobuf:"varint,6,opt,name=TxBytes,proto3" json:"TxBytes,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *PortStats) Reset()         { *m = PortStats{} }
+func (m *PortStats) String() string { return proto.CompactTextString(m) }
+func (*PortStats) ProtoMessage()    {}
+func (*PortStats) Descriptor() ([]byte, []int) {
+	return fileDescriptor_ef7750073d18011b, []int{17}
+}
+
+func (m *PortStats) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PortStats.Unmarshal(m, b)
+}
+func (m *PortStats) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PortStats.Marshal(b, m, deterministic)
+}
+func (m *PortStats) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PortStats.Merge(m, src)
+}
+func (m *PortStats) XXX_Size() int {
+	return xxx_messageInfo_PortStats.Size(m)
+}
+func (m *PortStats) XXX_DiscardUnknown() {
+	xxx_messageInfo_PortStats.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PortStats proto.InternalMessageInfo
+
+func (m *PortStats) GetPortType() string {
+	if m != nil {
+		return m.PortType
+	}
+	return ""
+}
+
+func (m *PortStats) GetID() uint32 {
+	if m != nil {
+		return m.ID
+	}
+	return 0
+}
+
+func (m *PortStats) GetRxPackets() uint64 {
+	if m != nil {
+		return m.RxPackets
+	}
+	return 0
+}
+
+func (m *PortStats) GetRxBytes() uint64 {
+	if m != nil {
+		return m.RxBytes
+	}
+	return 0
+}
+
+func (m *PortStats) GetTxPackets() uint64 {
+	if m != nil {
+		return m.TxPackets
+	}
+	return 0
+}
+
+func (m *PortStats) GetTxBytes() uint64 {
+	if m != nil {
+		return m.TxBytes
+	}
+	return 0
+}
+
+type FlowStats struct {
+	FlowId               uint32   `protobuf:"varint,1,opt,name=FlowId,proto3" json:"FlowId,omitempty"`
+	FlowType             string   `protobuf:"bytes,2,opt,name=FlowType,proto3" json:"FlowType,omitempty"`
+	RxPackets            uint64   `protobuf:"varint,3,opt,name=RxPackets,proto3" json:"RxPackets,omitempty"`
+	RxBytes              uint64   `protobuf:"varint,4,opt,name=RxBytes,proto3" json:"RxBytes,omitempty"`
+	TxPackets            uint64   `protobuf:"varint,5,opt,name=TxPackets,proto3" json:"TxPackets,omitempty"`
+	TxBytes              uint64   `protobuf:"varint,6,opt,name=TxBytes,proto3" json:"TxBytes,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *FlowStats) Reset()         { *m = FlowStats{} }
+func (m *FlowStats) String() string { return proto.CompactTextString(m) }
+func (*FlowStats) ProtoMessage()    {}
+func (*FlowStats) Descriptor() ([]byte, []int) {
+	return fileDescriptor_ef7750073d18011b, []int{18}
+}
+
+func (m *FlowStats) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_FlowStats.Unmarshal(m, b)
+}
+func (m *FlowStats) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_FlowStats.Marshal(b, m, deterministic)
+}
+func (m *FlowStats) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_FlowStats.Merge(m, src)
+}
+func (m *FlowStats) XXX_Size() int {
+	return xxx_messageInfo_FlowStats.Size(m)
+}
+func (m *FlowStats) XXX_DiscardUnknown() {
+	xxx_messageInfo_FlowStats.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_FlowStats proto.InternalMessageInfo
+
+func (m *FlowStats) GetFlowId() uint32 {
+	if m != nil {
+		return m.FlowId
+	}
+	return 0
+}
+
+func (m *FlowStats) GetFlowType() string {
+	if m != nil {
+		return m.FlowType
+	}
+	return ""
+}
+
+func (m *FlowStats) GetRxPackets() uint64 {
+	if m != nil {
+		return m.RxPackets
+	}
+	return 0
+}
+
+func (m *FlowStats) GetRxBytes() uint64 {
+	if m != nil {
+		return m.RxBytes
+	}
+	return 0
+}
+
+func (m *FlowStats) GetTxPackets() uint64 {
+	if m != nil {
+		return m.TxPackets
+	}
+	return 0
+}
+
+func (m *FlowStats) GetTxBytes() uint64 {
+	if m != nil {
+		return m.TxBytes
+	}
+	return 0
+}
+
+type OltStats struct {
+	Ports                []*PortStats `protobuf:"bytes,1,rep,name=Ports,proto3" json:"Ports,omitempty"`
+	Flows                []*FlowStats `protobuf:"bytes,2,rep,name=Flows,proto3" json:"Flows,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}     `json:"-"`
+	XXX_unrecognized     []byte       `json:"-"`
+	XXX_sizecache        int32        `json:"-"`
+}
+
+func (m *OltStats) Reset()         { *m = OltStats{} }
+func (m *OltStats) String() string { return proto.CompactTextString(m) }
+func (*OltStats) ProtoMessage()    {}
+func (*OltStats) Descriptor() ([]byte, []int) {
+	return fileDescriptor_ef7750073d18011b, []int{19}
+}
+
+func (m *OltStats) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_OltStats.Unmarshal(m, b)
+}
+func (m *OltStats) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_OltStats.Marshal(b, m, deterministic)
+}
+func (m *OltStats) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_OltStats.Merge(m, src)
+}
+func (m *OltStats) XXX_Size() int {
+	return xxx_messageInfo_OltStats.Size(m)
+}
+func (m *OltStats) XXX_DiscardUnknown() {
+	xxx_messageInfo_OltStats.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_OltStats proto.InternalMessageInfo
+
+func (m *OltStats) GetPorts() []*PortStats {
+	if m != nil {
+		return m.Ports
+	}
+	return nil
+}
+
+func (m *OltStats) GetFlows() []*FlowStats {
+	if m != nil {
+		return m.Flows
+	}
+	return nil
+}
+
+// a fault injected in the calls to an OpenOLT method
+type FaultRule struct {
+	Method               string   `protobuf:"bytes,1,opt,name=Method,proto3" json:"Method,omitempty"`
+	ErrorCode            string   `protobuf:"bytes,2,opt,name=ErrorCode,proto3" json:"ErrorCode,omitempty"`
+	Rate                 float32  `protobuf:"fixed32,3,opt,name=Rate,proto3" json:"Rate,omitempty"`
+	LatencyMs            uint32   `protobuf:"varint,4,opt,name=LatencyMs,proto3" json:"LatencyMs,omitempty"`
+	JitterMs             uint32   `protobuf:"varint,5,opt,name=JitterMs,proto3" json:"JitterMs,omitempty"`
+	Drop                 bool     `protobuf:"varint,6,opt,name=Drop,proto3" json:"Drop,omitempty"`
+	DropTimeoutMs        uint32   `protobuf:"varint,7,opt,name=DropTimeoutMs,proto3" json:"DropTimeoutMs,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *FaultRule) Reset()         { *m = FaultRule{} }
+func (m *FaultRule) String() string { return proto.CompactTextString(m) }
+func (*FaultRule) ProtoMessage()    {}
+func (*FaultRule) Descriptor() ([]byte, []int) {
+	return fileDescriptor_ef7750073d18011b, []int{20}
+}
+
+func (m *FaultRule) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_FaultRule.Unmarshal(m, b)
+}
+func (m *FaultRule) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_FaultRule.Marshal(b, m, deterministic)
+}
+func (m *FaultRule) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_FaultRule.Merge(m, src)
+}
+func (m *FaultRule) XXX_Size() int {
+	return xxx_messageInfo_FaultRule.Size(m)
+}
+func (m *FaultRule) XXX_DiscardUnknown() {
+	xxx_messageInfo_FaultRule.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_FaultRule proto.InternalMessageInfo
+
+func (m *FaultRule) GetMethod() string {
+	if m != nil {
+		return m.Method
+	}
+	return ""
+}
+
+func (m *FaultRule) GetErrorCode() string {
+	if m != nil {
+		return m.ErrorCode
+	}
+	return ""
+}
+
+func (m *FaultRule) GetRate() float32 {
+	if m != nil {
+		return m.Rate
+	}
+	return 0
+}
+
+func (m *FaultRule) GetLatencyMs() uint32 {
+	if m != nil {
+		return m.LatencyMs
+	}
+	return 0
+}
+
+func (m *FaultRule) GetJitterMs() uint32 {
+	if m != nil {
+		return m.JitterMs
+	}
+	return 0
+}
+
+func (m *FaultRule) GetDrop() bool {
+	if m != nil {
+		return m.Drop
+	}
+	return false
+}
+
+func (m *FaultRule) GetDropTimeoutMs() uint32 {
+	if m != nil {
+		return m.DropTimeoutMs
+	}
+	return 0
+}
+
+type FaultRules struct {
+	OltID                int32        `protobuf:"varint,1,opt,name=OltID,proto3" json:"OltID,omitempty"`
+	Rules                []*FaultRule `protobuf:"bytes,2,rep,name=Rules,proto3" json:"Rules,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}     `json:"-"`
+	XXX_unrecognized     []byte       `json:"-"`
+	XXX_sizecache        int32        `json:"-"`
+}
+
+func (m *FaultRules) Reset()         { *m = FaultRules{} }
+func (m *FaultRules) String() string { return proto.CompactTextString(m) }
+func (*FaultRules) ProtoMessage()    {}
+func (*FaultRules) Descriptor() ([]byte, []int) {
+	return fileDescriptor_ef7750073d18011b, []int{21}
+}
+
+func (m *FaultRules) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_FaultRules.Unmarshal(m, b)
+}
+func (m *FaultRules) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_FaultRules.Marshal(b, m, deterministic)
+}
+func (m *FaultRules) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_FaultRules.Merge(m, src)
+}
+func (m *FaultRules) XXX_Size() int {
+	return xxx_messageInfo_FaultRules.Size(m)
+}
+func (m *FaultRules) XXX_DiscardUnknown() {
+	xxx_messageInfo_FaultRules.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_FaultRules proto.InternalMessageInfo
+
+func (m *FaultRules) GetOltID() int32 {
+	if m != nil {
+		return m.OltID
+	}
+	return 0
+}
+
+func (m *FaultRules) GetRules() []*FaultRule {
+	if m != nil {
+		return m.Rules
+	}
+	return nil
+}
+
+// an impairment of the OMCI responses of the ONUs
+type OmciFaultRule struct {
+	SerialNumber         string   `protobuf:"bytes,1,opt,name=SerialNumber,proto3" json:"SerialNumber,omitempty"`
+	MeClass              uint32   `protobuf:"varint,2,opt,name=MeClass,proto3" json:"MeClass,omitempty"`
+	DropEvery            uint32   `protobuf:"varint,3,opt,name=DropEvery,proto3" json:"DropEvery,omitempty"`
+	LatencyMs            uint32   `protobuf:"varint,4,opt,name=LatencyMs,proto3" json:"LatencyMs,omitempty"`
+	Corrupt              bool     `protobuf:"varint,5,opt,name=Corrupt,proto3" json:"Corrupt,omitempty"`
+	WrongTid             bool     `protobuf:"varint,6,opt,name=WrongTid,proto3" json:"WrongTid,omitempty"`
+	ResultCode           uint32   `protobuf:"varint,7,opt,name=ResultCode,proto3" json:"ResultCode,omitempty"`
+	DropNth              uint32   `protobuf:"varint,8,opt,name=DropNth,proto3" json:"DropNth,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *OmciFaultRule) Reset()         { *m = OmciFaultRule{} }
+func (m *OmciFaultRule) String() string { return proto.CompactTextString(m) }
+func (*OmciFaultRule) ProtoMessage()    {}
+func (*OmciFaultRule) Descriptor() ([]byte, []int) {
+	return fileDescriptor_ef7750073d18011b, []int{22}
+}
+
+func (m *OmciFaultRule) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_OmciFaultRule.Unmarshal(m, b)
+}
+func (m *OmciFaultRule) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_OmciFaultRule.Marshal(b, m, deterministic)
+}
+func (m *OmciFaultRule) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_OmciFaultRule.Merge(m, src)
+}
+func (m *OmciFaultRule) XXX_Size() int {
+	return xxx_messageInfo_OmciFaultRule.Size(m)
+}
+func (m *OmciFaultRule) XXX_DiscardUnknown() {
+	xxx_messageInfo_OmciFaultRule.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_OmciFaultRule proto.InternalMessageInfo
+
+func (m *OmciFaultRule) GetSerialNumber() string {
+	if m != nil {
+		return m.SerialNumber
+	}
+	return ""
+}
+
+func (m *OmciFaultRule) GetMeClass() uint32 {
+	if m != nil {
+		return m.MeClass
+	}
+	return 0
+}
+
+func (m *OmciFaultRule) GetDropEvery() uint32 {
+	if m != nil {
+		return m.DropEvery
+	}
+	return 0
+}
+
+func (m *OmciFaultRule) GetLatencyMs() uint32 {
+	if m != nil {
+		return m.LatencyMs
+	}
+	return 0
+}
+
+func (m *OmciFaultRule) GetCorrupt() bool {
+	if m != nil {
+		return m.Corrupt
+	}
+	return false
+}
+
+func (m *OmciFaultRule) GetWrongTid() bool {
+	if m != nil {
+		return m.WrongTid
+	}
+	return false
+}
+
+func (m *OmciFaultRule) GetResultCode() uint32 {
+	if m != nil {
+		return m.ResultCode
+	}
+	return 0
+}
+
+func (m *OmciFaultRule) GetDropNth() uint32 {
+	if m != nil {
+		return m.DropNth
+	}
+	return 0
+}
+
+type OmciFaultRules struct {
+	OltID                int32            `protobuf:"varint,1,opt,name=OltID,proto3" json:"OltID,omitempty"`
+	Rules                []*OmciFaultRule `protobuf:"bytes,2,rep,name=Rules,proto3" json:"Rules,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}         `json:"-"`
+	XXX_unrecognized     []byte           `json:"-"`
+	XXX_sizecache        int32            `json:"-"`
+}
+
+func (m *OmciFaultRules) Reset()         { *m = OmciFaultRules{} }
+func (m *OmciFaultRules) String() string { return proto.CompactTextString(m) }
+func (*OmciFaultRules) ProtoMessage()    {}
+func (*OmciFaultRules) Descriptor() ([]byte, []int) {
+	return fileDescriptor_ef7750073d18011b, []int{23}
+}
+
+func (m *OmciFaultRules) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_OmciFaultRules.Unmarshal(m, b)
+}
+func (m *OmciFaultRules) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_OmciFaultRules.Marshal(b, m, deterministic)
+}
+func (m *OmciFaultRules) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_OmciFaultRules.Merge(m, src)
+}
+func (m *OmciFaultRules) XXX_Size() int {
+	return xxx_messageInfo_OmciFaultRules.Size(m)
+}
+func (m *OmciFaultRules) XXX_DiscardUnknown() {
+	xxx_messageInfo_OmciFaultRules.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_OmciFaultRules proto.InternalMessageInfo
+
+func (m *OmciFaultRules) GetOltID() int32 {
+	if m != nil {
+		return m.OltID
+	}
+	return 0
+}
+
+func (m *OmciFaultRules) GetRules() []*OmciFaultRule {
+	if m != nil {
+		return m.Rules
+	}
+	return nil
+}
+
+type OltRequest struct {
+	OltID                int32    `protobuf:"varint,1,opt,name=OltID,proto3" json:"OltID,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *OltRequest) Reset()         { *m = OltRequest{} }
+func (m *OltRequest) String() string { return proto.CompactTextString(m) }
+func (*OltRequest) ProtoMessage()    {}
+func (*OltRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_ef7750073d18011b, []int{24}
+}
+
+func (m *OltRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_OltRequest.Unmarshal(m, b)
+}
+func (m *OltRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_OltRequest.Marshal(b, m, deterministic)
+}
+func (m *OltRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_OltRequest.Merge(m, src)
+}
+func (m *OltRequest) XXX_Size() int {
+	return xxx_messageInfo_OltRequest.Size(m)
+}
+func (m *OltRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_OltRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_OltRequest proto.InternalMessageInfo
+
+func (m *OltRequest) GetOltID() int32 {
+	if m != nil {
+		return m.OltID
+	}
+	return 0
+}
+
+type RebootRequest struct {
+	OltID                int32    `protobuf:"varint,1,opt,name=OltID,proto3" json:"OltID,omitempty"`
+	Mode                 string   `protobuf:"bytes,2,opt,name=Mode,proto3" json:"Mode,omitempty"`
+	DyingGasp            bool     `protobuf:"varint,3,opt,name=DyingGasp,proto3" json:"DyingGasp,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RebootRequest) Reset()         { *m = RebootRequest{} }
+func (m *RebootRequest) String() string { return proto.CompactTextString(m) }
+func (*RebootRequest) ProtoMessage()    {}
+func (*RebootRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_ef7750073d18011b, []int{25}
+}
+
+func (m *RebootRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RebootRequest.Unmarshal(m, b)
+}
+func (m *RebootRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RebootRequest.Marshal(b, m, deterministic)
+}
+func (m *RebootRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RebootRequest.Merge(m, src)
+}
+func (m *RebootRequest) XXX_Size() int {
+	return xxx_messageInfo_RebootRequest.Size(m)
+}
+func (m *RebootRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_RebootRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RebootRequest proto.InternalMessageInfo
+
+func (m *RebootRequest) GetOltID() int32 {
+	if m != nil {
+		return m.OltID
+	}
+	return 0
+}
+
+func (m *RebootRequest) GetMode() string {
+	if m != nil {
+		return m.Mode
+	}
+	return ""
+}
+
+func (m *RebootRequest) GetDyingGasp() bool {
+	if m != nil {
+		return m.DyingGasp
+	}
+	return false
+}
+
+type ONURequest struct {
+	SerialNumber         string   `protobuf:"bytes,1,opt,name=SerialNumber,proto3" json:"SerialNumber,omitempty"`
+	OltID                int32    `protobuf:"varint,2,opt,name=OltID,proto3" json:"OltID,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ONURequest) Reset()         { *m = ONURequest{} }
+func (m *ONURequest) String() string { return proto.CompactTextString(m) }
+func (*ONURequest) ProtoMessage()    {}
+func (*ONURequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_ef7750073d18011b, []int{26}
+}
+
+func (m *ONURequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ONURequest.Unmarshal(m, b)
+}
+func (m *ONURequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ONURequest.Marshal(b, m, deterministic)
+}
+func (m *ONURequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ONURequest.Merge(m, src)
+}
+func (m *ONURequest) XXX_Size() int {
+	return xxx_messageInfo_ONURequest.Size(m)
+}
+func (m *ONURequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ONURequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ONURequest proto.InternalMessageInfo
+
+func (m *ONURequest) GetSerialNumber() string {
+	if m != nil {
+		return m.SerialNumber
+	}
+	return ""
+}
+
+func (m *ONURequest) GetOltID() int32 {
+	if m != nil {
+		return m.OltID
+	}
+	return 0
+}
+
+type SoftwareImageFaultRequest struct {
+	SerialNumber         string   `protobuf:"bytes,1,opt,name=SerialNumber,proto3" json:"SerialNumber,omitempty"`
+	Fault                string   `protobuf:"bytes,2,opt,name=Fault,proto3" json:"Fault,omitempty"`
+	OltID                int32    `protobuf:"varint,3,opt,name=OltID,proto3" json:"OltID,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SoftwareImageFaultRequest) Reset()         { *m = SoftwareImageFaultRequest{} }
+func (m *SoftwareImageFaultRequest) String() string { return proto.CompactTextString(m) }
+func (*SoftwareImageFaultRequest) ProtoMessage()    {}
+func (*SoftwareImageFaultRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_ef7750073d18011b, []int{27}
+}
+
+func (m *SoftwareImageFaultRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SoftwareImageFaultRequest.Unmarshal(m, b)
+}
+func (m *SoftwareImageFaultRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SoftwareImageFaultRequest.Marshal(b, m, deterministic)
+}
+func (m *SoftwareImageFaultRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SoftwareImageFaultRequest.Merge(m, src)
+}
+func (m *SoftwareImageFaultRequest) XXX_Size() int {
+	return xxx_messageInfo_SoftwareImageFaultRequest.Size(m)
+}
+func (m *SoftwareImageFaultRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_SoftwareImageFaultRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SoftwareImageFaultRequest proto.InternalMessageInfo
+
+func (m *SoftwareImageFaultRequest) GetSerialNumber() string {
+	if m != nil {
+		return m.SerialNumber
+	}
+	return ""
+}
+
+func (m *SoftwareImageFaultRequest) GetFault() string {
+	if m != nil {
+		return m.Fault
+	}
+	return ""
+}
+
+func (m *SoftwareImageFaultRequest) GetOltID() int32 {
+	if m != nil {
+		return m.OltID
+	}
+	return 0
+}
+
+// the values of the ONU that are not set are generated as for the ONUs created with the OLT
+type CreateOnuRequest struct {
+	OltID                int32    `protobuf:"varint,1,opt,name=OltID,proto3" json:"OltID,omitempty"`
+	PonPortID            uint32   `protobuf:"varint,2,opt,name=PonPortID,proto3" json:"PonPortID,omitempty"`
+	SerialNumber         string   `protobuf:"bytes,3,opt,name=SerialNumber,proto3" json:"SerialNumber,omitempty"`
+	HwAddress            string   `protobuf:"bytes,4,opt,name=HwAddress,proto3" json:"HwAddress,omitempty"`
+	STag                 int32    `protobuf:"varint,5,opt,name=STag,proto3" json:"STag,omitempty"`
+	CTag                 int32    `protobuf:"varint,6,opt,name=CTag,proto3" json:"CTag,omitempty"`
+	EapolUsername        string   `protobuf:"bytes,7,opt,name=EapolUsername,proto3" json:"EapolUsername,omitempty"`
+	EapolPassword        string   `protobuf:"bytes,8,opt,name=EapolPassword,proto3" json:"EapolPassword,omitempty"`
+	MibTemplate          string   `protobuf:"bytes,9,opt,name=MibTemplate,proto3" json:"MibTemplate,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CreateOnuRequest) Reset()         { *m = CreateOnuRequest{} }
+func (m *CreateOnuRequest) String() string { return proto.CompactTextString(m) }
+func (*CreateOnuRequest) ProtoMessage()    {}
+func (*CreateOnuRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_ef7750073d18011b, []int{28}
+}
+
+func (m *CreateOnuRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CreateOnuRequest.Unmarshal(m, b)
+}
+func (m *CreateOnuRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CreateOnuRequest.Marshal(b, m, deterministic)
+}
+func (m *CreateOnuRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CreateOnuRequest.Merge(m, src)
+}
+func (m *CreateOnuRequest) XXX_Size() int {
+	return xxx_messageInfo_CreateOnuRequest.Size(m)
+}
+func (m *CreateOnuRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_CreateOnuRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CreateOnuRequest proto.InternalMessageInfo
+
+func (m *CreateOnuRequest) GetOltID() int32 {
+	if m != nil {
+		return m.OltID
+	}
+	return 0
+}
+
+func (m *CreateOnuRequest) GetPonPortID() uint32 {
+	if m != nil {
+		return m.PonPortID
+	}
+	return 0
+}
+
+func (m *CreateOnuRequest) GetSerialNumber() string {
+	if m != nil {
+		return m.SerialNumber
+	}
+	return ""
+}
+
+func (m *CreateOnuRequest) GetHwAddress() string {
+	if m != nil {
+		return m.HwAddress
+	}
+	return ""
+}
+
+func (m *CreateOnuRequest) GetSTag() int32 {
+	if m != nil {
+		return m.STag
+	}
+	return 0
+}
+
+func (m *CreateOnuRequest) GetCTag() int32 {
+	if m != nil {
+		return m.CTag
+	}
+	return 0
+}
+
+func (m *CreateOnuRequest) GetEapolUsername() string {
+	if m != nil {
+		return m.EapolUsername
+	}
+	return ""
+}
+
+func (m *CreateOnuRequest) GetEapolPassword() string {
+	if m != nil {
+		return m.EapolPassword
+	}
+	return ""
+}
+
+func (m *CreateOnuRequest) GetMibTemplate() string {
+	if m != nil {
+		return m.MibTemplate
+	}
+	return ""
+}
+
+type MoveOnuRequest struct {
+	SerialNumber         string   `protobuf:"bytes,1,opt,name=SerialNumber,proto3" json:"SerialNumber,omitempty"`
+	PonPortID            uint32   `protobuf:"varint,2,opt,name=PonPortID,proto3" json:"PonPortID,omitempty"`
+	OltID                int32    `protobuf:"varint,3,opt,name=OltID,proto3" json:"OltID,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *MoveOnuRequest) Reset()         { *m = MoveOnuRequest{} }
+func (m *MoveOnuRequest) String() string { return proto.CompactTextString(m) }
+func (*MoveOnuRequest) ProtoMessage()    {}
+func (*MoveOnuRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_ef7750073d18011b, []int{29}
+}
+
+func (m *MoveOnuRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_MoveOnuRequest.Unmarshal(m, b)
+}
+func (m *MoveOnuRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_MoveOnuRequest.Marshal(b, m, deterministic)
+}
+func (m *MoveOnuRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_MoveOnuRequest.Merge(m, src)
+}
+func (m *MoveOnuRequest) XXX_Size() int {
+	return xxx_messageInfo_MoveOnuRequest.Size(m)
+}
+func (m *MoveOnuRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_MoveOnuRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_MoveOnuRequest proto.InternalMessageInfo
+
+func (m *MoveOnuRequest) GetSerialNumber() string {
+	if m != nil {
+		return m.SerialNumber
+	}
+	return ""
+}
+
+func (m *MoveOnuRequest) GetPonPortID() uint32 {
+	if m != nil {
+		return m.PonPortID
+	}
+	return 0
+}
+
+func (m *MoveOnuRequest) GetOltID() int32 {
+	if m != nil {
+		return m.OltID
+	}
+	return 0
+}
+
+type AlarmRequest struct {
+	AlarmType            string   `protobuf:"bytes,1,opt,name=AlarmType,proto3" json:"AlarmType,omitempty"`
+	SerialNumber         string   `protobuf:"bytes,2,opt,name=SerialNumber,proto3" json:"SerialNumber,omitempty"`
+	Status               string   `protobuf:"bytes,3,opt,name=Status,proto3" json:"Status,omitempty"`
+	OltID                int32    `protobuf:"varint,4,opt,name=OltID,proto3" json:"OltID,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *AlarmRequest) Reset()         { *m = AlarmRequest{} }
+func (m *AlarmRequest) String() string { return proto.CompactTextString(m) }
+func (*AlarmRequest) ProtoMessage()    {}
+func (*AlarmRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_ef7750073d18011b, []int{30}
+}
+
+func (m *AlarmRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AlarmRequest.Unmarshal(m, b)
+}
+func (m *AlarmRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AlarmRequest.Marshal(b, m, deterministic)
+}
+func (m *AlarmRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AlarmRequest.Merge(m, src)
+}
+func (m *AlarmRequest) XXX_Size() int {
+	return xxx_messageInfo_AlarmRequest.Size(m)
+}
+func (m *AlarmRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_AlarmRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AlarmRequest proto.InternalMessageInfo
+
+func (m *AlarmRequest) GetAlarmType() string {
+	if m != nil {
+		return m.AlarmType
+	}
+	return ""
+}
+
+func (m *AlarmRequest) GetSerialNumber() string {
+	if m != nil {
+		return m.SerialNumber
+	}
+	return ""
+}
+
+func (m *AlarmRequest) GetStatus() string {
+	if m != nil {
+		return m.Status
+	}
+	return ""
+}
+
+func (m *AlarmRequest) GetOltID() int32 {
+	if m != nil {
+		return m.OltID
+	}
+	return 0
+}
+
+// an OMCI Alarm Notification or Attribute Value Change sent by the ONU
+type OmciAlarmRequest struct {
+	SerialNumber         string   `protobuf:"bytes,1,opt,name=SerialNumber,proto3" json:"SerialNumber,omitempty"`
+	AlarmType            string   `protobuf:"bytes,2,opt,name=AlarmType,proto3" json:"AlarmType,omitempty"`
+	UniID                uint32   `protobuf:"varint,3,opt,name=UniID,proto3" json:"UniID,omitempty"`
+	Status               string   `protobuf:"bytes,4,opt,name=Status,proto3" json:"Status,omitempty"`
+	OltID                int32    `protobuf:"varint,5,opt,name=OltID,proto3" json:"OltID,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *OmciAlarmRequest) Reset()         { *m = OmciAlarmRequest{} }
+func (m *OmciAlarmRequest) String() string { return proto.CompactTextString(m) }
+func (*OmciAlarmRequest) ProtoMessage()    {}
+func (*OmciAlarmRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_ef7750073d18011b, []int{31}
+}
+
+func (m *OmciAlarmRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_OmciAlarmRequest.Unmarshal(m, b)
+}
+func (m *OmciAlarmRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_OmciAlarmRequest.Marshal(b, m, deterministic)
+}
+func (m *OmciAlarmRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_OmciAlarmRequest.Merge(m, src)
+}
+func (m *OmciAlarmRequest) XXX_Size() int {
+	return xxx_messageInfo_OmciAlarmRequest.Size(m)
+}
+func (m *OmciAlarmRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_OmciAlarmRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_OmciAlarmRequest proto.InternalMessageInfo
+
+func (m *OmciAlarmRequest) GetSerialNumber() string {
+	if m != nil {
+		return m.SerialNumber
+	}
+	return ""
+}
+
+func (m *OmciAlarmRequest) GetAlarmType() string {
+	if m != nil {
+		return m.AlarmType
+	}
+	return ""
+}
+
+func (m *OmciAlarmRequest) GetUniID() uint32 {
+	if m != nil {
+		return m.UniID
+	}
+	return 0
+}
+
+func (m *OmciAlarmRequest) GetStatus() string {
+	if m != nil {
+		return m.Status
+	}
+	return ""
+}
+
+func (m *OmciAlarmRequest) GetOltID() int32 {
+	if m != nil {
+		return m.OltID
+	}
+	return 0
+}
+
+type OltAlarmRequest struct {
+	AlarmType            string   `protobuf:"bytes,1,opt,name=AlarmType,proto3" json:"AlarmType,omitempty"`
+	InterfaceID          uint32   `protobuf:"varint,2,opt,name=InterfaceID,proto3" json:"InterfaceID,omitempty"`
+	Status               string   `protobuf:"bytes,3,opt,name=Status,proto3" json:"Status,omitempty"`
+	OltID                int32    `protobuf:"varint,4,opt,name=OltID,proto3" json:"OltID,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *OltAlarmRequest) Reset()         { *m = OltAlarmRequest{} }
+func (m *OltAlarmRequest) String() string { return proto.CompactTextString(m) }
+func (*OltAlarmRequest) ProtoMessage()    {}
+func (*OltAlarmRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_ef7750073d18011b, []int{32}
+}
+
+func (m *OltAlarmRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_OltAlarmRequest.Unmarshal(m, b)
+}
+func (m *OltAlarmRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_OltAlarmRequest.Marshal(b, m, deterministic)
+}
+func (m *OltAlarmRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_OltAlarmRequest.Merge(m, src)
+}
+func (m *OltAlarmRequest) XXX_Size() int {
+	return xxx_messageInfo_OltAlarmRequest.Size(m)
+}
+func (m *OltAlarmRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_OltAlarmRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_OltAlarmRequest proto.InternalMessageInfo
+
+func (m *OltAlarmRequest) GetAlarmType() string {
+	if m != nil {
+		return m.AlarmType
+	}
+	return ""
+}
+
+func (m *OltAlarmRequest) GetInterfaceID() uint32 {
+	if m != nil {
+		return m.InterfaceID
+	}
+	return 0
+}
+
+func (m *OltAlarmRequest) GetStatus() string {
+	if m != nil {
+		return m.Status
+	}
+	return ""
+}
+
+func (m *OltAlarmRequest) GetOltID() int32 {
+	if m != nil {
+		return m.OltID
+	}
+	return 0
+}
+
+// a packet sent by the subscriber on a UNI, it is forwarded according to the upstream flows of the UNI
+type UniPacketRequest struct {
+	SerialNumber         string   `protobuf:"bytes,1,opt,name=SerialNumber,proto3" json:"SerialNumber,omitempty"`
+	UniID                uint32   `protobuf:"varint,2,opt,name=UniID,proto3" json:"UniID,omitempty"`
+	Packet               []byte   `protobuf:"bytes,3,opt,name=Packet,proto3" json:"Packet,omitempty"`
+	OltID                int32    `protobuf:"varint,4,opt,name=OltID,proto3" json:"OltID,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *UniPacketRequest) Reset()         { *m = UniPacketRequest{} }
+func (m *UniPacketRequest) String() string { return proto.CompactTextString(m) }
+func (*UniPacketRequest) ProtoMessage()    {}
+func (*UniPacketRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_ef7750073d18011b, []int{33}
+}
+
+func (m *UniPacketRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_UniPacketRequest.Unmarshal(m, b)
+}
+func (m *UniPacketRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_UniPacketRequest.Marshal(b, m, deterministic)
+}
+func (m *UniPacketRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_UniPacketRequest.Merge(m, src)
+}
+func (m *UniPacketRequest) XXX_Size() int {
+	return xxx_messageInfo_UniPacketRequest.Size(m)
+}
+func (m *UniPacketRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_UniPacketRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_UniPacketRequest proto.InternalMessageInfo
+
+func (m *UniPacketRequest) GetSerialNumber() string {
+	if m != nil {
+		return m.SerialNumber
+	}
+	return ""
+}
+
+func (m *UniPacketRequest) GetUniID() uint32 {
+	if m != nil {
+		return m.UniID
+	}
+	return 0
+}
+
+func (m *UniPacketRequest) GetPacket() []byte {
+	if m != nil {
+		return m.Packet
+	}
+	return nil
+}
+
+func (m *UniPacketRequest) GetOltID() int32 {
+	if m != nil {
+		return m.OltID
+	}
+	return 0
+}
+
+type VersionNumber struct {
+	Version              string   `protobuf:"bytes,1,opt,name=version,proto3" json:"version,omitempty"`
+	BuildTime            string   `protobuf:"bytes,2,opt,name=buildTime,proto3" json:"buildTime,omitempty"`
+	CommitHash           string   `protobuf:"bytes,3,opt,name=commitHash,proto3" json:"commitHash,omitempty"`
+	GitStatus            string   `protobuf:"bytes,4,opt,name=gitStatus,proto3" json:"gitStatus,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *VersionNumber) Reset()         { *m = VersionNumber{} }
+func (m *VersionNumber) String() string { return proto.CompactTextString(m) }
+func (*VersionNumber) ProtoMessage()    {}
+func (*VersionNumber) Descriptor() ([]byte, []int) {
+	return fileDescriptor_ef7750073d18011b, []int{34}
+}
+
+func (m *VersionNumber) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_VersionNumber.Unmarshal(m, b)
+}
+func (m *VersionNumber) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_VersionNumber.Marshal(b, m, deterministic)
+}
+func (m *VersionNumber) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_VersionNumber.Merge(m, src)
+}
+func (m *VersionNumber) XXX_Size() int {
+	return xxx_messageInfo_VersionNumber.Size(m)
+}
+func (m *VersionNumber) XXX_DiscardUnknown() {
+	xxx_messageInfo_VersionNumber.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_VersionNumber proto.InternalMessageInfo
+
+func (m *VersionNumber) GetVersion() string {
+	if m != nil {
+		return m.Version
+	}
+	return ""
+}
+
+func (m *VersionNumber) GetBuildTime() string {
+	if m != nil {
+		return m.BuildTime
+	}
+	return ""
+}
+
+func (m *VersionNumber) GetCommitHash() string {
+	if m != nil {
+		return m.CommitHash
+	}
+	return ""
+}
+
+func (m *VersionNumber) GetGitStatus() string {
+	if m != nil {
+		return m.GitStatus
+	}
+	return ""
+}
+
+type LogLevel struct {
+	Level                string   `protobuf:"bytes,1,opt,name=level,proto3" json:"level,omitempty"`
+	Caller               bool     `protobuf:"varint,2,opt,name=caller,proto3" json:"caller,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *LogLevel) Reset()         { *m = LogLevel{} }
+func (m *LogLevel) String() string { return proto.CompactTextString(m) }
+func (*LogLevel) ProtoMessage()    {}
+func (*LogLevel) Descriptor() ([]byte, []int) {
+	return fileDescriptor_ef7750073d18011b, []int{35}
+}
+
+func (m *LogLevel) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_LogLevel.Unmarshal(m, b)
+}
+func (m *LogLevel) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_LogLevel.Marshal(b, m, deterministic)
+}
+func (m *LogLevel) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_LogLevel.Merge(m, src)
+}
+func (m *LogLevel) XXX_Size() int {
+	return xxx_messageInfo_LogLevel.Size(m)
+}
+func (m *LogLevel) XXX_DiscardUnknown() {
+	xxx_messageInfo_LogLevel.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_LogLevel proto.InternalMessageInfo
+
+func (m *LogLevel) GetLevel() string {
+	if m != nil {
+		return m.Level
+	}
+	return ""
+}
+
+func (m *LogLevel) GetCaller() bool {
+	if m != nil {
+		return m.Caller
+	}
+	return false
+}
+
+type Response struct {
+	StatusCode           int32    `protobuf:"varint,1,opt,name=status_code,json=statusCode,proto3" json:"status_code,omitempty"`
+	Message              string   `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Response) Reset()         { *m = Response{} }
+func (m *Response) String() string { return proto.CompactTextString(m) }
+func (*Response) ProtoMessage()    {}
+func (*Response) Descriptor() ([]byte, []int) {
+	return fileDescriptor_ef7750073d18011b, []int{36}
+}
+
+func (m *Response) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Response.Unmarshal(m, b)
+}
+func (m *Response) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Response.Marshal(b, m, deterministic)
+}
+func (m *Response) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Response.Merge(m, src)
+}
+func (m *Response) XXX_Size() int {
+	return xxx_messageInfo_Response.Size(m)
+}
+func (m *Response) XXX_DiscardUnknown() {
+	xxx_messageInfo_Response.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Response proto.InternalMessageInfo
+
+func (m *Response) GetStatusCode() int32 {
+	if m != nil {
+		return m.StatusCode
+	}
+	return 0
+}
+
+func (m *Response) GetMessage() string {
+	if m != nil {
+		return m.Message
+	}
+	return ""
+}
+
+type Empty struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Empty) Reset()         { *m = Empty{} }
+func (m *Empty) String() string { return proto.CompactTextString(m) }
+func (*Empty) ProtoMessage()    {}
+func (*Empty) Descriptor() ([]byte, []int) {
+	return fileDescriptor_ef7750073d18011b, []int{37}
+}
+
+func (m *Empty) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Empty.Unmarshal(m, b)
+}
+func (m *Empty) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Empty.Marshal(b, m, deterministic)
+}
+func (m *Empty) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Empty.Merge(m, src)
+}
+func (m *Empty) XXX_Size() int {
+	return xxx_messageInfo_Empty.Size(m)
+}
+func (m *Empty) XXX_DiscardUnknown() {
+	xxx_messageInfo_Empty.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Empty proto.InternalMessageInfo
+
+func init() {
+	proto.RegisterType((*PONPort)(nil), "bbsim.PONPort")
+	proto.RegisterType((*NNIPort)(nil), "bbsim.NNIPort")
+	proto.RegisterType((*Olt)(nil), "bbsim.Olt")
+	proto.RegisterType((*Olts)(nil), "bbsim.Olts")
 	proto.RegisterType((*ONU)(nil), "bbsim.ONU")
+	proto.RegisterType((*UNI)(nil), "bbsim.UNI")
 	proto.RegisterType((*ONUs)(nil), "bbsim.ONUs")
+	proto.RegisterType((*Flows)(nil), "bbsim.Flows")
+	proto.RegisterType((*TCont)(nil), "bbsim.TCont")
+	proto.RegisterType((*GemPort)(nil), "bbsim.GemPort")
+	proto.RegisterType((*TConts)(nil), "bbsim.TConts")
+	proto.RegisterType((*OmciHistoryEntry)(nil), "bbsim.OmciHistoryEntry")
+	proto.RegisterType((*OmciHistory)(nil), "bbsim.OmciHistory")
+	proto.RegisterType((*OmciMeInstance)(nil), "bbsim.OmciMeInstance")
+	proto.RegisterType((*OnuMib)(nil), "bbsim.OnuMib")
+	proto.RegisterType((*SoftwareImage)(nil), "bbsim.SoftwareImage")
+	proto.RegisterType((*SoftwareImages)(nil), "bbsim.SoftwareImages")
+	proto.RegisterType((*PortStats)(nil), "bbsim.PortStats")
+	proto.RegisterType((*FlowStats)(nil), "bbsim.FlowStats")
+	proto.RegisterType((*OltStats)(nil), "bbsim.OltStats")
+	proto.RegisterType((*FaultRule)(nil), "bbsim.FaultRule")
+	proto.RegisterType((*FaultRules)(nil), "bbsim.FaultRules")
+	proto.RegisterType((*OmciFaultRule)(nil), "bbsim.OmciFaultRule")
+	proto.RegisterType((*OmciFaultRules)(nil), "bbsim.OmciFaultRules")
+	proto.RegisterType((*OltRequest)(nil), "bbsim.OltRequest")
+	proto.RegisterType((*RebootRequest)(nil), "bbsim.RebootRequest")
 	proto.RegisterType((*ONURequest)(nil), "bbsim.ONURequest")
+	proto.RegisterType((*SoftwareImageFaultRequest)(nil), "bbsim.SoftwareImageFaultRequest")
+	proto.RegisterType((*CreateOnuRequest)(nil), "bbsim.CreateOnuRequest")
+	proto.RegisterType((*MoveOnuRequest)(nil), "bbsim.MoveOnuRequest")
+	proto.RegisterType((*AlarmRequest)(nil), "bbsim.AlarmRequest")
+	proto.RegisterType((*OmciAlarmRequest)(nil), "bbsim.OmciAlarmRequest")
+	proto.RegisterType((*OltAlarmRequest)(nil), "bbsim.OltAlarmRequest")
+	proto.RegisterType((*UniPacketRequest)(nil), "bbsim.UniPacketRequest")
 	proto.RegisterType((*VersionNumber)(nil), "bbsim.VersionNumber")
 	proto.RegisterType((*LogLevel)(nil), "bbsim.LogLevel")
 	proto.RegisterType((*Response)(nil), "bbsim.Response")
 	proto.RegisterType((*Empty)(nil), "bbsim.Empty")
 }
 
-func init() { proto.RegisterFile("api/bbsim/bbsim.proto", fileDescriptor_ef7750073d18011b) }
+func init() { proto.RegisterFile("api/bbsim/bbsim.proto", fileDescriptor_ef7750073d18011b) }
+
+var fileDescriptor_ef7750073d18011b = []byte{
+	// 2107 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xc4, 0x59, 0xcd, 0x72, 0x1b, 0xb9,
+	0xf1, 0x27, 0x29, 0x92, 0x22, 0x9b, 0x1a, 0x59, 0xc2, 0x5f, 0xf6, 0x9f, 0x51, 0x9c, 0x8d, 0x6a,
+	0xe2, 0xda, 0x52, 0x9c, 0x94, 0x5d, 0x96, 0x36, 0xb5, 0x4e, 0xe5, 0xd3, 0x16, 0x65, 0x9b, 0x1b,
+	0x93, 0x54, 0x81, 0xe4, 0xba, 0x6a, 0x0f, 0x71, 0x0d, 0x49, 0x58, 0x9a, 0xda, 0xe1, 0x80, 0x19,
+	0x80, 0xd2, 0x72, 0x0f, 0xc9, 0x29, 0xef, 0x90, 0x1c, 0x92, 0x43, 0x2e, 0xa9, 0x9c, 0x52, 0x79,
+	0x8c, 0x3c, 0x40, 0xde, 0x25, 0x7b, 0x4b, 0x35, 0x3e, 0xe6, 0x83, 0x1c, 0xc9, 0x94, 0x0f, 0xc9,
+	0x45, 0x42, 0x37, 0xf0, 0x43, 0x37, 0x7e, 0x0d, 0x34, 0x1a, 0x43, 0xb8, 0xeb, 0xcd, 0xfc, 0xc7,
+	0xa3, 0x91, 0xf0, 0xa7, 0xfa, 0xef, 0xa3, 0x59, 0xc4, 0x25, 0x27, 0x15, 0x25, 0xec, 0x7f, 0xfb,
+	0x92, 0x07, 0xf2, 0xc2, 0x7b, 0xab, 0x94, 0xe2, 0x31, 0x9f, 0xb1, 0x90, 0x07, 0x52, 0x8f, 0x71,
+	0x3f, 0x85, 0xcd, 0xb3, 0x5e, 0xf7, 0x8c, 0x47, 0x92, 0x6c, 0x43, 0xa9, 0xdd, 0x6a, 0x16, 0x0f,
+	0x8a, 0x87, 0x15, 0x5a, 0x6a, 0xb7, 0xc8, 0x7d, 0xa8, 0xf7, 0x66, 0x2c, 0xea, 0x4b, 0x4f, 0xb2,
+	0x66, 0xe9, 0xa0, 0x78, 0x58, 0xa7, 0x89, 0x02, 0x81, 0xdd, 0x6e, 0xfb, 0x03, 0x80, 0xff, 0x2a,
+	0xc2, 0x46, 0x2f, 0x58, 0x45, 0xb9, 0xb0, 0xd5, 0x67, 0x91, 0xef, 0x05, 0xdd, 0xf9, 0x74, 0xc4,
+	0x22, 0x03, 0xcc, 0xe8, 0xb2, 0x33, 0x6f, 0x2c, 0xcd, 0x4c, 0x1e, 0x80, 0xd3, 0x0e, 0x25, 0x8b,
+	0x42, 0x2f, 0xd0, 0x23, 0xca, 0x6a, 0x44, 0x56, 0x49, 0x1e, 0x42, 0xcd, 0x38, 0x2e, 0x9a, 0x95,
+	0x83, 0x8d, 0xc3, 0xc6, 0xd1, 0xf6, 0x23, 0xcd, 0x9a, 0x51, 0xd3, 0xb8, 0x1f, 0xc7, 0x1a, 0x76,
+	0x44, 0xb3, 0x9a, 0x19, 0x6b, 0xd4, 0x34, 0xee, 0x77, 0x0f, 0xa1, 0xdc, 0x0b, 0xa4, 0x20, 0x07,
+	0x50, 0xf1, 0x25, 0x9b, 0x8a, 0x66, 0x51, 0x01, 0xc0, 0x00, 0x7a, 0x81, 0xa4, 0xba, 0xc3, 0xfd,
+	0x63, 0x09, 0x36, 0x7a, 0xdd, 0xe1, 0xff, 0x8c, 0x81, 0xfb, 0x50, 0x3f, 0xe3, 0x21, 0x7a, 0xdd,
+	0x6e, 0x35, 0x2b, 0xca, 0x7c, 0xa2, 0x20, 0x04, 0xca, 0xfd, 0x81, 0x77, 0xde, 0xac, 0xaa, 0x0e,
+	0xd5, 0x46, 0xdd, 0x09, 0xea, 0x36, 0xb5, 0x0e, 0xdb, 0x38, 0xcb, 0xab, 0xab, 0x67, 0x93, 0x49,
+	0xc4, 0x84, 0x68, 0xd6, 0xb4, 0x27, 0xb1, 0x82, 0xdc, 0x83, 0x2a, 0xce, 0xd7, 0xe5, 0xcd, 0xba,
+	0xc2, 0x18, 0x89, 0x7c, 0x04, 0xe5, 0x61, 0xe8, 0x8b, 0x26, 0x64, 0xc8, 0x19, 0x76, 0xdb, 0x54,
+	0xe9, 0xdd, 0x7f, 0x14, 0x61, 0x63, 0xd8, 0x6d, 0xaf, 0x70, 0xb3, 0x07, 0x95, 0x5e, 0x38, 0x6f,
+	0xb7, 0x14, 0x29, 0x15, 0xaa, 0x05, 0xa3, 0xed, 0x87, 0x86, 0x09, 0x2d, 0xa4, 0x6c, 0x97, 0x33,
+	0xb6, 0x33, 0x1e, 0x57, 0x96, 0x3d, 0xb6, 0x6b, 0xac, 0xa6, 0xd6, 0xb8, 0xc2, 0xe7, 0x66, 0x0e,
+	0x9f, 0x2a, 0xf2, 0xdd, 0xe1, 0xf5, 0x91, 0xef, 0x0e, 0x6d, 0xe4, 0x7f, 0x05, 0x95, 0x17, 0x01,
+	0xbf, 0x12, 0xe4, 0x3b, 0x00, 0xef, 0x02, 0x7e, 0xf5, 0x76, 0xcc, 0xe7, 0xa1, 0x54, 0xcb, 0x74,
+	0x68, 0x1d, 0x35, 0x27, 0xa8, 0x20, 0xdf, 0x83, 0x0a, 0x0a, 0xa2, 0x59, 0x52, 0x33, 0x39, 0x8f,
+	0xec, 0xa1, 0x45, 0x34, 0xd5, 0x7d, 0xee, 0x10, 0x2a, 0x83, 0x13, 0x1e, 0x4a, 0x64, 0x61, 0x18,
+	0xfa, 0x86, 0x2e, 0x87, 0x6a, 0x01, 0x57, 0xdb, 0xf2, 0x23, 0x36, 0x96, 0x3e, 0x0f, 0xed, 0x29,
+	0x8c, 0x15, 0xa4, 0x09, 0x9b, 0xcf, 0x82, 0x80, 0x8f, 0xdb, 0x2d, 0xc5, 0x9d, 0x43, 0xad, 0xe8,
+	0xfe, 0xad, 0x08, 0x9b, 0x2f, 0xd9, 0x54, 0x9d, 0xec, 0x0f, 0x99, 0xf9, 0x3e, 0xd4, 0x5f, 0xb2,
+	0xe9, 0x4c, 0xef, 0x2e, 0x3d, 0x77, 0xa2, 0x40, 0xbb, 0x67, 0x23, 0x5f, 0x76, 0xbc, 0x99, 0xd9,
+	0x9b, 0x56, 0x24, 0xfb, 0x50, 0x3b, 0x8b, 0x7c, 0x1e, 0xf9, 0x72, 0xa1, 0x82, 0xe3, 0xd0, 0x58,
+	0xc6, 0x88, 0xbe, 0x61, 0xfe, 0xf9, 0x85, 0x54, 0xd1, 0x71, 0xa8, 0x91, 0xdc, 0x2f, 0xa0, 0xaa,
+	0x28, 0x10, 0xe4, 0x81, 0x6d, 0x19, 0xf2, 0xb7, 0x0c, 0xf9, 0x4a, 0x49, 0xed, 0xa8, 0x87, 0x50,
+	0x33, 0x4b, 0xb3, 0xd4, 0xda, 0xf3, 0x6c, 0xd4, 0x34, 0xee, 0x77, 0xff, 0x5d, 0x84, 0x9d, 0xde,
+	0x74, 0xec, 0xbf, 0xf2, 0x85, 0xe4, 0xd1, 0xe2, 0x34, 0x94, 0xd1, 0x02, 0x37, 0xc9, 0xc0, 0x9f,
+	0x32, 0xc5, 0x47, 0x9d, 0xaa, 0xf6, 0x7b, 0xe8, 0x78, 0x00, 0xce, 0x20, 0xf2, 0x42, 0xe1, 0x29,
+	0xb1, 0x3d, 0x31, 0x94, 0x64, 0x95, 0xe4, 0x00, 0x1a, 0x1d, 0x26, 0x84, 0x77, 0xce, 0x06, 0x8b,
+	0x99, 0x3d, 0xb6, 0x69, 0x15, 0x12, 0xd7, 0x61, 0x27, 0x81, 0x67, 0xb6, 0xae, 0x43, 0xad, 0x48,
+	0x3e, 0x02, 0xe8, 0xb0, 0x76, 0x28, 0xa4, 0x17, 0x8e, 0x99, 0x21, 0x28, 0xa5, 0x41, 0xf2, 0x28,
+	0x13, 0xf3, 0x40, 0x9a, 0xdd, 0x6b, 0x24, 0x9c, 0xb1, 0x15, 0xf1, 0xd9, 0x8c, 0x4d, 0xd4, 0xf1,
+	0xad, 0x51, 0x2b, 0xba, 0xbf, 0x84, 0x46, 0x6a, 0xe5, 0xe4, 0x09, 0x6c, 0xe2, 0xea, 0x7d, 0x66,
+	0xc9, 0xfd, 0x7f, 0xbb, 0xb3, 0x97, 0xe8, 0xa1, 0x76, 0x9c, 0xfb, 0x6b, 0xd8, 0xc6, 0xce, 0x94,
+	0x17, 0x29, 0xff, 0x8b, 0x37, 0xf9, 0x5f, 0x5a, 0xf1, 0x9f, 0x40, 0xb9, 0xeb, 0x4d, 0x6d, 0xb6,
+	0x53, 0x6d, 0xf7, 0x2d, 0x54, 0x7b, 0xe1, 0xbc, 0xe3, 0x8f, 0x14, 0x73, 0xfe, 0xa8, 0xe5, 0x49,
+	0xaf, 0xbf, 0x08, 0xc7, 0x66, 0xee, 0xb4, 0x8a, 0x1c, 0x43, 0xdd, 0xce, 0x65, 0xa3, 0x7e, 0x37,
+	0xb5, 0x80, 0xc4, 0x12, 0x4d, 0xc6, 0xb9, 0x7f, 0x2a, 0x82, 0xd3, 0xe7, 0xef, 0xe4, 0x95, 0x17,
+	0xb1, 0xf6, 0xd4, 0x3b, 0x67, 0xb8, 0x3f, 0x63, 0x27, 0xb5, 0x95, 0x5a, 0x7a, 0x71, 0x9f, 0xb3,
+	0x48, 0x24, 0x1b, 0xc0, 0x8a, 0xe8, 0x5e, 0x5b, 0x9c, 0xf0, 0xe9, 0xd4, 0x97, 0x92, 0xe9, 0xe0,
+	0xd7, 0x68, 0x5a, 0xa5, 0xe6, 0x15, 0xcf, 0xc6, 0xd2, 0xbf, 0xd4, 0x71, 0xaf, 0xd1, 0x58, 0xc6,
+	0x79, 0xdb, 0xe2, 0x73, 0x2f, 0xf0, 0x27, 0x2a, 0xe8, 0x35, 0x6a, 0x45, 0xf7, 0x2f, 0x45, 0xd8,
+	0xce, 0xf8, 0x27, 0xc8, 0x0f, 0xa1, 0xaa, 0x5b, 0x26, 0x4a, 0x7b, 0x66, 0x91, 0x99, 0x61, 0xd4,
+	0x8c, 0x21, 0x87, 0x70, 0xa7, 0xc5, 0xaf, 0xc2, 0x80, 0x7b, 0x13, 0x36, 0x79, 0xbe, 0x90, 0x8a,
+	0x1b, 0x5c, 0xd5, 0xb2, 0x1a, 0xaf, 0x25, 0xab, 0xea, 0xfb, 0x5f, 0x33, 0xb3, 0x81, 0x33, 0x3a,
+	0x4c, 0x14, 0x2f, 0x3c, 0xdc, 0x62, 0x7a, 0xe7, 0x6a, 0xc1, 0xfd, 0x6b, 0x11, 0x6f, 0x9a, 0x48,
+	0x62, 0x9a, 0x14, 0xea, 0x80, 0xf3, 0x48, 0xaa, 0x0d, 0xae, 0xcf, 0x4f, 0x2c, 0x9b, 0x74, 0xaf,
+	0x1d, 0x30, 0x25, 0x04, 0xfd, 0xea, 0xcc, 0x1b, 0x7f, 0xc9, 0xa4, 0x50, 0x06, 0xcb, 0x34, 0x51,
+	0x20, 0x2d, 0xf4, 0x2b, 0xed, 0x73, 0x59, 0xf5, 0x59, 0x11, 0x71, 0x83, 0x18, 0x57, 0xd1, 0xb8,
+	0x41, 0x1a, 0x37, 0x30, 0xb8, 0xaa, 0xc6, 0x19, 0xd1, 0xfd, 0x7b, 0x11, 0xea, 0x98, 0x5b, 0xb5,
+	0xa7, 0xf7, 0xa0, 0x8a, 0x42, 0x7b, 0x62, 0x02, 0x6d, 0x24, 0x5c, 0x01, 0xb6, 0xd4, 0x0a, 0x74,
+	0x9c, 0x63, 0xf9, 0xbf, 0xee, 0xf1, 0x17, 0x50, 0xeb, 0x05, 0x86, 0xd9, 0x8f, 0xa1, 0xa2, 0x73,
+	0x9a, 0x0e, 0xfc, 0x8e, 0xad, 0x51, 0x2c, 0xf5, 0x54, 0x77, 0xe3, 0xb8, 0x17, 0xa9, 0x6b, 0xc5,
+	0x8e, 0x8b, 0x17, 0x4e, 0x75, 0xb7, 0xfb, 0x4f, 0x64, 0x03, 0x23, 0x48, 0xe7, 0x81, 0xca, 0x1f,
+	0x1d, 0x26, 0x2f, 0xf8, 0xc4, 0x44, 0xcd, 0x48, 0xe8, 0xf9, 0x69, 0x14, 0xf1, 0xe8, 0x84, 0x4f,
+	0xe2, 0x32, 0x2f, 0x56, 0xe0, 0xa9, 0xa5, 0xb6, 0x46, 0x29, 0x51, 0xd5, 0x46, 0xc4, 0x6b, 0x4f,
+	0xb2, 0x70, 0xbc, 0xe8, 0x68, 0x1e, 0x1c, 0x9a, 0x28, 0x90, 0xdd, 0xcf, 0xf0, 0x48, 0x44, 0x1d,
+	0x9b, 0xe2, 0x62, 0x19, 0x67, 0xc3, 0xe4, 0xa4, 0x48, 0xa8, 0x51, 0xd5, 0xc6, 0xcc, 0x8a, 0xff,
+	0x31, 0x07, 0xf3, 0xb9, 0xec, 0x08, 0x95, 0xde, 0x1c, 0x9a, 0x55, 0xba, 0x9f, 0x01, 0xc4, 0x4b,
+	0x11, 0xaa, 0x60, 0x08, 0x64, 0x5c, 0x59, 0x68, 0x01, 0x79, 0x51, 0xdd, 0xcb, 0xbc, 0x58, 0x1c,
+	0xd5, 0xdd, 0xee, 0x37, 0x45, 0x70, 0x30, 0x65, 0x24, 0xdc, 0x2c, 0x97, 0x6c, 0xc5, 0x9c, 0x92,
+	0x2d, 0x95, 0xf9, 0x4a, 0xd9, 0xcc, 0x87, 0x37, 0x47, 0xc4, 0x67, 0xa7, 0x97, 0x2c, 0x5a, 0xd8,
+	0xab, 0x32, 0x56, 0xbc, 0x87, 0xad, 0x26, 0x6c, 0x9e, 0xf0, 0x28, 0x9a, 0xcf, 0xa4, 0x4d, 0x0d,
+	0x46, 0x44, 0x1e, 0xdf, 0x44, 0x3c, 0x3c, 0x1f, 0xf8, 0x13, 0xc3, 0x57, 0x2c, 0x63, 0xae, 0xd5,
+	0xd9, 0x5f, 0x05, 0x4d, 0x13, 0x96, 0xd2, 0xd8, 0x3b, 0xa1, 0x2b, 0x2f, 0xd4, 0x9d, 0xe0, 0x50,
+	0x2b, 0xba, 0x14, 0xb6, 0x33, 0x4b, 0xbf, 0x8e, 0xcb, 0x87, 0x59, 0x2e, 0xf7, 0x52, 0x99, 0x76,
+	0x85, 0x4f, 0x17, 0x00, 0xcb, 0x62, 0xf6, 0x9b, 0x39, 0x13, 0x32, 0x7f, 0x3e, 0xf7, 0x0d, 0x38,
+	0x94, 0x8d, 0x38, 0xbf, 0x79, 0x18, 0x6e, 0x90, 0x4e, 0xb2, 0x0f, 0x55, 0x5b, 0xd1, 0xbb, 0xf0,
+	0xc3, 0xf3, 0x97, 0x9e, 0x98, 0x99, 0xcc, 0x9b, 0x28, 0xdc, 0x17, 0x00, 0x58, 0x99, 0x99, 0x59,
+	0xd7, 0x09, 0x64, 0x6c, 0xb9, 0x94, 0x76, 0xf0, 0x4b, 0xf8, 0x56, 0x26, 0xc3, 0xea, 0x55, 0xde,
+	0x6e, 0x5a, 0x85, 0x31, 0xbe, 0x6b, 0x21, 0x31, 0xb6, 0x91, 0x36, 0xf6, 0xe7, 0x12, 0xec, 0x9c,
+	0x44, 0xcc, 0x93, 0xac, 0x17, 0xce, 0x6f, 0x66, 0x24, 0x53, 0xe5, 0xeb, 0x8d, 0x97, 0x28, 0x56,
+	0x1c, 0xdb, 0xc8, 0x7f, 0x6b, 0x24, 0xf5, 0x72, 0x39, 0xa7, 0x5e, 0x56, 0xef, 0x84, 0x4a, 0xce,
+	0x3b, 0x61, 0xa9, 0x86, 0x3e, 0xf5, 0x66, 0x3c, 0x18, 0x0a, 0x2c, 0x99, 0xa7, 0x71, 0x0d, 0x9d,
+	0x51, 0xc6, 0xa3, 0xce, 0x3c, 0x21, 0xae, 0x78, 0x34, 0x31, 0x2f, 0x8a, 0xac, 0xd2, 0x5c, 0xf6,
+	0x03, 0x36, 0x9d, 0x05, 0x98, 0x5b, 0xea, 0xa6, 0x4c, 0x4a, 0x54, 0xee, 0x05, 0x6c, 0x77, 0xf8,
+	0x65, 0x9a, 0x9d, 0x75, 0x42, 0x70, 0x33, 0x57, 0xf9, 0xa1, 0xf8, 0x2d, 0x6c, 0x3d, 0x0b, 0xbc,
+	0x68, 0x6a, 0xed, 0xdc, 0x87, 0xba, 0x92, 0x53, 0xf7, 0x5b, 0xa2, 0x58, 0xeb, 0x6d, 0x77, 0x0f,
+	0xaa, 0x98, 0x86, 0xe7, 0xc2, 0x44, 0xc3, 0x48, 0x89, 0xfd, 0x72, 0xda, 0xfe, 0x1f, 0x4c, 0x7d,
+	0x9a, 0x71, 0x62, 0xcd, 0xc5, 0x26, 0x8e, 0x96, 0x96, 0x1d, 0x8d, 0x4b, 0xfe, 0x8d, 0x74, 0xc9,
+	0x9f, 0xb8, 0x56, 0xce, 0x77, 0xad, 0x92, 0x76, 0xed, 0x77, 0x70, 0xa7, 0x17, 0xc8, 0x5b, 0xb0,
+	0x83, 0x55, 0x12, 0x3e, 0xa9, 0xde, 0x79, 0x63, 0x16, 0x47, 0x20, 0xad, 0xba, 0x25, 0x37, 0x5f,
+	0xc3, 0xce, 0x30, 0xf4, 0xf5, 0x25, 0x7a, 0xcb, 0xa3, 0xa8, 0x17, 0x5f, 0x5a, 0x5a, 0xbc, 0x9e,
+	0x4a, 0xd9, 0xde, 0xa2, 0x46, 0xba, 0xc6, 0xf6, 0xef, 0x8b, 0xe0, 0x98, 0xea, 0x2f, 0xb9, 0x00,
+	0x2e, 0xb5, 0xc2, 0x18, 0xb5, 0x22, 0xb2, 0x32, 0x9a, 0xfb, 0xc1, 0x44, 0xbd, 0x29, 0x4c, 0x28,
+	0x62, 0x05, 0x26, 0xeb, 0xb1, 0x2a, 0x13, 0x5f, 0x79, 0xe2, 0xc2, 0xac, 0x3b, 0xa5, 0x41, 0xf4,
+	0xb9, 0x2f, 0x33, 0x71, 0x49, 0x14, 0xee, 0x53, 0xa8, 0xbd, 0xe6, 0xe7, 0xaf, 0xd9, 0x25, 0x0b,
+	0xd0, 0xd3, 0x00, 0x1b, 0xc6, 0xbe, 0x16, 0x70, 0x5d, 0x63, 0x2f, 0x08, 0xcc, 0x6e, 0xac, 0x51,
+	0x23, 0xb9, 0xa7, 0x50, 0xa3, 0x4c, 0xcc, 0x78, 0x28, 0x18, 0xf9, 0x2e, 0x34, 0x84, 0x9a, 0xef,
+	0xed, 0x18, 0xd3, 0xab, 0xce, 0x30, 0xa0, 0x55, 0xf6, 0xc6, 0x98, 0xea, 0x67, 0x8a, 0x2d, 0x7d,
+	0x8d, 0xe8, 0x6e, 0x42, 0xe5, 0x74, 0x3a, 0x93, 0x8b, 0xa3, 0x6f, 0xb6, 0xa1, 0xf2, 0xfc, 0x79,
+	0xdf, 0x9f, 0x92, 0xc7, 0x71, 0x9d, 0x4c, 0xec, 0x03, 0x4d, 0x0d, 0xd9, 0xb7, 0xd7, 0x44, 0x86,
+	0x38, 0xb7, 0x40, 0x3e, 0xc6, 0xb7, 0xa8, 0x54, 0xdf, 0x55, 0xb2, 0x80, 0x46, 0xf2, 0x59, 0x45,
+	0xb8, 0x05, 0xf2, 0x7d, 0xa8, 0xea, 0x71, 0x64, 0x37, 0xe9, 0x30, 0x91, 0xdf, 0x4f, 0x7d, 0x82,
+	0x71, 0x0b, 0xe4, 0x08, 0xe0, 0x8c, 0x5f, 0xb1, 0x88, 0x87, 0xd7, 0x0c, 0xbf, 0x63, 0x54, 0x96,
+	0x03, 0xb7, 0x40, 0x8e, 0xa1, 0xd1, 0xbf, 0x98, 0xcb, 0x09, 0xbf, 0xba, 0x05, 0xe8, 0x13, 0xa8,
+	0xeb, 0x9b, 0x0b, 0x21, 0x7b, 0x71, 0x7f, 0xea, 0x2e, 0xcb, 0x43, 0x3d, 0x85, 0x9d, 0xbe, 0xe4,
+	0xb3, 0x5e, 0x20, 0x5f, 0x31, 0x2f, 0x92, 0x23, 0xe6, 0xad, 0x6b, 0xef, 0xc7, 0xb0, 0xdb, 0x97,
+	0x5e, 0x24, 0x3f, 0x00, 0x7a, 0x0c, 0x0d, 0x4d, 0x9f, 0xae, 0x27, 0x6f, 0x00, 0xd9, 0x31, 0x6e,
+	0x81, 0xfc, 0x40, 0xc7, 0xa6, 0x3b, 0xcc, 0x05, 0x34, 0x92, 0xaf, 0x1f, 0xa9, 0x00, 0x75, 0x87,
+	0xc9, 0xd8, 0xee, 0x70, 0x25, 0x40, 0xdd, 0xa1, 0x5b, 0x20, 0x4f, 0xa0, 0xd1, 0x67, 0x32, 0xde,
+	0xbb, 0xd6, 0xb2, 0x55, 0xec, 0x2f, 0x2b, 0x96, 0xe2, 0x93, 0x6f, 0x22, 0x67, 0xd1, 0xa9, 0x8d,
+	0xb0, 0x36, 0xe6, 0x49, 0x1c, 0xd3, 0xb5, 0x21, 0x9f, 0xc0, 0x16, 0x65, 0x02, 0x03, 0xa3, 0xee,
+	0xb2, 0x35, 0x51, 0xc7, 0xd0, 0x30, 0xa8, 0xd6, 0xc5, 0x78, 0xb6, 0xb6, 0x77, 0x5b, 0xaf, 0x7d,
+	0x81, 0x71, 0xd4, 0x5f, 0x99, 0x72, 0xc2, 0xb2, 0x95, 0xaa, 0xf9, 0x45, 0x0a, 0x12, 0xce, 0x97,
+	0x20, 0xdd, 0xe1, 0x75, 0x90, 0x23, 0xd8, 0xc2, 0x50, 0x86, 0x73, 0xf3, 0x51, 0x25, 0x07, 0xe2,
+	0xa4, 0xbf, 0xbe, 0x20, 0xe6, 0xa7, 0xb0, 0xab, 0x31, 0xe9, 0xef, 0x0a, 0x39, 0x40, 0xb2, 0xfa,
+	0x65, 0xc1, 0x2d, 0x90, 0xc7, 0x50, 0xd7, 0x68, 0x7c, 0xf0, 0xdf, 0x60, 0x4e, 0x8f, 0x70, 0x0b,
+	0xe4, 0x39, 0xec, 0x69, 0xc0, 0xd2, 0x13, 0x39, 0x07, 0x7b, 0x37, 0xef, 0x95, 0x8c, 0x2e, 0xf7,
+	0xa0, 0xd9, 0x5f, 0x9d, 0x43, 0x17, 0x67, 0x07, 0x79, 0xa0, 0x74, 0xe1, 0x97, 0x17, 0x9d, 0x1f,
+	0xa9, 0x7d, 0xdd, 0x0b, 0xe7, 0xea, 0xde, 0x23, 0xff, 0x67, 0x46, 0xa4, 0xaf, 0xc9, 0x3c, 0xd8,
+	0xcf, 0x61, 0xa7, 0xcf, 0xc2, 0x89, 0xe1, 0x4e, 0x63, 0xd3, 0x1f, 0x60, 0xde, 0x87, 0x7f, 0xaa,
+	0xcd, 0x9a, 0xfb, 0x98, 0xdc, 0x4b, 0xf6, 0xc4, 0xfb, 0x90, 0x47, 0x50, 0x8f, 0x6b, 0xcd, 0xd8,
+	0xe4, 0x72, 0xf5, 0xb9, 0x72, 0x78, 0xeb, 0x2d, 0x16, 0x30, 0x8d, 0x59, 0x6f, 0xd7, 0x3e, 0x82,
+	0x4d, 0x53, 0xb2, 0x11, 0x1b, 0x8c, 0x6c, 0x09, 0xb7, 0x64, 0xe2, 0x27, 0xe0, 0x20, 0x21, 0xf1,
+	0x05, 0x1f, 0xbb, 0xb6, 0x7c, 0xe5, 0xe7, 0x07, 0xc1, 0xe9, 0x33, 0x99, 0x7a, 0xc5, 0xec, 0x2e,
+	0x3f, 0xf6, 0x44, 0x1e, 0xec, 0x53, 0x70, 0x5e, 0xe6, 0xc2, 0x52, 0x47, 0x6b, 0x75, 0x26, 0xb7,
+	0x40, 0x7e, 0x06, 0xbb, 0xc8, 0x7e, 0xf6, 0xe5, 0x74, 0x37, 0xef, 0x51, 0x94, 0x6b, 0xf7, 0x17,
+	0xfa, 0xdc, 0x64, 0xe1, 0x39, 0xb6, 0xf3, 0x67, 0x74, 0x0b, 0xa3, 0xaa, 0xfa, 0x99, 0xe7, 0xf8,
+	0x3f, 0x03, 0x00, 0xaa, 0x3a, 0xf7, 0x2c, 0x23, 0x1a, 0x00, 0x00,
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConn
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion4
+
+// BBSimClient is the client API for BBSim service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type BBSimClient interface {
+	Version(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*VersionNumber, error)
+	GetOlts(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*Olts, error)
+	GetOlt(ctx context.Context, in *OltRequest, opts ...grpc.CallOption) (*Olt, error)
+	PoweronOlt(ctx context.Context, in *OltRequest, opts ...grpc.CallOption) (*Response, error)
+	ShutdownOlt(ctx context.Context, in *OltRequest, opts ...grpc.CallOption) (*Response, error)
+	RebootOlt(ctx context.Context, in *RebootRequest, opts ...grpc.CallOption) (*Response, error)
+	StopOltHeartbeat(ctx context.Context, in *OltRequest, opts ...grpc.CallOption) (*Response, error)
+	StartOltHeartbeat(ctx context.Context, in *OltRequest, opts ...grpc.CallOption) (*Response, error)
+	GetOltStats(ctx context.Context, in *OltRequest, opts ...grpc.CallOption) (*OltStats, error)
+	GetONUs(ctx context.Context, in *OltRequest, opts ...grpc.CallOption) (*ONUs, error)
+	GetONU(ctx context.Context, in *ONURequest, opts ...grpc.CallOption) (*ONU, error)
+	SetLogLevel(ctx context.Context, in *LogLevel, opts ...grpc.CallOption) (*LogLevel, error)
+	ShutdownONU(ctx context.Context, in *ONURequest, opts ...grpc.CallOption) (*Response, error)
+	PoweronONU(ctx context.Context, in *ONURequest, opts ...grpc.CallOption) (*Response, error)
+	RebootONU(ctx context.Context, in *ONURequest, opts ...grpc.CallOption) (*Response, error)
+	RestartEapol(ctx context.Context, in *ONURequest, opts ...grpc.CallOption) (*Response, error)
+	RestartDhcp(ctx context.Context, in *ONURequest, opts ...grpc.CallOption) (*Response, error)
+	ListOltFlows(ctx context.Context, in *OltRequest, opts ...grpc.CallOption) (*Flows, error)
+	ListOnuFlows(ctx context.Context, in *ONURequest, opts ...grpc.CallOption) (*Flows, error)
+	GetOnuTConts(ctx context.Context, in *ONURequest, opts ...grpc.CallOption) (*TConts, error)
+	GetOnuOmciHistory(ctx context.Context, in *ONURequest, opts ...grpc.CallOption) (*OmciHistory, error)
+	GetOnuMib(ctx context.Context, in *ONURequest, opts ...grpc.CallOption) (*OnuMib, error)
+	GetOnuSoftwareImages(ctx context.Context, in *ONURequest, opts ...grpc.CallOption) (*SoftwareImages, error)
+	SetOnuSoftwareImageFault(ctx context.Context, in *SoftwareImageFaultRequest, opts ...grpc.CallOption) (*Response, error)
+	SetOnuAlarm(ctx context.Context, in *AlarmRequest, opts ...grpc.CallOption) (*Response, error)
+	SendOnuOmciAlarm(ctx context.Context, in *OmciAlarmRequest, opts ...grpc.CallOption) (*Response, error)
+	SetOltAlarm(ctx context.Context, in *OltAlarmRequest, opts ...grpc.CallOption) (*Response, error)
+	CreateOnu(ctx context.Context, in *CreateOnuRequest, opts ...grpc.CallOption) (*ONU, error)
+	DeleteOnu(ctx context.Context, in *ONURequest, opts ...grpc.CallOption) (*Response, error)
+	MoveOnu(ctx context.Context, in *MoveOnuRequest, opts ...grpc.CallOption) (*ONU, error)
+	SendUniPacket(ctx context.Context, in *UniPacketRequest, opts ...grpc.CallOption) (*Response, error)
+	SetFaultRules(ctx context.Context, in *FaultRules, opts ...grpc.CallOption) (*Response, error)
+	GetFaultRules(ctx context.Context, in *OltRequest, opts ...grpc.CallOption) (*FaultRules, error)
+	SetOmciFaultRules(ctx context.Context, in *OmciFaultRules, opts ...grpc.CallOption) (*Response, error)
+	GetOmciFaultRules(ctx context.Context, in *OltRequest, opts ...grpc.CallOption) (*OmciFaultRules, error)
+}
+
+type bBSimClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewBBSimClient(cc *grpc.ClientConn) BBSimClient {
+	return &bBSimClient{cc}
+}
+
+func (c *bBSimClient) Version(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*VersionNumber, error) {
+	out := new(VersionNumber)
+	err := c.cc.Invoke(ctx, "/bbsim.BBSim/Version", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bBSimClient) GetOlts(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*Olts, error) {
+	out := new(Olts)
+	err := c.cc.Invoke(ctx, "/bbsim.BBSim/GetOlts", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bBSimClient) GetOlt(ctx context.Context, in *OltRequest, opts ...grpc.CallOption) (*Olt, error) {
+	out := new(Olt)
+	err := c.cc.Invoke(ctx, "/bbsim.BBSim/GetOlt", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bBSimClient) PoweronOlt(ctx context.Context, in *OltRequest, opts ...grpc.CallOption) (*Response, error) {
+	out := new(Response)
+	err := c.cc.Invoke(ctx, "/bbsim.BBSim/PoweronOlt", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bBSimClient) ShutdownOlt(ctx context.Context, in *OltRequest, opts ...grpc.CallOption) (*Response, error) {
+	out := new(Response)
+	err := c.cc.Invoke(ctx, "/bbsim.BBSim/ShutdownOlt", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bBSimClient) RebootOlt(ctx context.Context, in *RebootRequest, opts ...grpc.CallOption) (*Response, error) {
+	out := new(Response)
+	err := c.cc.Invoke(ctx, "/bbsim.BBSim/RebootOlt", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bBSimClient) StopOltHeartbeat(ctx context.Context, in *OltRequest, opts ...grpc.CallOption) (*Response, error) {
+	out := new(Response)
+	err := c.cc.Invoke(ctx, "/bbsim.BBSim/StopOltHeartbeat", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bBSimClient) StartOltHeartbeat(ctx context.Context, in *OltRequest, opts ...grpc.CallOption) (*Response, error) {
+	out := new(Response)
+	err := c.cc.Invoke(ctx, "/bbsim.BBSim/StartOltHeartbeat", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bBSimClient) GetOltStats(ctx context.Context, in *OltRequest, opts ...grpc.CallOption) (*OltStats, error) {
+	out := new(OltStats)
+	err := c.cc.Invoke(ctx, "/bbsim.BBSim/GetOltStats", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bBSimClient) GetONUs(ctx context.Context, in *OltRequest, opts ...grpc.CallOption) (*ONUs, error) {
+	out := new(ONUs)
+	err := c.cc.Invoke(ctx, "/bbsim.BBSim/GetONUs", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bBSimClient) GetONU(ctx context.Context, in *ONURequest, opts ...grpc.CallOption) (*ONU, error) {
+	out := new(ONU)
+	err := c.cc.Invoke(ctx, "/bbsim.BBSim/GetONU", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bBSimClient) SetLogLevel(ctx context.Context, in *LogLevel, opts ...grpc.CallOption) (*LogLevel, error) {
+	out := new(LogLevel)
+	err := c.cc.Invoke(ctx, "/bbsim.BBSim/SetLogLevel", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bBSimClient) ShutdownONU(ctx context.Context, in *ONURequest, opts ...grpc.CallOption) (*Response, error) {
+	out := new(Response)
+	err := c.cc.Invoke(ctx, "/bbsim.BBSim/ShutdownONU", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bBSimClient) PoweronONU(ctx context.Context, in *ONURequest, opts ...grpc.CallOption) (*Response, error) {
+	out := new(Response)
+	err := c.cc.Invoke(ctx, "/bbsim.BBSim/PoweronONU", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bBSimClient) RebootONU(ctx context.Context, in *ONURequest, opts ...grpc.CallOption) (*Response, error) {
+	out := new(Response)
+	err := c.cc.Invoke(ctx, "/bbsim.BBSim/RebootONU", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bBSimClient) RestartEapol(ctx context.Context, in *ONURequest, opts ...grpc.CallOption) (*Response, error) {
+	out := new(Response)
+	err := c.cc.Invoke(ctx, "/bbsim.BBSim/RestartEapol", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bBSimClient) RestartDhcp(ctx context.Context, in *ONURequest, opts ...grpc.CallOption) (*Response, error) {
+	out := new(Response)
+	err := c.cc.Invoke(ctx, "/bbsim.BBSim/RestartDhcp", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bBSimClient) ListOltFlows(ctx context.Context, in *OltRequest, opts ...grpc.CallOption) (*Flows, error) {
+	out := new(Flows)
+	err := c.cc.Invoke(ctx, "/bbsim.BBSim/ListOltFlows", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bBSimClient) ListOnuFlows(ctx context.Context, in *ONURequest, opts ...grpc.CallOption) (*Flows, error) {
+	out := new(Flows)
+	err := c.cc.Invoke(ctx, "/bbsim.BBSim/ListOnuFlows", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bBSimClient) GetOnuTConts(ctx context.Context, in *ONURequest, opts ...grpc.CallOption) (*TConts, error) {
+	out := new(TConts)
+	err := c.cc.Invoke(ctx, "/bbsim.BBSim/GetOnuTConts", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bBSimClient) GetOnuOmciHistory(ctx context.Context, in *ONURequest, opts ...grpc.CallOption) (*OmciHistory, error) {
+	out := new(OmciHistory)
+	err := c.cc.Invoke(ctx, "/bbsim.BBSim/GetOnuOmciHistory", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bBSimClient) GetOnuMib(ctx context.Context, in *ONURequest, opts ...grpc.CallOption) (*OnuMib, error) {
+	out := new(OnuMib)
+	err := c.cc.Invoke(ctx, "/bbsim.BBSim/GetOnuMib", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bBSimClient) GetOnuSoftwareImages(ctx context.Context, in *ONURequest, opts ...grpc.CallOption) (*SoftwareImages, error) {
+	out := new(SoftwareImages)
+	err := c.cc.Invoke(ctx, "/bbsim.BBSim/GetOnuSoftwareImages", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bBSimClient) SetOnuSoftwareImageFault(ctx context.Context, in *SoftwareImageFaultRequest, opts ...grpc.CallOption) (*Response, error) {
+	out := new(Response)
+	err := c.cc.Invoke(ctx, "/bbsim.BBSim/SetOnuSoftwareImageFault", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bBSimClient) SetOnuAlarm(ctx context.Context, in *AlarmRequest, opts ...grpc.CallOption) (*Response, error) {
+	out := new(Response)
+	err := c.cc.Invoke(ctx, "/bbsim.BBSim/SetOnuAlarm", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bBSimClient) SendOnuOmciAlarm(ctx context.Context, in *OmciAlarmRequest, opts ...grpc.CallOption) (*Response, error) {
+	out := new(Response)
+	err := c.cc.Invoke(ctx, "/bbsim.BBSim/SendOnuOmciAlarm", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bBSimClient) SetOltAlarm(ctx context.Context, in *OltAlarmRequest, opts ...grpc.CallOption) (*Response, error) {
+	out := new(Response)
+	err := c.cc.Invoke(ctx, "/bbsim.BBSim/SetOltAlarm", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bBSimClient) CreateOnu(ctx context.Context, in *CreateOnuRequest, opts ...grpc.CallOption) (*ONU, error) {
+	out := new(ONU)
+	err := c.cc.Invoke(ctx, "/bbsim.BBSim/CreateOnu", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bBSimClient) DeleteOnu(ctx context.Context, in *ONURequest, opts ...grpc.CallOption) (*Response, error) {
+	out := new(Response)
+	err := c.cc.Invoke(ctx, "/bbsim.BBSim/DeleteOnu", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bBSimClient) MoveOnu(ctx context.Context, in *MoveOnuRequest, opts ...grpc.CallOption) (*ONU, error) {
+	out := new(ONU)
+	err := c.cc.Invoke(ctx, "/bbsim.BBSim/MoveOnu", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bBSimClient) SendUniPacket(ctx context.Context, in *UniPacketRequest, opts ...grpc.CallOption) (*Response, error) {
+	out := new(Response)
+	err := c.cc.Invoke(ctx, "/bbsim.BBSim/SendUniPacket", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bBSimClient) SetFaultRules(ctx context.Context, in *FaultRules, opts ...grpc.CallOption) (*Response, error) {
+	out := new(Response)
+	err := c.cc.Invoke(ctx, "/bbsim.BBSim/SetFaultRules", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bBSimClient) GetFaultRules(ctx context.Context, in *OltRequest, opts ...grpc.CallOption) (*FaultRules, error) {
+	out := new(FaultRules)
+	err := c.cc.Invoke(ctx, "/bbsim.BBSim/GetFaultRules", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bBSimClient) SetOmciFaultRules(ctx context.Context, in *OmciFaultRules, opts ...grpc.CallOption) (*Response, error) {
+	out := new(Response)
+	err := c.cc.Invoke(ctx, "/bbsim.BBSim/SetOmciFaultRules", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bBSimClient) GetOmciFaultRules(ctx context.Context, in *OltRequest, opts ...grpc.CallOption) (*OmciFaultRules, error) {
+	out := new(OmciFaultRules)
+	err := c.cc.Invoke(ctx, "/bbsim.BBSim/GetOmciFaultRules", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// BBSimServer is the server API for BBSim service.
+type BBSimServer interface {
+	Version(context.Context, *Empty) (*VersionNumber, error)
+	GetOlts(context.Context, *Empty) (*Olts, error)
+	GetOlt(context.Context, *OltRequest) (*Olt, error)
+	PoweronOlt(context.Context, *OltRequest) (*Response, error)
+	ShutdownOlt(context.Context, *OltRequest) (*Response, error)
+	RebootOlt(context.Context, *RebootRequest) (*Response, error)
+	StopOltHeartbeat(context.Context, *OltRequest) (*Response, error)
+	StartOltHeartbeat(context.Context, *OltRequest) (*Response, error)
+	GetOltStats(context.Context, *OltRequest) (*OltStats, error)
+	GetONUs(context.Context, *OltRequest) (*ONUs, error)
+	GetONU(context.Context, *ONURequest) (*ONU, error)
+	SetLogLevel(context.Context, *LogLevel) (*LogLevel, error)
+	ShutdownONU(context.Context, *ONURequest) (*Response, error)
+	PoweronONU(context.Context, *ONURequest) (*Response, error)
+	RebootONU(context.Context, *ONURequest) (*Response, error)
+	RestartEapol(context.Context, *ONURequest) (*Response, error)
+	RestartDhcp(context.Context, *ONURequest) (*Response, error)
+	ListOltFlows(context.Context, *OltRequest) (*Flows, error)
+	ListOnuFlows(context.Context, *ONURequest) (*Flows, error)
+	GetOnuTConts(context.Context, *ONURequest) (*TConts, error)
+	GetOnuOmciHistory(context.Context, *ONURequest) (*OmciHistory, error)
+	GetOnuMib(context.Context, *ONURequest) (*OnuMib, error)
+	GetOnuSoftwareImages(context.Context, *ONURequest) (*SoftwareImages, error)
+	SetOnuSoftwareImageFault(context.Context, *SoftwareImageFaultRequest) (*Response, error)
+	SetOnuAlarm(context.Context, *AlarmRequest) (*Response, error)
+	SendOnuOmciAlarm(context.Context, *OmciAlarmRequest) (*Response, error)
+	SetOltAlarm(context.Context, *OltAlarmRequest) (*Response, error)
+	CreateOnu(context.Context, *CreateOnuRequest) (*ONU, error)
+	DeleteOnu(context.Context, *ONURequest) (*Response, error)
+	MoveOnu(context.Context, *MoveOnuRequest) (*ONU, error)
+	SendUniPacket(context.Context, *UniPacketRequest) (*Response, error)
+	SetFaultRules(context.Context, *FaultRules) (*Response, error)
+	GetFaultRules(context.Context, *OltRequest) (*FaultRules, error)
+	SetOmciFaultRules(context.Context, *OmciFaultRules) (*Response, error)
+	GetOmciFaultRules(context.Context, *OltRequest) (*OmciFaultRules, error)
+}
+
+// UnimplementedBBSimServer can be embedded to have forward compatible implementations.
+type UnimplementedBBSimServer struct {
+}
+
+func (*UnimplementedBBSimServer) Version(ctx context.Context, req *Empty) (*VersionNumber, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Version not implemented")
+}
+func (*UnimplementedBBSimServer) GetOlts(ctx context.Context, req *Empty) (*Olts, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetOlts not implemented")
+}
+func (*UnimplementedBBSimServer) GetOlt(ctx context.Context, req *OltRequest) (*Olt, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetOlt not implemented")
+}
+func (*UnimplementedBBSimServer) PoweronOlt(ctx context.Context, req *OltRequest) (*Response, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PoweronOlt not implemented")
+}
+func (*UnimplementedBBSimServer) ShutdownOlt(ctx context.Context, req *OltRequest) (*Response, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ShutdownOlt not implemented")
+}
+func (*UnimplementedBBSimServer) RebootOlt(ctx context.Context, req *RebootRequest) (*Response, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RebootOlt not implemented")
+}
+func (*UnimplementedBBSimServer) StopOltHeartbeat(ctx context.Context, req *OltRequest) (*Response, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method StopOltHeartbeat not implemented")
+}
+func (*UnimplementedBBSimServer) StartOltHeartbeat(ctx context.Context, req *OltRequest) (*Response, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method StartOltHeartbeat not implemented")
+}
+func (*UnimplementedBBSimServer) GetOltStats(ctx context.Context, req *OltRequest) (*OltStats, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetOltStats not implemented")
+}
+func (*UnimplementedBBSimServer) GetONUs(ctx context.Context, req *OltRequest) (*ONUs, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetONUs not implemented")
+}
+func (*UnimplementedBBSimServer) GetONU(ctx context.Context, req *ONURequest) (*ONU, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetONU not implemented")
+}
+func (*UnimplementedBBSimServer) SetLogLevel(ctx context.Context, req *LogLevel) (*LogLevel, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetLogLevel not implemented")
+}
+func (*UnimplementedBBSimServer) ShutdownONU(ctx context.Context, req *ONURequest) (*Response, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ShutdownONU not implemented")
+}
+func (*UnimplementedBBSimServer) PoweronONU(ctx context.Context, req *ONURequest) (*Response, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PoweronONU not implemented")
+}
+func (*UnimplementedBBSimServer) RebootONU(ctx context.Context, req *ONURequest) (*Response, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RebootONU not implemented")
+}
+func (*UnimplementedBBSimServer) RestartEapol(ctx context.Context, req *ONURequest) (*Response, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RestartEapol not implemented")
+}
+func (*UnimplementedBBSimServer) RestartDhcp(ctx context.Context, req *ONURequest) (*Response, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RestartDhcp not implemented")
+}
+func (*UnimplementedBBSimServer) ListOltFlows(ctx context.Context, req *OltRequest) (*Flows, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListOltFlows not implemented")
+}
+func (*UnimplementedBBSimServer) ListOnuFlows(ctx context.Context, req *ONURequest) (*Flows, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListOnuFlows not implemented")
+}
+func (*UnimplementedBBSimServer) GetOnuTConts(ctx context.Context, req *ONURequest) (*TConts, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetOnuTConts not implemented")
+}
+func (*UnimplementedBBSimServer) GetOnuOmciHistory(ctx context.Context, req *ONURequest) (*OmciHistory, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetOnuOmciHistory not implemented")
+}
+func (*UnimplementedBBSimServer) GetOnuMib(ctx context.Context, req *ONURequest) (*OnuMib, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetOnuMib not implemented")
+}
+func (*UnimplementedBBSimServer) GetOnuSoftwareImages(ctx context.Context, req *ONURequest) (*SoftwareImages, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetOnuSoftwareImages not implemented")
+}
+func (*UnimplementedBBSimServer) SetOnuSoftwareImageFault(ctx context.Context, req *SoftwareImageFaultRequest) (*Response, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetOnuSoftwareImageFault not implemented")
+}
+func (*UnimplementedBBSimServer) SetOnuAlarm(ctx context.Context, req *AlarmRequest) (*Response, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetOnuAlarm not implemented")
+}
+func (*UnimplementedBBSimServer) SendOnuOmciAlarm(ctx context.Context, req *OmciAlarmRequest) (*Response, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SendOnuOmciAlarm not implemented")
+}
+func (*UnimplementedBBSimServer) SetOltAlarm(ctx context.Context, req *OltAlarmRequest) (*Response, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetOltAlarm not implemented")
+}
+func (*UnimplementedBBSimServer) CreateOnu(ctx context.Context, req *CreateOnuRequest) (*ONU, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateOnu not implemented")
+}
+func (*UnimplementedBBSimServer) DeleteOnu(ctx context.Context, req *ONURequest) (*Response, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteOnu not implemented")
+}
+func (*UnimplementedBBSimServer) MoveOnu(ctx context.Context, req *MoveOnuRequest) (*ONU, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method MoveOnu not implemented")
+}
+func (*UnimplementedBBSimServer) SendUniPacket(ctx context.Context, req *UniPacketRequest) (*Response, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SendUniPacket not implemented")
+}
+func (*UnimplementedBBSimServer) SetFaultRules(ctx context.Context, req *FaultRules) (*Response, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetFaultRules not implemented")
+}
+func (*UnimplementedBBSimServer) GetFaultRules(ctx context.Context, req *OltRequest) (*FaultRules, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetFaultRules not implemented")
+}
+func (*UnimplementedBBSimServer) SetOmciFaultRules(ctx context.Context, req *OmciFaultRules) (*Response, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetOmciFaultRules not implemented")
+}
+func (*UnimplementedBBSimServer) GetOmciFaultRules(ctx context.Context, req *OltRequest) (*OmciFaultRules, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetOmciFaultRules not implemented")
+}
+
+func RegisterBBSimServer(s *grpc.Server, srv BBSimServer) {
+	s.RegisterService(&_BBSim_serviceDesc, srv)
+}
+
+func _BBSim_Version_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BBSimServer).Version(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/bbsim.BBSim/Version",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BBSimServer).Version(ctx, req.(*Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BBSim_GetOlts_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BBSimServer).GetOlts(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/bbsim.BBSim/GetOlts",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BBSimServer).GetOlts(ctx, req.(*Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BBSim_GetOlt_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(OltRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BBSimServer).GetOlt(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/bbsim.BBSim/GetOlt",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BBSimServer).GetOlt(ctx, req.(*OltRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
 
-var fileDescriptor_ef7750073d18011b = []byte{
-	// 617 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xc4, 0x55, 0xd1, 0x8e, 0xd2, 0x4c,
-	0x18, 0x6d, 0x81, 0x52, 0xf8, 0xca, 0xee, 0x9f, 0x7f, 0xb2, 0x9a, 0x86, 0x6c, 0x94, 0x4c, 0x36,
-	0x06, 0x8d, 0xb2, 0x0a, 0x26, 0x7a, 0xeb, 0x2e, 0xc4, 0x25, 0xd9, 0x14, 0xd2, 0x2e, 0xde, 0x9a,
-	0x96, 0x4e, 0xa0, 0x49, 0xdb, 0xa9, 0x9d, 0x61, 0x89, 0x0f, 0xe0, 0xa3, 0xf9, 0x08, 0x3e, 0x8c,
-	0x77, 0x66, 0xda, 0x69, 0xa1, 0xcb, 0x5e, 0x10, 0x6f, 0xbc, 0x21, 0x73, 0xce, 0x9c, 0x33, 0xf3,
-	0x7d, 0x67, 0x3e, 0x52, 0x78, 0xe2, 0x26, 0xc1, 0xa5, 0xe7, 0xb1, 0x20, 0xca, 0x7f, 0x07, 0x49,
-	0x4a, 0x39, 0x45, 0x5a, 0x06, 0xf0, 0x07, 0xd0, 0xe7, 0x33, 0x6b, 0x4e, 0x53, 0x8e, 0x4e, 0xa1,
-	0x36, 0x1d, 0x9b, 0x6a, 0x4f, 0xed, 0x6b, 0x76, 0x6d, 0x3a, 0x46, 0xe7, 0xd0, 0x9e, 0x25, 0x24,
-	0x75, 0xb8, 0xcb, 0x89, 0x59, 0xeb, 0xa9, 0xfd, 0xb6, 0xbd, 0x23, 0x84, 0xd1, 0xb2, 0xa6, 0x7f,
-	0x61, 0xfc, 0xa5, 0x42, 0x7d, 0x16, 0x1e, 0xba, 0x30, 0x74, 0x1c, 0x92, 0x06, 0x6e, 0x68, 0x6d,
-	0x22, 0x8f, 0xa4, 0xd2, 0x58, 0xe1, 0xaa, 0x27, 0xd7, 0x1f, 0x9c, 0x8c, 0x2e, 0xe0, 0x64, 0x1a,
-	0x73, 0x92, 0xc6, 0x6e, 0x98, 0x2b, 0x1a, 0x99, 0xa2, 0x4a, 0xa2, 0x57, 0xd0, 0x92, 0x85, 0x33,
-	0x53, 0xeb, 0xd5, 0xfb, 0xc6, 0xf0, 0x74, 0x90, 0x07, 0x23, 0x69, 0xbb, 0xdc, 0x17, 0x5a, 0x99,
-	0x0e, 0x33, 0x9b, 0x15, 0xad, 0xa4, 0xed, 0x72, 0x1f, 0xff, 0x16, 0x7d, 0x59, 0x8b, 0x7f, 0xd6,
-	0xd7, 0x39, 0xb4, 0xe7, 0x34, 0x16, 0xb5, 0x4c, 0xc7, 0xa6, 0x96, 0x5d, 0xbf, 0x23, 0x10, 0x82,
-	0x86, 0x73, 0xe7, 0xae, 0xcc, 0x66, 0xb6, 0x91, 0xad, 0x05, 0x77, 0x2d, 0x38, 0x3d, 0xe7, 0xc4,
-	0x5a, 0x9c, 0x72, 0xb3, 0xfd, 0xe4, 0xfb, 0x29, 0x61, 0xcc, 0x6c, 0xe5, 0x95, 0x94, 0x04, 0x7a,
-	0x0a, 0x4d, 0x71, 0x9e, 0x45, 0xcd, 0x76, 0xe6, 0x91, 0x08, 0xf7, 0xa1, 0x31, 0xb3, 0x16, 0x0c,
-	0xf5, 0x40, 0x0b, 0x38, 0x89, 0x98, 0xa9, 0x66, 0x61, 0x81, 0x0c, 0x6b, 0x66, 0x2d, 0xec, 0x7c,
-	0x03, 0xbf, 0x05, 0x10, 0x88, 0x7c, 0xdb, 0x10, 0xc6, 0x0f, 0xb2, 0x51, 0x0f, 0xb3, 0xc1, 0x3f,
-	0x54, 0x38, 0xf9, 0x42, 0x52, 0x16, 0xd0, 0x58, 0xa6, 0x65, 0x82, 0x7e, 0x9f, 0x13, 0xd2, 0x50,
-	0x40, 0x51, 0xbd, 0xb7, 0x09, 0x42, 0xff, 0x2e, 0x88, 0xca, 0xc9, 0x2b, 0x09, 0xf4, 0x0c, 0x60,
-	0x49, 0xa3, 0x28, 0xe0, 0x37, 0x2e, 0x5b, 0xcb, 0x98, 0xf7, 0x18, 0xe1, 0x5e, 0x05, 0x5c, 0xa4,
-	0xb9, 0x61, 0x32, 0xe3, 0x1d, 0x81, 0x3f, 0x42, 0xeb, 0x96, 0xae, 0x6e, 0xc9, 0x3d, 0x09, 0xd1,
-	0x19, 0x68, 0xa1, 0x58, 0xc8, 0xfb, 0x73, 0x20, 0xd2, 0x59, 0xba, 0x61, 0x28, 0xdf, 0xb8, 0x65,
-	0x4b, 0x84, 0x27, 0xd0, 0xb2, 0x09, 0x4b, 0x68, 0xcc, 0x08, 0x7a, 0x0e, 0x06, 0xcb, 0xce, 0xfb,
-	0xba, 0xa4, 0x3e, 0x91, 0x63, 0x02, 0x39, 0x75, 0x4d, 0x7d, 0x22, 0x9a, 0x8b, 0x08, 0x63, 0xee,
-	0xaa, 0x68, 0xa0, 0x80, 0x58, 0x07, 0x6d, 0x12, 0x25, 0xfc, 0xfb, 0xf0, 0x67, 0x03, 0xb4, 0xab,
-	0x2b, 0x27, 0x88, 0xd0, 0x25, 0xe8, 0x32, 0x1a, 0xd4, 0x91, 0x59, 0x67, 0x92, 0xee, 0x99, 0x44,
-	0x95, 0xe0, 0xb0, 0x82, 0x2e, 0xa0, 0xf9, 0x99, 0x70, 0xf1, 0xf7, 0xab, 0xea, 0xcb, 0x97, 0x0a,
-	0x39, 0x56, 0xd0, 0x1b, 0x80, 0x39, 0xdd, 0x92, 0x94, 0xc6, 0x87, 0xca, 0xff, 0x24, 0x2a, 0x3a,
-	0xc2, 0x0a, 0x1a, 0x80, 0xe1, 0xac, 0x37, 0xdc, 0xa7, 0xdb, 0xe3, 0xf4, 0xaf, 0xa1, 0x6d, 0x13,
-	0x8f, 0x52, 0x7e, 0x94, 0xfa, 0x05, 0xe8, 0xa2, 0x64, 0x31, 0x5e, 0x55, 0xad, 0xb1, 0x9b, 0x2e,
-	0x86, 0x15, 0xf4, 0x32, 0x6f, 0xcd, 0x5a, 0xa0, 0xff, 0xf7, 0xc6, 0x2e, 0x1f, 0xb4, 0xee, 0xde,
-	0x24, 0x62, 0x05, 0xbd, 0x03, 0xc3, 0x21, 0xbc, 0x7c, 0xcd, 0xe2, 0xd2, 0x82, 0xe8, 0x3e, 0x24,
-	0xb0, 0x82, 0x46, 0x7b, 0x3d, 0x3e, 0x7e, 0xc5, 0x23, 0xa5, 0x0f, 0x77, 0x39, 0x1e, 0xed, 0x79,
-	0x0f, 0x1d, 0x9b, 0x30, 0xee, 0xa6, 0x7c, 0xe2, 0x26, 0x34, 0x3c, 0xd2, 0x35, 0x02, 0x43, 0xba,
-	0xc6, 0xeb, 0x65, 0x72, 0x9c, 0xc9, 0x6b, 0x66, 0x5f, 0x82, 0xd1, 0x9f, 0x00, 0x00, 0x00, 0xff,
-	0xff, 0x57, 0x53, 0xc7, 0x05, 0x22, 0x06, 0x00, 0x00,
+func _BBSim_PoweronOlt_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(OltRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BBSimServer).PoweronOlt(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/bbsim.BBSim/PoweronOlt",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BBSimServer).PoweronOlt(ctx, req.(*OltRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-// Reference imports to suppress errors if they are not otherwise used.
-var _ context.Context
-var _ grpc.ClientConn
+func _BBSim_ShutdownOlt_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(OltRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BBSimServer).ShutdownOlt(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/bbsim.BBSim/ShutdownOlt",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BBSimServer).ShutdownOlt(ctx, req.(*OltRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
 
-// This is a compile-time assertion to ensure that this generated file
-// is compatible with the grpc package it is being compiled against.
-const _ = grpc.SupportPackageIsVersion4
+func _BBSim_RebootOlt_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RebootRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BBSimServer).RebootOlt(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/bbsim.BBSim/RebootOlt",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BBSimServer).RebootOlt(ctx, req.(*RebootRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
 
-// BBSimClient is the client API for BBSim service.
-//
-// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
-type BBSimClient interface {
-	Version(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*VersionNumber, error)
-	GetOlt(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*Olt, error)
-	PoweronOlt(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*Response, error)
-	ShutdownOlt(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*Response, error)
-	RebootOlt(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*Response, error)
-	GetONUs(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*ONUs, error)
-	GetONU(ctx context.Context, in *ONURequest, opts ...grpc.CallOption) (*ONU, error)
-	SetLogLevel(ctx context.Context, in *LogLevel, opts ...grpc.CallOption) (*LogLevel, error)
-	ShutdownONU(ctx context.Context, in *ONURequest, opts ...grpc.CallOption) (*Response, error)
-	PoweronONU(ctx context.Context, in *ONURequest, opts ...grpc.CallOption) (*Response, error)
-	RestartEapol(ctx context.Context, in *ONURequest, opts ...grpc.CallOption) (*Response, error)
-	RestartDhcp(ctx context.Context, in *ONURequest, opts ...grpc.CallOption) (*Response, error)
+func _BBSim_StopOltHeartbeat_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(OltRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BBSimServer).StopOltHeartbeat(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/bbsim.BBSim/StopOltHeartbeat",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BBSimServer).StopOltHeartbeat(ctx, req.(*OltRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-type bBSimClient struct {
-	cc *grpc.ClientConn
+func _BBSim_StartOltHeartbeat_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(OltRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BBSimServer).StartOltHeartbeat(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/bbsim.BBSim/StartOltHeartbeat",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BBSimServer).StartOltHeartbeat(ctx, req.(*OltRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func NewBBSimClient(cc *grpc.ClientConn) BBSimClient {
-	return &bBSimClient{cc}
+func _BBSim_GetOltStats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(OltRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BBSimServer).GetOltStats(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/bbsim.BBSim/GetOltStats",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BBSimServer).GetOltStats(ctx, req.(*OltRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (c *bBSimClient) Version(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*VersionNumber, error) {
-	out := new(VersionNumber)
-	err := c.cc.Invoke(ctx, "/bbsim.BBSim/Version", in, out, opts...)
-	if err != nil {
+func _BBSim_GetONUs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(OltRequest)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	return out, nil
+	if interceptor == nil {
+		return srv.(BBSimServer).GetONUs(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/bbsim.BBSim/GetONUs",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BBSimServer).GetONUs(ctx, req.(*OltRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (c *bBSimClient) GetOlt(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*Olt, error) {
-	out := new(Olt)
-	err := c.cc.Invoke(ctx, "/bbsim.BBSim/GetOlt", in, out, opts...)
-	if err != nil {
+func _BBSim_GetONU_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ONURequest)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	return out, nil
+	if interceptor == nil {
+		return srv.(BBSimServer).GetONU(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/bbsim.BBSim/GetONU",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BBSimServer).GetONU(ctx, req.(*ONURequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (c *bBSimClient) PoweronOlt(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*Response, error) {
-	out := new(Response)
-	err := c.cc.Invoke(ctx, "/bbsim.BBSim/PoweronOlt", in, out, opts...)
-	if err != nil {
+func _BBSim_SetLogLevel_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LogLevel)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	return out, nil
+	if interceptor == nil {
+		return srv.(BBSimServer).SetLogLevel(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/bbsim.BBSim/SetLogLevel",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BBSimServer).SetLogLevel(ctx, req.(*LogLevel))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (c *bBSimClient) ShutdownOlt(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*Response, error) {
-	out := new(Response)
-	err := c.cc.Invoke(ctx, "/bbsim.BBSim/ShutdownOlt", in, out, opts...)
-	if err != nil {
+func _BBSim_ShutdownONU_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ONURequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BBSimServer).ShutdownONU(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/bbsim.BBSim/ShutdownONU",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BBSimServer).ShutdownONU(ctx, req.(*ONURequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BBSim_PoweronONU_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ONURequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BBSimServer).PoweronONU(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/bbsim.BBSim/PoweronONU",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BBSimServer).PoweronONU(ctx, req.(*ONURequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BBSim_RebootONU_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ONURequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BBSimServer).RebootONU(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/bbsim.BBSim/RebootONU",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BBSimServer).RebootONU(ctx, req.(*ONURequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BBSim_RestartEapol_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ONURequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BBSimServer).RestartEapol(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/bbsim.BBSim/RestartEapol",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BBSimServer).RestartEapol(ctx, req.(*ONURequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BBSim_RestartDhcp_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ONURequest)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	return out, nil
+	if interceptor == nil {
+		return srv.(BBSimServer).RestartDhcp(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/bbsim.BBSim/RestartDhcp",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BBSimServer).RestartDhcp(ctx, req.(*ONURequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (c *bBSimClient) RebootOlt(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*Response, error) {
-	out := new(Response)
-	err := c.cc.Invoke(ctx, "/bbsim.BBSim/RebootOlt", in, out, opts...)
-	if err != nil {
+func _BBSim_ListOltFlows_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(OltRequest)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	return out, nil
-}
-
-func (c *bBSimClient) GetONUs(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*ONUs, error) {
-	out := new(ONUs)
-	err := c.cc.Invoke(ctx, "/bbsim.BBSim/GetONUs", in, out, opts...)
-	if err != nil {
-		return nil, err
+	if interceptor == nil {
+		return srv.(BBSimServer).ListOltFlows(ctx, in)
 	}
-	return out, nil
-}
-
-func (c *bBSimClient) GetONU(ctx context.Context, in *ONURequest, opts ...grpc.CallOption) (*ONU, error) {
-	out := new(ONU)
-	err := c.cc.Invoke(ctx, "/bbsim.BBSim/GetONU", in, out, opts...)
-	if err != nil {
-		return nil, err
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/bbsim.BBSim/ListOltFlows",
 	}
-	return out, nil
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BBSimServer).ListOltFlows(ctx, req.(*OltRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (c *bBSimClient) SetLogLevel(ctx context.Context, in *LogLevel, opts ...grpc.CallOption) (*LogLevel, error) {
-	out := new(LogLevel)
-	err := c.cc.Invoke(ctx, "/bbsim.BBSim/SetLogLevel", in, out, opts...)
-	if err != nil {
+func _BBSim_ListOnuFlows_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ONURequest)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	return out, nil
+	if interceptor == nil {
+		return srv.(BBSimServer).ListOnuFlows(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/bbsim.BBSim/ListOnuFlows",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BBSimServer).ListOnuFlows(ctx, req.(*ONURequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (c *bBSimClient) ShutdownONU(ctx context.Context, in *ONURequest, opts ...grpc.CallOption) (*Response, error) {
-	out := new(Response)
-	err := c.cc.Invoke(ctx, "/bbsim.BBSim/ShutdownONU", in, out, opts...)
-	if err != nil {
+func _BBSim_GetOnuTConts_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ONURequest)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	return out, nil
+	if interceptor == nil {
+		return srv.(BBSimServer).GetOnuTConts(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/bbsim.BBSim/GetOnuTConts",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BBSimServer).GetOnuTConts(ctx, req.(*ONURequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (c *bBSimClient) PoweronONU(ctx context.Context, in *ONURequest, opts ...grpc.CallOption) (*Response, error) {
-	out := new(Response)
-	err := c.cc.Invoke(ctx, "/bbsim.BBSim/PoweronONU", in, out, opts...)
-	if err != nil {
+func _BBSim_GetOnuOmciHistory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ONURequest)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	return out, nil
+	if interceptor == nil {
+		return srv.(BBSimServer).GetOnuOmciHistory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/bbsim.BBSim/GetOnuOmciHistory",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BBSimServer).GetOnuOmciHistory(ctx, req.(*ONURequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (c *bBSimClient) RestartEapol(ctx context.Context, in *ONURequest, opts ...grpc.CallOption) (*Response, error) {
-	out := new(Response)
-	err := c.cc.Invoke(ctx, "/bbsim.BBSim/RestartEapol", in, out, opts...)
-	if err != nil {
+func _BBSim_GetOnuMib_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ONURequest)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	return out, nil
+	if interceptor == nil {
+		return srv.(BBSimServer).GetOnuMib(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/bbsim.BBSim/GetOnuMib",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BBSimServer).GetOnuMib(ctx, req.(*ONURequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (c *bBSimClient) RestartDhcp(ctx context.Context, in *ONURequest, opts ...grpc.CallOption) (*Response, error) {
-	out := new(Response)
-	err := c.cc.Invoke(ctx, "/bbsim.BBSim/RestartDhcp", in, out, opts...)
-	if err != nil {
+func _BBSim_GetOnuSoftwareImages_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ONURequest)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	return out, nil
-}
-
-// BBSimServer is the server API for BBSim service.
-type BBSimServer interface {
-	Version(context.Context, *Empty) (*VersionNumber, error)
-	GetOlt(context.Context, *Empty) (*Olt, error)
-	PoweronOlt(context.Context, *Empty) (*Response, error)
-	ShutdownOlt(context.Context, *Empty) (*Response, error)
-	RebootOlt(context.Context, *Empty) (*Response, error)
-	GetONUs(context.Context, *Empty) (*ONUs, error)
-	GetONU(context.Context, *ONURequest) (*ONU, error)
-	SetLogLevel(context.Context, *LogLevel) (*LogLevel, error)
-	ShutdownONU(context.Context, *ONURequest) (*Response, error)
-	PoweronONU(context.Context, *ONURequest) (*Response, error)
-	RestartEapol(context.Context, *ONURequest) (*Response, error)
-	RestartDhcp(context.Context, *ONURequest) (*Response, error)
-}
-
-// UnimplementedBBSimServer can be embedded to have forward compatible implementations.
-type UnimplementedBBSimServer struct {
-}
-
-func (*UnimplementedBBSimServer) Version(ctx context.Context, req *Empty) (*VersionNumber, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method Version not implemented")
-}
-func (*UnimplementedBBSimServer) GetOlt(ctx context.Context, req *Empty) (*Olt, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method GetOlt not implemented")
-}
-func (*UnimplementedBBSimServer) PoweronOlt(ctx context.Context, req *Empty) (*Response, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method PoweronOlt not implemented")
-}
-func (*UnimplementedBBSimServer) ShutdownOlt(ctx context.Context, req *Empty) (*Response, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method ShutdownOlt not implemented")
-}
-func (*UnimplementedBBSimServer) RebootOlt(ctx context.Context, req *Empty) (*Response, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method RebootOlt not implemented")
-}
-func (*UnimplementedBBSimServer) GetONUs(ctx context.Context, req *Empty) (*ONUs, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method GetONUs not implemented")
-}
-func (*UnimplementedBBSimServer) GetONU(ctx context.Context, req *ONURequest) (*ONU, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method GetONU not implemented")
-}
-func (*UnimplementedBBSimServer) SetLogLevel(ctx context.Context, req *LogLevel) (*LogLevel, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method SetLogLevel not implemented")
-}
-func (*UnimplementedBBSimServer) ShutdownONU(ctx context.Context, req *ONURequest) (*Response, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method ShutdownONU not implemented")
-}
-func (*UnimplementedBBSimServer) PoweronONU(ctx context.Context, req *ONURequest) (*Response, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method PoweronONU not implemented")
-}
-func (*UnimplementedBBSimServer) RestartEapol(ctx context.Context, req *ONURequest) (*Response, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method RestartEapol not implemented")
-}
-func (*UnimplementedBBSimServer) RestartDhcp(ctx context.Context, req *ONURequest) (*Response, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method RestartDhcp not implemented")
-}
-
-func RegisterBBSimServer(s *grpc.Server, srv BBSimServer) {
-	s.RegisterService(&_BBSim_serviceDesc, srv)
+	if interceptor == nil {
+		return srv.(BBSimServer).GetOnuSoftwareImages(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/bbsim.BBSim/GetOnuSoftwareImages",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BBSimServer).GetOnuSoftwareImages(ctx, req.(*ONURequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func _BBSim_Version_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(Empty)
+func _BBSim_SetOnuSoftwareImageFault_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SoftwareImageFaultRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(BBSimServer).Version(ctx, in)
+		return srv.(BBSimServer).SetOnuSoftwareImageFault(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/bbsim.BBSim/Version",
+		FullMethod: "/bbsim.BBSim/SetOnuSoftwareImageFault",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(BBSimServer).Version(ctx, req.(*Empty))
+		return srv.(BBSimServer).SetOnuSoftwareImageFault(ctx, req.(*SoftwareImageFaultRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _BBSim_GetOlt_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(Empty)
+func _BBSim_SetOnuAlarm_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AlarmRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(BBSimServer).GetOlt(ctx, in)
+		return srv.(BBSimServer).SetOnuAlarm(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/bbsim.BBSim/GetOlt",
+		FullMethod: "/bbsim.BBSim/SetOnuAlarm",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(BBSimServer).GetOlt(ctx, req.(*Empty))
+		return srv.(BBSimServer).SetOnuAlarm(ctx, req.(*AlarmRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _BBSim_PoweronOlt_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(Empty)
+func _BBSim_SendOnuOmciAlarm_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(OmciAlarmRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(BBSimServer).PoweronOlt(ctx, in)
+		return srv.(BBSimServer).SendOnuOmciAlarm(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/bbsim.BBSim/PoweronOlt",
+		FullMethod: "/bbsim.BBSim/SendOnuOmciAlarm",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(BBSimServer).PoweronOlt(ctx, req.(*Empty))
+		return srv.(BBSimServer).SendOnuOmciAlarm(ctx, req.(*OmciAlarmRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _BBSim_ShutdownOlt_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(Empty)
+func _BBSim_SetOltAlarm_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(OltAlarmRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(BBSimServer).ShutdownOlt(ctx, in)
+		return srv.(BBSimServer).SetOltAlarm(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/bbsim.BBSim/ShutdownOlt",
+		FullMethod: "/bbsim.BBSim/SetOltAlarm",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(BBSimServer).ShutdownOlt(ctx, req.(*Empty))
+		return srv.(BBSimServer).SetOltAlarm(ctx, req.(*OltAlarmRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _BBSim_RebootOlt_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(Empty)
+func _BBSim_CreateOnu_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateOnuRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(BBSimServer).RebootOlt(ctx, in)
+		return srv.(BBSimServer).CreateOnu(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/bbsim.BBSim/RebootOlt",
+		FullMethod: "/bbsim.BBSim/CreateOnu",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(BBSimServer).RebootOlt(ctx, req.(*Empty))
+		return srv.(BBSimServer).CreateOnu(ctx, req.(*CreateOnuRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _BBSim_GetONUs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(Empty)
+func _BBSim_DeleteOnu_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ONURequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(BBSimServer).GetONUs(ctx, in)
+		return srv.(BBSimServer).DeleteOnu(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/bbsim.BBSim/GetONUs",
+		FullMethod: "/bbsim.BBSim/DeleteOnu",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(BBSimServer).GetONUs(ctx, req.(*Empty))
+		return srv.(BBSimServer).DeleteOnu(ctx, req.(*ONURequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _BBSim_GetONU_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(ONURequest)
+func _BBSim_MoveOnu_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MoveOnuRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(BBSimServer).GetONU(ctx, in)
+		return srv.(BBSimServer).MoveOnu(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/bbsim.BBSim/GetONU",
+		FullMethod: "/bbsim.BBSim/MoveOnu",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(BBSimServer).GetONU(ctx, req.(*ONURequest))
+		return srv.(BBSimServer).MoveOnu(ctx, req.(*MoveOnuRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _BBSim_SetLogLevel_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(LogLevel)
+func _BBSim_SendUniPacket_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UniPacketRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(BBSimServer).SetLogLevel(ctx, in)
+		return srv.(BBSimServer).SendUniPacket(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/bbsim.BBSim/SetLogLevel",
+		FullMethod: "/bbsim.BBSim/SendUniPacket",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(BBSimServer).SetLogLevel(ctx, req.(*LogLevel))
+		return srv.(BBSimServer).SendUniPacket(ctx, req.(*UniPacketRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _BBSim_ShutdownONU_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(ONURequest)
+func _BBSim_SetFaultRules_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FaultRules)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(BBSimServer).ShutdownONU(ctx, in)
+		return srv.(BBSimServer).SetFaultRules(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/bbsim.BBSim/ShutdownONU",
+		FullMethod: "/bbsim.BBSim/SetFaultRules",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(BBSimServer).ShutdownONU(ctx, req.(*ONURequest))
+		return srv.(BBSimServer).SetFaultRules(ctx, req.(*FaultRules))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _BBSim_PoweronONU_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(ONURequest)
+func _BBSim_GetFaultRules_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(OltRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(BBSimServer).PoweronONU(ctx, in)
+		return srv.(BBSimServer).GetFaultRules(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/bbsim.BBSim/PoweronONU",
+		FullMethod: "/bbsim.BBSim/GetFaultRules",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(BBSimServer).PoweronONU(ctx, req.(*ONURequest))
+		return srv.(BBSimServer).GetFaultRules(ctx, req.(*OltRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _BBSim_RestartEapol_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(ONURequest)
+func _BBSim_SetOmciFaultRules_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(OmciFaultRules)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(BBSimServer).RestartEapol(ctx, in)
+		return srv.(BBSimServer).SetOmciFaultRules(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/bbsim.BBSim/RestartEapol",
+		FullMethod: "/bbsim.BBSim/SetOmciFaultRules",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(BBSimServer).RestartEapol(ctx, req.(*ONURequest))
+		return srv.(BBSimServer).SetOmciFaultRules(ctx, req.(*OmciFaultRules))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _BBSim_RestartDhcp_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(ONURequest)
+func _BBSim_GetOmciFaultRules_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(OltRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(BBSimServer).RestartDhcp(ctx, in)
+		return srv.(BBSimServer).GetOmciFaultRules(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/bbsim.BBSim/RestartDhcp",
+		FullMethod: "/bbsim.BBSim/GetOmciFaultRules",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(BBSimServer).RestartDhcp(ctx, req.(*ONURequest))
+		return srv.(BBSimServer).GetOmciFaultRules(ctx, req.(*OltRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
@@ -1051,6 +3704,10 @@ var _BBSim_serviceDesc = grpc.ServiceDesc{
 			MethodName: "Version",
 			Handler:    _BBSim_Version_Handler,
 		},
+		{
+			MethodName: "GetOlts",
+			Handler:    _BBSim_GetOlts_Handler,
+		},
 		{
 			MethodName: "GetOlt",
 			Handler:    _BBSim_GetOlt_Handler,
@@ -1067,6 +3724,18 @@ var _BBSim_serviceDesc = grpc.ServiceDesc{
 			MethodName: "RebootOlt",
 			Handler:    _BBSim_RebootOlt_Handler,
 		},
+		{
+			MethodName: "StopOltHeartbeat",
+			Handler:    _BBSim_StopOltHeartbeat_Handler,
+		},
+		{
+			MethodName: "StartOltHeartbeat",
+			Handler:    _BBSim_StartOltHeartbeat_Handler,
+		},
+		{
+			MethodName: "GetOltStats",
+			Handler:    _BBSim_GetOltStats_Handler,
+		},
 		{
 			MethodName: "GetONUs",
 			Handler:    _BBSim_GetONUs_Handler,
@@ -1087,6 +3756,10 @@ var _BBSim_serviceDesc = grpc.ServiceDesc{
 			MethodName: "PoweronONU",
 			Handler:    _BBSim_PoweronONU_Handler,
 		},
+		{
+			MethodName: "RebootONU",
+			Handler:    _BBSim_RebootONU_Handler,
+		},
 		{
 			MethodName: "RestartEapol",
 			Handler:    _BBSim_RestartEapol_Handler,
@@ -1095,6 +3768,78 @@ var _BBSim_serviceDesc = grpc.ServiceDesc{
 			MethodName: "RestartDhcp",
 			Handler:    _BBSim_RestartDhcp_Handler,
 		},
+		{
+			MethodName: "ListOltFlows",
+			Handler:    _BBSim_ListOltFlows_Handler,
+		},
+		{
+			MethodName: "ListOnuFlows",
+			Handler:    _BBSim_ListOnuFlows_Handler,
+		},
+		{
+			MethodName: "GetOnuTConts",
+			Handler:    _BBSim_GetOnuTConts_Handler,
+		},
+		{
+			MethodName: "GetOnuOmciHistory",
+			Handler:    _BBSim_GetOnuOmciHistory_Handler,
+		},
+		{
+			MethodName: "GetOnuMib",
+			Handler:    _BBSim_GetOnuMib_Handler,
+		},
+		{
+			MethodName: "GetOnuSoftwareImages",
+			Handler:    _BBSim_GetOnuSoftwareImages_Handler,
+		},
+		{
+			MethodName: "SetOnuSoftwareImageFault",
+			Handler:    _BBSim_SetOnuSoftwareImageFault_Handler,
+		},
+		{
+			MethodName: "SetOnuAlarm",
+			Handler:    _BBSim_SetOnuAlarm_Handler,
+		},
+		{
+			MethodName: "SendOnuOmciAlarm",
+			Handler:    _BBSim_SendOnuOmciAlarm_Handler,
+		},
+		{
+			MethodName: "SetOltAlarm",
+			Handler:    _BBSim_SetOltAlarm_Handler,
+		},
+		{
+			MethodName: "CreateOnu",
+			Handler:    _BBSim_CreateOnu_Handler,
+		},
+		{
+			MethodName: "DeleteOnu",
+			Handler:    _BBSim_DeleteOnu_Handler,
+		},
+		{
+			MethodName: "MoveOnu",
+			Handler:    _BBSim_MoveOnu_Handler,
+		},
+		{
+			MethodName: "SendUniPacket",
+			Handler:    _BBSim_SendUniPacket_Handler,
+		},
+		{
+			MethodName: "SetFaultRules",
+			Handler:    _BBSim_SetFaultRules_Handler,
+		},
+		{
+			MethodName: "GetFaultRules",
+			Handler:    _BBSim_GetFaultRules_Handler,
+		},
+		{
+			MethodName: "SetOmciFaultRules",
+			Handler:    _BBSim_SetOmciFaultRules_Handler,
+		},
+		{
+			MethodName: "GetOmciFaultRules",
+			Handler:    _BBSim_GetOmciFaultRules_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "api/bbsim/bbsim.proto",
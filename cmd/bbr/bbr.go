@@ -62,16 +62,14 @@ func main() {
 		"NumNniPerOlt": options.Olt.NniPorts,
 		"NumPonPerOlt": options.Olt.PonPorts,
 		"NumOnuPerPon": options.Olt.OnusPonPort,
+		"NumUniPerOnu": options.Olt.UnisPerOnu,
 		"BBSimIp":      options.BBSimIp,
 		"BBSimPort":    options.BBSimPort,
 	}).Info("BroadBand Reflector is on")
 
 	// create the OLT device
-	olt := devices.CreateOLT(
-		options.Olt.ID,
-		int(options.Olt.NniPorts),
-		int(options.Olt.PonPorts),
-		int(options.Olt.OnusPonPort),
+	olt, err := devices.CreateOLT(
+		options.Olt,
 		options.BBSim.STag,
 		options.BBSim.CTagInit,
 		true, // this parameter is not important in the BBR Case
@@ -79,6 +77,9 @@ func main() {
 		0,    // this parameter does not matter in the BBR case
 		true,
 	)
+	if err != nil {
+		log.Fatalf("Cannot create OLT: %v", err)
+	}
 	oltMock := bbrdevices.OltMock{
 		Olt:           olt,
 		TargetOnus:    int(options.Olt.PonPorts * options.Olt.OnusPonPort),
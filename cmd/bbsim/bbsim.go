@@ -146,33 +146,49 @@ func main() {
 	}
 
 	log.WithFields(log.Fields{
-		"OltID":        options.Olt.ID,
-		"NumNniPerOlt": options.Olt.NniPorts,
-		"NumPonPerOlt": options.Olt.PonPorts,
-		"NumOnuPerPon": options.Olt.OnusPonPort,
-		"TotalOnus":    options.Olt.PonPorts * options.Olt.OnusPonPort,
-		"EnableAuth":   options.BBSim.EnableAuth,
-		"Dhcp":         options.BBSim.EnableDhcp,
-		"Delay":        options.BBSim.Delay,
+		"NumOlt":     len(options.Olts),
+		"EnableAuth": options.BBSim.EnableAuth,
+		"Dhcp":       options.BBSim.EnableDhcp,
+		"Delay":      options.BBSim.Delay,
 	}).Info("BroadBand Simulator is on")
 
 	// control channels, they are only closed when the goroutine needs to be terminated
 	apiDoneChannel := make(chan bool)
 
-	olt := devices.CreateOLT(
-		options.Olt.ID,
-		int(options.Olt.NniPorts),
-		int(options.Olt.PonPorts),
-		int(options.Olt.OnusPonPort),
-		options.BBSim.STag,
-		options.BBSim.CTagInit,
-		options.BBSim.EnableAuth,
-		options.BBSim.EnableDhcp,
-		options.BBSim.Delay,
-		false,
-	)
-
-	log.Debugf("Created OLT with id: %d", options.Olt.ID)
+	olts := []*devices.OltDevice{}
+	for _, oltOptions := range options.Olts {
+		log.WithFields(log.Fields{
+			"OltID":          oltOptions.ID,
+			"OpenOltAddress": oltOptions.OpenOltAddress,
+			"NumNniPerOlt":   oltOptions.NniPorts,
+			"NumPonPerOlt":   oltOptions.PonPorts,
+			"NumOnuPerPon":   oltOptions.OnusPonPort,
+			"NumUniPerOnu":   oltOptions.UnisPerOnu,
+			"TotalOnus":      oltOptions.PonPorts * oltOptions.OnusPonPort,
+		}).Info("Creating OLT")
+
+		olt, err := devices.CreateOLT(
+			oltOptions,
+			options.BBSim.STag,
+			options.BBSim.CTagInit,
+			options.BBSim.EnableAuth,
+			options.BBSim.EnableDhcp,
+			options.BBSim.Delay,
+			false,
+		)
+		if err != nil {
+			log.Fatalf("Cannot create OLT with id %d: %v", oltOptions.ID, err)
+		}
+		olts = append(olts, olt)
+
+		log.Debugf("Created OLT with id: %d", oltOptions.ID)
+	}
+
+	// a single DHCP server is listening on the NNI interfaces of all the OLTs,
+	// without it the subscribers can't get an address
+	if err := devices.StartDHCPServer(); err != nil {
+		log.Fatalf("Couldn't start the DHCP server: %v", err)
+	}
 
 	sigs := make(chan os.Signal, 1)
 	// stop API servers on SIGTERM
@@ -191,7 +207,7 @@ func main() {
 	log.Debugf("Started APIService")
 	if common.Options.BBSim.SadisServer != false {
 		wg.Add(1)
-		go sadis.StartRestServer(olt, &wg)
+		go sadis.StartRestServer(olts, &wg)
 	}
 
 	wg.Wait()
@@ -33,6 +33,9 @@ import (
 	"time"
 )
 
+// the ONU flows use the ONU ID as FlowId
+const nniDhcpTrapFlowId = uint32(0xFFFFFFFF)
+
 type OltMock struct {
 	Olt          *devices.OltDevice
 	BBSimIp      string
@@ -141,6 +144,7 @@ func (o *OltMock) handleIndication(client openolt.OpenoltClient, indication *ope
 	switch indication.Data.(type) {
 	case *openolt.Indication_OltInd:
 		log.Info("Received Indication_OltInd")
+		o.sendNniDhcpTrapFlow(client)
 	case *openolt.Indication_IntfInd:
 		log.Info("Received Indication_IntfInd")
 	case *openolt.Indication_IntfOperInd:
@@ -168,6 +172,42 @@ func (o *OltMock) handleIndication(client openolt.OpenoltClient, indication *ope
 	}
 }
 
+// sendNniDhcpTrapFlow installs the flow trapping the DHCP packets coming from the NNI,
+// as VOLTHA does, BBSim drops the NNI packets that don't match any flow
+func (o *OltMock) sendNniDhcpTrapFlow(client openolt.OpenoltClient) {
+	flow := openolt.Flow{
+		AccessIntfId:  -1,
+		OnuId:         -1,
+		UniId:         -1,
+		FlowId:        nniDhcpTrapFlowId,
+		FlowType:      "downstream",
+		AllocId:       -1,
+		NetworkIntfId: 0,
+		GemportId:     -1,
+		Classifier: &openolt.Classifier{
+			EthType:    uint32(layers.EthernetTypeIPv4),
+			IpProto:    uint32(layers.IPProtocolUDP),
+			SrcPort:    67,
+			DstPort:    68,
+			PktTagType: "double_tag",
+		},
+		Action: &openolt.Action{
+			Cmd: &openolt.ActionCmd{TrapToHost: true},
+		},
+		Priority: 10000,
+	}
+
+	if _, err := client.FlowAdd(context.Background(), &flow); err != nil {
+		log.WithFields(log.Fields{
+			"FlowId": flow.FlowId,
+			"error":  err,
+		}).Fatal("Failed to send the NNI DHCP trap flow")
+	}
+	log.WithFields(log.Fields{
+		"FlowId": flow.FlowId,
+	}).Info("Sent NNI DHCP trap flow")
+}
+
 func (o *OltMock) handleOnuDiscIndication(client openolt.OpenoltClient, onuDiscInd *openolt.OnuDiscIndication) {
 	log.WithFields(log.Fields{
 		"IntfId":       onuDiscInd.IntfId,
@@ -34,7 +34,7 @@ func ValidateAndClose(olt *OltMock) {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
-	onus, err := client.GetONUs(ctx, &bbsim.Empty{})
+	onus, err := client.GetONUs(ctx, &bbsim.OltRequest{OltID: int32(olt.Olt.ID)})
 
 	if err != nil {
 		log.WithFields(log.Fields{
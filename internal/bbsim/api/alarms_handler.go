@@ -0,0 +1,132 @@
+/*
+ * Copyright 2018-present Open Networking Foundation
+
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+
+ * http://www.apache.org/licenses/LICENSE-2.0
+
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package api
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/opencord/bbsim/api/bbsim"
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+)
+
+func (s BBSimServer) SetOnuAlarm(ctx context.Context, req *bbsim.AlarmRequest) (*bbsim.Response, error) {
+	res := &bbsim.Response{}
+
+	logger.WithFields(log.Fields{
+		"OnuSn":     req.SerialNumber,
+		"AlarmType": req.AlarmType,
+		"Status":    req.Status,
+	}).Infof("Received request to set ONU alarm")
+
+	olt, err := getOlt(req.OltID)
+	if err != nil {
+		res.StatusCode = int32(codes.NotFound)
+		res.Message = err.Error()
+		return res, err
+	}
+
+	if _, err := olt.FindOnuBySn(req.SerialNumber); err != nil {
+		res.StatusCode = int32(codes.NotFound)
+		res.Message = err.Error()
+		return res, err
+	}
+
+	if err := olt.SetOnuAlarm(req.SerialNumber, req.AlarmType, req.Status); err != nil {
+		logger.WithFields(log.Fields{
+			"OnuSn":     req.SerialNumber,
+			"AlarmType": req.AlarmType,
+		}).Errorf("Cannot set ONU alarm: %s", err.Error())
+		res.StatusCode = int32(codes.InvalidArgument)
+		res.Message = err.Error()
+		return res, err
+	}
+
+	res.StatusCode = int32(codes.OK)
+	res.Message = fmt.Sprintf("Alarm %s set to %s on ONU %s.", req.AlarmType, req.Status, req.SerialNumber)
+	return res, nil
+}
+
+func (s BBSimServer) SendOnuOmciAlarm(ctx context.Context, req *bbsim.OmciAlarmRequest) (*bbsim.Response, error) {
+	res := &bbsim.Response{}
+
+	logger.WithFields(log.Fields{
+		"OnuSn":     req.SerialNumber,
+		"AlarmType": req.AlarmType,
+		"UniID":     req.UniID,
+		"Status":    req.Status,
+	}).Infof("Received request to send an OMCI alarm")
+
+	olt, err := getOlt(req.OltID)
+	if err != nil {
+		res.StatusCode = int32(codes.NotFound)
+		res.Message = err.Error()
+		return res, err
+	}
+
+	if _, err := olt.FindOnuBySn(req.SerialNumber); err != nil {
+		res.StatusCode = int32(codes.NotFound)
+		res.Message = err.Error()
+		return res, err
+	}
+
+	if err := olt.SendOnuOmciAlarm(req.SerialNumber, req.AlarmType, req.UniID, req.Status); err != nil {
+		logger.WithFields(log.Fields{
+			"OnuSn":     req.SerialNumber,
+			"AlarmType": req.AlarmType,
+		}).Errorf("Cannot send OMCI alarm: %s", err.Error())
+		res.StatusCode = int32(codes.InvalidArgument)
+		res.Message = err.Error()
+		return res, err
+	}
+
+	res.StatusCode = int32(codes.OK)
+	res.Message = fmt.Sprintf("OMCI alarm %s set to %s on ONU %s.", req.AlarmType, req.Status, req.SerialNumber)
+	return res, nil
+}
+
+func (s BBSimServer) SetOltAlarm(ctx context.Context, req *bbsim.OltAlarmRequest) (*bbsim.Response, error) {
+	res := &bbsim.Response{}
+
+	logger.WithFields(log.Fields{
+		"IntfId":    req.InterfaceID,
+		"AlarmType": req.AlarmType,
+		"Status":    req.Status,
+	}).Infof("Received request to set OLT alarm")
+
+	olt, err := getOlt(req.OltID)
+	if err != nil {
+		res.StatusCode = int32(codes.NotFound)
+		res.Message = err.Error()
+		return res, err
+	}
+
+	if err := olt.SetOltAlarm(req.AlarmType, req.InterfaceID, req.Status); err != nil {
+		logger.WithFields(log.Fields{
+			"IntfId":    req.InterfaceID,
+			"AlarmType": req.AlarmType,
+		}).Errorf("Cannot set OLT alarm: %s", err.Error())
+		res.StatusCode = int32(codes.InvalidArgument)
+		res.Message = err.Error()
+		return res, err
+	}
+
+	res.StatusCode = int32(codes.OK)
+	res.Message = fmt.Sprintf("Alarm %s set to %s on interface %d.", req.AlarmType, req.Status, req.InterfaceID)
+	return res, nil
+}
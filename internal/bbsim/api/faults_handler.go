@@ -0,0 +1,202 @@
+/*
+ * Copyright 2018-present Open Networking Foundation
+
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+
+ * http://www.apache.org/licenses/LICENSE-2.0
+
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/opencord/bbsim/api/bbsim"
+	"github.com/opencord/bbsim/internal/bbsim/devices"
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+)
+
+func convertProtoFaultRuleToBBSim(r *bbsim.FaultRule) (devices.FaultRule, error) {
+	rule := devices.FaultRule{
+		Method:      r.Method,
+		Code:        codes.OK,
+		Rate:        float64(r.Rate),
+		Latency:     time.Duration(r.LatencyMs) * time.Millisecond,
+		Jitter:      time.Duration(r.JitterMs) * time.Millisecond,
+		Drop:        r.Drop,
+		DropTimeout: time.Duration(r.DropTimeoutMs) * time.Millisecond,
+	}
+	if r.ErrorCode != "" {
+		code, err := devices.ParseFaultCode(r.ErrorCode)
+		if err != nil {
+			return rule, err
+		}
+		rule.Code = code
+	}
+	return rule, nil
+}
+
+func convertBBSimFaultRuleToProto(r devices.FaultRule) *bbsim.FaultRule {
+	rule := &bbsim.FaultRule{
+		Method:        r.Method,
+		Rate:          float32(r.Rate),
+		LatencyMs:     uint32(r.Latency / time.Millisecond),
+		JitterMs:      uint32(r.Jitter / time.Millisecond),
+		Drop:          r.Drop,
+		DropTimeoutMs: uint32(r.DropTimeout / time.Millisecond),
+	}
+	if r.Code != codes.OK {
+		rule.ErrorCode = r.Code.String()
+	}
+	return rule
+}
+
+func (s BBSimServer) SetFaultRules(ctx context.Context, req *bbsim.FaultRules) (*bbsim.Response, error) {
+	res := &bbsim.Response{}
+
+	logger.WithFields(log.Fields{
+		"OltId": req.OltID,
+		"Rules": len(req.Rules),
+	}).Infof("Received request to set the fault rules")
+
+	olt, err := getOlt(req.OltID)
+	if err != nil {
+		res.StatusCode = int32(codes.NotFound)
+		res.Message = err.Error()
+		return res, err
+	}
+
+	rules := []devices.FaultRule{}
+	for _, r := range req.Rules {
+		rule, err := convertProtoFaultRuleToBBSim(r)
+		if err != nil {
+			res.StatusCode = int32(codes.InvalidArgument)
+			res.Message = err.Error()
+			return res, err
+		}
+		rules = append(rules, rule)
+	}
+
+	if err := olt.SetFaultRules(rules); err != nil {
+		res.StatusCode = int32(codes.InvalidArgument)
+		res.Message = err.Error()
+		return res, err
+	}
+
+	res.StatusCode = int32(codes.OK)
+	res.Message = fmt.Sprintf("%d fault rules set on OLT %s.", len(rules), olt.SerialNumber)
+	return res, nil
+}
+
+func (s BBSimServer) GetFaultRules(ctx context.Context, req *bbsim.OltRequest) (*bbsim.FaultRules, error) {
+	olt, err := getOlt(req.OltID)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &bbsim.FaultRules{
+		OltID: int32(olt.ID),
+		Rules: []*bbsim.FaultRule{},
+	}
+	for _, r := range olt.GetFaultRules() {
+		res.Rules = append(res.Rules, convertBBSimFaultRuleToProto(r))
+	}
+	return res, nil
+}
+
+func convertProtoOmciFaultRuleToBBSim(r *bbsim.OmciFaultRule) (devices.OmciFaultRule, error) {
+	if r.MeClass > 0xFFFF {
+		return devices.OmciFaultRule{}, errors.New(fmt.Sprintf("invalid-omci-fault-me-class-%d", r.MeClass))
+	}
+	if r.ResultCode > 0xFF {
+		return devices.OmciFaultRule{}, errors.New(fmt.Sprintf("invalid-omci-fault-result-code-%d", r.ResultCode))
+	}
+	return devices.OmciFaultRule{
+		OnuSn:      r.SerialNumber,
+		MeClass:    uint16(r.MeClass),
+		DropNth:    int(r.DropNth),
+		DropEvery:  int(r.DropEvery),
+		Latency:    time.Duration(r.LatencyMs) * time.Millisecond,
+		Corrupt:    r.Corrupt,
+		WrongTid:   r.WrongTid,
+		ResultCode: uint8(r.ResultCode),
+	}, nil
+}
+
+func convertBBSimOmciFaultRuleToProto(r devices.OmciFaultRule) *bbsim.OmciFaultRule {
+	return &bbsim.OmciFaultRule{
+		SerialNumber: r.OnuSn,
+		MeClass:      uint32(r.MeClass),
+		DropNth:      uint32(r.DropNth),
+		DropEvery:    uint32(r.DropEvery),
+		LatencyMs:    uint32(r.Latency / time.Millisecond),
+		Corrupt:      r.Corrupt,
+		WrongTid:     r.WrongTid,
+		ResultCode:   uint32(r.ResultCode),
+	}
+}
+
+func (s BBSimServer) SetOmciFaultRules(ctx context.Context, req *bbsim.OmciFaultRules) (*bbsim.Response, error) {
+	res := &bbsim.Response{}
+
+	logger.WithFields(log.Fields{
+		"OltId": req.OltID,
+		"Rules": len(req.Rules),
+	}).Infof("Received request to set the OMCI fault rules")
+
+	olt, err := getOlt(req.OltID)
+	if err != nil {
+		res.StatusCode = int32(codes.NotFound)
+		res.Message = err.Error()
+		return res, err
+	}
+
+	rules := []devices.OmciFaultRule{}
+	for _, r := range req.Rules {
+		rule, err := convertProtoOmciFaultRuleToBBSim(r)
+		if err != nil {
+			res.StatusCode = int32(codes.InvalidArgument)
+			res.Message = err.Error()
+			return res, err
+		}
+		rules = append(rules, rule)
+	}
+
+	if err := olt.SetOmciFaultRules(rules); err != nil {
+		res.StatusCode = int32(codes.InvalidArgument)
+		res.Message = err.Error()
+		return res, err
+	}
+
+	res.StatusCode = int32(codes.OK)
+	res.Message = fmt.Sprintf("%d OMCI fault rules set on OLT %s.", len(rules), olt.SerialNumber)
+	return res, nil
+}
+
+func (s BBSimServer) GetOmciFaultRules(ctx context.Context, req *bbsim.OltRequest) (*bbsim.OmciFaultRules, error) {
+	olt, err := getOlt(req.OltID)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &bbsim.OmciFaultRules{
+		OltID: int32(olt.ID),
+		Rules: []*bbsim.OmciFaultRule{},
+	}
+	for _, r := range olt.GetOmciFaultRules() {
+		res.Rules = append(res.Rules, convertBBSimOmciFaultRuleToProto(r))
+	}
+	return res, nil
+}
@@ -0,0 +1,100 @@
+/*
+ * Copyright 2018-present Open Networking Foundation
+
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+
+ * http://www.apache.org/licenses/LICENSE-2.0
+
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package api
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/opencord/bbsim/api/bbsim"
+	"github.com/opencord/voltha-protos/v2/go/openolt"
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func toApiFlows(flows []openolt.Flow) *bbsim.Flows {
+	res := &bbsim.Flows{
+		FlowCount: uint32(len(flows)),
+		Flows:     []*openolt.Flow{},
+	}
+	for i := range flows {
+		res.Flows = append(res.Flows, &flows[i])
+	}
+	return res
+}
+
+func (s BBSimServer) ListOltFlows(ctx context.Context, req *bbsim.OltRequest) (*bbsim.Flows, error) {
+	olt, err := getOlt(req.OltID)
+	if err != nil {
+		return &bbsim.Flows{}, err
+	}
+	return toApiFlows(olt.GetFlows()), nil
+}
+
+func (s BBSimServer) ListOnuFlows(ctx context.Context, req *bbsim.ONURequest) (*bbsim.Flows, error) {
+	olt, err := getOlt(req.OltID)
+	if err != nil {
+		return &bbsim.Flows{}, err
+	}
+
+	onu, err := olt.FindOnuBySn(req.SerialNumber)
+	if err != nil {
+		logger.WithFields(log.Fields{
+			"OnuSn": req.SerialNumber,
+		}).Errorf("Cannot list flows: %s", err.Error())
+		return &bbsim.Flows{}, status.Errorf(codes.NotFound, err.Error())
+	}
+
+	return toApiFlows(olt.GetOnuFlows(onu)), nil
+}
+
+func (s BBSimServer) SendUniPacket(ctx context.Context, req *bbsim.UniPacketRequest) (*bbsim.Response, error) {
+	res := &bbsim.Response{}
+
+	logger.WithFields(log.Fields{
+		"OnuSn": req.SerialNumber,
+		"UniID": req.UniID,
+	}).Infof("Received request to send a packet from a UNI")
+
+	olt, err := getOlt(req.OltID)
+	if err != nil {
+		res.StatusCode = int32(codes.NotFound)
+		res.Message = err.Error()
+		return res, err
+	}
+
+	if _, err := olt.FindOnuBySn(req.SerialNumber); err != nil {
+		res.StatusCode = int32(codes.NotFound)
+		res.Message = err.Error()
+		return res, err
+	}
+
+	if err := olt.SendUniPacket(req.SerialNumber, req.UniID, req.Packet); err != nil {
+		logger.WithFields(log.Fields{
+			"OnuSn": req.SerialNumber,
+			"UniID": req.UniID,
+		}).Errorf("Cannot send packet: %s", err.Error())
+		res.StatusCode = int32(codes.FailedPrecondition)
+		res.Message = err.Error()
+		return res, err
+	}
+
+	res.StatusCode = int32(codes.OK)
+	res.Message = fmt.Sprintf("Packet sent from UNI %d of ONU %s.", req.UniID, req.SerialNumber)
+	return res, nil
+}
@@ -25,6 +25,7 @@ import (
 	"github.com/opencord/bbsim/internal/common"
 	log "github.com/sirupsen/logrus"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 var logger = log.WithFields(log.Fields{
@@ -51,8 +52,16 @@ func (s BBSimServer) Version(ctx context.Context, req *bbsim.Empty) (*bbsim.Vers
 	}, nil
 }
 
-func (s BBSimServer) GetOlt(ctx context.Context, req *bbsim.Empty) (*bbsim.Olt, error) {
-	olt := devices.GetOLT()
+// getOlt returns the OLT selected by a request
+func getOlt(oltId int32) (*devices.OltDevice, error) {
+	olt, err := devices.GetOLTById(int(oltId))
+	if err != nil {
+		return nil, status.Errorf(codes.NotFound, err.Error())
+	}
+	return olt, nil
+}
+
+func convertBBSimOltToProtoOlt(olt *devices.OltDevice) *bbsim.Olt {
 	nnis := []*bbsim.NNIPort{}
 	pons := []*bbsim.PONPort{}
 
@@ -72,7 +81,7 @@ func (s BBSimServer) GetOlt(ctx context.Context, req *bbsim.Empty) (*bbsim.Olt,
 		pons = append(pons, &p)
 	}
 
-	res := bbsim.Olt{
+	return &bbsim.Olt{
 		ID:            int32(olt.ID),
 		SerialNumber:  olt.SerialNumber,
 		OperState:     olt.OperState.Current(),
@@ -80,12 +89,34 @@ func (s BBSimServer) GetOlt(ctx context.Context, req *bbsim.Empty) (*bbsim.Olt,
 		NNIPorts:      nnis,
 		PONPorts:      pons,
 	}
-	return &res, nil
 }
 
-func (s BBSimServer) PoweronOlt(ctx context.Context, req *bbsim.Empty) (*bbsim.Response, error) {
+func (s BBSimServer) GetOlts(ctx context.Context, req *bbsim.Empty) (*bbsim.Olts, error) {
+	res := &bbsim.Olts{
+		Items: []*bbsim.Olt{},
+	}
+	for _, olt := range devices.GetOLTs() {
+		res.Items = append(res.Items, convertBBSimOltToProtoOlt(olt))
+	}
+	return res, nil
+}
+
+func (s BBSimServer) GetOlt(ctx context.Context, req *bbsim.OltRequest) (*bbsim.Olt, error) {
+	olt, err := getOlt(req.OltID)
+	if err != nil {
+		return &bbsim.Olt{}, err
+	}
+	return convertBBSimOltToProtoOlt(olt), nil
+}
+
+func (s BBSimServer) PoweronOlt(ctx context.Context, req *bbsim.OltRequest) (*bbsim.Response, error) {
 	res := &bbsim.Response{}
-	o := devices.GetOLT()
+	o, err := getOlt(req.OltID)
+	if err != nil {
+		res.StatusCode = int32(codes.NotFound)
+		res.Message = err.Error()
+		return res, err
+	}
 
 	if err := o.InternalState.Event("initialize"); err != nil {
 		log.Errorf("Error initializing OLT: %v", err)
@@ -97,9 +128,14 @@ func (s BBSimServer) PoweronOlt(ctx context.Context, req *bbsim.Empty) (*bbsim.R
 	return res, nil
 }
 
-func (s BBSimServer) ShutdownOlt(ctx context.Context, req *bbsim.Empty) (*bbsim.Response, error) {
+func (s BBSimServer) ShutdownOlt(ctx context.Context, req *bbsim.OltRequest) (*bbsim.Response, error) {
 	res := &bbsim.Response{}
-	o := devices.GetOLT()
+	o, err := getOlt(req.OltID)
+	if err != nil {
+		res.StatusCode = int32(codes.NotFound)
+		res.Message = err.Error()
+		return res, err
+	}
 
 	if err := o.InternalState.Event("disable"); err != nil {
 		log.Errorf("Error disabling OLT: %v", err)
@@ -111,12 +147,56 @@ func (s BBSimServer) ShutdownOlt(ctx context.Context, req *bbsim.Empty) (*bbsim.
 	return res, nil
 }
 
-func (s BBSimServer) RebootOlt(ctx context.Context, req *bbsim.Empty) (*bbsim.Response, error) {
+func (s BBSimServer) RebootOlt(ctx context.Context, req *bbsim.RebootRequest) (*bbsim.Response, error) {
 	res := &bbsim.Response{}
-	o := devices.GetOLT()
-	go o.RestartOLT()
+	o, err := getOlt(req.OltID)
+	if err != nil {
+		res.StatusCode = int32(codes.NotFound)
+		res.Message = err.Error()
+		return res, err
+	}
+
+	mode := req.Mode
+	if mode == "" {
+		mode = devices.SoftReboot
+	}
+	if err := devices.ValidateRebootMode(mode); err != nil {
+		res.StatusCode = int32(codes.InvalidArgument)
+		res.Message = err.Error()
+		return res, status.Errorf(codes.InvalidArgument, err.Error())
+	}
+
+	go o.RestartOLT(mode, req.DyingGasp)
+	res.StatusCode = int32(codes.OK)
+	res.Message = fmt.Sprintf("OLT %s reboot triggered.", mode)
+	return res, nil
+}
+
+func (s BBSimServer) StopOltHeartbeat(ctx context.Context, req *bbsim.OltRequest) (*bbsim.Response, error) {
+	res := &bbsim.Response{}
+	o, err := getOlt(req.OltID)
+	if err != nil {
+		res.StatusCode = int32(codes.NotFound)
+		res.Message = err.Error()
+		return res, err
+	}
+	o.StopHeartbeat()
+	res.StatusCode = int32(codes.OK)
+	res.Message = fmt.Sprintf("OLT heartbeat stopped.")
+	return res, nil
+}
+
+func (s BBSimServer) StartOltHeartbeat(ctx context.Context, req *bbsim.OltRequest) (*bbsim.Response, error) {
+	res := &bbsim.Response{}
+	o, err := getOlt(req.OltID)
+	if err != nil {
+		res.StatusCode = int32(codes.NotFound)
+		res.Message = err.Error()
+		return res, err
+	}
+	o.StartHeartbeat()
 	res.StatusCode = int32(codes.OK)
-	res.Message = fmt.Sprintf("OLT restart triggered.")
+	res.Message = fmt.Sprintf("OLT heartbeat started.")
 	return res, nil
 }
 
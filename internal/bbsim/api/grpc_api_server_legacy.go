@@ -24,6 +24,7 @@ import (
 	"github.com/grpc-ecosystem/grpc-gateway/runtime"
 	"github.com/opencord/bbsim/api/legacy"
 	"github.com/opencord/bbsim/internal/bbsim/devices"
+	"github.com/opencord/bbsim/internal/common"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
@@ -43,11 +44,16 @@ const (
 	DeviceTypeOnu   = "onu"
 )
 
+// getLegacyOlt returns the OLT managed by the legacy API, it only supports the first OLT
+func getLegacyOlt() *devices.OltDevice {
+	return devices.GetOLTs()[0]
+}
+
 // OLTStatus method returns the OLT status.
 func (s BBSimLegacyServer) OLTStatus(ctx context.Context, in *legacy.Empty) (*legacy.OLTStatusResponse, error) {
 	logger.Trace("OLTStatus request received")
 
-	olt := devices.GetOLT()
+	olt := getLegacyOlt()
 	oltInfo := &legacy.OLTStatusResponse{
 		Olt: &legacy.OLTInfo{
 			OltId:     int64(olt.ID),
@@ -115,9 +121,9 @@ func (s BBSimLegacyServer) ONUStatus(ctx context.Context, in *legacy.ONURequest)
 	logger.Debug("Received all ONUs status request")
 
 	// Get status of all ONUs
-	olt := devices.GetOLT()
+	olt := getLegacyOlt()
 	for _, p := range olt.Pons {
-		for _, o := range p.Onus {
+		for _, o := range p.GetOnus() {
 			onuInfo.Onus = append(onuInfo.Onus, copyONUInfo(o))
 		}
 	}
@@ -144,15 +150,45 @@ func (s BBSimLegacyServer) ONUDeactivate(ctx context.Context, in *legacy.ONURequ
 // GenerateONUAlarm RPC generates alarm for the onu
 func (s BBSimLegacyServer) GenerateONUAlarm(ctx context.Context, in *legacy.ONUAlarmRequest) (*legacy.BBSimResponse, error) {
 	logger.Trace("GenerateONUAlarms() invoked")
-	logger.Error("Not implemented")
 
-	return nil, nil
+	olt := getLegacyOlt()
+	if !olt.InternalState.Is("enabled") {
+		return &legacy.BBSimResponse{StatusMsg: OLTNotEnabled}, status.Errorf(codes.FailedPrecondition, OLTNotEnabled)
+	}
+
+	if _, err := olt.FindOnuBySn(in.OnuSerial); err != nil {
+		return &legacy.BBSimResponse{StatusMsg: RequestFailed}, status.Errorf(codes.NotFound, err.Error())
+	}
+
+	if err := olt.SetOnuAlarm(in.OnuSerial, in.AlarmType, in.Status); err != nil {
+		return &legacy.BBSimResponse{StatusMsg: RequestFailed}, status.Errorf(codes.InvalidArgument, err.Error())
+	}
+
+	return &legacy.BBSimResponse{StatusMsg: RequestAccepted}, nil
 }
 
 // GenerateOLTAlarm RPC generates alarm for the OLT
 func (s BBSimLegacyServer) GenerateOLTAlarm(ctx context.Context, in *legacy.OLTAlarmRequest) (*legacy.BBSimResponse, error) {
 	logger.Trace("GenerateOLTAlarm() invoked")
-	logger.Error("Not implemented")
+
+	olt := getLegacyOlt()
+	if !olt.InternalState.Is("enabled") {
+		return &legacy.BBSimResponse{StatusMsg: OLTNotEnabled}, status.Errorf(codes.FailedPrecondition, OLTNotEnabled)
+	}
+
+	var alarmType string
+	switch in.PortType {
+	case "pon":
+		alarmType = common.OltAlarmPonLos
+	case "nni":
+		alarmType = common.OltAlarmNniLos
+	default:
+		return &legacy.BBSimResponse{StatusMsg: RequestFailed}, status.Errorf(codes.InvalidArgument, "Invalid port type")
+	}
+
+	if err := olt.SetOltAlarm(alarmType, in.PortId, in.Status); err != nil {
+		return &legacy.BBSimResponse{StatusMsg: RequestFailed}, status.Errorf(codes.InvalidArgument, err.Error())
+	}
 
 	return &legacy.BBSimResponse{StatusMsg: RequestAccepted}, nil
 }
@@ -160,7 +196,28 @@ func (s BBSimLegacyServer) GenerateOLTAlarm(ctx context.Context, in *legacy.OLTA
 // PerformDeviceAction rpc take the device request and performs OLT and ONU hard and soft reboot
 func (s BBSimLegacyServer) PerformDeviceAction(ctx context.Context, in *legacy.DeviceAction) (*legacy.BBSimResponse, error) {
 	logger.Trace("PerformDeviceAction() invoked")
-	logger.Error("Not implemented")
+
+	if in.DeviceType != DeviceTypeOlt {
+		// NOTE ONU reboots are not supported by the legacy API
+		return &legacy.BBSimResponse{StatusMsg: RequestFailed}, status.Errorf(codes.Unimplemented, "Action not supported for device type %s", in.DeviceType)
+	}
+
+	olt := getLegacyOlt()
+	if in.SerialNumber != "" && in.SerialNumber != olt.SerialNumber {
+		return &legacy.BBSimResponse{StatusMsg: RequestFailed}, status.Errorf(codes.NotFound, "cannot-find-olt-%s", in.SerialNumber)
+	}
+
+	var mode string
+	switch in.Action {
+	case SoftReboot:
+		mode = devices.SoftReboot
+	case HardReboot:
+		mode = devices.HardReboot
+	default:
+		return &legacy.BBSimResponse{StatusMsg: RequestFailed}, status.Errorf(codes.InvalidArgument, "Invalid action %s", in.Action)
+	}
+
+	go olt.RestartOLT(mode, false)
 
 	return &legacy.BBSimResponse{StatusMsg: RequestAccepted}, nil
 }
@@ -168,9 +225,23 @@ func (s BBSimLegacyServer) PerformDeviceAction(ctx context.Context, in *legacy.D
 // GetFlows returns all flows or flows for specified ONU
 func (s BBSimLegacyServer) GetFlows(ctx context.Context, in *legacy.ONUInfo) (*legacy.Flows, error) {
 	logger.Info("GetFlow request received")
-	logger.Error("Not implemented")
 
-	return &legacy.Flows{}, nil
+	olt := getLegacyOlt()
+	flows := olt.GetFlows()
+
+	if in.OnuSerial != "" {
+		onu, err := olt.FindOnuBySn(in.OnuSerial)
+		if err != nil {
+			return &legacy.Flows{}, status.Errorf(codes.NotFound, err.Error())
+		}
+		flows = olt.GetOnuFlows(onu)
+	}
+
+	res := &legacy.Flows{}
+	for i := range flows {
+		res.Flows = append(res.Flows, &flows[i])
+	}
+	return res, nil
 }
 
 // StartRestGatewayService method starts REST server for BBSim.
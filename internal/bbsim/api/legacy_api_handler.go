@@ -41,7 +41,7 @@ const (
 func (s BBSimLegacyServer) handleONUStatusRequest(in *api.ONUInfo) (*api.ONUs, error) {
 	logger.Trace("handleONUStatusRequest() invoked")
 	onuInfo := &api.ONUs{}
-	olt := devices.GetOLT()
+	olt := getLegacyOlt()
 
 	if in.OnuSerial != "" { // Get status of a single ONU by SerialNumber
 		onu, err := olt.FindOnuBySn(in.OnuSerial)
@@ -61,7 +61,7 @@ func (s BBSimLegacyServer) handleONUStatusRequest(in *api.ONUInfo) (*api.ONUs, e
 			onuInfo.Onus = append(onuInfo.Onus, copyONUInfo(onu))
 		} else { // Get status of all ONUs
 			for _, p := range olt.Pons {
-				for _, o := range p.Onus {
+				for _, o := range p.GetOnus() {
 					onuInfo.Onus = append(onuInfo.Onus, copyONUInfo(o))
 				}
 			}
@@ -85,7 +85,7 @@ func copyONUInfo(onu *devices.Onu) *api.ONUInfo {
 
 func (s BBSimLegacyServer) fetchPortDetail(intfID uint32, portType string) (*api.PortInfo, error) {
 	logger.Tracef("fetchPortDetail() invoked %s-%d", portType, intfID)
-	olt := devices.GetOLT()
+	olt := getLegacyOlt()
 
 	switch portType {
 	case "pon":
@@ -0,0 +1,88 @@
+/*
+ * Copyright 2018-present Open Networking Foundation
+
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+
+ * http://www.apache.org/licenses/LICENSE-2.0
+
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package api
+
+import (
+	"context"
+	"time"
+
+	"github.com/opencord/bbsim/api/bbsim"
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (s BBSimServer) GetOnuOmciHistory(ctx context.Context, req *bbsim.ONURequest) (*bbsim.OmciHistory, error) {
+	olt, err := getOlt(req.OltID)
+	if err != nil {
+		return &bbsim.OmciHistory{}, err
+	}
+
+	onu, err := olt.FindOnuBySn(req.SerialNumber)
+	if err != nil {
+		logger.WithFields(log.Fields{
+			"OnuSn": req.SerialNumber,
+		}).Errorf("Cannot get the OMCI history: %s", err.Error())
+		return &bbsim.OmciHistory{}, status.Errorf(codes.NotFound, err.Error())
+	}
+
+	res := &bbsim.OmciHistory{
+		Entries: []*bbsim.OmciHistoryEntry{},
+	}
+	for _, e := range onu.GetOmciHistory() {
+		res.Entries = append(res.Entries, &bbsim.OmciHistoryEntry{
+			Time:          e.Time.Format(time.RFC3339Nano),
+			Direction:     e.Direction,
+			TransactionId: uint32(e.TransactionId),
+			MessageType:   e.MessageType,
+			MeClass:       uint32(e.MeClass),
+			MeInstance:    uint32(e.MeInstance),
+			Result:        e.Result,
+			Dropped:       e.Dropped,
+		})
+	}
+	return res, nil
+}
+
+func (s BBSimServer) GetOnuMib(ctx context.Context, req *bbsim.ONURequest) (*bbsim.OnuMib, error) {
+	olt, err := getOlt(req.OltID)
+	if err != nil {
+		return &bbsim.OnuMib{}, err
+	}
+
+	onu, err := olt.FindOnuBySn(req.SerialNumber)
+	if err != nil {
+		logger.WithFields(log.Fields{
+			"OnuSn": req.SerialNumber,
+		}).Errorf("Cannot get the MIB: %s", err.Error())
+		return &bbsim.OnuMib{}, status.Errorf(codes.NotFound, err.Error())
+	}
+
+	dataSync, instances := onu.GetOmciMib()
+	res := &bbsim.OnuMib{
+		MibDataSync: uint32(dataSync),
+		Instances:   []*bbsim.OmciMeInstance{},
+	}
+	for _, i := range instances {
+		res.Instances = append(res.Instances, &bbsim.OmciMeInstance{
+			MeClass:    uint32(i.Class),
+			MeInstance: uint32(i.Instance),
+			Name:       i.Name,
+		})
+	}
+	return res, nil
+}
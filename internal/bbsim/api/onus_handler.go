@@ -21,37 +21,62 @@ import (
 	"fmt"
 	"github.com/opencord/bbsim/api/bbsim"
 	"github.com/opencord/bbsim/internal/bbsim/devices"
+	"github.com/opencord/bbsim/internal/common"
 	log "github.com/sirupsen/logrus"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
-func (s BBSimServer) GetONUs(ctx context.Context, req *bbsim.Empty) (*bbsim.ONUs, error) {
-	olt := devices.GetOLT()
+func convertBBSimOnuToProtoOnu(o *devices.Onu) *bbsim.ONU {
+	onu := bbsim.ONU{
+		ID:            int32(o.ID),
+		SerialNumber:  o.Sn(),
+		OperState:     o.OperState.Current(),
+		InternalState: o.InternalState.Current(),
+		PonPortID:     int32(o.PonPortID),
+		STag:          int32(o.STag),
+		CTag:          int32(o.CTag),
+		HwAddress:     o.HwAddress.String(),
+		PortNo:        int32(o.UniPorts[0].PortNo),
+		Unis:          []*bbsim.UNI{},
+	}
+	for _, u := range o.UniPorts {
+		uni := bbsim.UNI{
+			ID:            int32(u.ID),
+			OnuID:         int32(o.ID),
+			OnuSn:         o.Sn(),
+			PortNo:        int32(u.PortNo),
+			HwAddress:     u.HwAddress.String(),
+			CTag:          int32(u.CTag),
+			InternalState: u.InternalState.Current(),
+		}
+		onu.Unis = append(onu.Unis, &uni)
+	}
+	return &onu
+}
+
+func (s BBSimServer) GetONUs(ctx context.Context, req *bbsim.OltRequest) (*bbsim.ONUs, error) {
+	olt, err := getOlt(req.OltID)
+	if err != nil {
+		return &bbsim.ONUs{}, err
+	}
 	onus := bbsim.ONUs{
 		Items: []*bbsim.ONU{},
 	}
 
 	for _, pon := range olt.Pons {
-		for _, o := range pon.Onus {
-			onu := bbsim.ONU{
-				ID:            int32(o.ID),
-				SerialNumber:  o.Sn(),
-				OperState:     o.OperState.Current(),
-				InternalState: o.InternalState.Current(),
-				PonPortID:     int32(o.PonPortID),
-				STag:          int32(o.STag),
-				CTag:          int32(o.CTag),
-				HwAddress:     o.HwAddress.String(),
-				PortNo:        int32(o.PortNo),
-			}
-			onus.Items = append(onus.Items, &onu)
+		for _, o := range pon.GetOnus() {
+			onus.Items = append(onus.Items, convertBBSimOnuToProtoOnu(o))
 		}
 	}
 	return &onus, nil
 }
 
 func (s BBSimServer) GetONU(ctx context.Context, req *bbsim.ONURequest) (*bbsim.ONU, error) {
-	olt := devices.GetOLT()
+	olt, err := getOlt(req.OltID)
+	if err != nil {
+		return &bbsim.ONU{}, err
+	}
 
 	onu, err := olt.FindOnuBySn(req.SerialNumber)
 
@@ -60,18 +85,7 @@ func (s BBSimServer) GetONU(ctx context.Context, req *bbsim.ONURequest) (*bbsim.
 		return &res, err
 	}
 
-	res := bbsim.ONU{
-		ID:            int32(onu.ID),
-		SerialNumber:  onu.Sn(),
-		OperState:     onu.OperState.Current(),
-		InternalState: onu.InternalState.Current(),
-		PonPortID:     int32(onu.PonPortID),
-		STag:          int32(onu.STag),
-		CTag:          int32(onu.CTag),
-		HwAddress:     onu.HwAddress.String(),
-		PortNo:        int32(onu.PortNo),
-	}
-	return &res, nil
+	return convertBBSimOnuToProtoOnu(onu), nil
 }
 
 func (s BBSimServer) ShutdownONU(ctx context.Context, req *bbsim.ONURequest) (*bbsim.Response, error) {
@@ -87,7 +101,12 @@ func (s BBSimServer) ShutdownONU(ctx context.Context, req *bbsim.ONURequest) (*b
 		"OnuSn": req.SerialNumber,
 	}).Infof("Received request to shutdown ONU")
 
-	olt := devices.GetOLT()
+	olt, err := getOlt(req.OltID)
+	if err != nil {
+		res.StatusCode = int32(codes.NotFound)
+		res.Message = err.Error()
+		return res, err
+	}
 
 	onu, err := olt.FindOnuBySn(req.SerialNumber)
 
@@ -106,7 +125,11 @@ func (s BBSimServer) ShutdownONU(ctx context.Context, req *bbsim.ONURequest) (*b
 		},
 	}
 
-	onu.Channel <- dyingGasp
+	if err := onu.SendMessage(dyingGasp); err != nil {
+		res.StatusCode = int32(codes.FailedPrecondition)
+		res.Message = err.Error()
+		return res, err
+	}
 
 	if err := onu.InternalState.Event("disable"); err != nil {
 		logger.WithFields(log.Fields{
@@ -132,7 +155,12 @@ func (s BBSimServer) PoweronONU(ctx context.Context, req *bbsim.ONURequest) (*bb
 		"OnuSn": req.SerialNumber,
 	}).Infof("Received request to poweron ONU")
 
-	olt := devices.GetOLT()
+	olt, err := getOlt(req.OltID)
+	if err != nil {
+		res.StatusCode = int32(codes.NotFound)
+		res.Message = err.Error()
+		return res, err
+	}
 
 	onu, err := olt.FindOnuBySn(req.SerialNumber)
 
@@ -159,6 +187,41 @@ func (s BBSimServer) PoweronONU(ctx context.Context, req *bbsim.ONURequest) (*bb
 	return res, nil
 }
 
+func (s BBSimServer) RebootONU(ctx context.Context, req *bbsim.ONURequest) (*bbsim.Response, error) {
+	res := &bbsim.Response{}
+
+	logger.WithFields(log.Fields{
+		"OnuSn": req.SerialNumber,
+	}).Infof("Received request to reboot ONU")
+
+	olt, err := getOlt(req.OltID)
+	if err != nil {
+		res.StatusCode = int32(codes.NotFound)
+		res.Message = err.Error()
+		return res, err
+	}
+
+	if _, err := olt.FindOnuBySn(req.SerialNumber); err != nil {
+		res.StatusCode = int32(codes.NotFound)
+		res.Message = err.Error()
+		return res, err
+	}
+
+	if err := olt.RebootOnu(req.SerialNumber); err != nil {
+		logger.WithFields(log.Fields{
+			"OnuSn": req.SerialNumber,
+		}).Errorf("Cannot reboot ONU: %s", err.Error())
+		res.StatusCode = int32(codes.FailedPrecondition)
+		res.Message = err.Error()
+		return res, err
+	}
+
+	res.StatusCode = int32(codes.OK)
+	res.Message = fmt.Sprintf("ONU %s reboot triggered.", req.SerialNumber)
+
+	return res, nil
+}
+
 func (s BBSimServer) RestartEapol(ctx context.Context, req *bbsim.ONURequest) (*bbsim.Response, error) {
 	res := &bbsim.Response{}
 
@@ -166,7 +229,12 @@ func (s BBSimServer) RestartEapol(ctx context.Context, req *bbsim.ONURequest) (*
 		"OnuSn": req.SerialNumber,
 	}).Infof("Received request to restart authentication ONU")
 
-	olt := devices.GetOLT()
+	olt, err := getOlt(req.OltID)
+	if err != nil {
+		res.StatusCode = int32(codes.NotFound)
+		res.Message = err.Error()
+		return res, err
+	}
 
 	onu, err := olt.FindOnuBySn(req.SerialNumber)
 
@@ -176,15 +244,18 @@ func (s BBSimServer) RestartEapol(ctx context.Context, req *bbsim.ONURequest) (*
 		return res, err
 	}
 
-	if err := onu.InternalState.Event("start_auth"); err != nil {
-		logger.WithFields(log.Fields{
-			"OnuId":  onu.ID,
-			"IntfId": onu.PonPortID,
-			"OnuSn":  onu.Sn(),
-		}).Errorf("Cannot restart authenticaton for ONU: %s", err.Error())
-		res.StatusCode = int32(codes.FailedPrecondition)
-		res.Message = err.Error()
-		return res, err
+	for _, uni := range onu.UniPorts {
+		if err := uni.InternalState.Event("start_auth"); err != nil {
+			logger.WithFields(log.Fields{
+				"OnuId":  onu.ID,
+				"IntfId": onu.PonPortID,
+				"OnuSn":  onu.Sn(),
+				"UniId":  uni.ID,
+			}).Errorf("Cannot restart authenticaton for UNI: %s", err.Error())
+			res.StatusCode = int32(codes.FailedPrecondition)
+			res.Message = err.Error()
+			return res, err
+		}
 	}
 
 	res.StatusCode = int32(codes.OK)
@@ -200,7 +271,12 @@ func (s BBSimServer) RestartDhcp(ctx context.Context, req *bbsim.ONURequest) (*b
 		"OnuSn": req.SerialNumber,
 	}).Infof("Received request to restart DHCP on ONU")
 
-	olt := devices.GetOLT()
+	olt, err := getOlt(req.OltID)
+	if err != nil {
+		res.StatusCode = int32(codes.NotFound)
+		res.Message = err.Error()
+		return res, err
+	}
 
 	onu, err := olt.FindOnuBySn(req.SerialNumber)
 
@@ -210,19 +286,111 @@ func (s BBSimServer) RestartDhcp(ctx context.Context, req *bbsim.ONURequest) (*b
 		return res, err
 	}
 
-	if err := onu.InternalState.Event("start_dhcp"); err != nil {
+	for _, uni := range onu.UniPorts {
+		if err := uni.InternalState.Event("start_dhcp"); err != nil {
+			logger.WithFields(log.Fields{
+				"OnuId":  onu.ID,
+				"IntfId": onu.PonPortID,
+				"OnuSn":  onu.Sn(),
+				"UniId":  uni.ID,
+			}).Errorf("Cannot restart DHCP for UNI: %s", err.Error())
+			res.StatusCode = int32(codes.FailedPrecondition)
+			res.Message = err.Error()
+			return res, err
+		}
+	}
+
+	res.StatusCode = int32(codes.OK)
+	res.Message = fmt.Sprintf("DHCP restarted for ONU %s.", onu.Sn())
+
+	return res, nil
+}
+
+func (s BBSimServer) CreateOnu(ctx context.Context, req *bbsim.CreateOnuRequest) (*bbsim.ONU, error) {
+	logger.WithFields(log.Fields{
+		"IntfId": req.PonPortID,
+		"OnuSn":  req.SerialNumber,
+	}).Infof("Received request to create ONU")
+
+	olt, err := getOlt(req.OltID)
+	if err != nil {
+		return &bbsim.ONU{}, err
+	}
+
+	t := common.OnuTopology{
+		SerialNumber:  req.SerialNumber,
+		MacAddress:    req.HwAddress,
+		STag:          int(req.STag),
+		CTag:          int(req.CTag),
+		EapolUsername: req.EapolUsername,
+		EapolPassword: req.EapolPassword,
+		MibTemplate:   req.MibTemplate,
+	}
+	if err := t.Validate(); err != nil {
+		return &bbsim.ONU{}, status.Errorf(codes.InvalidArgument, err.Error())
+	}
+
+	onu, err := olt.AddOnu(req.PonPortID, t)
+	if err != nil {
 		logger.WithFields(log.Fields{
-			"OnuId":  onu.ID,
-			"IntfId": onu.PonPortID,
-			"OnuSn":  onu.Sn(),
-		}).Errorf("Cannot restart DHCP for ONU: %s", err.Error())
-		res.StatusCode = int32(codes.FailedPrecondition)
+			"IntfId": req.PonPortID,
+			"OnuSn":  req.SerialNumber,
+		}).Errorf("Cannot create ONU: %s", err.Error())
+		return &bbsim.ONU{}, status.Errorf(codes.FailedPrecondition, err.Error())
+	}
+
+	return convertBBSimOnuToProtoOnu(onu), nil
+}
+
+func (s BBSimServer) DeleteOnu(ctx context.Context, req *bbsim.ONURequest) (*bbsim.Response, error) {
+	res := &bbsim.Response{}
+
+	logger.WithFields(log.Fields{
+		"OnuSn": req.SerialNumber,
+	}).Infof("Received request to delete ONU")
+
+	olt, err := getOlt(req.OltID)
+	if err != nil {
+		res.StatusCode = int32(codes.NotFound)
+		res.Message = err.Error()
+		return res, err
+	}
+
+	if err := olt.RemoveOnu(req.SerialNumber); err != nil {
+		res.StatusCode = int32(codes.NotFound)
 		res.Message = err.Error()
 		return res, err
 	}
 
 	res.StatusCode = int32(codes.OK)
-	res.Message = fmt.Sprintf("DHCP restarted for ONU %s.", onu.Sn())
+	res.Message = fmt.Sprintf("ONU %s successfully deleted.", req.SerialNumber)
 
 	return res, nil
 }
+
+func (s BBSimServer) MoveOnu(ctx context.Context, req *bbsim.MoveOnuRequest) (*bbsim.ONU, error) {
+	logger.WithFields(log.Fields{
+		"OnuSn":  req.SerialNumber,
+		"IntfId": req.PonPortID,
+	}).Infof("Received request to move ONU")
+
+	olt, err := getOlt(req.OltID)
+	if err != nil {
+		return &bbsim.ONU{}, err
+	}
+
+	if _, err := olt.FindOnuBySn(req.SerialNumber); err != nil {
+		return &bbsim.ONU{}, status.Errorf(codes.NotFound, err.Error())
+	}
+
+	onu, err := olt.MoveOnu(req.SerialNumber, req.PonPortID)
+	if err != nil {
+		logger.WithFields(log.Fields{
+			"OnuSn":  req.SerialNumber,
+			"IntfId": req.PonPortID,
+		}).Errorf("Cannot move ONU: %s", err.Error())
+		return &bbsim.ONU{}, status.Errorf(codes.FailedPrecondition, err.Error())
+	}
+
+	return convertBBSimOnuToProtoOnu(onu), nil
+}
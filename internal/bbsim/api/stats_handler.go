@@ -0,0 +1,68 @@
+/*
+ * Copyright 2018-present Open Networking Foundation
+
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+
+ * http://www.apache.org/licenses/LICENSE-2.0
+
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package api
+
+import (
+	"context"
+
+	"github.com/opencord/bbsim/api/bbsim"
+	"github.com/opencord/bbsim/internal/bbsim/devices"
+)
+
+func toApiPortStats(portType string, id uint32, stats devices.PacketStats) *bbsim.PortStats {
+	return &bbsim.PortStats{
+		PortType:  portType,
+		ID:        id,
+		RxPackets: stats.RxPackets,
+		RxBytes:   stats.RxBytes,
+		TxPackets: stats.TxPackets,
+		TxBytes:   stats.TxBytes,
+	}
+}
+
+func (s BBSimServer) GetOltStats(ctx context.Context, req *bbsim.OltRequest) (*bbsim.OltStats, error) {
+	olt, err := getOlt(req.OltID)
+	if err != nil {
+		return &bbsim.OltStats{}, err
+	}
+
+	res := &bbsim.OltStats{
+		Ports: []*bbsim.PortStats{},
+		Flows: []*bbsim.FlowStats{},
+	}
+
+	for _, nni := range olt.Nnis {
+		res.Ports = append(res.Ports, toApiPortStats("nni", nni.ID, nni.Stats.Get()))
+	}
+	for _, pon := range olt.Pons {
+		res.Ports = append(res.Ports, toApiPortStats("pon", pon.ID, pon.Stats.Get()))
+	}
+
+	for _, flow := range olt.GetFlows() {
+		stats := olt.GetFlowStats(devices.FlowKey{ID: flow.FlowId, Direction: flow.FlowType})
+		res.Flows = append(res.Flows, &bbsim.FlowStats{
+			FlowId:    flow.FlowId,
+			FlowType:  flow.FlowType,
+			RxPackets: stats.RxPackets,
+			RxBytes:   stats.RxBytes,
+			TxPackets: stats.TxPackets,
+			TxBytes:   stats.TxBytes,
+		})
+	}
+
+	return res, nil
+}
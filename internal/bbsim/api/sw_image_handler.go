@@ -0,0 +1,97 @@
+/*
+ * Copyright 2018-present Open Networking Foundation
+
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+
+ * http://www.apache.org/licenses/LICENSE-2.0
+
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package api
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/opencord/bbsim/api/bbsim"
+	"github.com/opencord/bbsim/internal/bbsim/devices"
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (s BBSimServer) GetOnuSoftwareImages(ctx context.Context, req *bbsim.ONURequest) (*bbsim.SoftwareImages, error) {
+	olt, err := getOlt(req.OltID)
+	if err != nil {
+		return &bbsim.SoftwareImages{}, err
+	}
+
+	onu, err := olt.FindOnuBySn(req.SerialNumber)
+	if err != nil {
+		logger.WithFields(log.Fields{
+			"OnuSn": req.SerialNumber,
+		}).Errorf("Cannot get the software images: %s", err.Error())
+		return &bbsim.SoftwareImages{}, status.Errorf(codes.NotFound, err.Error())
+	}
+
+	downloaded, size := onu.GetSoftwareDownload()
+	res := &bbsim.SoftwareImages{
+		Images:          []*bbsim.SoftwareImage{},
+		DownloadedBytes: downloaded,
+		DownloadSize:    size,
+		Fault:           string(onu.GetSoftwareImageFault()),
+	}
+	for _, i := range onu.GetSoftwareImages() {
+		res.Images = append(res.Images, &bbsim.SoftwareImage{
+			Instance:    uint32(i.Instance),
+			Version:     i.Version,
+			IsCommitted: i.IsCommitted,
+			IsActive:    i.IsActive,
+			IsValid:     i.IsValid,
+		})
+	}
+	return res, nil
+}
+
+func (s BBSimServer) SetOnuSoftwareImageFault(ctx context.Context, req *bbsim.SoftwareImageFaultRequest) (*bbsim.Response, error) {
+	res := &bbsim.Response{}
+
+	logger.WithFields(log.Fields{
+		"OnuSn": req.SerialNumber,
+		"Fault": req.Fault,
+	}).Infof("Received request to set the software image fault")
+
+	olt, err := getOlt(req.OltID)
+	if err != nil {
+		res.StatusCode = int32(codes.NotFound)
+		res.Message = err.Error()
+		return res, err
+	}
+
+	onu, err := olt.FindOnuBySn(req.SerialNumber)
+	if err != nil {
+		res.StatusCode = int32(codes.NotFound)
+		res.Message = err.Error()
+		return res, err
+	}
+
+	fault, err := devices.ParseSwImageFault(req.Fault)
+	if err != nil {
+		res.StatusCode = int32(codes.InvalidArgument)
+		res.Message = err.Error()
+		return res, err
+	}
+
+	onu.SetSoftwareImageFault(fault)
+
+	res.StatusCode = int32(codes.OK)
+	res.Message = fmt.Sprintf("Software image fault set to %s on ONU %s.", fault, onu.Sn())
+	return res, nil
+}
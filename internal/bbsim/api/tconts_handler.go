@@ -0,0 +1,68 @@
+/*
+ * Copyright 2018-present Open Networking Foundation
+
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+
+ * http://www.apache.org/licenses/LICENSE-2.0
+
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package api
+
+import (
+	"context"
+
+	"github.com/opencord/bbsim/api/bbsim"
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (s BBSimServer) GetOnuTConts(ctx context.Context, req *bbsim.ONURequest) (*bbsim.TConts, error) {
+	olt, err := getOlt(req.OltID)
+	if err != nil {
+		return &bbsim.TConts{}, err
+	}
+
+	onu, err := olt.FindOnuBySn(req.SerialNumber)
+	if err != nil {
+		logger.WithFields(log.Fields{
+			"OnuSn": req.SerialNumber,
+		}).Errorf("Cannot list T-CONTs: %s", err.Error())
+		return &bbsim.TConts{}, status.Errorf(codes.NotFound, err.Error())
+	}
+
+	res := &bbsim.TConts{
+		TConts:   []*bbsim.TCont{},
+		GemPorts: []*bbsim.GemPort{},
+	}
+
+	for _, uni := range onu.UniPorts {
+		for _, ts := range olt.GetTrafficSchedulers(uni) {
+			res.TConts = append(res.TConts, &bbsim.TCont{
+				UniID:     uni.ID,
+				Direction: ts.Direction.String(),
+				AllocID:   ts.AllocId,
+			})
+		}
+		for _, tq := range olt.GetTrafficQueues(uni) {
+			res.GemPorts = append(res.GemPorts, &bbsim.GemPort{
+				UniID:     uni.ID,
+				Direction: tq.Direction.String(),
+				GemportID: tq.GemportId,
+				PbitMap:   tq.PbitMap,
+				Priority:  tq.Priority,
+				Weight:    tq.Weight,
+			})
+		}
+	}
+
+	return res, nil
+}
@@ -0,0 +1,279 @@
+/*
+ * Copyright 2018-present Open Networking Foundation
+
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+
+ * http://www.apache.org/licenses/LICENSE-2.0
+
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package devices
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/opencord/bbsim/internal/common"
+	"github.com/opencord/voltha-protos/v2/go/openolt"
+	log "github.com/sirupsen/logrus"
+)
+
+// the interface IDs reported in the LOS indications are encoded the same way VOLTHA does
+const (
+	ponIntfIdPrefix = uint32(0x2 << 28)
+	nniIntfIdPrefix = uint32(0x1 << 20)
+)
+
+// createOnuAlarm builds the AlarmIndication VOLTHA receives when an ONU alarm is raised or cleared
+func createOnuAlarm(onu *Onu, alarmType string, status string) (*openolt.AlarmIndication, error) {
+	if err := common.ValidateAlarmStatus(status); err != nil {
+		return nil, err
+	}
+
+	alarm := &openolt.AlarmIndication{}
+
+	switch alarmType {
+	case common.OnuAlarmLos, common.OnuAlarmLob, common.OnuAlarmLopcMiss,
+		common.OnuAlarmLopcMicError, common.OnuAlarmLofi, common.OnuAlarmLoami:
+		ind := &openolt.OnuAlarmIndication{
+			IntfId:             onu.PonPortID,
+			OnuId:              onu.ID,
+			LosStatus:          common.AlarmStatusOff,
+			LobStatus:          common.AlarmStatusOff,
+			LopcMissStatus:     common.AlarmStatusOff,
+			LopcMicErrorStatus: common.AlarmStatusOff,
+			LofiStatus:         common.AlarmStatusOff,
+			LoamiStatus:        common.AlarmStatusOff,
+		}
+		switch alarmType {
+		case common.OnuAlarmLos:
+			ind.LosStatus = status
+		case common.OnuAlarmLob:
+			ind.LobStatus = status
+		case common.OnuAlarmLopcMiss:
+			ind.LopcMissStatus = status
+		case common.OnuAlarmLopcMicError:
+			ind.LopcMicErrorStatus = status
+		case common.OnuAlarmLofi:
+			ind.LofiStatus = status
+		case common.OnuAlarmLoami:
+			ind.LoamiStatus = status
+		}
+		alarm.Data = &openolt.AlarmIndication_OnuAlarmInd{OnuAlarmInd: ind}
+	case common.OnuAlarmDyingGasp:
+		alarm.Data = &openolt.AlarmIndication_DyingGaspInd{DyingGaspInd: &openolt.DyingGaspIndication{
+			IntfId: onu.PonPortID,
+			OnuId:  onu.ID,
+			Status: status,
+		}}
+	case common.OnuAlarmStartupFailure:
+		alarm.Data = &openolt.AlarmIndication_OnuStartupFailInd{OnuStartupFailInd: &openolt.OnuStartupFailureIndication{
+			IntfId: onu.PonPortID,
+			OnuId:  onu.ID,
+			Status: status,
+		}}
+	case common.OnuAlarmSignalDegrade:
+		alarm.Data = &openolt.AlarmIndication_OnuSignalDegradeInd{OnuSignalDegradeInd: &openolt.OnuSignalDegradeIndication{
+			IntfId:              onu.PonPortID,
+			OnuId:               onu.ID,
+			Status:              status,
+			InverseBitErrorRate: 0,
+		}}
+	case common.OnuAlarmDriftOfWindow:
+		alarm.Data = &openolt.AlarmIndication_OnuDriftOfWindowInd{OnuDriftOfWindowInd: &openolt.OnuDriftOfWindowIndication{
+			IntfId: onu.PonPortID,
+			OnuId:  onu.ID,
+			Status: status,
+			Drift:  0,
+			NewEqd: 0,
+		}}
+	case common.OnuAlarmLossOfOmciChannel:
+		alarm.Data = &openolt.AlarmIndication_OnuLossOmciInd{OnuLossOmciInd: &openolt.OnuLossOfOmciChannelIndication{
+			IntfId: onu.PonPortID,
+			OnuId:  onu.ID,
+			Status: status,
+		}}
+	case common.OnuAlarmSignalsFailure:
+		alarm.Data = &openolt.AlarmIndication_OnuSignalsFailInd{OnuSignalsFailInd: &openolt.OnuSignalsFailureIndication{
+			IntfId:              onu.PonPortID,
+			OnuId:               onu.ID,
+			Status:              status,
+			InverseBitErrorRate: 0,
+		}}
+	case common.OnuAlarmTransmissionWarning:
+		alarm.Data = &openolt.AlarmIndication_OnuTiwiInd{OnuTiwiInd: &openolt.OnuTransmissionInterferenceWarning{
+			IntfId: onu.PonPortID,
+			OnuId:  onu.ID,
+			Status: status,
+			Drift:  0,
+		}}
+	case common.OnuAlarmLossOfKeySyncFailure:
+		alarm.Data = &openolt.AlarmIndication_OnuLossOfSyncFailInd{OnuLossOfSyncFailInd: &openolt.OnuLossOfKeySyncFailureIndication{
+			IntfId: onu.PonPortID,
+			OnuId:  onu.ID,
+			Status: status,
+		}}
+	case common.OnuAlarmActivationFailure, common.OnuAlarmProcessingError:
+		// these indications do not carry a status, thus they can only be raised
+		if status != common.AlarmStatusOn {
+			return nil, errors.New(fmt.Sprintf("alarm-%s-cannot-be-cleared", alarmType))
+		}
+		if alarmType == common.OnuAlarmActivationFailure {
+			alarm.Data = &openolt.AlarmIndication_OnuActivationFailInd{OnuActivationFailInd: &openolt.OnuActivationFailureIndication{
+				IntfId:     onu.PonPortID,
+				OnuId:      onu.ID,
+				FailReason: 0,
+			}}
+		} else {
+			alarm.Data = &openolt.AlarmIndication_OnuProcessingErrorInd{OnuProcessingErrorInd: &openolt.OnuProcessingErrorIndication{
+				IntfId: onu.PonPortID,
+				OnuId:  onu.ID,
+			}}
+		}
+	default:
+		return nil, errors.New(fmt.Sprintf("unknown-alarm-type-%s", alarmType))
+	}
+
+	return alarm, nil
+}
+
+// createOltAlarm builds the AlarmIndication VOLTHA receives when an OLT port alarm is raised or cleared
+func createOltAlarm(alarmType string, intfId uint32, status string) (*openolt.AlarmIndication, error) {
+	if err := common.ValidateAlarmStatus(status); err != nil {
+		return nil, err
+	}
+
+	var id uint32
+	switch alarmType {
+	case common.OltAlarmPonLos:
+		id = ponIntfIdPrefix | intfId
+	case common.OltAlarmNniLos:
+		id = nniIntfIdPrefix | intfId
+	default:
+		return nil, errors.New(fmt.Sprintf("unknown-alarm-type-%s", alarmType))
+	}
+
+	return &openolt.AlarmIndication{
+		Data: &openolt.AlarmIndication_LosInd{LosInd: &openolt.LosIndication{
+			IntfId: id,
+			Status: status,
+		}},
+	}, nil
+}
+
+// SetOnuAlarm raises or clears an alarm on the ONU with the given serial number
+func (o *OltDevice) SetOnuAlarm(serialNumber string, alarmType string, status string) error {
+	if !o.InternalState.Is("enabled") {
+		return errors.New(fmt.Sprintf("olt-%d-is-not-enabled", o.ID))
+	}
+
+	onu, err := o.FindOnuBySn(serialNumber)
+	if err != nil {
+		return err
+	}
+
+	t, err := common.GetOnuAlarmType(alarmType)
+	if err != nil {
+		return err
+	}
+
+	alarm, err := createOnuAlarm(onu, t, status)
+	if err != nil {
+		return err
+	}
+
+	onuLogger.WithFields(log.Fields{
+		"IntfId":    onu.PonPortID,
+		"OnuId":     onu.ID,
+		"OnuSn":     onu.Sn(),
+		"AlarmType": t,
+		"Status":    status,
+	}).Info("Setting ONU alarm")
+
+	o.channel <- Message{
+		Type: AlarmIndication,
+		Data: AlarmIndicationMessage{
+			AlarmType: t,
+			Alarm:     alarm,
+		},
+	}
+	return nil
+}
+
+// SendOnuOmciAlarm sends an OMCI Alarm Notification or Attribute Value Change from the ONU with the given serial number,
+// see Onu.SendOmciAlarm
+func (o *OltDevice) SendOnuOmciAlarm(serialNumber string, alarmType string, uniId uint32, status string) error {
+	if !o.InternalState.Is("enabled") {
+		return errors.New(fmt.Sprintf("olt-%d-is-not-enabled", o.ID))
+	}
+
+	onu, err := o.FindOnuBySn(serialNumber)
+	if err != nil {
+		return err
+	}
+
+	return onu.SendOmciAlarm(alarmType, uniId, status)
+}
+
+// SetOltAlarm raises or clears a LOS alarm on a PON or NNI port of the OLT
+func (o *OltDevice) SetOltAlarm(alarmType string, intfId uint32, status string) error {
+	if !o.InternalState.Is("enabled") {
+		return errors.New(fmt.Sprintf("olt-%d-is-not-enabled", o.ID))
+	}
+
+	t, err := common.GetOltAlarmType(alarmType)
+	if err != nil {
+		return err
+	}
+
+	switch t {
+	case common.OltAlarmPonLos:
+		if _, err := o.GetPonById(intfId); err != nil {
+			return err
+		}
+	case common.OltAlarmNniLos:
+		if _, err := o.getNniById(intfId); err != nil {
+			return err
+		}
+	}
+
+	alarm, err := createOltAlarm(t, intfId, status)
+	if err != nil {
+		return err
+	}
+
+	oltLogger.WithFields(log.Fields{
+		"IntfId":    intfId,
+		"AlarmType": t,
+		"Status":    status,
+	}).Info("Setting OLT alarm")
+
+	o.channel <- Message{
+		Type: AlarmIndication,
+		Data: AlarmIndicationMessage{
+			AlarmType: t,
+			Alarm:     alarm,
+		},
+	}
+	return nil
+}
+
+func (o *OltDevice) sendAlarmIndication(msg AlarmIndicationMessage, stream openolt.Openolt_EnableIndicationServer) {
+	data := &openolt.Indication_AlarmInd{AlarmInd: msg.Alarm}
+
+	if err := stream.Send(&openolt.Indication{Data: data}); err != nil {
+		oltLogger.Errorf("Failed to send AlarmIndication %s: %v", msg.AlarmType, err)
+		return
+	}
+
+	oltLogger.WithFields(log.Fields{
+		"AlarmType": msg.AlarmType,
+	}).Debug("Sent AlarmIndication")
+}
@@ -0,0 +1,122 @@
+/*
+ * Copyright 2018-present Open Networking Foundation
+
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+
+ * http://www.apache.org/licenses/LICENSE-2.0
+
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package devices
+
+import (
+	"testing"
+
+	"github.com/looplab/fsm"
+	"github.com/opencord/bbsim/internal/common"
+	"github.com/opencord/voltha-protos/v2/go/openolt"
+	"gotest.tools/assert"
+)
+
+func enableMockOlt(olt *OltDevice) {
+	olt.channel = make(chan Message, 10)
+	olt.InternalState = fsm.NewFSM("enabled", fsm.Events{}, fsm.Callbacks{})
+}
+
+func Test_CreateOnuAlarm_OnuAlarmInd(t *testing.T) {
+	onu := createTestOnu()
+
+	alarm, err := createOnuAlarm(onu, common.OnuAlarmLob, "on")
+	assert.NilError(t, err)
+
+	ind := alarm.GetOnuAlarmInd()
+	assert.Equal(t, ind.IntfId, onu.PonPortID)
+	assert.Equal(t, ind.OnuId, onu.ID)
+	assert.Equal(t, ind.LobStatus, "on")
+	assert.Equal(t, ind.LosStatus, "off")
+	assert.Equal(t, ind.LopcMissStatus, "off")
+}
+
+func Test_CreateOnuAlarm_SignalDegrade(t *testing.T) {
+	onu := createTestOnu()
+
+	alarm, err := createOnuAlarm(onu, common.OnuAlarmSignalDegrade, "off")
+	assert.NilError(t, err)
+
+	ind := alarm.GetOnuSignalDegradeInd()
+	assert.Equal(t, ind.OnuId, onu.ID)
+	assert.Equal(t, ind.Status, "off")
+}
+
+func Test_CreateOnuAlarm_Errors(t *testing.T) {
+	onu := createTestOnu()
+
+	_, err := createOnuAlarm(onu, common.OnuAlarmLos, "maybe")
+	assert.Equal(t, err.Error(), "invalid-alarm-status-maybe")
+
+	_, err = createOnuAlarm(onu, common.OnuAlarmProcessingError, "off")
+	assert.Equal(t, err.Error(), "alarm-ProcessingError-cannot-be-cleared")
+}
+
+func Test_CreateOltAlarm(t *testing.T) {
+	alarm, err := createOltAlarm(common.OltAlarmPonLos, 1, "on")
+	assert.NilError(t, err)
+	assert.Equal(t, alarm.GetLosInd().IntfId, uint32(0x20000001))
+
+	alarm, err = createOltAlarm(common.OltAlarmNniLos, 0, "off")
+	assert.NilError(t, err)
+	assert.Equal(t, alarm.GetLosInd().IntfId, uint32(1048576))
+	assert.Equal(t, alarm.GetLosInd().Status, "off")
+}
+
+func Test_Olt_SetOnuAlarm(t *testing.T) {
+	olt := createMockOlt(1, 1)
+	enableMockOlt(&olt)
+
+	// the legacy API uses lowercase names and "lossofploam" for LOPC
+	err := olt.SetOnuAlarm("BBSM00000000", "lossofploam", "on")
+	assert.NilError(t, err)
+
+	msg := <-olt.channel
+	assert.Equal(t, msg.Type, AlarmIndication)
+	data := msg.Data.(AlarmIndicationMessage)
+	assert.Equal(t, data.AlarmType, common.OnuAlarmLopcMiss)
+	assert.Equal(t, data.Alarm.Data.(*openolt.AlarmIndication_OnuAlarmInd).OnuAlarmInd.LopcMissStatus, "on")
+}
+
+func Test_Olt_SetOnuAlarm_Errors(t *testing.T) {
+	olt := createMockOlt(1, 1)
+	enableMockOlt(&olt)
+
+	err := olt.SetOnuAlarm("BBSM00000000", "foo", "on")
+	assert.Equal(t, err.Error(), "unknown-alarm-type-foo")
+
+	err = olt.SetOnuAlarm("BBSM00000303", "los", "on")
+	assert.Equal(t, err.Error(), "cannot-find-onu-by-serial-number-BBSM00000303")
+
+	olt.InternalState.SetState("disabled")
+	err = olt.SetOnuAlarm("BBSM00000000", "los", "on")
+	assert.Equal(t, err.Error(), "olt-0-is-not-enabled")
+}
+
+func Test_Olt_SetOltAlarm(t *testing.T) {
+	olt := createMockOlt(2, 1)
+	enableMockOlt(&olt)
+
+	err := olt.SetOltAlarm("pon_los", 1, "on")
+	assert.NilError(t, err)
+
+	msg := <-olt.channel
+	assert.Equal(t, msg.Type, AlarmIndication)
+	assert.Equal(t, msg.Data.(AlarmIndicationMessage).Alarm.GetLosInd().IntfId, uint32(0x20000001))
+
+	err = olt.SetOltAlarm(common.OltAlarmPonLos, 5, "on")
+	assert.Equal(t, err.Error(), "Cannot find PonPort with id 5 in OLT 0")
+}
@@ -0,0 +1,411 @@
+/*
+ * Copyright 2018-present Open Networking Foundation
+
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+
+ * http://www.apache.org/licenses/LICENSE-2.0
+
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package devices
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net"
+
+	"github.com/google/gopacket"
+	"github.com/google/gopacket/layers"
+	"github.com/opencord/bbsim/internal/bbsim/packetHandlers"
+	"github.com/opencord/voltha-protos/v2/go/openolt"
+	log "github.com/sirupsen/logrus"
+)
+
+// the packet tag types of the flow classifiers
+const (
+	pktTagTypeUntagged = "untagged"
+	pktTagTypeSingle   = "single_tag"
+	pktTagTypeDouble   = "double_tag"
+)
+
+// packetFields are the fields of a packet the flow classifiers match on
+type packetFields struct {
+	vlans   []*layers.Dot1Q // the VLAN tags, the outer one first
+	ethType uint32
+	srcMac  net.HardwareAddr
+	dstMac  net.HardwareAddr
+	ipProto uint32
+	srcIp   uint32
+	dstIp   uint32
+	srcPort uint32
+	dstPort uint32
+}
+
+func getPacketFields(pkt gopacket.Packet) packetFields {
+	fields := packetFields{ethType: packetEthType(pkt)}
+	for _, l := range pkt.Layers() {
+		switch layer := l.(type) {
+		case *layers.Ethernet:
+			fields.srcMac = layer.SrcMAC
+			fields.dstMac = layer.DstMAC
+		case *layers.Dot1Q:
+			fields.vlans = append(fields.vlans, layer)
+		case *layers.IPv4:
+			fields.ipProto = uint32(layer.Protocol)
+			if ip := layer.SrcIP.To4(); ip != nil {
+				fields.srcIp = binary.BigEndian.Uint32(ip)
+			}
+			if ip := layer.DstIP.To4(); ip != nil {
+				fields.dstIp = binary.BigEndian.Uint32(ip)
+			}
+		case *layers.UDP:
+			fields.srcPort = uint32(layer.SrcPort)
+			fields.dstPort = uint32(layer.DstPort)
+		case *layers.TCP:
+			fields.srcPort = uint32(layer.SrcPort)
+			fields.dstPort = uint32(layer.DstPort)
+		}
+	}
+	return fields
+}
+
+// classifierMatches checks a packet against a flow classifier, the fields that are not set match any packet
+func classifierMatches(c *openolt.Classifier, fields packetFields) bool {
+	if c == nil {
+		return true
+	}
+
+	switch c.PktTagType {
+	case pktTagTypeUntagged:
+		if len(fields.vlans) != 0 {
+			return false
+		}
+	case pktTagTypeSingle:
+		if len(fields.vlans) != 1 {
+			return false
+		}
+	case pktTagTypeDouble:
+		if len(fields.vlans) != 2 {
+			return false
+		}
+	}
+
+	if c.OVid != 0 && (len(fields.vlans) < 1 || uint32(fields.vlans[0].VLANIdentifier) != c.OVid) {
+		return false
+	}
+	if c.IVid != 0 && (len(fields.vlans) < 2 || uint32(fields.vlans[1].VLANIdentifier) != c.IVid) {
+		return false
+	}
+	// NOTE the p-bits are only classified when they are in range, VOLTHA uses 0xff for any
+	if c.OPbits != 0 && c.OPbits <= 7 && (len(fields.vlans) < 1 || uint32(fields.vlans[0].Priority) != c.OPbits) {
+		return false
+	}
+	if c.IPbits != 0 && c.IPbits <= 7 && (len(fields.vlans) < 2 || uint32(fields.vlans[1].Priority) != c.IPbits) {
+		return false
+	}
+
+	if c.EthType != 0 && c.EthType != fields.ethType {
+		return false
+	}
+	if len(c.SrcMac) != 0 && !bytes.Equal(c.SrcMac, fields.srcMac) {
+		return false
+	}
+	if len(c.DstMac) != 0 && !bytes.Equal(c.DstMac, fields.dstMac) {
+		return false
+	}
+	if c.IpProto != 0 && c.IpProto != fields.ipProto {
+		return false
+	}
+	if c.SrcIp != 0 && c.SrcIp != fields.srcIp {
+		return false
+	}
+	if c.DstIp != 0 && c.DstIp != fields.dstIp {
+		return false
+	}
+	if c.SrcPort != 0 && c.SrcPort != fields.srcPort {
+		return false
+	}
+	if c.DstPort != 0 && c.DstPort != fields.dstPort {
+		return false
+	}
+	return true
+}
+
+// matchForwardingFlow returns the flow a packet is forwarded with and accounts the packet on it:
+// among the flows of the given direction installed on the ingress port that match the packet,
+// the one with the highest priority (the lowest FlowId if more have the same priority)
+func (o *OltDevice) matchForwardingFlow(direction string, ingress func(flow openolt.Flow) bool, pkt gopacket.Packet) (openolt.Flow, bool) {
+	fields := getPacketFields(pkt)
+
+	o.flowsLock.Lock()
+	defer o.flowsLock.Unlock()
+
+	var matched *openolt.Flow
+	for _, flow := range o.Flows {
+		if flow.FlowType != direction || !ingress(flow) || !classifierMatches(flow.Classifier, fields) {
+			continue
+		}
+		if matched == nil || flow.Priority > matched.Priority ||
+			(flow.Priority == matched.Priority && flow.FlowId < matched.FlowId) {
+			f := flow
+			matched = &f
+		}
+	}
+	if matched == nil {
+		return openolt.Flow{}, false
+	}
+
+	o.countFlowStats(FlowKey{ID: matched.FlowId, Direction: matched.FlowType}, len(pkt.Data()))
+	return *matched, true
+}
+
+func isTrapFlow(flow openolt.Flow) bool {
+	return flow.Action != nil && flow.Action.Cmd != nil && flow.Action.Cmd.TrapToHost
+}
+
+// applyFlowActions changes the VLAN tags of a packet as the action of the flow says,
+// a VLAN translation is a remove_outer_tag followed by an add_outer_tag
+func applyFlowActions(flow openolt.Flow, pkt gopacket.Packet) (gopacket.Packet, error) {
+	if flow.Action == nil || flow.Action.Cmd == nil {
+		return pkt, nil
+	}
+
+	var err error
+	if flow.Action.Cmd.RemoveOuterTag {
+		if pkt, err = packetHandlers.PopSingleTag(pkt); err != nil {
+			return nil, err
+		}
+	}
+	if flow.Action.Cmd.AddOuterTag {
+		if pkt, err = packetHandlers.PushSingleTag(int(flow.Action.OVid), pkt); err != nil {
+			return nil, err
+		}
+	}
+	return pkt, nil
+}
+
+// tagDhcpServerPacket adds the tags of the subscriber to the packets of the DHCP server running on the upstream veth.
+// The server stands for the BNG, but its replies are untagged, thus they would not match the flows
+func (o *OltDevice) tagDhcpServerPacket(pkt gopacket.Packet) gopacket.Packet {
+	if !packetHandlers.IsDhcpPacket(pkt) || pkt.Layer(layers.LayerTypeDot1Q) != nil {
+		return pkt
+	}
+
+	mac, err := packetHandlers.GetDstMacAddressFromPacket(pkt)
+	if err != nil {
+		return pkt
+	}
+	uni, err := o.FindUniByMacAddress(mac)
+	if err != nil {
+		oltLogger.WithFields(log.Fields{
+			"MacAddress": mac.String(),
+		}).Debug("Can't find the UNI of a DHCP packet")
+		return pkt
+	}
+
+	tagged, err := packetHandlers.PushDoubleTag(uni.Onu.STag, uni.CTag, pkt)
+	if err != nil {
+		oltLogger.Errorf("Fail to add double tag to packet: %v", err)
+		return pkt
+	}
+	return tagged
+}
+
+// forwardNniPacket handles a packet received on an NNI according to the downstream flows of the NNI:
+// the trapped packets are sent to VOLTHA, the others are delivered to the ONU of the flow
+// and the ones that don't match any flow are dropped
+func (o *OltDevice) forwardNniPacket(nni *NniPort, pkt gopacket.Packet, stream openolt.Openolt_EnableIndicationServer) {
+	nni.Stats.countRx(len(pkt.Data()))
+
+	pkt = o.tagDhcpServerPacket(pkt)
+
+	flow, ok := o.matchForwardingFlow("downstream", func(flow openolt.Flow) bool {
+		return flow.NetworkIntfId < 0 || uint32(flow.NetworkIntfId) == nni.ID
+	}, pkt)
+	if !ok {
+		nniLogger.WithFields(log.Fields{
+			"IntfId": nni.ID,
+			"Pkt":    pkt.Data(),
+		}).Trace("Dropping NNI packet as it doesn't match any flow")
+		return
+	}
+
+	if isTrapFlow(flow) {
+		data := &openolt.Indication_PktInd{PktInd: &openolt.PacketIndication{
+			IntfType: "nni",
+			IntfId:   nni.ID,
+			Pkt:      pkt.Data()}}
+		if err := stream.Send(&openolt.Indication{Data: data}); err != nil {
+			oltLogger.WithFields(log.Fields{
+				"IntfType": data.PktInd.IntfType,
+				"IntfId":   nni.ID,
+				"Pkt":      pkt.Data(),
+			}).Errorf("Fail to send PktInd indication: %v", err)
+			return
+		}
+		oltLogger.WithFields(log.Fields{
+			"IntfType": data.PktInd.IntfType,
+			"IntfId":   nni.ID,
+			"FlowId":   flow.FlowId,
+		}).Tracef("Sent PktInd indication")
+		return
+	}
+
+	pkt, err := applyFlowActions(flow, pkt)
+	if err != nil {
+		nniLogger.WithFields(log.Fields{
+			"IntfId": nni.ID,
+			"FlowId": flow.FlowId,
+		}).Errorf("Can't apply the flow actions to the packet: %v", err)
+		return
+	}
+
+	onu, err := o.FindOnuById(uint32(flow.AccessIntfId), uint32(flow.OnuId))
+	if err != nil {
+		oltLogger.WithFields(log.Fields{
+			"IntfId": flow.AccessIntfId,
+			"OnuId":  flow.OnuId,
+			"FlowId": flow.FlowId,
+		}).Warn("Dropping NNI packet as the flow has no ONU")
+		return
+	}
+	if onu.IsOffline() {
+		oltLogger.WithFields(log.Fields{
+			"IntfId": onu.PonPortID,
+			"OnuId":  onu.ID,
+			"OnuSn":  onu.Sn(),
+		}).Trace("Dropping NNI packet as the ONU is offline")
+		return
+	}
+
+	var portNo uint32
+	if uni, err := onu.GetUniById(uint32(flow.UniId)); err == nil {
+		portNo = uni.PortNo
+	}
+	pktType, _ := packetHandlers.IsEapolOrDhcp(pkt)
+
+	o.countPonTx(onu, pkt)
+
+	// NOTE the ONU handles the packets as the ones VOLTHA sends to it
+	msg := Message{
+		Type: OnuPacketOut,
+		Data: OnuPacketMessage{
+			IntfId:    onu.PonPortID,
+			OnuId:     onu.ID,
+			PortNo:    portNo,
+			GemPortId: uint32(flow.GemportId),
+			Packet:    pkt,
+			Type:      pktType,
+		},
+	}
+	if err := onu.SendMessage(msg); err != nil {
+		oltLogger.WithFields(log.Fields{
+			"IntfId": onu.PonPortID,
+			"OnuId":  onu.ID,
+			"OnuSn":  onu.Sn(),
+		}).Tracef("Dropping NNI packet: %v", err)
+	}
+}
+
+// SendUniPacket sends a packet from a UNI of the ONU with the given serial number, the packet is expected
+// to be as the ONU sends it (with the tags the ONU adds) and it is forwarded according to the upstream flows of the UNI:
+// it is sent to VOLTHA if trapped, out of the NNI of the flow otherwise
+func (o *OltDevice) SendUniPacket(serialNumber string, uniId uint32, data []byte) error {
+	if !o.InternalState.Is("enabled") || o.enableStream == nil {
+		return errors.New(fmt.Sprintf("olt-%d-is-not-enabled", o.ID))
+	}
+
+	onu, err := o.FindOnuBySn(serialNumber)
+	if err != nil {
+		return err
+	}
+	if !onu.InternalState.Is("enabled") {
+		return errors.New(fmt.Sprintf("onu-%s-is-not-enabled", onu.Sn()))
+	}
+	uni, err := onu.GetUniById(uniId)
+	if err != nil {
+		return err
+	}
+
+	pkt := gopacket.NewPacket(data, layers.LayerTypeEthernet, gopacket.Default)
+	if pkt.Layer(layers.LayerTypeEthernet) == nil {
+		return errors.New("invalid-ethernet-packet")
+	}
+
+	return o.forwardUniPacket(uni, pkt, o.enableStream)
+}
+
+// forwardUniPacket handles a packet coming from a UNI according to the upstream flows of the UNI
+func (o *OltDevice) forwardUniPacket(uni *UniPort, pkt gopacket.Packet, stream openolt.Openolt_EnableIndicationServer) error {
+	onu := uni.Onu
+
+	flow, ok := o.matchForwardingFlow("upstream", func(flow openolt.Flow) bool {
+		return (flow.AccessIntfId < 0 || uint32(flow.AccessIntfId) == onu.PonPortID) &&
+			(flow.OnuId < 0 || uint32(flow.OnuId) == onu.ID) &&
+			(flow.UniId < 0 || uint32(flow.UniId) == uni.ID)
+	}, pkt)
+	if !ok {
+		// NOTE the ONU sends the packet anyway, the OLT drops it
+		onu.countOmciPmFrame(true, uni, 0, pkt.Data(), false)
+		o.countPonRx(onu, pkt)
+		return errors.New(fmt.Sprintf("no-flow-matches-packet-from-uni-%d-on-onu-%s", uni.ID, onu.Sn()))
+	}
+	onu.countOmciPmFrame(true, uni, uint32(flow.GemportId), pkt.Data(), false)
+
+	if isTrapFlow(flow) {
+		// NOTE the packets sent to VOLTHA are counted on the PON port by the OLT stream, see ponStatsStream
+		data := &openolt.Indication_PktInd{PktInd: &openolt.PacketIndication{
+			IntfType:  "pon",
+			IntfId:    onu.PonPortID,
+			GemportId: uint32(flow.GemportId),
+			FlowId:    flow.FlowId,
+			PortNo:    uni.PortNo,
+			Cookie:    flow.Cookie,
+			Pkt:       pkt.Data(),
+		}}
+		return stream.Send(&openolt.Indication{Data: data})
+	}
+
+	o.countPonRx(onu, pkt)
+
+	pkt, err := applyFlowActions(flow, pkt)
+	if err != nil {
+		return err
+	}
+
+	nniId := uint32(0)
+	if flow.NetworkIntfId >= 0 {
+		nniId = uint32(flow.NetworkIntfId)
+	}
+	nni, err := o.getNniById(nniId)
+	if err != nil {
+		return err
+	}
+
+	nni.Stats.countTx(len(pkt.Data()))
+	return nni.writePacket(pkt)
+}
+
+// countPonTx counts a packet sent downstream to an ONU on the PON port of the OLT
+// NOTE onu.PonPort is a copy of the PON port, its statistics are not reported
+func (o *OltDevice) countPonTx(onu *Onu, pkt gopacket.Packet) {
+	if pon, err := o.GetPonById(onu.PonPortID); err == nil {
+		pon.Stats.countTx(len(pkt.Data()))
+	}
+}
+
+// countPonRx counts a packet an ONU sends upstream on the PON port of the OLT
+func (o *OltDevice) countPonRx(onu *Onu, pkt gopacket.Packet) {
+	if pon, err := o.GetPonById(onu.PonPortID); err == nil {
+		pon.Stats.countRx(len(pkt.Data()))
+	}
+}
@@ -0,0 +1,238 @@
+/*
+ * Copyright 2018-present Open Networking Foundation
+
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+
+ * http://www.apache.org/licenses/LICENSE-2.0
+
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package devices
+
+import (
+	"testing"
+
+	"github.com/google/gopacket"
+	"github.com/google/gopacket/layers"
+	omcisim "github.com/opencord/omci-sim"
+	"github.com/opencord/voltha-protos/v2/go/openolt"
+	"gotest.tools/assert"
+)
+
+func newTestPacket(t *testing.T, ethType layers.EthernetType, vlans ...uint16) gopacket.Packet {
+	return gopacket.NewPacket(createTestPacket(t, ethType, vlans...), layers.LayerTypeEthernet, gopacket.Default)
+}
+
+func getVlans(pkt gopacket.Packet) []uint16 {
+	vlans := []uint16{}
+	for _, tag := range getPacketFields(pkt).vlans {
+		vlans = append(vlans, tag.VLANIdentifier)
+	}
+	return vlans
+}
+
+func Test_ClassifierMatches(t *testing.T) {
+	untagged := getPacketFields(newTestPacket(t, layers.EthernetTypeEAPOL))
+	single := getPacketFields(newTestPacket(t, layers.EthernetTypeEAPOL, 4091))
+	double := getPacketFields(newTestPacket(t, layers.EthernetTypeIPv4, 900, 901))
+
+	assert.Assert(t, classifierMatches(nil, untagged))
+	assert.Assert(t, classifierMatches(&openolt.Classifier{EthType: 0x888e}, untagged))
+	assert.Assert(t, !classifierMatches(&openolt.Classifier{EthType: 0x888e}, double))
+
+	assert.Assert(t, classifierMatches(&openolt.Classifier{PktTagType: "untagged"}, untagged))
+	assert.Assert(t, !classifierMatches(&openolt.Classifier{PktTagType: "untagged"}, single))
+	assert.Assert(t, classifierMatches(&openolt.Classifier{PktTagType: "single_tag", OVid: 4091}, single))
+	assert.Assert(t, !classifierMatches(&openolt.Classifier{PktTagType: "single_tag"}, double))
+	assert.Assert(t, classifierMatches(&openolt.Classifier{PktTagType: "double_tag"}, double))
+
+	assert.Assert(t, classifierMatches(&openolt.Classifier{OVid: 900, IVid: 901}, double))
+	assert.Assert(t, !classifierMatches(&openolt.Classifier{OVid: 900, IVid: 902}, double))
+	assert.Assert(t, !classifierMatches(&openolt.Classifier{IVid: 901}, single))
+
+	// the p-bits out of range match any packet
+	assert.Assert(t, classifierMatches(&openolt.Classifier{OPbits: 0xff}, double))
+	assert.Assert(t, !classifierMatches(&openolt.Classifier{OPbits: 3}, double))
+}
+
+func Test_ApplyFlowActions(t *testing.T) {
+	pkt := newTestPacket(t, layers.EthernetTypeIPv4, 901)
+
+	// push
+	out, err := applyFlowActions(openolt.Flow{Action: &openolt.Action{
+		Cmd:  &openolt.ActionCmd{AddOuterTag: true},
+		OVid: 900,
+	}}, pkt)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, getVlans(out), []uint16{900, 901})
+	assert.Equal(t, packetEthType(out), uint32(0x0800))
+
+	// pop
+	out, err = applyFlowActions(openolt.Flow{Action: &openolt.Action{
+		Cmd: &openolt.ActionCmd{RemoveOuterTag: true},
+	}}, out)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, getVlans(out), []uint16{901})
+
+	// translate
+	out, err = applyFlowActions(openolt.Flow{Action: &openolt.Action{
+		Cmd:  &openolt.ActionCmd{RemoveOuterTag: true, AddOuterTag: true},
+		OVid: 100,
+	}}, out)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, getVlans(out), []uint16{100})
+
+	_, err = applyFlowActions(openolt.Flow{Action: &openolt.Action{
+		Cmd: &openolt.ActionCmd{RemoveOuterTag: true},
+	}}, newTestPacket(t, layers.EthernetTypeIPv4))
+	assert.Error(t, err, "no-dot1q-layer-in-packet")
+}
+
+func Test_Olt_ForwardNniPacket(t *testing.T) {
+	olt := createMockOlt(1, 1)
+	onu := olt.Pons[0].Onus[0]
+	nni := &NniPort{ID: 0}
+	olt.Nnis = []*NniPort{nni}
+
+	olt.Flows[FlowKey{ID: 1, Direction: "downstream"}] = openolt.Flow{
+		AccessIntfId: 0, OnuId: int32(onu.ID), UniId: 0, FlowId: 1, FlowType: "downstream",
+		Classifier: &openolt.Classifier{OVid: 900, IVid: 901},
+		Action:     &openolt.Action{Cmd: &openolt.ActionCmd{RemoveOuterTag: true}},
+		Priority:   1000,
+	}
+	olt.Flows[FlowKey{ID: 2, Direction: "downstream"}] = openolt.Flow{
+		AccessIntfId: -1, OnuId: -1, UniId: -1, FlowId: 2, FlowType: "downstream",
+		Classifier: &openolt.Classifier{EthType: uint32(layers.EthernetTypeLinkLayerDiscovery)},
+		Action:     &openolt.Action{Cmd: &openolt.ActionCmd{TrapToHost: true}},
+		Priority:   10000,
+	}
+
+	stream := &mockPktStream{
+		channel: make(chan *openolt.PacketIndication, 10),
+	}
+
+	// the data flow pops the S-Tag and delivers the packet to the ONU
+	olt.forwardNniPacket(nni, newTestPacket(t, layers.EthernetTypeIPv4, 900, 901), stream)
+	assert.Equal(t, len(onu.Channel), 1)
+	msg := <-onu.Channel
+	assert.Equal(t, msg.Type, OnuPacketOut)
+	pktMsg, _ := msg.Data.(OnuPacketMessage)
+	assert.DeepEqual(t, getVlans(pktMsg.Packet), []uint16{901})
+	assert.Equal(t, pktMsg.OnuId, onu.ID)
+
+	// the trapped packets go to VOLTHA
+	olt.forwardNniPacket(nni, newTestPacket(t, layers.EthernetTypeLinkLayerDiscovery), stream)
+	assert.Equal(t, len(stream.channel), 1)
+	pktInd := <-stream.channel
+	assert.Equal(t, pktInd.IntfType, "nni")
+
+	// the packets without a flow are dropped
+	olt.forwardNniPacket(nni, newTestPacket(t, layers.EthernetTypeIPv4, 900, 902), stream)
+	assert.Equal(t, len(onu.Channel), 0)
+	assert.Equal(t, len(stream.channel), 0)
+
+	assert.Equal(t, nni.Stats.Get().RxPackets, uint64(3))
+	assert.Equal(t, olt.GetFlowStats(FlowKey{ID: 1, Direction: "downstream"}).TxPackets, uint64(1))
+	assert.Equal(t, olt.GetFlowStats(FlowKey{ID: 2, Direction: "downstream"}).TxPackets, uint64(1))
+}
+
+func Test_Olt_ForwardUniPacket(t *testing.T) {
+	olt := createMockOlt(1, 1)
+	onu := olt.Pons[0].Onus[0]
+	uni := onu.UniPorts[0]
+	nni := &NniPort{ID: 0, nniVeth: "nni"}
+	olt.Nnis = []*NniPort{nni}
+
+	written := [][]byte{}
+	_writeOnVeth := writeOnVeth
+	defer func() { writeOnVeth = _writeOnVeth }()
+	writeOnVeth = func(vethName string, data []byte) error {
+		assert.Equal(t, vethName, "nni")
+		written = append(written, data)
+		return nil
+	}
+
+	olt.Flows[FlowKey{ID: 1, Direction: "upstream"}] = openolt.Flow{
+		AccessIntfId: 0, OnuId: int32(onu.ID), UniId: 0, FlowId: 1, FlowType: "upstream", NetworkIntfId: 0,
+		Classifier: &openolt.Classifier{OVid: 901},
+		Action:     &openolt.Action{Cmd: &openolt.ActionCmd{AddOuterTag: true}, OVid: 900},
+		Priority:   1000,
+	}
+	olt.Flows[FlowKey{ID: 2, Direction: "upstream"}] = openolt.Flow{
+		AccessIntfId: 0, OnuId: int32(onu.ID), UniId: 0, FlowId: 2, FlowType: "upstream", GemportId: 1024,
+		Classifier: &openolt.Classifier{EthType: uint32(layers.EthernetTypeEAPOL), OVid: 901},
+		Action:     &openolt.Action{Cmd: &openolt.ActionCmd{TrapToHost: true}},
+		Priority:   10000,
+	}
+
+	stream := &mockPktStream{
+		channel: make(chan *openolt.PacketIndication, 10),
+	}
+
+	// the data flow pushes the S-Tag and sends the packet out of the NNI
+	err := olt.forwardUniPacket(uni, newTestPacket(t, layers.EthernetTypeIPv4, 901), stream)
+	assert.NilError(t, err)
+	assert.Equal(t, len(written), 1)
+	pkt := gopacket.NewPacket(written[0], layers.LayerTypeEthernet, gopacket.Default)
+	assert.DeepEqual(t, getVlans(pkt), []uint16{900, 901})
+	assert.Equal(t, nni.Stats.Get().TxPackets, uint64(1))
+
+	// the higher priority flow traps the packet
+	err = olt.forwardUniPacket(uni, newTestPacket(t, layers.EthernetTypeEAPOL, 901), stream)
+	assert.NilError(t, err)
+	pktInd := <-stream.channel
+	assert.Equal(t, pktInd.IntfType, "pon")
+	assert.Equal(t, pktInd.GemportId, uint32(1024))
+	assert.Equal(t, pktInd.FlowId, uint32(2))
+
+	err = olt.forwardUniPacket(uni, newTestPacket(t, layers.EthernetTypeIPv4), stream)
+	assert.Error(t, err, "no-flow-matches-packet-from-uni-0-on-onu-BBSM00000000")
+	assert.Equal(t, len(written), 1)
+}
+
+func Test_Olt_ForwardUniPacket_OmciPm(t *testing.T) {
+	olt := createMockOlt(1, 1)
+	onu := olt.Pons[0].Onus[0]
+	uni := onu.UniPorts[0]
+	nni := &NniPort{ID: 0, nniVeth: "nni"}
+	olt.Nnis = []*NniPort{nni}
+
+	_writeOnVeth := writeOnVeth
+	defer func() { writeOnVeth = _writeOnVeth }()
+	writeOnVeth = func(vethName string, data []byte) error {
+		return nil
+	}
+
+	olt.Flows[FlowKey{ID: 1, Direction: "upstream"}] = openolt.Flow{
+		AccessIntfId: 0, OnuId: int32(onu.ID), UniId: 0, FlowId: 1, FlowType: "upstream", NetworkIntfId: 0, GemportId: 1024,
+		Classifier: &openolt.Classifier{OVid: 901},
+		Action:     &openolt.Action{Cmd: &openolt.ActionCmd{AddOuterTag: true}, OVid: 900},
+		Priority:   1000,
+	}
+
+	createOmciBridgePort(t, onu, 0x0101, uni)
+	_, _ = onu.omciResponse(omciRequest(omcisim.Create, omciEthFrameUpPmClass, 0x0101, 0x00, 0x00))
+	_, _ = onu.omciResponse(omciRequest(omcisim.Create, omciGemPortPmClass, 1024, 0x00, 0x00))
+
+	stream := &mockPktStream{
+		channel: make(chan *openolt.PacketIndication, 10),
+	}
+
+	// the data the UNI sends upstream is counted by the ONU, even if the OLT drops it
+	err := olt.forwardUniPacket(uni, newTestPacket(t, layers.EthernetTypeIPv4, 901), stream)
+	assert.NilError(t, err)
+	err = olt.forwardUniPacket(uni, newTestPacket(t, layers.EthernetTypeIPv4), stream)
+	assert.Error(t, err, "no-flow-matches-packet-from-uni-0-on-onu-BBSM00000000")
+
+	resp, _ := onu.omciResponse(omciRequest(omcisim.GetCurrentData, omciEthFrameUpPmClass, 0x0101, 0x08, 0x00))
+	assert.Equal(t, omciPmCounter(resp, 11), uint32(2))
+	resp, _ = onu.omciResponse(omciRequest(omcisim.GetCurrentData, omciGemPortPmClass, 1024, 0x20, 0x00))
+	assert.Equal(t, omciPmCounter(resp, 11), uint32(1))
+}
@@ -0,0 +1,205 @@
+/*
+ * Copyright 2018-present Open Networking Foundation
+
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+
+ * http://www.apache.org/licenses/LICENSE-2.0
+
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package devices
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"path"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/opencord/voltha-protos/v2/go/openolt"
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// FaultRuleAllMethods matches all the OpenOLT methods that don't have a rule of their own
+const FaultRuleAllMethods = "*"
+
+// DefaultFaultDropTimeout is the time a dropped call waits for, if neither the rule nor the caller set a shorter one
+const DefaultFaultDropTimeout = 1 * time.Minute
+
+// FaultRule describes the faults injected in the calls to an OpenOLT method
+type FaultRule struct {
+	Method  string        // the OpenOLT method, eg: FlowAdd, or FaultRuleAllMethods
+	Code    codes.Code    // the gRPC error returned to the failing calls, codes.OK to not return errors
+	Rate    float64       // the fraction of the calls that fail (or are dropped), between 0 and 1, 0 to only add the latency
+	Latency time.Duration // added to every call
+	Jitter  time.Duration // a random delay up to Jitter is added to Latency
+	Drop    bool          // the failing calls are never answered, the caller gets a deadline exceeded
+
+	// DropTimeout is the time after which a dropped call fails with a deadline exceeded,
+	// if the caller has no earlier deadline. DefaultFaultDropTimeout if not set
+	DropTimeout time.Duration
+}
+
+// openOltMethods contains the names of the methods of the OpenOLT gRPC service,
+// the value is true for the unary methods and false for the streaming ones (EnableIndication)
+var openOltMethods = func() map[string]bool {
+	methods := map[string]bool{}
+	ctxType := reflect.TypeOf((*context.Context)(nil)).Elem()
+	t := reflect.TypeOf((*openolt.OpenoltServer)(nil)).Elem()
+	for i := 0; i < t.NumMethod(); i++ {
+		m := t.Method(i)
+		methods[m.Name] = m.Type.NumIn() > 0 && m.Type.In(0) == ctxType
+	}
+	return methods
+}()
+
+// ParseFaultCode returns the gRPC code with the given name, eg: Unavailable or UNAVAILABLE
+func ParseFaultCode(name string) (codes.Code, error) {
+	normalized := strings.ToLower(strings.Replace(name, "_", "", -1))
+	for c := codes.OK; c <= codes.Unauthenticated; c++ {
+		if strings.ToLower(c.String()) == normalized {
+			return c, nil
+		}
+	}
+	return codes.OK, errors.New(fmt.Sprintf("invalid-fault-error-code-%s", name))
+}
+
+// Validate checks the values of a FaultRule
+func (r FaultRule) Validate() error {
+	if r.Method != FaultRuleAllMethods {
+		unary, ok := openOltMethods[r.Method]
+		if !ok {
+			return errors.New(fmt.Sprintf("unknown-openolt-method-%s", r.Method))
+		}
+		// NOTE the faults are injected by a unary interceptor, they would be ignored on a stream
+		if !unary {
+			return errors.New(fmt.Sprintf("streaming-openolt-method-%s-not-supported", r.Method))
+		}
+	}
+	if r.Code > codes.Unauthenticated {
+		return errors.New(fmt.Sprintf("invalid-fault-error-code-%d", r.Code))
+	}
+	if r.Rate < 0 || r.Rate > 1 {
+		return errors.New(fmt.Sprintf("invalid-fault-rate-%g", r.Rate))
+	}
+	if r.Latency < 0 || r.Jitter < 0 {
+		return errors.New(fmt.Sprintf("invalid-fault-latency-%s-jitter-%s", r.Latency, r.Jitter))
+	}
+	if r.DropTimeout < 0 {
+		return errors.New(fmt.Sprintf("invalid-fault-drop-timeout-%s", r.DropTimeout))
+	}
+	return nil
+}
+
+// SetFaultRules replaces the faults injected in the OpenOLT calls, an empty list removes them all.
+// A rule for a method takes precedence over a rule for FaultRuleAllMethods, if more rules
+// match the same method only the first one applies
+func (o *OltDevice) SetFaultRules(rules []FaultRule) error {
+	for _, rule := range rules {
+		if err := rule.Validate(); err != nil {
+			return err
+		}
+	}
+
+	o.faultRulesLock.Lock()
+	defer o.faultRulesLock.Unlock()
+	o.faultRules = make([]FaultRule, len(rules))
+	copy(o.faultRules, rules)
+
+	oltLogger.WithFields(log.Fields{
+		"oltId": o.ID,
+		"rules": rules,
+	}).Info("Fault rules updated")
+	return nil
+}
+
+// GetFaultRules returns the faults injected in the OpenOLT calls
+func (o *OltDevice) GetFaultRules() []FaultRule {
+	o.faultRulesLock.RLock()
+	defer o.faultRulesLock.RUnlock()
+
+	res := make([]FaultRule, len(o.faultRules))
+	copy(res, o.faultRules)
+	return res
+}
+
+// getFaultRule returns the rule that applies to an OpenOLT method, if any
+func (o *OltDevice) getFaultRule(method string) (FaultRule, bool) {
+	o.faultRulesLock.RLock()
+	defer o.faultRulesLock.RUnlock()
+
+	for _, rule := range o.faultRules {
+		if rule.Method == method {
+			return rule, true
+		}
+	}
+	for _, rule := range o.faultRules {
+		if rule.Method == FaultRuleAllMethods {
+			return rule, true
+		}
+	}
+	return FaultRule{}, false
+}
+
+func (o *OltDevice) faultUnaryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	method := path.Base(info.FullMethod)
+	rule, ok := o.getFaultRule(method)
+	if !ok {
+		return handler(ctx, req)
+	}
+
+	delay := rule.Latency
+	if rule.Jitter > 0 {
+		delay += time.Duration(rand.Int63n(int64(rule.Jitter)))
+	}
+	if delay > 0 {
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	if rule.Rate == 0 || rand.Float64() >= rule.Rate {
+		return handler(ctx, req)
+	}
+
+	if rule.Drop {
+		oltLogger.WithFields(log.Fields{
+			"oltId":  o.ID,
+			"Method": method,
+		}).Debug("Dropping OpenOLT call")
+		timeout := rule.DropTimeout
+		if timeout == 0 {
+			timeout = DefaultFaultDropTimeout
+		}
+		select {
+		case <-time.After(timeout):
+			return nil, status.Errorf(codes.DeadlineExceeded, "injected-fault-on-%s", method)
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	if rule.Code != codes.OK {
+		oltLogger.WithFields(log.Fields{
+			"oltId":  o.ID,
+			"Method": method,
+			"Code":   rule.Code,
+		}).Debug("Failing OpenOLT call")
+		return nil, status.Errorf(rule.Code, "injected-fault-on-%s", method)
+	}
+	return handler(ctx, req)
+}
@@ -0,0 +1,147 @@
+/*
+ * Copyright 2018-present Open Networking Foundation
+
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+
+ * http://www.apache.org/licenses/LICENSE-2.0
+
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package devices
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"gotest.tools/assert"
+)
+
+func callWithFaults(olt *OltDevice, ctx context.Context, method string) (interface{}, error) {
+	info := &grpc.UnaryServerInfo{FullMethod: "/openolt.Openolt/" + method}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return "answered", nil
+	}
+	return olt.faultUnaryInterceptor(ctx, nil, info, handler)
+}
+
+func Test_ParseFaultCode(t *testing.T) {
+	for _, name := range []string{"Unavailable", "UNAVAILABLE", "unavailable"} {
+		code, err := ParseFaultCode(name)
+		assert.NilError(t, err)
+		assert.Equal(t, code, codes.Unavailable)
+	}
+
+	code, err := ParseFaultCode("DEADLINE_EXCEEDED")
+	assert.NilError(t, err)
+	assert.Equal(t, code, codes.DeadlineExceeded)
+
+	_, err = ParseFaultCode("Broken")
+	assert.Error(t, err, "invalid-fault-error-code-Broken")
+}
+
+func Test_Olt_SetFaultRules_validation(t *testing.T) {
+	olt := createMockOlt(1, 1)
+
+	err := olt.SetFaultRules([]FaultRule{{Method: "Explode", Rate: 1}})
+	assert.Error(t, err, "unknown-openolt-method-Explode")
+
+	err = olt.SetFaultRules([]FaultRule{{Method: "FlowAdd", Rate: 2}})
+	assert.Error(t, err, "invalid-fault-rate-2")
+
+	err = olt.SetFaultRules([]FaultRule{{Method: "FlowAdd", Rate: 1, Latency: -1}})
+	assert.Error(t, err, "invalid-fault-latency--1ns-jitter-0s")
+
+	err = olt.SetFaultRules([]FaultRule{{Method: "FlowAdd", Rate: 1, Drop: true, DropTimeout: -1}})
+	assert.Error(t, err, "invalid-fault-drop-timeout--1ns")
+
+	// the faults are not injected in the indication stream
+	err = olt.SetFaultRules([]FaultRule{{Method: "EnableIndication", Code: codes.Unavailable, Rate: 1}})
+	assert.Error(t, err, "streaming-openolt-method-EnableIndication-not-supported")
+
+	// an invalid rule does not change the installed ones
+	assert.Equal(t, len(olt.GetFaultRules()), 0)
+
+	err = olt.SetFaultRules([]FaultRule{{Method: "FlowAdd", Code: codes.Unavailable, Rate: 1}})
+	assert.NilError(t, err)
+	assert.Equal(t, len(olt.GetFaultRules()), 1)
+
+	err = olt.SetFaultRules([]FaultRule{})
+	assert.NilError(t, err)
+	assert.Equal(t, len(olt.GetFaultRules()), 0)
+}
+
+func Test_Olt_FaultInterceptor_error(t *testing.T) {
+	olt := createMockOlt(1, 1)
+
+	err := olt.SetFaultRules([]FaultRule{
+		{Method: FaultRuleAllMethods, Code: codes.Internal, Rate: 1},
+		{Method: "FlowAdd", Code: codes.Unavailable, Rate: 1},
+		// no call fails with a rate of 0
+		{Method: "HeartbeatCheck", Code: codes.Unavailable, Rate: 0},
+	})
+	assert.NilError(t, err)
+
+	// the rule for the method takes precedence over the one for all the methods
+	_, err = callWithFaults(&olt, context.TODO(), "FlowAdd")
+	assert.Equal(t, status.Code(err), codes.Unavailable)
+
+	_, err = callWithFaults(&olt, context.TODO(), "ActivateOnu")
+	assert.Equal(t, status.Code(err), codes.Internal)
+
+	res, err := callWithFaults(&olt, context.TODO(), "HeartbeatCheck")
+	assert.NilError(t, err)
+	assert.Equal(t, res, "answered")
+}
+
+func Test_Olt_FaultInterceptor_latency(t *testing.T) {
+	olt := createMockOlt(1, 1)
+
+	err := olt.SetFaultRules([]FaultRule{{Method: "OmciMsgOut", Latency: 50 * time.Millisecond, Jitter: 10 * time.Millisecond}})
+	assert.NilError(t, err)
+
+	start := time.Now()
+	res, err := callWithFaults(&olt, context.TODO(), "OmciMsgOut")
+	assert.NilError(t, err)
+	assert.Equal(t, res, "answered")
+	assert.Assert(t, time.Since(start) >= 50*time.Millisecond)
+
+	// the other methods are not delayed
+	start = time.Now()
+	_, err = callWithFaults(&olt, context.TODO(), "FlowAdd")
+	assert.NilError(t, err)
+	assert.Assert(t, time.Since(start) < 50*time.Millisecond)
+}
+
+func Test_Olt_FaultInterceptor_drop(t *testing.T) {
+	olt := createMockOlt(1, 1)
+
+	err := olt.SetFaultRules([]FaultRule{{Method: "ActivateOnu", Rate: 1, Drop: true}})
+	assert.NilError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.TODO(), 50*time.Millisecond)
+	defer cancel()
+	_, err = callWithFaults(&olt, ctx, "ActivateOnu")
+	assert.Equal(t, err, context.DeadlineExceeded)
+}
+
+func Test_Olt_FaultInterceptor_drop_timeout(t *testing.T) {
+	olt := createMockOlt(1, 1)
+
+	err := olt.SetFaultRules([]FaultRule{{Method: "ActivateOnu", Rate: 1, Drop: true, DropTimeout: 50 * time.Millisecond}})
+	assert.NilError(t, err)
+
+	// the call is released even if the caller has no deadline
+	_, err = callWithFaults(&olt, context.TODO(), "ActivateOnu")
+	assert.Equal(t, status.Code(err), codes.DeadlineExceeded)
+}
@@ -42,6 +42,21 @@ const (
 	SendEapolFlow  MessageType = 12
 	SendDhcpFlow   MessageType = 13
 	OnuPacketIn    MessageType = 14
+
+	// Alarms
+	AlarmIndication MessageType = 15
+
+	// Port and flow statistics
+	StatisticsIndication MessageType = 16
+
+	// FlushIndications is a barrier, the OLT closes the channel in its data once the previous messages have been sent
+	FlushIndications MessageType = 17
+
+	// DelayedOmciResponse is an OMCI response delayed by an OmciFaultRule, the ONU sends it once the latency expires
+	DelayedOmciResponse MessageType = 18
+
+	// AutonomousOmci is an Alarm Notification or an Attribute Value Change the ONU sends to VOLTHA
+	AutonomousOmci MessageType = 19
 )
 
 func (m MessageType) String() string {
@@ -61,6 +76,11 @@ func (m MessageType) String() string {
 		"SendEapolFlow",
 		"SendDhcpFlow",
 		"OnuPacketIn",
+		"AlarmIndication",
+		"StatisticsIndication",
+		"FlushIndications",
+		"DelayedOmciResponse",
+		"AutonomousOmci",
 	}
 	return names[m]
 }
@@ -97,9 +117,18 @@ type OnuIndicationMessage struct {
 }
 
 type OmciMessage struct {
-	OnuSN   *openolt.SerialNumber
-	OnuID   uint32
-	omciMsg *openolt.OmciMsg
+	OnuSN      *openolt.SerialNumber
+	OnuID      uint32
+	omciMsg    *openolt.OmciMsg
+	impairment *omciImpairment // set if the response has to be impaired, see OmciFaultRule
+}
+
+type DelayedOmciResponseMessage struct {
+	Pkt []byte
+}
+
+type AutonomousOmciMessage struct {
+	Pkt []byte
 }
 
 type OmciIndicationMessage struct {
@@ -117,13 +146,16 @@ type OnuFlowUpdateMessage struct {
 type PacketMessage struct {
 	PonPortID uint32
 	OnuID     uint32
+	UniID     uint32
 }
 
 type OnuPacketMessage struct {
-	IntfId uint32
-	OnuId  uint32
-	Packet gopacket.Packet
-	Type   packetHandlers.PacketType
+	IntfId    uint32
+	OnuId     uint32
+	PortNo    uint32
+	GemPortId uint32 // 0 if not known
+	Packet    gopacket.Packet
+	Type      packetHandlers.PacketType
 }
 
 type DyingGaspIndicationMessage struct {
@@ -132,6 +164,11 @@ type DyingGaspIndicationMessage struct {
 	Status    string
 }
 
+type AlarmIndicationMessage struct {
+	AlarmType string
+	Alarm     *openolt.AlarmIndication
+}
+
 type OperState int
 
 const (
@@ -18,6 +18,7 @@ package devices
 
 import (
 	"bytes"
+	"fmt"
 	"os/exec"
 
 	"github.com/google/gopacket"
@@ -54,19 +55,38 @@ type NniPort struct {
 	ID           uint32
 	nniVeth      string
 	upstreamVeth string
+	pktInChannel chan *types.PacketMsg // packets coming in from the NNI and going to VOLTHA
+	handle       *pcap.Handle          // handle on the NNI interface, close it when shutting down the NNI channel
 
 	// PON Attributes
 	OperState *fsm.FSM
 	Type      string
+	Stats     PacketStats
 }
 
-func CreateNNI(olt *OltDevice) (NniPort, error) {
+// nniVethNames returns the names of the veth pair backing an NNI port,
+// the first NNI keeps the "nni" and "upstream" names, the others have the NNI ID appended.
+// The NNIs of the OLTs other than OLT 0 also have the OLT ID in the name (eg: nni1-0/upstream1-0)
+func nniVethNames(oltId int, id uint32) (string, string) {
+	if oltId != 0 {
+		return fmt.Sprintf("nni%d-%d", oltId, id), fmt.Sprintf("upstream%d-%d", oltId, id)
+	}
+	if id == 0 {
+		return "nni", "upstream"
+	}
+	return fmt.Sprintf("nni%d", id), fmt.Sprintf("upstream%d", id)
+}
+
+func CreateNNI(olt *OltDevice, id uint32) (NniPort, error) {
+	nniVeth, upstreamVeth := nniVethNames(olt.ID, id)
 	nniPort := NniPort{
-		ID:           uint32(0),
-		nniVeth:      "nni",
-		upstreamVeth: "upstream",
+		ID:           id,
+		nniVeth:      nniVeth,
+		upstreamVeth: upstreamVeth,
 		OperState: getOperStateFSM(func(e *fsm.Event) {
-			oltLogger.Debugf("Changing NNI OperState from %s to %s", e.Src, e.Dst)
+			oltLogger.WithFields(log.Fields{
+				"ID": id,
+			}).Debugf("Changing NNI OperState from %s to %s", e.Src, e.Dst)
 		}),
 		Type: "nni",
 	}
@@ -74,52 +94,41 @@ func CreateNNI(olt *OltDevice) (NniPort, error) {
 	return nniPort, nil
 }
 
-// sendNniPacket will send a packet out of the NNI interface.
-// We will send upstream only DHCP packets and drop anything else
+// sendNniPacket sends a packet VOLTHA sends to the NNI out of the NNI interface,
+// the DHCP packets lose their tags as the DHCP server on the upstream veth expects them untagged
 func (n *NniPort) sendNniPacket(packet gopacket.Packet) error {
-	isDhcp := packetHandlers.IsDhcpPacket(packet)
-	isLldp := packetHandlers.IsLldpPacket(packet)
-
-	if isDhcp == false && isLldp == false {
-		nniLogger.WithFields(log.Fields{
-			"packet": packet,
-		}).Trace("Dropping NNI packet as it's not DHCP")
-		return nil
-	}
-
-	if isDhcp {
-		packet, err := packetHandlers.PopDoubleTag(packet)
+	if packetHandlers.IsDhcpPacket(packet) {
+		untagged, err := packetHandlers.PopDoubleTag(packet)
 		if err != nil {
 			nniLogger.WithFields(log.Fields{
 				"packet": packet,
 			}).Errorf("Can't remove double tags from packet: %v", err)
 			return err
 		}
+		packet = untagged
+	}
+	return n.writePacket(packet)
+}
 
-		handle, err := getVethHandler(n.nniVeth)
-		if err != nil {
-			return err
-		}
-
-		err = handle.WritePacketData(packet.Data())
-		if err != nil {
-			nniLogger.WithFields(log.Fields{
-				"packet": packet,
-			}).Errorf("Failed to send packet out of the NNI: %s", err)
-			return err
-		}
-
-		nniLogger.Infof("Sent packet out of NNI")
-	} else if isLldp {
-		// TODO rework this when BBSim supports data-plane packets
-		nniLogger.Trace("Received LLDP Packet, ignoring it")
+// writePacket writes a packet on the NNI interface
+func (n *NniPort) writePacket(packet gopacket.Packet) error {
+	if err := writeOnVeth(n.nniVeth, packet.Data()); err != nil {
+		nniLogger.WithFields(log.Fields{
+			"IntfId": n.ID,
+			"packet": packet,
+		}).Errorf("Failed to send packet out of the NNI: %s", err)
+		return err
 	}
+
+	nniLogger.WithFields(log.Fields{
+		"IntfId": n.ID,
+	}).Tracef("Sent packet out of NNI")
 	return nil
 }
 
-//createNNIBridge will create a veth bridge to fake the connection between the NNI port
+//createNNIPair will create a veth pair to fake the connection between the NNI port
 //and something upstream, in this case a DHCP server.
-//It is also responsible to start the DHCP server itself
+//The DHCP server is started separately as it listens on all the NNI interfaces
 func createNNIPair(executor Executor, olt *OltDevice, nniPort *NniPort) error {
 
 	if err := executor.Command("ip", "link", "add", nniPort.nniVeth, "type", "veth", "peer", "name", nniPort.upstreamVeth).Run(); err != nil {
@@ -135,12 +144,6 @@ func createNNIPair(executor Executor, olt *OltDevice, nniPort *NniPort) error {
 		return err
 	}
 
-	// TODO should be moved out of this function in case there are multiple NNI interfaces.
-	// Only one DHCP server should be running and listening on all NNI interfaces
-	if err := startDHCPServer(nniPort.upstreamVeth, dhcpServerIp); err != nil {
-		return err
-	}
-
 	return nil
 }
 
@@ -161,6 +164,39 @@ func (n *NniPort) NewVethChan() (chan *types.PacketMsg, *pcap.Handle, error) {
 	return ch, handle, err
 }
 
+// openChannel starts listening for packets on the NNI interface,
+// if that fails the NNI still gets a channel so that the OLT can process it
+func (n *NniPort) openChannel() {
+	n.pktInChannel = make(chan *types.PacketMsg, 1024)
+	n.handle = nil
+
+	ch, handle, err := n.NewVethChan()
+	if err != nil {
+		nniLogger.WithFields(log.Fields{
+			"IntfId": n.ID,
+		}).Errorf("Error getting NNI channel: %v", err)
+		return
+	}
+
+	nniLogger.WithFields(log.Fields{
+		"Type":      n.Type,
+		"IntfId":    n.ID,
+		"OperState": n.OperState.Current(),
+	}).Info("NNI Channel created")
+	n.pktInChannel = ch
+	n.handle = handle
+}
+
+// closeChannel stops listening on the NNI interface and terminates the goroutine processing its packets
+func (n *NniPort) closeChannel() {
+	if n.handle != nil {
+		n.handle.Close()
+	}
+	if n.pktInChannel != nil {
+		close(n.pktInChannel)
+	}
+}
+
 // setVethUp is responsible to activate a virtual interface
 func setVethUp(executor Executor, vethName string) error {
 	if err := executor.Command("ip", "link", "set", vethName, "up").Run(); err != nil {
@@ -170,22 +206,27 @@ func setVethUp(executor Executor, vethName string) error {
 	return nil
 }
 
-var startDHCPServer = func(upstreamVeth string, dhcpServerIp string) error {
-	// TODO the DHCP server should support multiple interfaces
-	if err := exec.Command("ip", "addr", "add", dhcpServerIp, "dev", upstreamVeth).Run(); err != nil {
-		nniLogger.Errorf("Couldn't assing ip %s to interface %s: %v", dhcpServerIp, upstreamVeth, err)
-		return err
-	}
+// startDHCPServer starts a single DHCP server listening on all the upstream interfaces
+var startDHCPServer = func(upstreamVeths []string, dhcpServerIp string) error {
+	for _, upstreamVeth := range upstreamVeths {
+		if err := exec.Command("ip", "addr", "add", dhcpServerIp, "dev", upstreamVeth).Run(); err != nil {
+			nniLogger.Errorf("Couldn't assing ip %s to interface %s: %v", dhcpServerIp, upstreamVeth, err)
+			return err
+		}
 
-	if err := setVethUp(executor, upstreamVeth); err != nil {
-		return err
+		if err := setVethUp(executor, upstreamVeth); err != nil {
+			return err
+		}
 	}
 
 	dhcp := "/usr/local/bin/dhcpd"
 	conf := "/etc/dhcp/dhcpd.conf" // copied in the container from configs/dhcpd.conf
 	logfile := "/tmp/dhcplog"
 	var stderr bytes.Buffer
-	cmd := exec.Command(dhcp, "-cf", conf, upstreamVeth, "-tf", logfile, "-4")
+	args := []string{"-cf", conf}
+	args = append(args, upstreamVeths...)
+	args = append(args, "-tf", logfile, "-4")
+	cmd := exec.Command(dhcp, args...)
 	cmd.Stderr = &stderr
 	err := cmd.Run()
 	if err != nil {
@@ -211,6 +252,15 @@ func getVethHandler(vethName string) (*pcap.Handle, error) {
 	return handle, nil
 }
 
+var writeOnVeth = func(vethName string, data []byte) error {
+	handle, err := getVethHandler(vethName)
+	if err != nil {
+		return err
+	}
+	defer handle.Close()
+	return handle.WritePacketData(data)
+}
+
 var listenOnVeth = func(vethName string) (chan *types.PacketMsg, *pcap.Handle, error) {
 
 	handle, err := getVethHandler(vethName)
@@ -221,7 +271,9 @@ var listenOnVeth = func(vethName string) (chan *types.PacketMsg, *pcap.Handle, e
 	channel := make(chan *types.PacketMsg, 1024)
 
 	go func() {
-		nniLogger.Info("Start listening on NNI for packets")
+		nniLogger.WithFields(log.Fields{
+			"Veth": vethName,
+		}).Info("Start listening on NNI for packets")
 		packetSource := gopacket.NewPacketSource(handle, handle.LinkType())
 		for packet := range packetSource.Packets() {
 
@@ -238,7 +290,9 @@ var listenOnVeth = func(vethName string) (chan *types.PacketMsg, *pcap.Handle, e
 			}
 			channel <- &pkt
 		}
-		nniLogger.Info("Stop listening on NNI for packets")
+		nniLogger.WithFields(log.Fields{
+			"Veth": vethName,
+		}).Info("Stop listening on NNI for packets")
 	}()
 
 	return channel, handle, nil
@@ -18,11 +18,19 @@
 package devices
 
 import (
+	"context"
 	"errors"
-	"github.com/google/gopacket/pcap"
+	"net"
+	"sync"
 	"testing"
+	"time"
 
+	"github.com/google/gopacket"
+	"github.com/google/gopacket/layers"
+	"github.com/google/gopacket/pcap"
 	"github.com/opencord/bbsim/internal/bbsim/types"
+	"github.com/opencord/voltha-protos/v2/go/openolt"
+	"google.golang.org/grpc"
 	"gotest.tools/assert"
 )
 
@@ -48,14 +56,6 @@ func TestSetVethUpFail(t *testing.T) {
 
 func TestCreateNNIPair(t *testing.T) {
 
-	startDHCPServerCalled := false
-	_startDHCPServer := startDHCPServer
-	defer func() { startDHCPServer = _startDHCPServer }()
-	startDHCPServer = func(upstreamVeth string, dhcpServerIp string) error {
-		startDHCPServerCalled = true
-		return nil
-	}
-
 	listenOnVethCalled := false
 	_listenOnVeth := listenOnVeth
 	defer func() { listenOnVeth = _listenOnVeth }()
@@ -72,13 +72,149 @@ func TestCreateNNIPair(t *testing.T) {
 	nni := NniPort{}
 
 	err := createNNIPair(spy, &olt, &nni)
-	olt.nniPktInChannel, olt.nniHandle, _ = nni.NewVethChan()
+	nni.pktInChannel, nni.handle, _ = nni.NewVethChan()
 
 	assert.Equal(t, spy.CommandCallCount, 3)
-	assert.Equal(t, startDHCPServerCalled, true)
 	assert.Equal(t, listenOnVethCalled, true)
 	assert.Equal(t, err, nil)
-	assert.Assert(t, olt.nniPktInChannel != nil)
+	assert.Assert(t, nni.pktInChannel != nil)
+}
+
+func TestNniVethNames(t *testing.T) {
+	nni, upstream := nniVethNames(0, 0)
+	assert.Equal(t, nni, "nni")
+	assert.Equal(t, upstream, "upstream")
+
+	nni, upstream = nniVethNames(0, 2)
+	assert.Equal(t, nni, "nni2")
+	assert.Equal(t, upstream, "upstream2")
+
+	nni, upstream = nniVethNames(3, 0)
+	assert.Equal(t, nni, "nni3-0")
+	assert.Equal(t, upstream, "upstream3-0")
+}
+
+type mockPktStream struct {
+	grpc.ServerStream
+	channel chan *openolt.PacketIndication
+}
+
+func (s *mockPktStream) Send(ind *openolt.Indication) error {
+	if pktInd := ind.GetPktInd(); pktInd != nil {
+		s.channel <- pktInd
+	}
+	return nil
+}
+
+func TestProcessNniPacketIns_IntfId(t *testing.T) {
+	olt := createMockOlt(1, 1)
+	onu := olt.Pons[0].Onus[0]
+	onu.STag = 900
+	onu.UniPorts[0].CTag = 901
+
+	nni := &NniPort{
+		ID:           1,
+		pktInChannel: make(chan *types.PacketMsg, 1),
+	}
+	olt.Nnis = []*NniPort{{ID: 0}, nni}
+	olt.Flows[FlowKey{ID: 1, Direction: "downstream"}] = openolt.Flow{
+		AccessIntfId:  -1,
+		OnuId:         -1,
+		UniId:         -1,
+		FlowId:        1,
+		FlowType:      "downstream",
+		NetworkIntfId: 1,
+		Classifier:    &openolt.Classifier{EthType: uint32(layers.EthernetTypeIPv4)},
+		Action:        &openolt.Action{Cmd: &openolt.ActionCmd{TrapToHost: true}},
+	}
+
+	stream := &mockPktStream{
+		channel: make(chan *openolt.PacketIndication, 1),
+	}
+	ctx, cancel := context.WithCancel(context.TODO())
+	defer cancel()
+	wg := sync.WaitGroup{}
+	wg.Add(1)
+	go olt.processNniPacketIns(ctx, nni, stream, &wg)
+
+	eth := &layers.Ethernet{
+		SrcMAC:       net.HardwareAddr{0x0e, 0x00, 0x00, 0x00, 0x00, 0x01},
+		DstMAC:       onu.UniPorts[0].HwAddress,
+		EthernetType: layers.EthernetTypeIPv4,
+	}
+	buffer := gopacket.NewSerializeBuffer()
+	_ = gopacket.SerializeLayers(buffer, gopacket.SerializeOptions{}, eth, gopacket.Payload([]byte{0x01}))
+	nni.pktInChannel <- &types.PacketMsg{
+		Pkt: gopacket.NewPacket(buffer.Bytes(), layers.LayerTypeEthernet, gopacket.Default),
+	}
+
+	select {
+	case pktInd := <-stream.channel:
+		assert.Equal(t, pktInd.IntfType, "nni")
+		assert.Equal(t, pktInd.IntfId, uint32(1))
+	case <-time.After(time.Second):
+		t.Fatal("PktInd not received")
+	}
+}
+
+func TestUplinkPacketOut_UnknownNni(t *testing.T) {
+	olt := createMockOlt(1, 1)
+	olt.Nnis = []*NniPort{{ID: 0}}
+
+	_, err := olt.UplinkPacketOut(context.TODO(), &openolt.UplinkPacket{IntfId: 3})
+	assert.Equal(t, err.Error(), "Cannot find NniPort with id 3 in OLT 0")
+}
+
+func TestSendNniPacket(t *testing.T) {
+	nni := &NniPort{ID: 0, nniVeth: "nni"}
+
+	written := []gopacket.Packet{}
+	_writeOnVeth := writeOnVeth
+	defer func() { writeOnVeth = _writeOnVeth }()
+	writeOnVeth = func(vethName string, data []byte) error {
+		written = append(written, gopacket.NewPacket(data, layers.LayerTypeEthernet, gopacket.Default))
+		return nil
+	}
+
+	// the packets other than DHCP are sent as they are
+	lldp := gopacket.NewPacket(createTestPacket(t, layers.EthernetTypeLinkLayerDiscovery, 900), layers.LayerTypeEthernet, gopacket.Default)
+	err := nni.sendNniPacket(lldp)
+	assert.NilError(t, err)
+	assert.Equal(t, len(written), 1)
+	assert.Assert(t, written[0].Layer(layers.LayerTypeDot1Q) != nil)
+
+	// the DHCP packets lose their tags
+	eth := &layers.Ethernet{
+		SrcMAC:       net.HardwareAddr{0x0e, 0x00, 0x00, 0x00, 0x00, 0x01},
+		DstMAC:       layers.EthernetBroadcast,
+		EthernetType: layers.EthernetTypeDot1Q,
+	}
+	sTag := &layers.Dot1Q{VLANIdentifier: 900, Type: layers.EthernetTypeDot1Q}
+	cTag := &layers.Dot1Q{VLANIdentifier: 901, Type: layers.EthernetTypeIPv4}
+	ip := &layers.IPv4{
+		Version:  4,
+		TTL:      64,
+		Protocol: layers.IPProtocolUDP,
+		SrcIP:    net.IPv4zero,
+		DstIP:    net.IPv4bcast,
+	}
+	udp := &layers.UDP{SrcPort: 68, DstPort: 67}
+	_ = udp.SetNetworkLayerForChecksum(ip)
+	dhcp := &layers.DHCPv4{
+		Operation:    layers.DHCPOpRequest,
+		HardwareType: layers.LinkTypeEthernet,
+		HardwareLen:  6,
+		ClientHWAddr: eth.SrcMAC,
+	}
+	buffer := gopacket.NewSerializeBuffer()
+	err = gopacket.SerializeLayers(buffer, gopacket.SerializeOptions{FixLengths: true, ComputeChecksums: true}, eth, sTag, cTag, ip, udp, dhcp)
+	assert.NilError(t, err)
+
+	err = nni.sendNniPacket(gopacket.NewPacket(buffer.Bytes(), layers.LayerTypeEthernet, gopacket.Default))
+	assert.NilError(t, err)
+	assert.Equal(t, len(written), 2)
+	assert.Assert(t, written[1].Layer(layers.LayerTypeDot1Q) == nil)
+	assert.Assert(t, written[1].Layer(layers.LayerTypeDHCPv4) != nil)
 }
 
 type ExecutorSpy struct {
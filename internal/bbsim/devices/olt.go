@@ -20,8 +20,8 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"github.com/google/gopacket/pcap"
 	"net"
+	"sort"
 	"sync"
 	"time"
 
@@ -29,33 +29,44 @@ import (
 	"github.com/google/gopacket/layers"
 	"github.com/looplab/fsm"
 	"github.com/opencord/bbsim/internal/bbsim/packetHandlers"
-	bbsim "github.com/opencord/bbsim/internal/bbsim/types"
 	"github.com/opencord/bbsim/internal/common"
 	omcisim "github.com/opencord/omci-sim"
 	"github.com/opencord/voltha-protos/v2/go/openolt"
-	"github.com/opencord/voltha-protos/v2/go/tech_profile"
 	log "github.com/sirupsen/logrus"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/status"
 )
 
 var oltLogger = log.WithFields(log.Fields{
 	"module": "OLT",
 })
 
+// resource ranges reported to VOLTHA in GetDeviceInfo
+const (
+	onuIdStart     = 1
+	onuIdEnd       = 255
+	allocIdStart   = 1024
+	allocIdEnd     = 16383
+	gemportIdStart = 1024
+	gemportIdEnd   = 65535
+	flowIdStart    = 1
+	flowIdEnd      = 16383
+)
+
 type OltDevice struct {
 	sync.Mutex
 
 	// BBSIM Internals
-	ID              int
-	SerialNumber    string
-	NumNni          int
-	NumPon          int
-	NumOnuPerPon    int
-	InternalState   *fsm.FSM
-	channel         chan Message
-	nniPktInChannel chan *bbsim.PacketMsg // packets coming in from the NNI and going to VOLTHA
-	nniHandle       *pcap.Handle          // handle on the NNI interface, close it when shutting down the NNI channel
+	ID            int
+	SerialNumber  string
+	NumNni        int
+	NumPon        int
+	NumOnuPerPon  int
+	NumUniPerOnu  int
+	InternalState *fsm.FSM
+	channel       chan Message
 
 	Delay int
 
@@ -67,35 +78,172 @@ type OltDevice struct {
 
 	enableContext       context.Context
 	enableContextCancel context.CancelFunc
+	enableStream        openolt.Openolt_EnableIndicationServer // used to restart the ONUs when a PON port is enabled
+
+	// Flows installed by VOLTHA, keyed by FlowId and direction
+	Flows     map[FlowKey]openolt.Flow
+	flowStats map[FlowKey]*PacketStats
+	flowsLock sync.RWMutex
+
+	// HeartbeatSignature changes every time the OLT reboots, VOLTHA uses it to detect restarts
+	HeartbeatSignature uint32
+	heartbeatStopped   bool
+	heartbeatLock      sync.RWMutex
+
+	// protects the traffic schedulers and queues installed on the UNIs
+	trafficLock sync.RWMutex
+
+	// Options contains the configuration of this OLT (OpenOLT address, device info, reboot delay, ...)
+	Options   common.OltConfig
+	oltServer *grpc.Server
+
+	// defaults for the ONUs created at runtime, nextCTag is the C-Tag of the next ONU
+	sTag     int
+	nextCTag int
+	auth     bool
+	dhcp     bool
+	// serializes the ONUs created, deleted and moved at runtime
+	onusLock sync.Mutex
+
+	// hangChannel is set while the OLT is hung, the gRPC requests wait for it to be closed
+	hangChannel chan struct{}
+	hangLock    sync.RWMutex
+
+	// the faults injected in the OpenOLT calls, see SetFaultRules
+	faultRules     []FaultRule
+	faultRulesLock sync.RWMutex
+
+	// the impairments of the OMCI responses of the ONUs, see SetOmciFaultRules
+	omciFaultRules     []*omciFaultRuleState
+	omciFaultRulesLock sync.RWMutex
+}
+
+// the ways an OLT can be rebooted
+const (
+	// the OLT reports itself, the PONs and the activated ONUs down and closes the indication stream
+	SoftReboot = "soft"
+	// a power cut, the connections drop without any indication (the ONUs can send a dying gasp)
+	HardReboot = "hard"
+	// the OLT keeps accepting connections but stops answering, until it is rebooted again
+	HangReboot = "hang"
+)
+
+// ValidateRebootMode returns an error if the mode is not soft, hard or hang
+func ValidateRebootMode(mode string) error {
+	switch mode {
+	case SoftReboot, HardReboot, HangReboot:
+		return nil
+	}
+	return errors.New(fmt.Sprintf("invalid-reboot-mode-%s", mode))
 }
 
-var olt OltDevice
-var oltServer *grpc.Server
+// FlowKey identifies a flow, VOLTHA reuses the same FlowId for the upstream and downstream flows
+type FlowKey struct {
+	ID        uint32
+	Direction string
+}
+
+var olts = []*OltDevice{}
+var oltsLock sync.RWMutex
 
-func GetOLT() *OltDevice {
-	return &olt
+// GetOLTs returns all the OLTs emulated by BBSim
+func GetOLTs() []*OltDevice {
+	oltsLock.RLock()
+	defer oltsLock.RUnlock()
+
+	res := make([]*OltDevice, len(olts))
+	copy(res, olts)
+	return res
 }
 
-func CreateOLT(oltId int, nni int, pon int, onuPerPon int, sTag int, cTagInit int, auth bool, dhcp bool, delay int, isMock bool) *OltDevice {
+// GetOLTById returns the OLT with a given ID
+func GetOLTById(id int) (*OltDevice, error) {
+	oltsLock.RLock()
+	defer oltsLock.RUnlock()
+
+	for _, olt := range olts {
+		if olt.ID == id {
+			return olt, nil
+		}
+	}
+	return nil, errors.New(fmt.Sprintf("cannot-find-olt-%d", id))
+}
+
+// registerOlt adds an OLT to the OLTs emulated by BBSim, the OLT IDs are unique
+func registerOlt(olt *OltDevice) error {
+	oltsLock.Lock()
+	defer oltsLock.Unlock()
+
+	for _, o := range olts {
+		if o.ID == olt.ID {
+			return errors.New(fmt.Sprintf("olt-%d-already-exists", olt.ID))
+		}
+	}
+	olts = append(olts, olt)
+	return nil
+}
+
+// unregisterOlt removes an OLT from the OLTs emulated by BBSim
+func unregisterOlt(olt *OltDevice) {
+	oltsLock.Lock()
+	defer oltsLock.Unlock()
+
+	for i, o := range olts {
+		if o == olt {
+			olts = append(olts[:i], olts[i+1:]...)
+			return
+		}
+	}
+}
+
+// CreateOLT creates an OLT with its ports and ONUs, it returns an error if an OLT with the same ID already exists
+func CreateOLT(options common.OltConfig, sTag int, cTagInit int, auth bool, dhcp bool, delay int, isMock bool) (*OltDevice, error) {
+	oltId := options.ID
+	if _, err := GetOLTById(oltId); err == nil {
+		return nil, errors.New(fmt.Sprintf("olt-%d-already-exists", oltId))
+	}
+
+	nni := int(options.NniPorts)
+	pon := int(options.PonPorts)
+	onuPerPon := int(options.OnusPonPort)
+	uniPerOnu := int(options.UnisPerOnu)
+
 	oltLogger.WithFields(log.Fields{
 		"ID":           oltId,
 		"NumNni":       nni,
 		"NumPon":       pon,
 		"NumOnuPerPon": onuPerPon,
+		"NumUniPerOnu": uniPerOnu,
 	}).Debug("CreateOLT")
 
-	olt = OltDevice{
+	// the S-Tag option is the one of the first OLT, each OLT gets its own S-Tag
+	// so that the subscribers of different OLTs never share the same pair of tags
+	sTag = sTag + oltId
+
+	serialNumber := options.SerialNumber
+	if serialNumber == "" {
+		serialNumber = fmt.Sprintf("BBSIM_OLT_%d", oltId)
+	}
+
+	olt := OltDevice{
 		ID:           oltId,
-		SerialNumber: fmt.Sprintf("BBSIM_OLT_%d", oltId),
+		SerialNumber: serialNumber,
 		OperState: getOperStateFSM(func(e *fsm.Event) {
 			oltLogger.Debugf("Changing OLT OperState from %s to %s", e.Src, e.Dst)
 		}),
 		NumNni:       nni,
 		NumPon:       pon,
 		NumOnuPerPon: onuPerPon,
+		NumUniPerOnu: uniPerOnu,
 		Pons:         []*PonPort{},
 		Nnis:         []*NniPort{},
 		Delay:        delay,
+		Flows:        make(map[FlowKey]openolt.Flow),
+		flowStats:    make(map[FlowKey]*PacketStats),
+		Options:      options,
+		sTag:         sTag,
+		auth:         auth,
+		dhcp:         dhcp,
 	}
 
 	// OLT State machine
@@ -106,7 +254,7 @@ func CreateOLT(oltId int, nni int, pon int, onuPerPon int, sTag int, cTagInit in
 			{Name: "initialize", Src: []string{"created", "deleted"}, Dst: "initialized"},
 			{Name: "enable", Src: []string{"initialized", "disabled"}, Dst: "enabled"},
 			{Name: "disable", Src: []string{"enabled"}, Dst: "disabled"},
-			{Name: "delete", Src: []string{"disabled"}, Dst: "deleted"},
+			{Name: "delete", Src: []string{"initialized", "enabled", "disabled"}, Dst: "deleted"},
 		},
 		fsm.Callbacks{
 			"enter_state": func(e *fsm.Event) {
@@ -117,20 +265,29 @@ func CreateOLT(oltId int, nni int, pon int, onuPerPon int, sTag int, cTagInit in
 	)
 
 	if isMock != true {
-		// create NNI Port
-		nniPort, err := CreateNNI(&olt)
-		if err != nil {
-			oltLogger.Fatalf("Couldn't create NNI Port: %v", err)
-		}
+		// create NNI Ports
+		for i := 0; i < nni; i++ {
+			nniPort, err := CreateNNI(&olt, uint32(i))
+			if err != nil {
+				oltLogger.Fatalf("Couldn't create NNI Port: %v", err)
+			}
 
-		olt.Nnis = append(olt.Nnis, &nniPort)
+			olt.Nnis = append(olt.Nnis, &nniPort)
+		}
 	}
 
 	// create PON ports
 	availableCTag := cTagInit
 	for i := 0; i < pon; i++ {
+		// the ONUs listed in the topology file replace the generated ones
+		onuTopologies, inTopology := options.Topology.GetOnus(uint32(i))
+		numOnu := onuPerPon
+		if inTopology {
+			numOnu = len(onuTopologies)
+		}
+
 		p := PonPort{
-			NumOnu: olt.NumOnuPerPon,
+			NumOnu: numOnu,
 			ID:     uint32(i),
 			Type:   "pon",
 			Olt:    olt,
@@ -143,29 +300,54 @@ func CreateOLT(oltId int, nni int, pon int, onuPerPon int, sTag int, cTagInit in
 		})
 
 		// create ONU devices
-		for j := 0; j < onuPerPon; j++ {
+		for j := 0; j < numOnu; j++ {
 			o := CreateONU(olt, p, uint32(j+1), sTag, availableCTag, auth, dhcp)
+			if inTopology {
+				if err := o.applyTopology(onuTopologies[j]); err != nil {
+					oltLogger.Fatalf("Couldn't apply the topology to ONU %d on PON %d: %v", o.ID, p.ID, err)
+				}
+			}
 			p.Onus = append(p.Onus, o)
-			availableCTag = availableCTag + 1
+			// each UNI gets its own C-Tag, unless the C-Tag is set in the topology
+			if !inTopology || onuTopologies[j].CTag == 0 {
+				availableCTag = availableCTag + len(o.UniPorts)
+			}
 		}
 
 		olt.Pons = append(olt.Pons, &p)
 	}
+	olt.nextCTag = availableCTag
+
+	if err := registerOlt(&olt); err != nil {
+		return nil, err
+	}
 
 	if isMock != true {
 		if err := olt.InternalState.Event("initialize"); err != nil {
 			log.Errorf("Error initializing OLT: %v", err)
-			return nil
+			unregisterOlt(&olt)
+			return nil, err
 		}
 	}
 
-	return &olt
+	return &olt, nil
+}
+
+// StartDHCPServer starts a single DHCP server listening on the NNI interfaces of all the OLTs
+func StartDHCPServer() error {
+	upstreamVeths := []string{}
+	for _, olt := range GetOLTs() {
+		for _, nni := range olt.Nnis {
+			upstreamVeths = append(upstreamVeths, nni.upstreamVeth)
+		}
+	}
+	return startDHCPServer(upstreamVeths, dhcpServerIp)
 }
 
 func (o *OltDevice) InitOlt() error {
 
-	if oltServer == nil {
-		oltServer, _ = o.newOltServer()
+	if o.oltServer == nil {
+		o.oltServer, _ = o.newOltServer()
 	} else {
 		// FIXME there should never be a server running if we are initializing the OLT
 		oltLogger.Fatal("OLT server already running.")
@@ -174,29 +356,21 @@ func (o *OltDevice) InitOlt() error {
 	// create new channel for processOltMessages Go routine
 	o.channel = make(chan Message)
 
-	o.nniPktInChannel = make(chan *bbsim.PacketMsg, 1024)
-	// FIXME we are assuming we have only one NNI
-	if o.Nnis[0] != nil {
+	// a new signature lets VOLTHA know that the OLT has been restarted
+	o.heartbeatLock.Lock()
+	o.HeartbeatSignature = newHeartbeatSignature(o.HeartbeatSignature)
+	o.heartbeatLock.Unlock()
+
+	for _, nni := range o.Nnis {
 		// NOTE we want to make sure the state is down when we initialize the OLT,
 		// the NNI may be in a bad state after a disable/reboot as we are not disabling it for
 		// in-band management
-		o.Nnis[0].OperState.SetState("down")
-		ch, handle, err := o.Nnis[0].NewVethChan()
-		if err == nil {
-			oltLogger.WithFields(log.Fields{
-				"Type":      o.Nnis[0].Type,
-				"IntfId":    o.Nnis[0].ID,
-				"OperState": o.Nnis[0].OperState.Current(),
-			}).Info("NNI Channel created")
-			o.nniPktInChannel = ch
-			o.nniHandle = handle
-		} else {
-			oltLogger.Errorf("Error getting NNI channel: %v", err)
-		}
+		nni.OperState.SetState("down")
+		nni.openChannel()
 	}
 
-	for i := range olt.Pons {
-		for _, onu := range olt.Pons[i].Onus {
+	for i := range o.Pons {
+		for _, onu := range o.Pons[i].GetOnus() {
 			if err := onu.InternalState.Event("initialize"); err != nil {
 				oltLogger.Errorf("Error initializing ONU: %v", err)
 				return err
@@ -207,14 +381,45 @@ func (o *OltDevice) InitOlt() error {
 	return nil
 }
 
-func (o *OltDevice) RestartOLT() error {
+// RestartOLT reboots the OLT, see SoftReboot, HardReboot and HangReboot.
+// If dyingGasp is set a DyingGasp is sent for every activated ONU before a hard reboot
+func (o *OltDevice) RestartOLT(mode string, dyingGasp bool) error {
+	if err := ValidateRebootMode(mode); err != nil {
+		return err
+	}
+
+	rebootDelay := o.Options.OltRebootDelay
 
-	rebootDelay := common.Options.Olt.OltRebootDelay
+	if mode == HangReboot {
+		oltLogger.WithFields(log.Fields{
+			"oltId": o.ID,
+		}).Info("Simulating OLT hang")
+		o.hang()
+		return nil
+	}
 
 	oltLogger.WithFields(log.Fields{
-		"oltId": o.ID,
+		"oltId":     o.ID,
+		"Mode":      mode,
+		"DyingGasp": dyingGasp,
 	}).Infof("Simulating OLT restart... (%ds)", rebootDelay)
 
+	if o.InternalState.Is("enabled") {
+		switch mode {
+		case SoftReboot:
+			// report everything down, as when the OLT is disabled
+			if _, err := o.DisableOlt(context.TODO(), nil); err != nil {
+				return err
+			}
+			o.flushIndications()
+		case HardReboot:
+			if dyingGasp {
+				o.sendDyingGasps()
+				o.flushIndications()
+			}
+		}
+	}
+
 	// transition internal state to deleted
 	if err := o.InternalState.Event("delete"); err != nil {
 		oltLogger.WithFields(log.Fields{
@@ -223,25 +428,36 @@ func (o *OltDevice) RestartOLT() error {
 		return err
 	}
 
-	// TODO handle hard poweroff (i.e. no indications sent to Voltha) vs soft poweroff
-	time.Sleep(1 * time.Second) // we need to give the OLT the time to respond to all the pending gRPC request before stopping the server
+	if mode == SoftReboot {
+		// close the indication stream
+		o.cancelEnableContext()
+		time.Sleep(1 * time.Second) // we need to give the OLT the time to respond to all the pending gRPC request before stopping the server
+	}
+	// NOTE stopping the server drops the connections, including the requests waiting for an hung OLT
 	if err := o.StopOltServer(); err != nil {
 		return err
 	}
+	o.cancelEnableContext()
+	o.unhang()
 
 	// terminate the OLT's processOltMessages go routine
 	close(o.channel)
-	// terminate the OLT's processNniPacketIns go routine
-	o.nniHandle.Close()
-	close(o.nniPktInChannel)
+	// terminate the OLT's processNniPacketIns go routines
+	for _, nni := range o.Nnis {
+		nni.closeChannel()
+	}
 
-	for i := range olt.Pons {
-		for _, onu := range olt.Pons[i].Onus {
+	for i := range o.Pons {
+		for _, onu := range o.Pons[i].GetOnus() {
 			// NOTE while the olt is off, restore the ONU to the initial state
 			onu.InternalState.SetState("created")
 		}
 	}
 
+	// a rebooted OLT does not retain any flow, nor any scheduler and queue
+	o.clearFlows()
+	o.clearTrafficConfig()
+
 	time.Sleep(time.Duration(rebootDelay) * time.Second)
 
 	if err := o.InternalState.Event("initialize"); err != nil {
@@ -256,21 +472,165 @@ func (o *OltDevice) RestartOLT() error {
 	return nil
 }
 
+// cancelEnableContext stops the Go routines started by Enable, this terminates the indication stream
+func (o *OltDevice) cancelEnableContext() {
+	o.Lock()
+	defer o.Unlock()
+	if o.enableContextCancel != nil {
+		o.enableContextCancel()
+	}
+}
+
+// flushIndications waits (up to a second) for the OLT to send the indications queued in its channel,
+// the OLT handles its messages in order so the barrier is reached once the previous indications have been sent
+func (o *OltDevice) flushIndications() {
+	done := make(chan struct{})
+	timeout := time.After(1 * time.Second)
+
+	select {
+	case o.channel <- Message{Type: FlushIndications, Data: done}:
+	case <-timeout:
+		oltLogger.WithFields(log.Fields{
+			"oltId": o.ID,
+		}).Warn("Timed out queueing the flush of the indications")
+		return
+	}
+
+	select {
+	case <-done:
+	case <-timeout:
+		oltLogger.WithFields(log.Fields{
+			"oltId": o.ID,
+		}).Warn("Timed out flushing the indications")
+	}
+}
+
+// sendDyingGasps sends a DyingGasp for every activated ONU, as when the power is cut
+func (o *OltDevice) sendDyingGasps() {
+	for _, pon := range o.Pons {
+		for _, onu := range pon.GetOnus() {
+			if !onu.InternalState.Is("enabled") {
+				continue
+			}
+			alarm, _ := createOnuAlarm(onu, common.OnuAlarmDyingGasp, common.AlarmStatusOn)
+			o.channel <- Message{
+				Type: AlarmIndication,
+				Data: AlarmIndicationMessage{
+					AlarmType: common.OnuAlarmDyingGasp,
+					Alarm:     alarm,
+				},
+			}
+		}
+	}
+}
+
+// hang makes the OLT stop answering to the gRPC requests
+func (o *OltDevice) hang() {
+	o.hangLock.Lock()
+	defer o.hangLock.Unlock()
+	if o.hangChannel == nil {
+		o.hangChannel = make(chan struct{})
+	}
+}
+
+// unhang lets the gRPC requests waiting for an hung OLT go on
+func (o *OltDevice) unhang() {
+	o.hangLock.Lock()
+	defer o.hangLock.Unlock()
+	if o.hangChannel != nil {
+		close(o.hangChannel)
+		o.hangChannel = nil
+	}
+}
+
+// isHung returns true if the OLT is not answering to the gRPC requests
+func (o *OltDevice) isHung() bool {
+	o.hangLock.RLock()
+	defer o.hangLock.RUnlock()
+	return o.hangChannel != nil
+}
+
+// waitIfHung blocks while the OLT is hung, or until the request is canceled
+func (o *OltDevice) waitIfHung(ctx context.Context) error {
+	o.hangLock.RLock()
+	ch := o.hangChannel
+	o.hangLock.RUnlock()
+
+	if ch == nil {
+		return nil
+	}
+	select {
+	case <-ch:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// hangStream holds the indications while the OLT is hung, an hung OLT doesn't send anything to VOLTHA
+type hangStream struct {
+	openolt.Openolt_EnableIndicationServer
+	ctx context.Context
+	olt *OltDevice
+}
+
+func newHangStream(ctx context.Context, olt *OltDevice, stream openolt.Openolt_EnableIndicationServer) *hangStream {
+	return &hangStream{
+		Openolt_EnableIndicationServer: stream,
+		ctx:                            ctx,
+		olt:                            olt,
+	}
+}
+
+func (s *hangStream) Send(ind *openolt.Indication) error {
+	// NOTE the indications are dropped if the OLT is restarted while it is hung
+	if err := s.olt.waitIfHung(s.ctx); err != nil {
+		return err
+	}
+	return s.Openolt_EnableIndicationServer.Send(ind)
+}
+
+func (o *OltDevice) hangUnaryInterceptor(ctx context.Context, req interface{}, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	if err := o.waitIfHung(ctx); err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+func (o *OltDevice) hangStreamInterceptor(srv interface{}, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	if err := o.waitIfHung(ss.Context()); err != nil {
+		return err
+	}
+	return handler(srv, ss)
+}
+
+// unaryInterceptor holds the calls while the OLT is hung, then injects the configured faults
+func (o *OltDevice) unaryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	return o.hangUnaryInterceptor(ctx, req, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+		return o.faultUnaryInterceptor(ctx, req, info, handler)
+	})
+}
+
 // newOltServer launches a new grpc server for OpenOLT
 func (o *OltDevice) newOltServer() (*grpc.Server, error) {
-	address := common.Options.BBSim.OpenOltAddress
+	address := o.Options.OpenOltAddress
 	lis, err := net.Listen("tcp", address)
 	if err != nil {
 		oltLogger.Fatalf("OLT failed to listen: %v", err)
 	}
-	grpcServer := grpc.NewServer()
+	grpcServer := grpc.NewServer(
+		grpc.UnaryInterceptor(o.unaryInterceptor),
+		grpc.StreamInterceptor(o.hangStreamInterceptor),
+	)
 
 	openolt.RegisterOpenoltServer(grpcServer, o)
 
 	reflection.Register(grpcServer)
 
 	go grpcServer.Serve(lis)
-	oltLogger.Debugf("OLT listening on %v", address)
+	oltLogger.WithFields(log.Fields{
+		"oltId": o.ID,
+	}).Debugf("OLT listening on %v", address)
 
 	return grpcServer, nil
 }
@@ -278,12 +638,12 @@ func (o *OltDevice) newOltServer() (*grpc.Server, error) {
 // StopOltServer stops the OpenOLT grpc server
 func (o *OltDevice) StopOltServer() error {
 	// TODO handle poweroff vs graceful shutdown
-	if oltServer != nil {
+	if o.oltServer != nil {
 		oltLogger.WithFields(log.Fields{
 			"oltId": o.SerialNumber,
 		}).Warnf("Stopping OLT gRPC server")
-		oltServer.Stop()
-		oltServer = nil
+		o.oltServer.Stop()
+		o.oltServer = nil
 	}
 
 	return nil
@@ -304,14 +664,19 @@ func (o *OltDevice) Enable(stream openolt.Openolt_EnableIndicationServer) error
 		o.enableContextCancel()
 	}
 	o.enableContext, o.enableContextCancel = context.WithCancel(context.TODO())
+	stream = newHangStream(o.enableContext, o, newPonStatsStream(o, stream))
+	o.enableStream = stream
 	o.Unlock()
 
 	wg := sync.WaitGroup{}
-	wg.Add(3)
+	wg.Add(3 + len(o.Nnis))
 
 	// create Go routine to process all OLT events
 	go o.processOltMessages(o.enableContext, stream, &wg)
-	go o.processNniPacketIns(o.enableContext, stream, &wg)
+	// create a Go routine per NNI to process the packets coming in from it
+	for _, nni := range o.Nnis {
+		go o.processNniPacketIns(o.enableContext, nni, stream, &wg)
+	}
 
 	// enable the OLT
 	oltMsg := Message{
@@ -334,7 +699,13 @@ func (o *OltDevice) Enable(stream openolt.Openolt_EnableIndicationServer) error
 		o.channel <- msg
 	}
 
-	go o.processOmciMessages(o.enableContext, &wg)
+	startOmciDispatcher()
+
+	// periodically report the port and flow statistics
+	go o.processStatistics(o.enableContext, &wg)
+
+	// end the OMCI PM intervals of the ONUs
+	go o.processOmciPmIntervals(o.enableContext, &wg)
 
 	// send PON Port indications
 	for i, pon := range o.Pons {
@@ -347,7 +718,7 @@ func (o *OltDevice) Enable(stream openolt.Openolt_EnableIndicationServer) error
 		}
 		o.channel <- msg
 
-		for _, onu := range o.Pons[i].Onus {
+		for _, onu := range o.Pons[i].GetOnus() {
 			go onu.ProcessOnuMessages(o.enableContext, stream, nil)
 			if onu.InternalState.Current() != "initialized" {
 				continue
@@ -364,34 +735,39 @@ func (o *OltDevice) Enable(stream openolt.Openolt_EnableIndicationServer) error
 	return nil
 }
 
-func (o *OltDevice) processOmciMessages(ctx context.Context, wg *sync.WaitGroup) {
-	ch := omcisim.GetChannel()
+var omciDispatcherOnce sync.Once
+
+// startOmciDispatcher starts the goroutine that handles the messages omci-sim sends on its channel,
+// there is a single channel for all the OLTs so it is started once, by the first OLT that is enabled
+func startOmciDispatcher() {
+	omciDispatcherOnce.Do(func() {
+		go dispatchOmciMessages(omcisim.GetChannel())
+	})
+}
 
+// dispatchOmciMessages routes the messages from omci-sim to the ONU they belong to,
+// the OLT is found by the interface ID the ONU is known by in omci-sim (see common.OmciSimIntfId)
+func dispatchOmciMessages(ch chan omcisim.OmciChMessage) {
 	oltLogger.Debug("Starting OMCI Indication Channel")
 
-loop:
-	for {
-		select {
-		case <-ctx.Done():
-			oltLogger.Debug("OMCI processing canceled via context")
-			break loop
-		case message, ok := <-ch:
-			if !ok || ctx.Err() != nil {
-				oltLogger.Debug("OMCI processing canceled via channel close")
-				break loop
-			}
-			onuId := message.Data.OnuId
-			intfId := message.Data.IntfId
-			onu, err := o.FindOnuById(intfId, onuId)
-			if err != nil {
-				oltLogger.Errorf("Failed to find onu: %v", err)
-				continue
-			}
-			go onu.processOmciMessage(message)
+	for message := range ch {
+		oltId, intfId := common.SplitOmciSimIntfId(message.Data.IntfId)
+		onuId := message.Data.OnuId
+
+		olt, err := GetOLTById(oltId)
+		if err != nil {
+			oltLogger.Errorf("Failed to find olt: %v", err)
+			continue
+		}
+		onu, err := olt.FindOnuById(intfId, onuId)
+		if err != nil {
+			oltLogger.Errorf("Failed to find onu: %v", err)
+			continue
 		}
+		go onu.processOmciMessage(message)
 	}
 
-	wg.Done()
+	oltLogger.Warn("Stopped handling OMCI Indication Channel")
 }
 
 // Helpers method
@@ -428,7 +804,8 @@ func (o *OltDevice) sendOltIndication(msg OltIndicationMessage, stream openolt.O
 
 func (o *OltDevice) sendNniIndication(msg NniIndicationMessage, stream openolt.Openolt_EnableIndicationServer) {
 	nni, _ := o.getNniById(msg.NniPortID)
-	if msg.OperState == UP {
+	// NOTE the NNI is not disabled with the OLT, so it may already be up when the OLT is reenabled
+	if msg.OperState == UP && !nni.OperState.Is("up") {
 		if err := nni.OperState.Event("enable"); err != nil {
 			log.WithFields(log.Fields{
 				"Type":      nni.Type,
@@ -555,6 +932,14 @@ loop:
 			case PonIndication:
 				msg, _ := message.Data.(PonIndicationMessage)
 				o.sendPonIndication(msg, stream)
+			case AlarmIndication:
+				msg, _ := message.Data.(AlarmIndicationMessage)
+				o.sendAlarmIndication(msg, stream)
+			case StatisticsIndication:
+				o.sendStatistics(stream)
+			case FlushIndications:
+				done, _ := message.Data.(chan struct{})
+				close(done)
 			default:
 				oltLogger.Warnf("Received unknown message data %v for type %v in OLT Channel", message.Data, message.Type)
 			}
@@ -564,14 +949,15 @@ loop:
 	oltLogger.Warn("Stopped handling OLT Indication Channel")
 }
 
-// processNniPacketIns handles messages received over the NNI interface
-func (o *OltDevice) processNniPacketIns(ctx context.Context, stream openolt.Openolt_EnableIndicationServer, wg *sync.WaitGroup) {
+// processNniPacketIns handles messages received over an NNI interface
+func (o *OltDevice) processNniPacketIns(ctx context.Context, nni *NniPort, stream openolt.Openolt_EnableIndicationServer, wg *sync.WaitGroup) {
 	oltLogger.WithFields(log.Fields{
-		"nniChannel": o.nniPktInChannel,
+		"IntfId":     nni.ID,
+		"nniChannel": nni.pktInChannel,
 	}).Debug("Started Processing Packets arriving from the NNI")
-	nniId := o.Nnis[0].ID // FIXME we are assuming we have only one NNI
+	nniId := nni.ID
 
-	ch := o.nniPktInChannel
+	ch := nni.pktInChannel
 
 loop:
 	for {
@@ -586,55 +972,13 @@ loop:
 			}
 			oltLogger.Tracef("Received packets on NNI Channel")
 
-			onuMac, err := packetHandlers.GetDstMacAddressFromPacket(message.Pkt)
-
-			if err != nil {
-				log.WithFields(log.Fields{
-					"IntfType": "nni",
-					"IntfId":   nniId,
-					"Pkt":      message.Pkt.Data(),
-				}).Error("Can't find Dst MacAddress in packet")
-				return
-			}
-
-			onu, err := o.FindOnuByMacAddress(onuMac)
-			if err != nil {
-				log.WithFields(log.Fields{
-					"IntfType":   "nni",
-					"IntfId":     nniId,
-					"Pkt":        message.Pkt.Data(),
-					"MacAddress": onuMac.String(),
-				}).Error("Can't find ONU with MacAddress")
-				return
-			}
-
-			doubleTaggedPkt, err := packetHandlers.PushDoubleTag(onu.STag, onu.CTag, message.Pkt)
-			if err != nil {
-				log.Error("Fail to add double tag to packet")
-			}
-
-			data := &openolt.Indication_PktInd{PktInd: &openolt.PacketIndication{
-				IntfType: "nni",
-				IntfId:   nniId,
-				Pkt:      doubleTaggedPkt.Data()}}
-			if err := stream.Send(&openolt.Indication{Data: data}); err != nil {
-				oltLogger.WithFields(log.Fields{
-					"IntfType": data.PktInd.IntfType,
-					"IntfId":   nniId,
-					"Pkt":      doubleTaggedPkt.Data(),
-				}).Errorf("Fail to send PktInd indication: %v", err)
-			}
-			oltLogger.WithFields(log.Fields{
-				"IntfType": data.PktInd.IntfType,
-				"IntfId":   nniId,
-				"Pkt":      doubleTaggedPkt.Data(),
-				"OnuSn":    onu.Sn(),
-			}).Tracef("Sent PktInd indication")
+			o.forwardNniPacket(nni, message.Pkt, stream)
 		}
 	}
 	wg.Done()
 	oltLogger.WithFields(log.Fields{
-		"nniChannel": o.nniPktInChannel,
+		"IntfId":     nniId,
+		"nniChannel": ch,
 	}).Warn("Stopped handling NNI Channel")
 }
 
@@ -643,7 +987,7 @@ func (o OltDevice) FindOnuBySn(serialNumber string) (*Onu, error) {
 	// TODO this function can be a performance bottleneck when we have many ONUs,
 	// memoizing it will remove the bottleneck
 	for _, pon := range o.Pons {
-		for _, onu := range pon.Onus {
+		for _, onu := range pon.GetOnus() {
 			if onu.Sn() == serialNumber {
 				return onu, nil
 			}
@@ -659,7 +1003,7 @@ func (o OltDevice) FindOnuById(intfId uint32, onuId uint32) (*Onu, error) {
 	// memoizing it will remove the bottleneck
 	for _, pon := range o.Pons {
 		if pon.ID == intfId {
-			for _, onu := range pon.Onus {
+			for _, onu := range pon.GetOnus() {
 				if onu.ID == onuId {
 					return onu, nil
 				}
@@ -671,17 +1015,28 @@ func (o OltDevice) FindOnuById(intfId uint32, onuId uint32) (*Onu, error) {
 
 // returns an ONU with a given Mac Address
 func (o OltDevice) FindOnuByMacAddress(mac net.HardwareAddr) (*Onu, error) {
+	uni, err := o.FindUniByMacAddress(mac)
+	if err != nil {
+		return &Onu{}, errors.New(fmt.Sprintf("cannot-find-onu-by-mac-address-%s", mac))
+	}
+	return uni.Onu, nil
+}
+
+// returns the UNI port with a given MacAddress
+func (o *OltDevice) FindUniByMacAddress(mac net.HardwareAddr) (*UniPort, error) {
 	// TODO this function can be a performance bottleneck when we have many ONUs,
 	// memoizing it will remove the bottleneck
 	for _, pon := range o.Pons {
-		for _, onu := range pon.Onus {
-			if onu.HwAddress.String() == mac.String() {
-				return onu, nil
+		for _, onu := range pon.GetOnus() {
+			for _, uni := range onu.UniPorts {
+				if uni.HwAddress.String() == mac.String() {
+					return uni, nil
+				}
 			}
 		}
 	}
 
-	return &Onu{}, errors.New(fmt.Sprintf("cannot-find-onu-by-mac-address-%s", mac))
+	return &UniPort{}, errors.New(fmt.Sprintf("cannot-find-uni-by-mac-address-%s", mac))
 }
 
 // GRPC Endpoints
@@ -715,23 +1070,135 @@ func (o OltDevice) ActivateOnu(context context.Context, onu *openolt.Onu) (*open
 	return new(openolt.Empty), nil
 }
 
-func (o OltDevice) DeactivateOnu(context.Context, *openolt.Onu) (*openolt.Empty, error) {
-	oltLogger.Error("DeactivateOnu not implemented")
-	return new(openolt.Empty), nil
+func (o *OltDevice) DeactivateOnu(_ context.Context, onu *openolt.Onu) (*openolt.Empty, error) {
+	oltLogger.WithFields(log.Fields{
+		"IntfId": onu.IntfId,
+		"OnuId":  onu.OnuId,
+		"OnuSn":  onuSnToString(onu.SerialNumber),
+	}).Info("Received DeactivateOnu call from VOLTHA")
+
+	return new(openolt.Empty), o.teardownOnu(onu)
 }
 
-func (o OltDevice) DeleteOnu(context.Context, *openolt.Onu) (*openolt.Empty, error) {
-	oltLogger.Error("DeleteOnu not implemented")
-	return new(openolt.Empty), nil
+func (o *OltDevice) DeleteOnu(_ context.Context, onu *openolt.Onu) (*openolt.Empty, error) {
+	oltLogger.WithFields(log.Fields{
+		"IntfId": onu.IntfId,
+		"OnuId":  onu.OnuId,
+		"OnuSn":  onuSnToString(onu.SerialNumber),
+	}).Info("Received DeleteOnu call from VOLTHA")
+
+	return new(openolt.Empty), o.teardownOnu(onu)
 }
 
-func (o OltDevice) DisableOlt(context.Context, *openolt.Empty) (*openolt.Empty, error) {
-	// NOTE when we disable the OLT should we disable NNI, PONs and ONUs altogether?
+// teardownOnu brings an ONU down and removes everything VOLTHA configured on it
+// (flows, schedulers, queues and OMCI state), then the ONU goes through discovery again
+// so that it can be added back without restarting BBSim
+func (o *OltDevice) teardownOnu(req *openolt.Onu) error {
+	pon, err := o.GetPonById(req.IntfId)
+	if err != nil {
+		oltLogger.WithFields(log.Fields{
+			"IntfId": req.IntfId,
+			"err":    err,
+		}).Error("Can't find PonPort")
+		return err
+	}
+	onu, err := pon.GetOnuBySn(req.SerialNumber)
+	if err != nil {
+		oltLogger.WithFields(log.Fields{
+			"IntfId": req.IntfId,
+			"OnuSn":  onuSnToString(req.SerialNumber),
+			"err":    err,
+		}).Error("Can't find Onu")
+		return err
+	}
+
+	if onu.OperState.Is("up") {
+		if err := onu.OperState.Event("disable"); err != nil {
+			oltLogger.WithFields(log.Fields{
+				"IntfId": onu.PonPortID,
+				"OnuSn":  onu.Sn(),
+				"OnuId":  onu.ID,
+			}).Errorf("Failed to transition ONU.OperState to disabled state: %s", err.Error())
+		}
+	}
+
+	// NOTE the disabled state sends the ONU DOWN indication and stops the ONU message processing
+	if onu.InternalState.Can("disable") {
+		if err := onu.InternalState.Event("disable"); err != nil {
+			oltLogger.WithFields(log.Fields{
+				"IntfId": onu.PonPortID,
+				"OnuSn":  onu.Sn(),
+				"OnuId":  onu.ID,
+			}).Errorf("Failed to transition ONU to disabled state: %s", err.Error())
+			return err
+		}
+	}
+
+	o.clearOnuFlows(onu)
+	o.clearOnuTrafficConfig(onu)
+	onu.resetConfig()
+
+	// NOTE an ONU that is not yet activated is still being discovered,
+	// and an ONU on a disabled PON is rediscovered when the PON is enabled
+	if !onu.InternalState.Is("disabled") {
+		return nil
+	}
+	if err := onu.InternalState.Event("initialize"); err != nil {
+		oltLogger.WithFields(log.Fields{
+			"IntfId": onu.PonPortID,
+			"OnuSn":  onu.Sn(),
+			"OnuId":  onu.ID,
+		}).Errorf("Error initializing ONU: %v", err)
+		return err
+	}
+	go onu.ProcessOnuMessages(o.enableContext, o.enableStream, nil)
+	if err := onu.InternalState.Event("discover"); err != nil {
+		oltLogger.WithFields(log.Fields{
+			"IntfId": onu.PonPortID,
+			"OnuSn":  onu.Sn(),
+			"OnuId":  onu.ID,
+		}).Errorf("Error discover ONU: %v", err)
+		return err
+	}
+	return nil
+}
+
+func (o *OltDevice) DisableOlt(context.Context, *openolt.Empty) (*openolt.Empty, error) {
 	oltLogger.WithFields(log.Fields{
 		"oltId": o.ID,
 	}).Info("Disabling OLT")
 
 	for _, pon := range o.Pons {
+		// report the activated ONUs as down, their internal state is retained
+		// so that they can be restored when the OLT is reenabled
+		for _, onu := range pon.GetOnus() {
+			if !onu.InternalState.Is("enabled") || !onu.OperState.Is("up") {
+				continue
+			}
+			if err := onu.OperState.Event("disable"); err != nil {
+				oltLogger.WithFields(log.Fields{
+					"IntfId": onu.PonPortID,
+					"OnuId":  onu.ID,
+					"OnuSn":  onu.Sn(),
+				}).Errorf("Failed to transition ONU.OperState to disabled state: %s", err.Error())
+			}
+			msg := Message{
+				Type: OnuIndication,
+				Data: OnuIndicationMessage{
+					OnuSN:     onu.SerialNumber,
+					PonPortID: onu.PonPortID,
+					OperState: DOWN,
+				},
+			}
+			if err := onu.SendMessage(msg); err != nil {
+				oltLogger.WithFields(log.Fields{
+					"IntfId": onu.PonPortID,
+					"OnuId":  onu.ID,
+					"OnuSn":  onu.Sn(),
+				}).Warnf("Can't send the ONU indication: %v", err)
+			}
+		}
+
 		// disable PONs
 		msg := Message{
 			Type: PonIndication,
@@ -758,8 +1225,53 @@ func (o OltDevice) DisableOlt(context.Context, *openolt.Empty) (*openolt.Empty,
 	return new(openolt.Empty), nil
 }
 
-func (o OltDevice) DisablePonIf(context.Context, *openolt.Interface) (*openolt.Empty, error) {
-	oltLogger.Error("DisablePonIf not implemented")
+func (o *OltDevice) DisablePonIf(_ context.Context, intf *openolt.Interface) (*openolt.Empty, error) {
+	oltLogger.WithFields(log.Fields{
+		"IntfId": intf.IntfId,
+	}).Info("Received DisablePonIf call from VOLTHA")
+
+	// the PON indications are sent by the OLT message loop, that only runs while the OLT is enabled
+	if !o.InternalState.Is("enabled") {
+		return new(openolt.Empty), status.Errorf(codes.FailedPrecondition, "olt-%d-is-not-enabled", o.ID)
+	}
+
+	pon, err := o.GetPonById(intf.IntfId)
+	if err != nil {
+		oltLogger.WithFields(log.Fields{
+			"IntfId": intf.IntfId,
+			"err":    err,
+		}).Error("Can't find PonPort")
+		return new(openolt.Empty), err
+	}
+
+	msg := Message{
+		Type: PonIndication,
+		Data: PonIndicationMessage{
+			OperState: DOWN,
+			PonPortID: pon.ID,
+		},
+	}
+	o.channel <- msg
+
+	// all the ONUs connected to the PON lose the signal
+	for _, onu := range pon.GetOnus() {
+		if onu.InternalState.Is("rebooting") {
+			// NOTE the ONU is already down, it comes back when the PON is enabled
+			onu.InternalState.SetState("pon_disabled")
+			continue
+		}
+		if !onu.InternalState.Can("pon_disabled") {
+			continue
+		}
+		if err := onu.InternalState.Event("pon_disabled"); err != nil {
+			oltLogger.WithFields(log.Fields{
+				"IntfId": onu.PonPortID,
+				"OnuSn":  onu.Sn(),
+				"OnuId":  onu.ID,
+			}).Errorf("Failed to transition ONU to pon_disabled state: %s", err.Error())
+		}
+	}
+
 	return new(openolt.Empty), nil
 }
 
@@ -769,12 +1281,61 @@ func (o *OltDevice) EnableIndication(_ *openolt.Empty, stream openolt.Openolt_En
 	return nil
 }
 
-func (o OltDevice) EnablePonIf(context.Context, *openolt.Interface) (*openolt.Empty, error) {
-	oltLogger.Error("EnablePonIf not implemented")
+func (o *OltDevice) EnablePonIf(_ context.Context, intf *openolt.Interface) (*openolt.Empty, error) {
+	oltLogger.WithFields(log.Fields{
+		"IntfId": intf.IntfId,
+	}).Info("Received EnablePonIf call from VOLTHA")
+
+	// the PON indications are sent by the OLT message loop, that only runs while the OLT is enabled
+	if !o.InternalState.Is("enabled") {
+		return new(openolt.Empty), status.Errorf(codes.FailedPrecondition, "olt-%d-is-not-enabled", o.ID)
+	}
+
+	pon, err := o.GetPonById(intf.IntfId)
+	if err != nil {
+		oltLogger.WithFields(log.Fields{
+			"IntfId": intf.IntfId,
+			"err":    err,
+		}).Error("Can't find PonPort")
+		return new(openolt.Empty), err
+	}
+
+	msg := Message{
+		Type: PonIndication,
+		Data: PonIndicationMessage{
+			OperState: UP,
+			PonPortID: pon.ID,
+		},
+	}
+	o.channel <- msg
+
+	// rediscover the ONUs that went down together with the PON
+	for _, onu := range pon.GetOnus() {
+		if !onu.InternalState.Is("pon_disabled") {
+			continue
+		}
+		if err := onu.InternalState.Event("initialize"); err != nil {
+			oltLogger.WithFields(log.Fields{
+				"IntfId": onu.PonPortID,
+				"OnuSn":  onu.Sn(),
+				"OnuId":  onu.ID,
+			}).Errorf("Error initializing ONU: %v", err)
+			continue
+		}
+		go onu.ProcessOnuMessages(o.enableContext, o.enableStream, nil)
+		if err := onu.InternalState.Event("discover"); err != nil {
+			oltLogger.WithFields(log.Fields{
+				"IntfId": onu.PonPortID,
+				"OnuSn":  onu.Sn(),
+				"OnuId":  onu.ID,
+			}).Errorf("Error discover ONU: %v", err)
+		}
+	}
+
 	return new(openolt.Empty), nil
 }
 
-func (o OltDevice) FlowAdd(ctx context.Context, flow *openolt.Flow) (*openolt.Empty, error) {
+func (o *OltDevice) FlowAdd(ctx context.Context, flow *openolt.Flow) (*openolt.Empty, error) {
 	oltLogger.WithFields(log.Fields{
 		"IntfId":    flow.AccessIntfId,
 		"OnuId":     flow.OnuId,
@@ -786,28 +1347,62 @@ func (o OltDevice) FlowAdd(ctx context.Context, flow *openolt.Flow) (*openolt.Em
 		"UniID":     flow.UniId,
 		"PortNo":    flow.PortNo,
 	}).Tracef("OLT receives Flow")
-	// TODO optionally store flows somewhere
 
-	if flow.AccessIntfId == -1 {
-		oltLogger.WithFields(log.Fields{
-			"FlowId": flow.FlowId,
-		}).Debugf("This is an OLT flow")
-	} else {
-		pon, err := o.GetPonById(uint32(flow.AccessIntfId))
+	flowKey := FlowKey{
+		ID:        flow.FlowId,
+		Direction: flow.FlowType,
+	}
+
+	// NOTE the ONU is found before storing the flow, the flows of an unknown ONU are rejected
+	var pon *PonPort
+	var onu *Onu
+	if flow.AccessIntfId != -1 {
+		var err error
+		pon, err = o.GetPonById(uint32(flow.AccessIntfId))
 		if err != nil {
 			oltLogger.WithFields(log.Fields{
 				"OnuId":  flow.OnuId,
 				"IntfId": flow.AccessIntfId,
 				"err":    err,
 			}).Error("Can't find PonPort")
+			return new(openolt.Empty), status.Errorf(codes.NotFound, err.Error())
 		}
-		onu, err := pon.GetOnuById(uint32(flow.OnuId))
+		onu, err = pon.GetOnuById(uint32(flow.OnuId))
 		if err != nil {
 			oltLogger.WithFields(log.Fields{
 				"OnuId":  flow.OnuId,
 				"IntfId": flow.AccessIntfId,
 				"err":    err,
 			}).Error("Can't find Onu")
+			return new(openolt.Empty), status.Errorf(codes.NotFound, err.Error())
+		}
+	}
+
+	o.flowsLock.Lock()
+	_, isUpdate := o.Flows[flowKey]
+	o.Flows[flowKey] = *flow
+	o.flowsLock.Unlock()
+
+	if flow.AccessIntfId == -1 {
+		oltLogger.WithFields(log.Fields{
+			"FlowId": flow.FlowId,
+		}).Debugf("This is an OLT flow")
+	} else {
+		if !isUpdate {
+			o.flowsLock.Lock()
+			onu.Flows = append(onu.Flows, flowKey)
+			o.flowsLock.Unlock()
+		}
+
+		if onu.IsOffline() {
+			oltLogger.WithFields(log.Fields{
+				"IntfId":        onu.PonPortID,
+				"OnuId":         onu.ID,
+				"OnuSn":         onu.Sn(),
+				"FlowId":        flow.FlowId,
+				"InternalState": onu.InternalState.Current(),
+			}).Warn("Not sending flow to ONU as it is offline")
+			return new(openolt.Empty), nil
 		}
 
 		msg := Message{
@@ -818,21 +1413,171 @@ func (o OltDevice) FlowAdd(ctx context.Context, flow *openolt.Flow) (*openolt.Em
 				Flow:      flow,
 			},
 		}
-		onu.Channel <- msg
+		if err := onu.SendMessage(msg); err != nil {
+			oltLogger.WithFields(log.Fields{
+				"IntfId": onu.PonPortID,
+				"OnuId":  onu.ID,
+				"OnuSn":  onu.Sn(),
+				"FlowId": flow.FlowId,
+			}).Warnf("Not sending flow to ONU: %v", err)
+		}
 	}
 
 	return new(openolt.Empty), nil
 }
 
-func (o OltDevice) FlowRemove(context.Context, *openolt.Flow) (*openolt.Empty, error) {
-	oltLogger.Tracef("received FlowRemove")
-	// TODO store flows somewhere
+func (o *OltDevice) FlowRemove(_ context.Context, flow *openolt.Flow) (*openolt.Empty, error) {
+	oltLogger.WithFields(log.Fields{
+		"FlowId":   flow.FlowId,
+		"FlowType": flow.FlowType,
+	}).Tracef("OLT receives FlowRemove")
+
+	flowKey := FlowKey{
+		ID:        flow.FlowId,
+		Direction: flow.FlowType,
+	}
+
+	o.flowsLock.Lock()
+	storedFlow, ok := o.Flows[flowKey]
+	delete(o.Flows, flowKey)
+	delete(o.flowStats, flowKey)
+	o.flowsLock.Unlock()
+
+	if !ok {
+		// NOTE VOLTHA may remove a flow we never received (eg: after an OLT reboot),
+		// there's nothing to clean up in that case
+		oltLogger.WithFields(log.Fields{
+			"FlowId":   flow.FlowId,
+			"FlowType": flow.FlowType,
+		}).Warn("Received FlowRemove for an unknown flow")
+		return new(openolt.Empty), nil
+	}
+
+	if storedFlow.AccessIntfId != -1 {
+		onu, err := o.FindOnuById(uint32(storedFlow.AccessIntfId), uint32(storedFlow.OnuId))
+		if err != nil {
+			oltLogger.WithFields(log.Fields{
+				"OnuId":  storedFlow.OnuId,
+				"IntfId": storedFlow.AccessIntfId,
+				"err":    err,
+			}).Error("Can't find Onu")
+			return new(openolt.Empty), nil
+		}
+		o.flowsLock.Lock()
+		onu.removeFlow(flowKey)
+		o.flowsLock.Unlock()
+	}
+
 	return new(openolt.Empty), nil
 }
 
-func (o OltDevice) HeartbeatCheck(context.Context, *openolt.Empty) (*openolt.Heartbeat, error) {
-	oltLogger.Error("HeartbeatCheck not implemented")
-	return new(openolt.Heartbeat), nil
+// clearFlows removes all the flows from the OLT and from its ONUs
+func (o *OltDevice) clearFlows() {
+	o.flowsLock.Lock()
+	defer o.flowsLock.Unlock()
+
+	o.Flows = make(map[FlowKey]openolt.Flow)
+	o.flowStats = make(map[FlowKey]*PacketStats)
+	for _, pon := range o.Pons {
+		for _, onu := range pon.GetOnus() {
+			onu.Flows = []FlowKey{}
+		}
+	}
+}
+
+// clearOnuFlows removes the flows installed for an ONU
+func (o *OltDevice) clearOnuFlows(onu *Onu) {
+	o.flowsLock.Lock()
+	defer o.flowsLock.Unlock()
+
+	for _, key := range onu.Flows {
+		delete(o.Flows, key)
+		delete(o.flowStats, key)
+	}
+	onu.Flows = []FlowKey{}
+}
+
+// GetFlows returns all the flows currently installed on the OLT
+func (o *OltDevice) GetFlows() []openolt.Flow {
+	o.flowsLock.RLock()
+	defer o.flowsLock.RUnlock()
+
+	flows := []openolt.Flow{}
+	for _, flow := range o.Flows {
+		flows = append(flows, flow)
+	}
+	sort.Slice(flows, func(i, j int) bool {
+		if flows[i].FlowId == flows[j].FlowId {
+			return flows[i].FlowType < flows[j].FlowType
+		}
+		return flows[i].FlowId < flows[j].FlowId
+	})
+	return flows
+}
+
+// GetOnuFlows returns the flows currently installed on the OLT for a given ONU
+func (o *OltDevice) GetOnuFlows(onu *Onu) []openolt.Flow {
+	o.flowsLock.RLock()
+	defer o.flowsLock.RUnlock()
+
+	flows := []openolt.Flow{}
+	for _, key := range onu.Flows {
+		if flow, ok := o.Flows[key]; ok {
+			flows = append(flows, flow)
+		}
+	}
+	return flows
+}
+
+// newHeartbeatSignature returns the time the OLT has been initialized,
+// making sure it differs from the previous signature even if the OLT rebooted within the same second
+func newHeartbeatSignature(previous uint32) uint32 {
+	signature := uint32(time.Now().Unix())
+	if signature <= previous {
+		signature = previous + 1
+	}
+	return signature
+}
+
+// StopHeartbeat makes the OLT stop responding to HeartbeatCheck requests,
+// simulating an OLT that is unreachable from VOLTHA
+func (o *OltDevice) StopHeartbeat() {
+	o.heartbeatLock.Lock()
+	defer o.heartbeatLock.Unlock()
+	o.heartbeatStopped = true
+}
+
+// StartHeartbeat resumes the responses to HeartbeatCheck requests
+func (o *OltDevice) StartHeartbeat() {
+	o.heartbeatLock.Lock()
+	defer o.heartbeatLock.Unlock()
+	o.heartbeatStopped = false
+}
+
+func (o *OltDevice) IsHeartbeatStopped() bool {
+	o.heartbeatLock.RLock()
+	defer o.heartbeatLock.RUnlock()
+	return o.heartbeatStopped
+}
+
+func (o *OltDevice) HeartbeatCheck(ctx context.Context, _ *openolt.Empty) (*openolt.Heartbeat, error) {
+	if o.IsHeartbeatStopped() {
+		// never respond, VOLTHA will time out the request and consider the OLT unreachable
+		oltLogger.WithFields(log.Fields{
+			"oltId": o.ID,
+		}).Debug("Heartbeat is stopped, not responding to HeartbeatCheck")
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+
+	o.heartbeatLock.RLock()
+	defer o.heartbeatLock.RUnlock()
+
+	oltLogger.WithFields(log.Fields{
+		"oltId":              o.ID,
+		"heartbeatSignature": o.HeartbeatSignature,
+	}).Trace("HeartbeatCheck")
+	return &openolt.Heartbeat{HeartbeatSignature: o.HeartbeatSignature}, nil
 }
 
 func (o OltDevice) GetDeviceInfo(context.Context, *openolt.Empty) (*openolt.DeviceInfo, error) {
@@ -842,27 +1587,40 @@ func (o OltDevice) GetDeviceInfo(context.Context, *openolt.Empty) (*openolt.Devi
 		"PonPorts": o.NumPon,
 	}).Info("OLT receives GetDeviceInfo call from VOLTHA")
 	devinfo := new(openolt.DeviceInfo)
-	devinfo.Vendor = common.Options.Olt.Vendor
-	devinfo.Model = common.Options.Olt.Model
-	devinfo.HardwareVersion = common.Options.Olt.HardwareVersion
-	devinfo.FirmwareVersion = common.Options.Olt.FirmwareVersion
-	devinfo.Technology = common.Options.Olt.Technology
+	devinfo.Vendor = o.Options.Vendor
+	devinfo.Model = o.Options.Model
+	devinfo.HardwareVersion = o.Options.HardwareVersion
+	devinfo.FirmwareVersion = o.Options.FirmwareVersion
+	devinfo.Technology = o.Options.Technology
 	devinfo.PonPorts = uint32(o.NumPon)
-	devinfo.OnuIdStart = 1
-	devinfo.OnuIdEnd = 255
-	devinfo.AllocIdStart = 1024
-	devinfo.AllocIdEnd = 16383
-	devinfo.GemportIdStart = 1024
-	devinfo.GemportIdEnd = 65535
-	devinfo.FlowIdStart = 1
-	devinfo.FlowIdEnd = 16383
+	devinfo.OnuIdStart = onuIdStart
+	devinfo.OnuIdEnd = onuIdEnd
+	devinfo.AllocIdStart = allocIdStart
+	devinfo.AllocIdEnd = allocIdEnd
+	devinfo.GemportIdStart = gemportIdStart
+	devinfo.GemportIdEnd = gemportIdEnd
+	devinfo.FlowIdStart = flowIdStart
+	devinfo.FlowIdEnd = flowIdEnd
 	devinfo.DeviceSerialNumber = o.SerialNumber
-	devinfo.DeviceId = common.Options.Olt.DeviceId
+	devinfo.DeviceId = o.Options.DeviceId
 
 	return devinfo, nil
 }
 
-func (o OltDevice) OmciMsgOut(ctx context.Context, omci_msg *openolt.OmciMsg) (*openolt.Empty, error) {
+// RebootOnu reboots an ONU as VOLTHA does via OMCI,
+// the ONU goes down and is discovered again after the ONU reboot delay
+func (o *OltDevice) RebootOnu(serialNumber string) error {
+	onu, err := o.FindOnuBySn(serialNumber)
+	if err != nil {
+		return err
+	}
+	if !o.InternalState.Is("enabled") || o.enableStream == nil {
+		return errors.New(fmt.Sprintf("olt-%d-is-not-enabled", o.ID))
+	}
+	return onu.Reboot(o.enableContext, o.enableStream, nil)
+}
+
+func (o *OltDevice) OmciMsgOut(ctx context.Context, omci_msg *openolt.OmciMsg) (*openolt.Empty, error) {
 	pon, _ := o.GetPonById(omci_msg.IntfId)
 	onu, _ := pon.GetOnuById(omci_msg.OnuId)
 	oltLogger.WithFields(log.Fields{
@@ -870,19 +1628,38 @@ func (o OltDevice) OmciMsgOut(ctx context.Context, omci_msg *openolt.OmciMsg) (*
 		"OnuId":  onu.ID,
 		"OnuSn":  onu.Sn(),
 	}).Tracef("Received OmciMsgOut")
+
+	if onu.IsOffline() {
+		oltLogger.WithFields(log.Fields{
+			"IntfId":        onu.PonPortID,
+			"OnuId":         onu.ID,
+			"OnuSn":         onu.Sn(),
+			"InternalState": onu.InternalState.Current(),
+		}).Warn("Dropping OMCI message as the ONU is offline")
+		return new(openolt.Empty), nil
+	}
+
 	msg := Message{
 		Type: OMCI,
 		Data: OmciMessage{
-			OnuSN:   onu.SerialNumber,
-			OnuID:   onu.ID,
-			omciMsg: omci_msg,
+			OnuSN:      onu.SerialNumber,
+			OnuID:      onu.ID,
+			omciMsg:    omci_msg,
+			impairment: o.getOmciImpairment(onu.Sn(), HexDecode(omci_msg.Pkt)),
 		},
 	}
-	onu.Channel <- msg
+	// NOTE the ONU can go offline after the check above, the message is dropped in that case
+	if err := onu.SendMessage(msg); err != nil {
+		oltLogger.WithFields(log.Fields{
+			"IntfId": onu.PonPortID,
+			"OnuId":  onu.ID,
+			"OnuSn":  onu.Sn(),
+		}).Warnf("Dropping OMCI message: %v", err)
+	}
 	return new(openolt.Empty), nil
 }
 
-func (o OltDevice) OnuPacketOut(ctx context.Context, onuPkt *openolt.OnuPacket) (*openolt.Empty, error) {
+func (o *OltDevice) OnuPacketOut(ctx context.Context, onuPkt *openolt.OnuPacket) (*openolt.Empty, error) {
 	pon, err := o.GetPonById(onuPkt.IntfId)
 	if err != nil {
 		oltLogger.WithFields(log.Fields{
@@ -890,6 +1667,7 @@ func (o OltDevice) OnuPacketOut(ctx context.Context, onuPkt *openolt.OnuPacket)
 			"IntfId": onuPkt.IntfId,
 			"err":    err,
 		}).Error("Can't find PonPort")
+		return new(openolt.Empty), status.Errorf(codes.NotFound, err.Error())
 	}
 	onu, err := pon.GetOnuById(onuPkt.OnuId)
 	if err != nil {
@@ -898,6 +1676,7 @@ func (o OltDevice) OnuPacketOut(ctx context.Context, onuPkt *openolt.OnuPacket)
 			"IntfId": onuPkt.IntfId,
 			"err":    err,
 		}).Error("Can't find Onu")
+		return new(openolt.Empty), status.Errorf(codes.NotFound, err.Error())
 	}
 
 	oltLogger.WithFields(log.Fields{
@@ -906,75 +1685,216 @@ func (o OltDevice) OnuPacketOut(ctx context.Context, onuPkt *openolt.OnuPacket)
 		"OnuSn":  onu.Sn(),
 	}).Tracef("Received OnuPacketOut")
 
+	if onu.IsOffline() {
+		oltLogger.WithFields(log.Fields{
+			"IntfId":        onu.PonPortID,
+			"OnuId":         onu.ID,
+			"OnuSn":         onu.Sn(),
+			"InternalState": onu.InternalState.Current(),
+		}).Warn("Dropping OnuPacketOut as the ONU is offline")
+		return new(openolt.Empty), nil
+	}
+
 	rawpkt := gopacket.NewPacket(onuPkt.Pkt, layers.LayerTypeEthernet, gopacket.Default)
 	pktType, err := packetHandlers.IsEapolOrDhcp(rawpkt)
 
+	pon.Stats.countTx(len(onuPkt.Pkt))
+	o.countFlowPacket("downstream", int32(onuPkt.IntfId), int32(onuPkt.OnuId), rawpkt)
+
 	msg := Message{
 		Type: OnuPacketOut,
 		Data: OnuPacketMessage{
-			IntfId: onuPkt.IntfId,
-			OnuId:  onuPkt.OnuId,
-			Packet: rawpkt,
-			Type:   pktType,
+			IntfId:    onuPkt.IntfId,
+			OnuId:     onuPkt.OnuId,
+			PortNo:    onuPkt.PortNo,
+			GemPortId: onuPkt.GemportId,
+			Packet:    rawpkt,
+			Type:      pktType,
 		},
 	}
-	onu.Channel <- msg
+	if err := onu.SendMessage(msg); err != nil {
+		oltLogger.WithFields(log.Fields{
+			"IntfId": onu.PonPortID,
+			"OnuId":  onu.ID,
+			"OnuSn":  onu.Sn(),
+		}).Warnf("Dropping OnuPacketOut: %v", err)
+	}
 
 	return new(openolt.Empty), nil
 }
 
-func (o OltDevice) Reboot(context.Context, *openolt.Empty) (*openolt.Empty, error) {
+func (o *OltDevice) Reboot(context.Context, *openolt.Empty) (*openolt.Empty, error) {
 	oltLogger.WithFields(log.Fields{
 		"oltId": o.ID,
 	}).Info("Shutting down")
-	go o.RestartOLT()
+	go o.RestartOLT(SoftReboot, false)
 	return new(openolt.Empty), nil
 }
 
-func (o OltDevice) ReenableOlt(context.Context, *openolt.Empty) (*openolt.Empty, error) {
-	oltLogger.Error("ReenableOlt not implemented")
+func (o *OltDevice) ReenableOlt(context.Context, *openolt.Empty) (*openolt.Empty, error) {
+	oltLogger.WithFields(log.Fields{
+		"oltId": o.ID,
+	}).Info("Reenabling OLT")
+
+	if !o.InternalState.Is("disabled") {
+		err := errors.New(fmt.Sprintf("olt-%d-is-not-disabled", o.ID))
+		oltLogger.WithFields(log.Fields{
+			"oltId":         o.ID,
+			"InternalState": o.InternalState.Current(),
+		}).Error("Can't reenable OLT")
+		return new(openolt.Empty), err
+	}
+
+	// enable OLT
+	oltMsg := Message{
+		Type: OltIndication,
+		Data: OltIndicationMessage{
+			OperState: UP,
+		},
+	}
+	o.channel <- oltMsg
+
+	// the NNIs are not disabled with the OLT, but VOLTHA expects them to be reported
+	for _, nni := range o.Nnis {
+		msg := Message{
+			Type: NniIndication,
+			Data: NniIndicationMessage{
+				OperState: UP,
+				NniPortID: nni.ID,
+			},
+		}
+		o.channel <- msg
+	}
+
+	for _, pon := range o.Pons {
+		msg := Message{
+			Type: PonIndication,
+			Data: PonIndicationMessage{
+				OperState: UP,
+				PonPortID: pon.ID,
+			},
+		}
+		o.channel <- msg
+
+		// restore the ONUs that were activated before the OLT was disabled
+		for _, onu := range pon.GetOnus() {
+			// the ONUs plugged while the OLT was disabled are discovered now
+			if onu.InternalState.Is("initialized") {
+				go onu.ProcessOnuMessages(o.enableContext, o.enableStream, nil)
+				if err := onu.InternalState.Event("discover"); err != nil {
+					oltLogger.WithFields(log.Fields{
+						"IntfId": onu.PonPortID,
+						"OnuId":  onu.ID,
+						"OnuSn":  onu.Sn(),
+					}).Errorf("Error discover ONU: %v", err)
+				}
+				continue
+			}
+			if !onu.InternalState.Is("enabled") || !onu.OperState.Is("down") {
+				continue
+			}
+			if err := onu.OperState.Event("enable"); err != nil {
+				oltLogger.WithFields(log.Fields{
+					"IntfId": onu.PonPortID,
+					"OnuId":  onu.ID,
+					"OnuSn":  onu.Sn(),
+				}).Errorf("Failed to transition ONU.OperState to enabled state: %s", err.Error())
+			}
+			msg := Message{
+				Type: OnuIndication,
+				Data: OnuIndicationMessage{
+					OnuSN:     onu.SerialNumber,
+					PonPortID: onu.PonPortID,
+					OperState: UP,
+				},
+			}
+			if err := onu.SendMessage(msg); err != nil {
+				oltLogger.WithFields(log.Fields{
+					"IntfId": onu.PonPortID,
+					"OnuId":  onu.ID,
+					"OnuSn":  onu.Sn(),
+				}).Warnf("Can't send the ONU indication: %v", err)
+			}
+		}
+	}
+
 	return new(openolt.Empty), nil
 }
 
-func (o OltDevice) UplinkPacketOut(context context.Context, packet *openolt.UplinkPacket) (*openolt.Empty, error) {
+func (o *OltDevice) UplinkPacketOut(context context.Context, packet *openolt.UplinkPacket) (*openolt.Empty, error) {
+	nni, err := o.getNniById(packet.IntfId)
+	if err != nil {
+		oltLogger.WithFields(log.Fields{
+			"IntfId": packet.IntfId,
+			"err":    err,
+		}).Error("Can't find NniPort")
+		return new(openolt.Empty), err
+	}
+
 	pkt := gopacket.NewPacket(packet.Pkt, layers.LayerTypeEthernet, gopacket.Default)
 
-	o.Nnis[0].sendNniPacket(pkt) // FIXME we are assuming we have only one NNI
+	nni.Stats.countTx(len(packet.Pkt))
+	o.countFlowPacket("upstream", -1, -1, pkt)
+
+	nni.sendNniPacket(pkt)
 	// NOTE should we return an error if sendNniPakcet fails?
 	return new(openolt.Empty), nil
 }
 
-func (o OltDevice) CollectStatistics(context.Context, *openolt.Empty) (*openolt.Empty, error) {
-	oltLogger.Error("CollectStatistics not implemented")
+func (o *OltDevice) CollectStatistics(context.Context, *openolt.Empty) (*openolt.Empty, error) {
+	oltLogger.WithFields(log.Fields{
+		"oltId": o.ID,
+	}).Debug("Received CollectStatistics call from VOLTHA")
+
+	if !o.InternalState.Is("enabled") {
+		return new(openolt.Empty), errors.New(fmt.Sprintf("olt-%d-is-not-enabled", o.ID))
+	}
+
+	o.channel <- Message{Type: StatisticsIndication}
 	return new(openolt.Empty), nil
 }
 
-func (o OltDevice) GetOnuInfo(context context.Context, packet *openolt.Onu) (*openolt.OnuIndication, error) {
-	oltLogger.Error("GetOnuInfo not implemented")
-	return new(openolt.OnuIndication), nil
-}
+func (o *OltDevice) GetOnuInfo(_ context.Context, packet *openolt.Onu) (*openolt.OnuIndication, error) {
+	oltLogger.WithFields(log.Fields{
+		"IntfId": packet.IntfId,
+		"OnuId":  packet.OnuId,
+	}).Debug("Received GetOnuInfo call from VOLTHA")
 
-func (o OltDevice) GetPonIf(context context.Context, packet *openolt.Interface) (*openolt.IntfIndication, error) {
-	oltLogger.Error("GetPonIf not implemented")
-	return new(openolt.IntfIndication), nil
-}
+	onu, err := o.FindOnuById(packet.IntfId, packet.OnuId)
+	if err != nil {
+		oltLogger.WithFields(log.Fields{
+			"IntfId": packet.IntfId,
+			"OnuId":  packet.OnuId,
+			"err":    err,
+		}).Error("Can't find Onu")
+		return new(openolt.OnuIndication), err
+	}
 
-func (s OltDevice) CreateTrafficQueues(context.Context, *tech_profile.TrafficQueues) (*openolt.Empty, error) {
-	oltLogger.Info("received CreateTrafficQueues")
-	return new(openolt.Empty), nil
+	return &openolt.OnuIndication{
+		IntfId:       onu.PonPortID,
+		OnuId:        onu.ID,
+		OperState:    onu.OperState.Current(),
+		AdminState:   onu.AdminState(),
+		SerialNumber: onu.SerialNumber,
+	}, nil
 }
 
-func (s OltDevice) RemoveTrafficQueues(context.Context, *tech_profile.TrafficQueues) (*openolt.Empty, error) {
-	oltLogger.Info("received RemoveTrafficQueues")
-	return new(openolt.Empty), nil
-}
+func (o *OltDevice) GetPonIf(_ context.Context, intf *openolt.Interface) (*openolt.IntfIndication, error) {
+	oltLogger.WithFields(log.Fields{
+		"IntfId": intf.IntfId,
+	}).Debug("Received GetPonIf call from VOLTHA")
 
-func (s OltDevice) CreateTrafficSchedulers(context.Context, *tech_profile.TrafficSchedulers) (*openolt.Empty, error) {
-	oltLogger.Info("received CreateTrafficSchedulers")
-	return new(openolt.Empty), nil
-}
+	pon, err := o.GetPonById(intf.IntfId)
+	if err != nil {
+		oltLogger.WithFields(log.Fields{
+			"IntfId": intf.IntfId,
+			"err":    err,
+		}).Error("Can't find PonPort")
+		return new(openolt.IntfIndication), err
+	}
 
-func (s OltDevice) RemoveTrafficSchedulers(context.Context, *tech_profile.TrafficSchedulers) (*openolt.Empty, error) {
-	oltLogger.Info("received RemoveTrafficSchedulers")
-	return new(openolt.Empty), nil
+	return &openolt.IntfIndication{
+		IntfId:    pon.ID,
+		OperState: pon.OperState.Current(),
+	}, nil
 }
@@ -17,14 +17,27 @@
 package devices
 
 import (
+	"context"
+	"github.com/looplab/fsm"
+	"github.com/opencord/bbsim/internal/common"
+	omcilib "github.com/opencord/bbsim/internal/common/omci"
+	omcisim "github.com/opencord/omci-sim"
+	"github.com/opencord/voltha-protos/v2/go/openolt"
+	"github.com/opencord/voltha-protos/v2/go/tech_profile"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 	"gotest.tools/assert"
 	"net"
+	"sync"
 	"testing"
+	"time"
 )
 
 func createMockOlt(numPon int, numOnu int) OltDevice {
 	olt := OltDevice{
-		ID: 0,
+		ID:    0,
+		Flows: make(map[FlowKey]openolt.Flow),
 	}
 
 	for i := 0; i < numPon; i++ {
@@ -34,14 +47,9 @@ func createMockOlt(numPon int, numOnu int) OltDevice {
 
 		for j := 0; j < numOnu; j++ {
 			onuId := uint32(i + j)
-			onu := Onu{
-				ID:        onuId,
-				PonPort:   pon,
-				PonPortID: pon.ID,
-				HwAddress: net.HardwareAddr{0x2e, 0x60, 0x70, 0x13, byte(pon.ID), byte(onuId)},
-			}
-			onu.SerialNumber = onu.NewSN(olt.ID, pon.ID, onu.ID)
-			pon.Onus = append(pon.Onus, &onu)
+			onu := CreateONU(olt, pon, onuId, 900, 900, false, false)
+			onu.Channel = make(chan Message, 10)
+			pon.Onus = append(pon.Onus, onu)
 		}
 		olt.Pons = append(olt.Pons, &pon)
 	}
@@ -105,3 +113,674 @@ func Test_Olt_FindOnuByMacAddress_Error(t *testing.T) {
 
 	assert.Equal(t, err.Error(), "cannot-find-onu-by-mac-address-2e:60:70:13:03:03")
 }
+
+func Test_Olt_FlowAdd_StoresFlow(t *testing.T) {
+
+	olt := createMockOlt(1, 2)
+
+	flow := openolt.Flow{
+		AccessIntfId: 0,
+		OnuId:        1,
+		FlowId:       64,
+		FlowType:     "upstream",
+		Classifier:   &openolt.Classifier{EthType: 34958},
+	}
+
+	_, err := olt.FlowAdd(context.TODO(), &flow)
+	assert.Equal(t, err, nil)
+
+	// adding the same flow twice must not duplicate it
+	_, err = olt.FlowAdd(context.TODO(), &flow)
+	assert.Equal(t, err, nil)
+
+	assert.Equal(t, len(olt.GetFlows()), 1)
+
+	onu, _ := olt.FindOnuById(0, 1)
+	onuFlows := olt.GetOnuFlows(onu)
+	assert.Equal(t, len(onuFlows), 1)
+	assert.Equal(t, onuFlows[0].FlowId, uint32(64))
+
+	other, _ := olt.FindOnuById(0, 0)
+	assert.Equal(t, len(olt.GetOnuFlows(other)), 0)
+}
+
+func Test_Olt_FlowAdd_UnknownOnu(t *testing.T) {
+
+	olt := createMockOlt(1, 2)
+
+	_, err := olt.FlowAdd(context.TODO(), &openolt.Flow{AccessIntfId: 1, OnuId: 1, FlowId: 64, FlowType: "upstream", Classifier: &openolt.Classifier{}})
+	assert.Equal(t, status.Code(err), codes.NotFound)
+
+	_, err = olt.FlowAdd(context.TODO(), &openolt.Flow{AccessIntfId: 0, OnuId: 5, FlowId: 64, FlowType: "upstream", Classifier: &openolt.Classifier{}})
+	assert.Equal(t, status.Code(err), codes.NotFound)
+
+	// the rejected flows are not stored
+	assert.Equal(t, len(olt.GetFlows()), 0)
+}
+
+func Test_Olt_FlowRemove_DeletesFlow(t *testing.T) {
+
+	olt := createMockOlt(1, 2)
+
+	flows := []openolt.Flow{
+		{AccessIntfId: 0, OnuId: 1, FlowId: 64, FlowType: "upstream", Classifier: &openolt.Classifier{}},
+		{AccessIntfId: 0, OnuId: 1, FlowId: 64, FlowType: "downstream", Classifier: &openolt.Classifier{}},
+	}
+
+	for i := range flows {
+		_, err := olt.FlowAdd(context.TODO(), &flows[i])
+		assert.Equal(t, err, nil)
+	}
+	assert.Equal(t, len(olt.GetFlows()), 2)
+
+	_, err := olt.FlowRemove(context.TODO(), &openolt.Flow{FlowId: 64, FlowType: "upstream"})
+	assert.Equal(t, err, nil)
+
+	assert.Equal(t, len(olt.GetFlows()), 1)
+
+	onu, _ := olt.FindOnuById(0, 1)
+	onuFlows := olt.GetOnuFlows(onu)
+	assert.Equal(t, len(onuFlows), 1)
+	assert.Equal(t, onuFlows[0].FlowType, "downstream")
+}
+
+func Test_Olt_DisablePonIf(t *testing.T) {
+
+	olt := createMockOlt(1, 2)
+	olt.InternalState = fsm.NewFSM("enabled", fsm.Events{}, fsm.Callbacks{})
+	olt.channel = make(chan Message, 10)
+
+	enabled, _ := olt.FindOnuById(0, 0)
+	enabled.InternalState.SetState("enabled")
+	enabled.UniPorts[0].InternalState.SetState("dhcp_ack_received")
+	discovered, _ := olt.FindOnuById(0, 1)
+	discovered.InternalState.SetState("discovered")
+
+	_, err := olt.DisablePonIf(context.TODO(), &openolt.Interface{IntfId: 0})
+	assert.Equal(t, err, nil)
+
+	msg := <-olt.channel
+	assert.Equal(t, msg.Type, PonIndication)
+	assert.Equal(t, msg.Data.(PonIndicationMessage).OperState, DOWN)
+
+	// an activated ONU reports that it went down, then stops processing messages
+	assert.Equal(t, enabled.InternalState.Current(), "pon_disabled")
+	assert.Equal(t, enabled.UniPorts[0].InternalState.Current(), "disabled")
+	msg = <-enabled.Channel
+	assert.Equal(t, msg.Type, OnuIndication)
+	assert.Equal(t, msg.Data.(OnuIndicationMessage).OperState, DOWN)
+	_, ok := <-enabled.Channel
+	assert.Equal(t, ok, false)
+
+	assert.Equal(t, discovered.InternalState.Current(), "pon_disabled")
+	_, ok = <-discovered.Channel
+	assert.Equal(t, ok, false)
+}
+
+func Test_Olt_DisablePonIf_Error(t *testing.T) {
+
+	olt := createMockOlt(1, 1)
+	olt.InternalState = fsm.NewFSM("enabled", fsm.Events{}, fsm.Callbacks{})
+
+	_, err := olt.DisablePonIf(context.TODO(), &openolt.Interface{IntfId: 5})
+	assert.Equal(t, err.Error(), "Cannot find PonPort with id 5 in OLT 0")
+}
+
+func Test_Olt_DisablePonIf_NotEnabled(t *testing.T) {
+
+	olt := createMockOlt(1, 1)
+	olt.InternalState = fsm.NewFSM("disabled", fsm.Events{}, fsm.Callbacks{})
+
+	// the OLT channel is not read, the call must not block on it
+	_, err := olt.DisablePonIf(context.TODO(), &openolt.Interface{IntfId: 0})
+	assert.Equal(t, status.Code(err), codes.FailedPrecondition)
+
+	_, err = olt.EnablePonIf(context.TODO(), &openolt.Interface{IntfId: 0})
+	assert.Equal(t, status.Code(err), codes.FailedPrecondition)
+}
+
+func Test_Olt_EnablePonIf(t *testing.T) {
+
+	olt := createMockOlt(1, 1)
+	olt.InternalState = fsm.NewFSM("enabled", fsm.Events{}, fsm.Callbacks{})
+	olt.channel = make(chan Message, 10)
+
+	stream := &mockStream{
+		Calls:   make(map[int]*openolt.OnuDiscIndication),
+		channel: make(chan int, 10),
+	}
+	ctx, cancel := context.WithCancel(context.TODO())
+	defer cancel()
+	olt.enableContext = ctx
+	olt.enableStream = stream
+
+	onu, _ := olt.FindOnuById(0, 0)
+	onu.InternalState.SetState("pon_disabled")
+
+	_, err := olt.EnablePonIf(context.TODO(), &openolt.Interface{IntfId: 0})
+	assert.Equal(t, err, nil)
+
+	msg := <-olt.channel
+	assert.Equal(t, msg.Type, PonIndication)
+	assert.Equal(t, msg.Data.(PonIndicationMessage).OperState, UP)
+
+	// the ONU is discovered again
+	assert.Equal(t, onu.InternalState.Current(), "discovered")
+	select {
+	case <-stream.channel:
+		assert.Equal(t, stream.Calls[1].SerialNumber, onu.SerialNumber)
+	case <-time.After(time.Second):
+		t.Fatal("OnuDiscIndication not received")
+	}
+}
+
+func Test_NewHeartbeatSignature(t *testing.T) {
+	signature := newHeartbeatSignature(0)
+	assert.Assert(t, signature > 0)
+
+	// the signature changes even if the OLT reboots within the same second
+	assert.Equal(t, newHeartbeatSignature(signature), signature+1)
+}
+
+func Test_Olt_HeartbeatCheck(t *testing.T) {
+
+	olt := createMockOlt(1, 1)
+	olt.HeartbeatSignature = 1234
+
+	res, err := olt.HeartbeatCheck(context.TODO(), &openolt.Empty{})
+	assert.NilError(t, err)
+	assert.Equal(t, res.HeartbeatSignature, uint32(1234))
+
+	// a stopped heartbeat never responds
+	olt.StopHeartbeat()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	res, err = olt.HeartbeatCheck(ctx, &openolt.Empty{})
+	assert.Equal(t, err, context.DeadlineExceeded)
+	assert.Assert(t, res == nil)
+
+	olt.StartHeartbeat()
+	res, err = olt.HeartbeatCheck(context.TODO(), &openolt.Empty{})
+	assert.NilError(t, err)
+	assert.Equal(t, res.HeartbeatSignature, uint32(1234))
+}
+
+func Test_Olt_GetOnuInfo(t *testing.T) {
+
+	olt := createMockOlt(2, 2)
+	onu, _ := olt.FindOnuById(1, 2)
+	onu.OperState.SetState("up")
+	onu.InternalState.SetState("enabled")
+
+	res, err := olt.GetOnuInfo(context.TODO(), &openolt.Onu{IntfId: 1, OnuId: 2})
+	assert.NilError(t, err)
+	assert.Equal(t, res.IntfId, uint32(1))
+	assert.Equal(t, res.OnuId, uint32(2))
+	assert.Equal(t, res.OperState, "up")
+	assert.Equal(t, res.AdminState, "up")
+	assert.Equal(t, common.OnuSnToString(res.SerialNumber), onu.Sn())
+
+	onu.InternalState.SetState("pon_disabled")
+	res, err = olt.GetOnuInfo(context.TODO(), &openolt.Onu{IntfId: 1, OnuId: 2})
+	assert.NilError(t, err)
+	assert.Equal(t, res.AdminState, "down")
+
+	_, err = olt.GetOnuInfo(context.TODO(), &openolt.Onu{IntfId: 1, OnuId: 5})
+	assert.Error(t, err, "cannot-find-onu-by-id-1-5")
+}
+
+func Test_Olt_GetPonIf(t *testing.T) {
+
+	olt := createMockOlt(2, 1)
+	olt.Pons[1].OperState = getOperStateFSM(func(e *fsm.Event) {})
+	olt.Pons[1].OperState.SetState("up")
+
+	res, err := olt.GetPonIf(context.TODO(), &openolt.Interface{IntfId: 1})
+	assert.NilError(t, err)
+	assert.Equal(t, res.IntfId, uint32(1))
+	assert.Equal(t, res.OperState, "up")
+
+	_, err = olt.GetPonIf(context.TODO(), &openolt.Interface{IntfId: 5})
+	assert.Error(t, err, "Cannot find PonPort with id 5 in OLT 0")
+}
+
+func Test_Olt_DisableOlt_ReenableOlt(t *testing.T) {
+
+	olt := createMockOlt(1, 2)
+	olt.channel = make(chan Message, 10)
+	olt.InternalState = fsm.NewFSM("enabled", fsm.Events{}, fsm.Callbacks{})
+
+	active, _ := olt.FindOnuById(0, 0)
+	active.InternalState.SetState("enabled")
+	active.OperState.SetState("up")
+	active.UniPorts[0].InternalState.SetState("dhcp_ack_received")
+	discovered, _ := olt.FindOnuById(0, 1)
+	discovered.InternalState.SetState("discovered")
+
+	_, err := olt.DisableOlt(context.TODO(), &openolt.Empty{})
+	assert.NilError(t, err)
+
+	msg := <-olt.channel
+	assert.Equal(t, msg.Type, PonIndication)
+	assert.Equal(t, msg.Data.(PonIndicationMessage).OperState, DOWN)
+	msg = <-olt.channel
+	assert.Equal(t, msg.Type, OltIndication)
+	assert.Equal(t, msg.Data.(OltIndicationMessage).OperState, DOWN)
+
+	// the activated ONU is reported as down but retains its state
+	assert.Equal(t, active.OperState.Current(), "down")
+	assert.Equal(t, active.InternalState.Current(), "enabled")
+	msg = <-active.Channel
+	assert.Equal(t, msg.Type, OnuIndication)
+	assert.Equal(t, msg.Data.(OnuIndicationMessage).OperState, DOWN)
+	assert.Equal(t, len(discovered.Channel), 0)
+
+	// processOltMessages moves the OLT to disabled when the indication is sent
+	olt.InternalState.SetState("disabled")
+
+	_, err = olt.ReenableOlt(context.TODO(), &openolt.Empty{})
+	assert.NilError(t, err)
+
+	msg = <-olt.channel
+	assert.Equal(t, msg.Type, OltIndication)
+	assert.Equal(t, msg.Data.(OltIndicationMessage).OperState, UP)
+	msg = <-olt.channel
+	assert.Equal(t, msg.Type, PonIndication)
+	assert.Equal(t, msg.Data.(PonIndicationMessage).OperState, UP)
+
+	assert.Equal(t, active.OperState.Current(), "up")
+	assert.Equal(t, active.UniPorts[0].InternalState.Current(), "dhcp_ack_received")
+	msg = <-active.Channel
+	assert.Equal(t, msg.Type, OnuIndication)
+	assert.Equal(t, msg.Data.(OnuIndicationMessage).OperState, UP)
+	assert.Equal(t, len(discovered.Channel), 0)
+}
+
+func Test_Olt_ReenableOlt_NotDisabled(t *testing.T) {
+
+	olt := createMockOlt(1, 1)
+	olt.InternalState = fsm.NewFSM("enabled", fsm.Events{}, fsm.Callbacks{})
+
+	_, err := olt.ReenableOlt(context.TODO(), &openolt.Empty{})
+	assert.Error(t, err, "olt-0-is-not-disabled")
+}
+
+func Test_Olt_DeleteOnu(t *testing.T) {
+
+	olt := createMockOlt(1, 2)
+	stream := &mockStream{
+		Calls:   make(map[int]*openolt.OnuDiscIndication),
+		channel: make(chan int, 10),
+	}
+	olt.enableStream = stream
+	olt.enableContext, olt.enableContextCancel = context.WithCancel(context.TODO())
+	defer olt.enableContextCancel()
+
+	onu := olt.Pons[0].Onus[0]
+	onu.InternalState.SetState("enabled")
+	onu.OperState.SetState("up")
+	onu.HasGemPort = true
+	onu.UniPorts[0].InternalState.SetState("dhcp_ack_received")
+	onu.UniPorts[0].PortNo = 16
+	onu.UniPorts[0].DhcpFlowReceived = true
+	onu.UniPorts[0].TrafficSchedulers = []*tech_profile.TrafficScheduler{{AllocId: 1024}}
+	onu.UniPorts[0].TrafficQueues = []*tech_profile.TrafficQueue{{GemportId: 1024}}
+	omcisim.OnuOmciStateMap[omcisim.OnuKey{IntfId: onu.omciSimIntfId(), OnuId: onu.ID}] = omcisim.NewOnuOmciState()
+
+	// a flow for the deleted ONU and one for another ONU
+	onuFlow := FlowKey{ID: 1, Direction: "upstream"}
+	otherFlow := FlowKey{ID: 2, Direction: "upstream"}
+	olt.Flows[onuFlow] = openolt.Flow{FlowId: 1, FlowType: "upstream", OnuId: int32(onu.ID)}
+	olt.Flows[otherFlow] = openolt.Flow{FlowId: 2, FlowType: "upstream", OnuId: int32(olt.Pons[0].Onus[1].ID)}
+	onu.Flows = []FlowKey{onuFlow}
+	olt.Pons[0].Onus[1].Flows = []FlowKey{otherFlow}
+
+	_, err := olt.DeleteOnu(context.TODO(), &openolt.Onu{
+		IntfId:       onu.PonPortID,
+		OnuId:        onu.ID,
+		SerialNumber: onu.SerialNumber,
+	})
+	assert.NilError(t, err)
+
+	assert.Equal(t, onu.OperState.Current(), "down")
+	assert.Equal(t, onu.HasGemPort, false)
+	assert.Equal(t, onu.UniPorts[0].InternalState.Current(), "disabled")
+	assert.Equal(t, onu.UniPorts[0].PortNo, uint32(0))
+	assert.Equal(t, onu.UniPorts[0].DhcpFlowReceived, false)
+	assert.Equal(t, len(onu.UniPorts[0].TrafficSchedulers), 0)
+	assert.Equal(t, len(onu.UniPorts[0].TrafficQueues), 0)
+	assert.Equal(t, len(onu.Flows), 0)
+	assert.Equal(t, len(olt.Flows), 1)
+	_, ok := olt.Flows[otherFlow]
+	assert.Equal(t, ok, true)
+	_, ok = omcisim.OnuOmciStateMap[omcisim.OnuKey{IntfId: onu.omciSimIntfId(), OnuId: onu.ID}]
+	assert.Equal(t, ok, false)
+
+	// the ONU is discovered again
+	assert.Equal(t, onu.InternalState.Current(), "discovered")
+	select {
+	case <-stream.channel:
+		assert.Equal(t, stream.Calls[1].SerialNumber, onu.SerialNumber)
+	case <-time.After(1 * time.Second):
+		t.Fatal("the ONU has not been rediscovered")
+	}
+}
+
+func Test_Olt_DeleteOnu_NotFound(t *testing.T) {
+
+	olt := createMockOlt(1, 1)
+
+	_, err := olt.DeleteOnu(context.TODO(), &openolt.Onu{
+		IntfId:       0,
+		OnuId:        1,
+		SerialNumber: olt.Pons[0].Onus[0].NewSN(0, 0, 4),
+	})
+	assert.ErrorContains(t, err, "Cannot find Onu")
+}
+
+func Test_Olt_GetOLTById(t *testing.T) {
+
+	created := []*OltDevice{}
+	for _, id := range []int{11, 12} {
+		options := common.OltConfig{
+			ID:          id,
+			PonPorts:    1,
+			OnusPonPort: 1,
+			UnisPerOnu:  1,
+		}
+		olt, err := CreateOLT(options, 900, 900, false, false, 0, true)
+		assert.NilError(t, err)
+		defer unregisterOlt(olt)
+		created = append(created, olt)
+	}
+
+	// the OLT IDs are unique
+	_, err := CreateOLT(common.OltConfig{ID: 11}, 900, 900, false, false, 0, true)
+	assert.Error(t, err, "olt-11-already-exists")
+
+	olt, err := GetOLTById(12)
+	assert.NilError(t, err)
+	assert.Equal(t, olt, created[1])
+	assert.Equal(t, olt.SerialNumber, "BBSIM_OLT_12")
+
+	// the ONU serial numbers contain the OLT ID
+	assert.Equal(t, olt.Pons[0].Onus[0].Sn(), "BBSM000c0001")
+
+	_, err = GetOLTById(13)
+	assert.Error(t, err, "cannot-find-olt-13")
+}
+
+func Test_Olt_CreateOLT_DisjointSubscribers(t *testing.T) {
+
+	created := []*OltDevice{}
+	for _, id := range []int{15, 16} {
+		options := common.OltConfig{
+			ID:          id,
+			PonPorts:    2,
+			OnusPonPort: 2,
+			UnisPerOnu:  2,
+		}
+		olt, err := CreateOLT(options, 900, 900, false, false, 0, true)
+		assert.NilError(t, err)
+		defer unregisterOlt(olt)
+		created = append(created, olt)
+	}
+
+	// the UNIs with the same IDs on different OLTs have different MAC addresses and S-Tags
+	// (the first UNI has the MAC address of its ONU)
+	macs := map[string]int{}
+	tags := map[[2]int]int{}
+	for _, olt := range created {
+		for _, pon := range olt.Pons {
+			for _, onu := range pon.Onus {
+				assert.Equal(t, onu.STag, 900+olt.ID)
+				for _, uni := range onu.UniPorts {
+					macs[uni.HwAddress.String()]++
+					tags[[2]int{onu.STag, uni.CTag}]++
+				}
+			}
+		}
+	}
+	for mac, count := range macs {
+		assert.Equal(t, count, 1, "MAC address %s is used %d times", mac, count)
+	}
+	assert.Equal(t, len(tags), 16)
+	for tag, count := range tags {
+		assert.Equal(t, count, 1, "S-Tag %d and C-Tag %d are used %d times", tag[0], tag[1], count)
+	}
+	assert.Equal(t, created[1].Pons[1].Onus[0].HwAddress.String(), "2e:60:80:13:01:01")
+}
+
+func Test_Olt_OmciMessages_OverlappingIds(t *testing.T) {
+
+	// the ONUs of the two OLTs have the same PON and ONU IDs
+	onus := []*Onu{}
+	for _, id := range []int{17, 18} {
+		options := common.OltConfig{
+			ID:          id,
+			PonPorts:    1,
+			OnusPonPort: 1,
+			UnisPerOnu:  1,
+		}
+		olt, err := CreateOLT(options, 900, 900, false, false, 0, true)
+		assert.NilError(t, err)
+		defer unregisterOlt(olt)
+		onu := olt.Pons[0].Onus[0]
+		onu.UniPorts[0].InternalState.SetState("enabled")
+		onus = append(onus, onu)
+	}
+	startOmciDispatcher()
+
+	// the GEM port is only created on the ONU of the second OLT
+	req, _ := omcilib.CreateGemPortRequest(1)
+	_, err := onus[1].omciResponse(HexDecode(req))
+	assert.NilError(t, err)
+
+	_, err = omcisim.GetGemPortId(onus[1].omciSimIntfId(), onus[1].ID)
+	assert.NilError(t, err)
+	_, err = omcisim.GetGemPortId(onus[0].omciSimIntfId(), onus[0].ID)
+	assert.ErrorContains(t, err, "Failed to find a key")
+
+	// the omci-sim message reaches the ONU of the second OLT
+	for i := 0; i < 100 && !onus[1].UniPorts[0].InternalState.Is("gem_port_added"); i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+	assert.Equal(t, onus[1].UniPorts[0].InternalState.Current(), "gem_port_added")
+	assert.Equal(t, onus[0].UniPorts[0].InternalState.Current(), "enabled")
+}
+
+func Test_Olt_CreateOLT_Topology(t *testing.T) {
+
+	auth := true
+	options := common.OltConfig{
+		ID:          14,
+		PonPorts:    2,
+		OnusPonPort: 2,
+		UnisPerOnu:  2,
+		Topology: &common.OltTopology{
+			ID: 14,
+			Pons: []common.PonTopology{
+				{
+					ID: 1,
+					Onus: []common.OnuTopology{
+						{SerialNumber: "ABCD00000042", MacAddress: "00:11:22:33:44:55", STag: 100, CTag: 200, Auth: &auth, EapolUsername: "subscriber", EapolPassword: "secret"},
+						{VendorId: "EFGH"},
+						{},
+					},
+				},
+			},
+		},
+	}
+	olt, err := CreateOLT(options, 900, 900, false, false, 0, true)
+	assert.NilError(t, err)
+	defer unregisterOlt(olt)
+
+	// the PONs that are not in the topology get onus_per_port ONUs
+	assert.Equal(t, len(olt.Pons[0].Onus), 2)
+	assert.Equal(t, olt.Pons[0].Onus[1].CTag, 902)
+
+	pon := olt.Pons[1]
+	assert.Equal(t, len(pon.Onus), 3)
+	assert.Equal(t, pon.NumOnu, 3)
+
+	onu := pon.Onus[0]
+	assert.Equal(t, onu.ID, uint32(1))
+	assert.Equal(t, onu.Sn(), "ABCD00000042")
+	assert.Equal(t, onu.HwAddress.String(), "00:11:22:33:44:55")
+	assert.Equal(t, onu.STag, 100)
+	assert.Equal(t, onu.CTag, 200)
+	assert.Equal(t, onu.Auth, true)
+	assert.Equal(t, onu.Dhcp, false)
+	assert.Equal(t, onu.EapolCredentials.Username, "subscriber")
+	assert.Equal(t, onu.EapolCredentials.Password, "secret")
+	assert.Equal(t, onu.UniPorts[1].HwAddress.String(), "00:11:22:34:44:55")
+	assert.Equal(t, onu.UniPorts[1].CTag, 201)
+
+	// the values that are not in the topology are generated
+	onu = pon.Onus[1]
+	assert.Equal(t, onu.Sn(), "EFGH000e0102")
+	assert.Equal(t, onu.STag, 914)
+	assert.Equal(t, onu.CTag, 904)
+	assert.Equal(t, onu.EapolCredentials.Username, "user")
+	assert.Equal(t, pon.Onus[2].Sn(), "BBSM000e0103")
+	assert.Equal(t, pon.Onus[2].CTag, 906)
+}
+
+func Test_ValidateRebootMode(t *testing.T) {
+	for _, mode := range []string{SoftReboot, HardReboot, HangReboot} {
+		assert.NilError(t, ValidateRebootMode(mode))
+	}
+	assert.Error(t, ValidateRebootMode("cold"), "invalid-reboot-mode-cold")
+}
+
+func Test_Olt_Hang(t *testing.T) {
+
+	olt := createMockOlt(1, 1)
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return "answered", nil
+	}
+
+	res, err := olt.hangUnaryInterceptor(context.TODO(), nil, nil, handler)
+	assert.NilError(t, err)
+	assert.Equal(t, res, "answered")
+
+	// an hung OLT does not answer
+	olt.hang()
+	assert.Equal(t, olt.isHung(), true)
+	ctx, cancel := context.WithTimeout(context.TODO(), 50*time.Millisecond)
+	defer cancel()
+	_, err = olt.hangUnaryInterceptor(ctx, nil, nil, handler)
+	assert.Equal(t, err, context.DeadlineExceeded)
+
+	// the pending requests are answered once the OLT is not hung anymore
+	done := make(chan interface{})
+	go func() {
+		res, _ := olt.hangUnaryInterceptor(context.TODO(), nil, nil, handler)
+		done <- res
+	}()
+	olt.unhang()
+	select {
+	case res := <-done:
+		assert.Equal(t, res, "answered")
+	case <-time.After(1 * time.Second):
+		t.Fatal("the request has not been answered")
+	}
+	assert.Equal(t, olt.isHung(), false)
+}
+
+// mockSlowStream takes some time to send each indication, as a busy connection to VOLTHA
+type mockSlowStream struct {
+	grpc.ServerStream
+	delay time.Duration
+	lock  sync.Mutex
+	sent  int
+}
+
+func (s *mockSlowStream) Send(ind *openolt.Indication) error {
+	time.Sleep(s.delay)
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.sent++
+	return nil
+}
+
+func (s *mockSlowStream) getSent() int {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.sent
+}
+
+func Test_Olt_FlushIndications(t *testing.T) {
+
+	olt := createMockOlt(1, 1)
+	olt.channel = make(chan Message)
+	stream := &mockSlowStream{delay: 100 * time.Millisecond}
+
+	ctx, cancel := context.WithCancel(context.TODO())
+	defer cancel()
+	wg := sync.WaitGroup{}
+	wg.Add(1)
+	go olt.processOltMessages(ctx, stream, &wg)
+
+	// the statistics of the NNI and of the PON
+	olt.channel <- Message{Type: StatisticsIndication}
+
+	// the flush returns once the statistics have been sent
+	olt.flushIndications()
+	assert.Equal(t, stream.getSent(), 1)
+}
+
+func Test_Olt_HangStream(t *testing.T) {
+
+	olt := createMockOlt(1, 1)
+	stream := &mockSlowStream{}
+	hangStream := newHangStream(context.TODO(), &olt, stream)
+
+	// an hung OLT doesn't send any indication
+	olt.hang()
+	done := make(chan error)
+	go func() {
+		done <- hangStream.Send(&openolt.Indication{})
+	}()
+	select {
+	case <-done:
+		t.Fatal("the indication has been sent by an hung OLT")
+	case <-time.After(50 * time.Millisecond):
+	}
+	assert.Equal(t, stream.getSent(), 0)
+
+	// the indications are sent once the OLT is not hung anymore
+	olt.unhang()
+	select {
+	case err := <-done:
+		assert.NilError(t, err)
+	case <-time.After(1 * time.Second):
+		t.Fatal("the indication has not been sent")
+	}
+	assert.Equal(t, stream.getSent(), 1)
+
+	// the indications are dropped if the OLT is restarted while hung
+	ctx, cancel := context.WithCancel(context.TODO())
+	hangStream = newHangStream(ctx, &olt, stream)
+	olt.hang()
+	cancel()
+	assert.Equal(t, hangStream.Send(&openolt.Indication{}), context.Canceled)
+	olt.unhang()
+	assert.Equal(t, stream.getSent(), 1)
+}
+
+func Test_Olt_SendDyingGasps(t *testing.T) {
+
+	olt := createMockOlt(1, 2)
+	olt.channel = make(chan Message, 10)
+	olt.Pons[0].Onus[1].InternalState.SetState("enabled")
+
+	olt.sendDyingGasps()
+
+	// only the activated ONUs send a DyingGasp
+	assert.Equal(t, len(olt.channel), 1)
+	msg := <-olt.channel
+	dyingGasp := msg.Data.(AlarmIndicationMessage).Alarm.GetDyingGaspInd()
+	assert.Equal(t, dyingGasp.OnuId, olt.Pons[0].Onus[1].ID)
+	assert.Equal(t, dyingGasp.Status, "on")
+}
@@ -0,0 +1,188 @@
+/*
+ * Copyright 2018-present Open Networking Foundation
+
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+
+ * http://www.apache.org/licenses/LICENSE-2.0
+
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package devices
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"time"
+
+	omcisim "github.com/opencord/omci-sim"
+	log "github.com/sirupsen/logrus"
+)
+
+// the highest OMCI result code, 9 is "attribute(s) failed or unknown"
+const omciMaxResultCode = 9
+
+// OmciFaultRule describes how the ONUs impair their OMCI responses
+type OmciFaultRule struct {
+	OnuSn      string        // the ONU the rule applies to, empty for all the ONUs that don't have a rule of their own
+	MeClass    uint16        // the ME class of the requests the rule applies to, 0 for all of them
+	DropNth    int           // drop only the Nth response (1 drops the first one), 0 to not drop responses
+	DropEvery  int           // drop every Nth response (1 drops all of them), 0 to not drop responses
+	Latency    time.Duration // delay the responses, the responses that are not delayed overtake the delayed ones
+	Corrupt    bool          // flip a random bit in the payload of the responses
+	WrongTid   bool          // answer with a transaction ID that does not match the request
+	ResultCode uint8         // answer with this result code, 0 keeps the result of the request
+}
+
+// omciFaultRuleState is an installed OmciFaultRule and the number of responses it matched
+type omciFaultRuleState struct {
+	OmciFaultRule
+	matched int
+}
+
+// omciImpairment is what an OmciFaultRule does to a single OMCI response
+type omciImpairment struct {
+	drop       bool
+	latency    time.Duration
+	corrupt    bool
+	wrongTid   bool
+	resultCode uint8
+}
+
+// Validate checks the values of an OmciFaultRule
+func (r OmciFaultRule) Validate() error {
+	if r.DropNth < 0 {
+		return errors.New(fmt.Sprintf("invalid-omci-fault-drop-nth-%d", r.DropNth))
+	}
+	if r.DropEvery < 0 {
+		return errors.New(fmt.Sprintf("invalid-omci-fault-drop-every-%d", r.DropEvery))
+	}
+	if r.Latency < 0 {
+		return errors.New(fmt.Sprintf("invalid-omci-fault-latency-%s", r.Latency))
+	}
+	if r.ResultCode > omciMaxResultCode {
+		return errors.New(fmt.Sprintf("invalid-omci-fault-result-code-%d", r.ResultCode))
+	}
+	return nil
+}
+
+// SetOmciFaultRules replaces the impairments of the OMCI responses, an empty list removes them all.
+// A rule for an ONU takes precedence over a rule for all the ONUs, if more rules
+// match the same request only the first one applies
+func (o *OltDevice) SetOmciFaultRules(rules []OmciFaultRule) error {
+	for _, rule := range rules {
+		if err := rule.Validate(); err != nil {
+			return err
+		}
+		if rule.OnuSn != "" {
+			if _, err := o.FindOnuBySn(rule.OnuSn); err != nil {
+				return err
+			}
+		}
+	}
+
+	o.omciFaultRulesLock.Lock()
+	defer o.omciFaultRulesLock.Unlock()
+	o.omciFaultRules = []*omciFaultRuleState{}
+	for _, rule := range rules {
+		o.omciFaultRules = append(o.omciFaultRules, &omciFaultRuleState{OmciFaultRule: rule})
+	}
+
+	oltLogger.WithFields(log.Fields{
+		"oltId": o.ID,
+		"rules": rules,
+	}).Info("OMCI fault rules updated")
+	return nil
+}
+
+// GetOmciFaultRules returns the impairments of the OMCI responses
+func (o *OltDevice) GetOmciFaultRules() []OmciFaultRule {
+	o.omciFaultRulesLock.RLock()
+	defer o.omciFaultRulesLock.RUnlock()
+
+	res := []OmciFaultRule{}
+	for _, rule := range o.omciFaultRules {
+		res = append(res, rule.OmciFaultRule)
+	}
+	return res
+}
+
+// getOmciImpairment returns how the response to an OMCI request of an ONU is impaired, nil if it's not
+func (o *OltDevice) getOmciImpairment(onuSn string, request []byte) *omciImpairment {
+	if len(request) < 6 {
+		return nil
+	}
+	class := uint16(request[4])<<8 | uint16(request[5])
+
+	o.omciFaultRulesLock.Lock()
+	defer o.omciFaultRulesLock.Unlock()
+
+	var rule *omciFaultRuleState
+	for _, sn := range []string{onuSn, ""} {
+		for _, r := range o.omciFaultRules {
+			if r.OnuSn == sn && (r.MeClass == 0 || r.MeClass == class) {
+				rule = r
+				break
+			}
+		}
+		if rule != nil {
+			break
+		}
+	}
+	if rule == nil {
+		return nil
+	}
+
+	rule.matched++
+	return &omciImpairment{
+		drop:       rule.matched == rule.DropNth || (rule.DropEvery > 0 && rule.matched%rule.DropEvery == 0),
+		latency:    rule.Latency,
+		corrupt:    rule.Corrupt,
+		wrongTid:   rule.WrongTid,
+		resultCode: rule.ResultCode,
+	}
+}
+
+// omciHasResultCode returns true if the response to an OMCI message type
+// starts with a result code (the MIB upload responses don't)
+func omciHasResultCode(msgType omcisim.OmciMsgType) bool {
+	switch msgType {
+	case omcisim.Create, omcisim.Delete, omcisim.Set, omcisim.Get, omcisim.GetNext, omcisim.MibReset,
+		omcisim.Test, omcisim.StartSoftwareDownload, omcisim.DownloadSection, omcisim.EndSoftwareDownload,
+		omcisim.ActivateSoftware, omcisim.CommitSoftware, omcisim.SynchronizeTime, omcisim.Reboot, omcisim.GetCurrentData:
+		return true
+	}
+	return false
+}
+
+// apply modifies an OMCI response (in the baseline message format) as the impairment requires
+func (i *omciImpairment) apply(response []byte) []byte {
+	if len(response) < 40 {
+		return response
+	}
+	res := make([]byte, len(response))
+	copy(res, response)
+
+	if i.wrongTid {
+		tid := uint16(res[0])<<8 | uint16(res[1])
+		tid++
+		res[0] = byte(tid >> 8)
+		res[1] = byte(tid & 0xFF)
+	}
+	if i.resultCode != 0 && omciHasResultCode(omcisim.OmciMsgType(res[2]&0x1F)) {
+		res[8] = i.resultCode
+	}
+	if i.corrupt {
+		// NOTE the payload are the 32 bytes of message contents that follow the header
+		bit := rand.Intn(32 * 8)
+		res[8+bit/8] ^= 1 << uint(bit%8)
+	}
+	return res
+}
@@ -0,0 +1,168 @@
+/*
+ * Copyright 2018-present Open Networking Foundation
+
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+
+ * http://www.apache.org/licenses/LICENSE-2.0
+
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package devices
+
+import (
+	"testing"
+	"time"
+
+	omcilib "github.com/opencord/bbsim/internal/common/omci"
+	"github.com/opencord/voltha-protos/v2/go/openolt"
+	"gotest.tools/assert"
+)
+
+// the ME class of the MIB reset requests (ONU data)
+const onuDataClass = 2
+
+func Test_Olt_SetOmciFaultRules_validation(t *testing.T) {
+	olt := createMockOlt(1, 1)
+
+	err := olt.SetOmciFaultRules([]OmciFaultRule{{DropEvery: -1}})
+	assert.Error(t, err, "invalid-omci-fault-drop-every--1")
+
+	err = olt.SetOmciFaultRules([]OmciFaultRule{{DropNth: -1}})
+	assert.Error(t, err, "invalid-omci-fault-drop-nth--1")
+
+	err = olt.SetOmciFaultRules([]OmciFaultRule{{ResultCode: 10}})
+	assert.Error(t, err, "invalid-omci-fault-result-code-10")
+
+	err = olt.SetOmciFaultRules([]OmciFaultRule{{OnuSn: "BBSM000000FF", WrongTid: true}})
+	assert.Error(t, err, "cannot-find-onu-by-serial-number-BBSM000000FF")
+
+	err = olt.SetOmciFaultRules([]OmciFaultRule{{OnuSn: olt.Pons[0].Onus[0].Sn(), WrongTid: true}})
+	assert.NilError(t, err)
+	assert.Equal(t, len(olt.GetOmciFaultRules()), 1)
+}
+
+func Test_Olt_getOmciImpairment(t *testing.T) {
+	olt := createMockOlt(1, 2)
+	onu0 := olt.Pons[0].Onus[0].Sn()
+	onu1 := olt.Pons[0].Onus[1].Sn()
+
+	err := olt.SetOmciFaultRules([]OmciFaultRule{
+		{MeClass: 256, ResultCode: 6},
+		{OnuSn: onu1, DropEvery: 2},
+	})
+	assert.NilError(t, err)
+
+	req, _ := omcilib.CreateMibResetRequest(1)
+	mibReset := HexDecode(req)
+
+	// the rule for all the ONUs only applies to the ONU-G
+	assert.Assert(t, olt.getOmciImpairment(onu0, mibReset) == nil)
+
+	// the rule for the ONU drops every other response
+	assert.Equal(t, olt.getOmciImpairment(onu1, mibReset).drop, false)
+	assert.Equal(t, olt.getOmciImpairment(onu1, mibReset).drop, true)
+	assert.Equal(t, olt.getOmciImpairment(onu1, mibReset).drop, false)
+
+	// the ONU that has a rule of its own ignores the one for all the ONUs
+	onuG := make([]byte, len(mibReset))
+	copy(onuG, mibReset)
+	onuG[4] = 0x01
+	onuG[5] = 0x00
+	assert.Equal(t, olt.getOmciImpairment(onu0, onuG).resultCode, uint8(6))
+	assert.Equal(t, olt.getOmciImpairment(onu1, onuG).resultCode, uint8(0))
+}
+
+func Test_Olt_getOmciImpairment_dropNth(t *testing.T) {
+	olt := createMockOlt(1, 1)
+	onu := olt.Pons[0].Onus[0].Sn()
+
+	err := olt.SetOmciFaultRules([]OmciFaultRule{{OnuSn: onu, DropNth: 2}})
+	assert.NilError(t, err)
+
+	req, _ := omcilib.CreateMibResetRequest(1)
+	mibReset := HexDecode(req)
+
+	// only the second response is dropped
+	assert.Equal(t, olt.getOmciImpairment(onu, mibReset).drop, false)
+	assert.Equal(t, olt.getOmciImpairment(onu, mibReset).drop, true)
+	assert.Equal(t, olt.getOmciImpairment(onu, mibReset).drop, false)
+	assert.Equal(t, olt.getOmciImpairment(onu, mibReset).drop, false)
+}
+
+func Test_OmciImpairment_apply(t *testing.T) {
+	onu := createTestOnu()
+	req, _ := omcilib.CreateMibResetRequest(0x0102)
+	resp, err := onu.omciResponse(HexDecode(req))
+	assert.NilError(t, err)
+
+	res := (&omciImpairment{wrongTid: true, resultCode: 6}).apply(resp)
+	assert.Equal(t, uint16(res[0])<<8|uint16(res[1]), uint16(0x0103))
+	assert.Equal(t, res[8], byte(6))
+	// the original response is not changed
+	assert.Equal(t, resp[1], byte(0x02))
+
+	// a single bit of the payload is flipped
+	res = (&omciImpairment{corrupt: true}).apply(resp)
+	flipped := 0
+	for i := range resp {
+		for diff := resp[i] ^ res[i]; diff != 0; diff &= diff - 1 {
+			assert.Assert(t, i >= 8 && i < 40)
+			flipped++
+		}
+	}
+	assert.Equal(t, flipped, 1)
+
+	// the MIB upload responses don't have a result code
+	req, _ = omcilib.CreateMibUploadRequest(1)
+	resp, _ = onu.omciResponse(HexDecode(req))
+	res = (&omciImpairment{resultCode: 6}).apply(resp)
+	assert.DeepEqual(t, res, resp)
+}
+
+func Test_Onu_handleOmciMessage_drop(t *testing.T) {
+	onu := createTestOnu()
+	stream := &mockStream{
+		Calls:   make(map[int]*openolt.OnuDiscIndication),
+		channel: make(chan int, 10),
+	}
+	req, _ := omcilib.CreateMibResetRequest(1)
+
+	msg := OmciMessage{omciMsg: &openolt.OmciMsg{Pkt: req}, impairment: &omciImpairment{drop: true}}
+	onu.handleOmciMessage(msg, stream)
+	assert.Equal(t, stream.CallCount, 0)
+
+	msg = OmciMessage{omciMsg: &openolt.OmciMsg{Pkt: req}}
+	onu.handleOmciMessage(msg, stream)
+	assert.Equal(t, stream.CallCount, 1)
+}
+
+func Test_Onu_handleOmciMessage_latency(t *testing.T) {
+	onu := createTestOnu()
+	stream := &mockStream{
+		Calls:   make(map[int]*openolt.OnuDiscIndication),
+		channel: make(chan int, 10),
+	}
+	req, _ := omcilib.CreateMibResetRequest(1)
+
+	// the delayed response is handed back to the ONU, that sends it
+	msg := OmciMessage{omciMsg: &openolt.OmciMsg{Pkt: req}, impairment: &omciImpairment{latency: 10 * time.Millisecond}}
+	onu.handleOmciMessage(msg, stream)
+	assert.Equal(t, stream.CallCount, 0)
+
+	select {
+	case delayed := <-onu.Channel:
+		assert.Equal(t, delayed.Type, DelayedOmciResponse)
+		pkt := delayed.Data.(DelayedOmciResponseMessage).Pkt
+		assert.Equal(t, uint16(pkt[0])<<8|uint16(pkt[1]), uint16(1))
+	case <-time.After(1 * time.Second):
+		t.Fatal("the delayed response has not been queued")
+	}
+	assert.Equal(t, stream.CallCount, 0)
+}
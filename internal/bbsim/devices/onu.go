@@ -21,7 +21,7 @@ import (
 	"errors"
 	"fmt"
 	"net"
-
+	"sync"
 	"time"
 
 	"github.com/cboling/omci"
@@ -46,31 +46,52 @@ type Onu struct {
 	PonPortID           uint32
 	PonPort             PonPort
 	STag                int
-	CTag                int
+	CTag                int  // C-Tag of the first UNI, the other UNIs get sequential ones
 	Auth                bool // automatically start EAPOL if set to true
 	Dhcp                bool // automatically start DHCP if set to true
 	HwAddress           net.HardwareAddr
+	EapolCredentials    eapol.Credentials
 	InternalState       *fsm.FSM
 	DiscoveryRetryDelay time.Duration
+	RebootDelay         time.Duration // the time the ONU stays silent when rebooted
 
 	// ONU State
-	// PortNo comes with flows and it's used when sending packetIndications,
-	// There is one PortNo per UNI Port, for now we're only storing the first one
-	// FIXME add support for multiple UNIs
-	PortNo           uint32
-	DhcpFlowReceived bool
+	UniPorts []*UniPort
+	Flows    []FlowKey // the keys of the flows installed for this ONU, the flows are stored in the OLT
 
 	OperState    *fsm.FSM
 	SerialNumber *openolt.SerialNumber
 
 	Channel chan Message // this Channel is to track state changes OMCI messages, EAPOL and DHCP packets
 
+	// channelLock protects the Channel, that is closed when the ONU goes down and created again when it comes back,
+	// see SendMessage
+	channelLock   sync.RWMutex
+	channelClosed bool
+
 	// OMCI params
 	tid        uint16
 	hpTid      uint16
 	seqNumber  uint16
 	HasGemPort bool
 
+	// the last OMCI requests and responses, see GetOmciHistory
+	omciHistory *omciHistory
+
+	// the Software Image MEs, see onu_sw_image.go
+	swImages *softwareImages
+
+	// the PM history data MEs, see onu_omci_pm.go
+	omciPm *omciPm
+	// the alarms raised via OMCI, see onu_omci_alarms.go
+	omciAlarms *omciAlarms
+	// the ME instances and the MIB data sync counter, see onu_omci_mib_sync.go
+	omciMib *omciMib
+	// the MIB uploaded by the ONU, nil for the one built in omci-sim (see setMibTemplate)
+	MibTemplate *common.MibTemplate
+	// the number of MIB Upload Next messages reported in the MIB Upload response (BBR)
+	mibUploads uint16
+
 	DoneChannel chan bool // this channel is used to signal once the onu is complete (when the struct is used by BBR)
 }
 
@@ -78,6 +99,15 @@ func (o *Onu) Sn() string {
 	return common.OnuSnToString(o.SerialNumber)
 }
 
+// AdminState is "down" if the ONU has been disabled, either directly or via its PON port
+func (o *Onu) AdminState() string {
+	switch o.InternalState.Current() {
+	case "disabled", "pon_disabled":
+		return "down"
+	}
+	return "up"
+}
+
 func CreateONU(olt OltDevice, pon PonPort, id uint32, sTag int, cTag int, auth bool, dhcp bool) *Onu {
 
 	o := Onu{
@@ -88,17 +118,35 @@ func CreateONU(olt OltDevice, pon PonPort, id uint32, sTag int, cTag int, auth b
 		CTag:                cTag,
 		Auth:                auth,
 		Dhcp:                dhcp,
-		HwAddress:           net.HardwareAddr{0x2e, 0x60, 0x70, 0x13, byte(pon.ID), byte(id)},
-		PortNo:              0,
+		HwAddress:           net.HardwareAddr{0x2e, 0x60, byte(0x70 + olt.ID), 0x13, byte(pon.ID), byte(id)},
+		EapolCredentials:    eapol.DefaultCredentials,
 		tid:                 0x1,
 		hpTid:               0x8000,
 		seqNumber:           0,
 		DoneChannel:         make(chan bool, 1),
-		DhcpFlowReceived:    false,
+		Flows:               []FlowKey{},
 		DiscoveryRetryDelay: 60 * time.Second, // this is used to send OnuDiscoveryIndications until an activate call is received
+		RebootDelay:         time.Duration(olt.Options.OnuRebootDelay) * time.Second,
+		omciHistory:         newOmciHistory(),
+		swImages:            newSoftwareImages(),
+		omciPm:              newOmciPm(),
+		omciAlarms:          newOmciAlarms(),
+		omciMib:             newOmciMib(),
 	}
 	o.SerialNumber = o.NewSN(olt.ID, pon.ID, o.ID)
 
+	numUni := olt.NumUniPerOnu
+	if numUni < 1 {
+		numUni = 1
+	}
+	for i := 0; i < numUni; i++ {
+		o.UniPorts = append(o.UniPorts, CreateUniPort(&o, uint32(i)))
+	}
+	// the vendor ID can be changed by the topology, see applyTopology
+	if template, err := common.Options.GetMibTemplate("", string(o.SerialNumber.VendorId)); err == nil {
+		o.setMibTemplate(template, numUni)
+	}
+
 	// NOTE this state machine is used to track the operational
 	// state as requested by VOLTHA
 	o.OperState = getOperStateFSM(func(e *fsm.Event) {
@@ -107,31 +155,21 @@ func CreateONU(olt OltDevice, pon PonPort, id uint32, sTag int, cTag int, auth b
 		}).Debugf("Changing ONU OperState from %s to %s", e.Src, e.Dst)
 	})
 
-	// NOTE this state machine is used to activate the OMCI, EAPOL and DHCP clients
+	// NOTE this state machine is used to activate the OMCI client,
+	// the EAPOL and DHCP clients are tracked by the UNI state machines
 	o.InternalState = fsm.NewFSM(
 		"created",
 		fsm.Events{
 			// DEVICE Lifecycle
-			{Name: "initialize", Src: []string{"created", "disabled"}, Dst: "initialized"},
+			{Name: "initialize", Src: []string{"created", "disabled", "pon_disabled", "rebooting"}, Dst: "initialized"},
 			{Name: "discover", Src: []string{"initialized"}, Dst: "discovered"},
 			{Name: "enable", Src: []string{"discovered", "disabled"}, Dst: "enabled"},
-			{Name: "receive_eapol_flow", Src: []string{"enabled", "gem_port_added"}, Dst: "eapol_flow_received"},
-			{Name: "add_gem_port", Src: []string{"enabled", "eapol_flow_received"}, Dst: "gem_port_added"},
 			// NOTE should disabled state be different for oper_disabled (emulating an error) and admin_disabled (received a disabled call via VOLTHA)?
-			{Name: "disable", Src: []string{"enabled", "eap_response_success_received", "auth_failed", "dhcp_ack_received", "dhcp_failed"}, Dst: "disabled"},
-			// EAPOL
-			{Name: "start_auth", Src: []string{"eapol_flow_received", "gem_port_added", "eap_start_sent", "eap_response_identity_sent", "eap_response_challenge_sent", "eap_response_success_received", "auth_failed", "dhcp_ack_received", "dhcp_failed"}, Dst: "auth_started"},
-			{Name: "eap_start_sent", Src: []string{"auth_started"}, Dst: "eap_start_sent"},
-			{Name: "eap_response_identity_sent", Src: []string{"eap_start_sent"}, Dst: "eap_response_identity_sent"},
-			{Name: "eap_response_challenge_sent", Src: []string{"eap_response_identity_sent"}, Dst: "eap_response_challenge_sent"},
-			{Name: "eap_response_success_received", Src: []string{"eap_response_challenge_sent"}, Dst: "eap_response_success_received"},
-			{Name: "auth_failed", Src: []string{"auth_started", "eap_start_sent", "eap_response_identity_sent", "eap_response_challenge_sent"}, Dst: "auth_failed"},
-			// DHCP
-			{Name: "start_dhcp", Src: []string{"eap_response_success_received", "dhcp_discovery_sent", "dhcp_request_sent", "dhcp_ack_received", "dhcp_failed"}, Dst: "dhcp_started"},
-			{Name: "dhcp_discovery_sent", Src: []string{"dhcp_started"}, Dst: "dhcp_discovery_sent"},
-			{Name: "dhcp_request_sent", Src: []string{"dhcp_discovery_sent"}, Dst: "dhcp_request_sent"},
-			{Name: "dhcp_ack_received", Src: []string{"dhcp_request_sent"}, Dst: "dhcp_ack_received"},
-			{Name: "dhcp_failed", Src: []string{"dhcp_started", "dhcp_discovery_sent", "dhcp_request_sent"}, Dst: "dhcp_failed"},
+			{Name: "disable", Src: []string{"enabled"}, Dst: "disabled"},
+			// NOTE the PON port the ONU is connected to has been disabled
+			{Name: "pon_disabled", Src: []string{"initialized", "discovered", "enabled"}, Dst: "pon_disabled"},
+			// NOTE the ONU has been rebooted, via OMCI or via the BBSim API
+			{Name: "reboot", Src: []string{"discovered", "enabled"}, Dst: "rebooting"},
 			// BBR States
 			// TODO add start OMCI state
 			{Name: "send_eapol_flow", Src: []string{"initialized"}, Dst: "eapol_flow_sent"},
@@ -143,7 +181,7 @@ func CreateONU(olt OltDevice, pon PonPort, id uint32, sTag int, cTag int, auth b
 			},
 			"enter_initialized": func(e *fsm.Event) {
 				// create new channel for ProcessOnuMessages Go routine
-				o.Channel = make(chan Message, 2048)
+				o.openChannel()
 			},
 			"enter_discovered": func(e *fsm.Event) {
 				msg := Message{
@@ -153,7 +191,7 @@ func CreateONU(olt OltDevice, pon PonPort, id uint32, sTag int, cTag int, auth b
 						OperState: UP,
 					},
 				}
-				o.Channel <- msg
+				o.SendMessage(msg)
 			},
 			"enter_enabled": func(event *fsm.Event) {
 				msg := Message{
@@ -164,9 +202,20 @@ func CreateONU(olt OltDevice, pon PonPort, id uint32, sTag int, cTag int, auth b
 						OperState: UP,
 					},
 				}
-				o.Channel <- msg
+				o.SendMessage(msg)
+				for _, uni := range o.UniPorts {
+					if err := uni.InternalState.Event("enable"); err != nil {
+						onuLogger.WithFields(log.Fields{
+							"OnuId":  o.ID,
+							"IntfId": o.PonPortID,
+							"OnuSn":  o.Sn(),
+							"UniId":  uni.ID,
+						}).Errorf("Cannot enable UNI: %s", err.Error())
+					}
+				}
 			},
 			"enter_disabled": func(event *fsm.Event) {
+				o.disableUniPorts()
 				msg := Message{
 					Type: OnuIndication,
 					Data: OnuIndicationMessage{
@@ -175,61 +224,55 @@ func CreateONU(olt OltDevice, pon PonPort, id uint32, sTag int, cTag int, auth b
 						OperState: DOWN,
 					},
 				}
-				o.Channel <- msg
+				o.SendMessage(msg)
 				// terminate the ONU's ProcessOnuMessages Go routine
-				close(o.Channel)
+				o.closeChannel()
 			},
-			"enter_auth_started": func(e *fsm.Event) {
-				o.logStateChange(e.Src, e.Dst)
-				msg := Message{
-					Type: StartEAPOL,
-					Data: PacketMessage{
-						PonPortID: o.PonPortID,
-						OnuID:     o.ID,
-					},
+			"enter_pon_disabled": func(event *fsm.Event) {
+				o.disableUniPorts()
+				// NOTE VOLTHA only knows about the ONU if it has been activated
+				if event.Src == "enabled" {
+					msg := Message{
+						Type: OnuIndication,
+						Data: OnuIndicationMessage{
+							OnuSN:     o.SerialNumber,
+							PonPortID: o.PonPortID,
+							OperState: DOWN,
+						},
+					}
+					o.SendMessage(msg)
 				}
-				o.Channel <- msg
+				// terminate the ONU's ProcessOnuMessages Go routine,
+				// this stops the OMCI, EAPOL and DHCP processing until the PON is enabled again
+				o.closeChannel()
 			},
-			"enter_auth_failed": func(e *fsm.Event) {
-				onuLogger.WithFields(log.Fields{
-					"OnuId":  o.ID,
-					"IntfId": o.PonPortID,
-					"OnuSn":  o.Sn(),
-				}).Errorf("ONU failed to authenticate!")
-			},
-			"before_start_dhcp": func(e *fsm.Event) {
-				if o.DhcpFlowReceived == false {
-					e.Cancel(errors.New("cannot-go-to-dhcp-started-as-dhcp-flow-is-missing"))
+			"enter_rebooting": func(event *fsm.Event) {
+				o.disableUniPorts()
+				if event.Src == "enabled" {
+					msg := Message{
+						Type: OnuIndication,
+						Data: OnuIndicationMessage{
+							OnuSN:     o.SerialNumber,
+							PonPortID: o.PonPortID,
+							OperState: DOWN,
+						},
+					}
+					o.SendMessage(msg)
 				}
-			},
-			"enter_dhcp_started": func(e *fsm.Event) {
-				msg := Message{
-					Type: StartDHCP,
-					Data: PacketMessage{
-						PonPortID: o.PonPortID,
-						OnuID:     o.ID,
-					},
-				}
-				o.Channel <- msg
-			},
-			"enter_dhcp_failed": func(e *fsm.Event) {
-				onuLogger.WithFields(log.Fields{
-					"OnuId":  o.ID,
-					"IntfId": o.PonPortID,
-					"OnuSn":  o.Sn(),
-				}).Errorf("ONU failed to DHCP!")
+				// terminate the ONU's ProcessOnuMessages Go routine, the ONU is silent until it is discovered again
+				o.closeChannel()
 			},
 			"enter_eapol_flow_sent": func(e *fsm.Event) {
 				msg := Message{
 					Type: SendEapolFlow,
 				}
-				o.Channel <- msg
+				o.SendMessage(msg)
 			},
 			"enter_dhcp_flow_sent": func(e *fsm.Event) {
 				msg := Message{
 					Type: SendDhcpFlow,
 				}
-				o.Channel <- msg
+				o.SendMessage(msg)
 			},
 		},
 	)
@@ -237,6 +280,163 @@ func CreateONU(olt OltDevice, pon PonPort, id uint32, sTag int, cTag int, auth b
 	return &o
 }
 
+// disableUniPorts stops the EAPOL and DHCP clients on all the UNIs
+func (o *Onu) disableUniPorts() {
+	for _, uni := range o.UniPorts {
+		// NOTE a UNI that never got enabled can't be disabled, there's nothing to stop in that case
+		if !uni.InternalState.Is("created") && !uni.InternalState.Is("disabled") {
+			if err := uni.InternalState.Event("disable"); err != nil {
+				onuLogger.WithFields(log.Fields{
+					"OnuId":  o.ID,
+					"IntfId": o.PonPortID,
+					"OnuSn":  o.Sn(),
+					"UniId":  uni.ID,
+				}).Errorf("Cannot disable UNI: %s", err.Error())
+			}
+		}
+	}
+}
+
+// resetConfig forgets what VOLTHA configured on the ONU via OMCI and flows,
+// as it happens when an ONU is deleted
+func (o *Onu) resetConfig() {
+	for _, uni := range o.UniPorts {
+		uni.PortNo = 0
+		uni.DhcpFlowReceived = false
+	}
+	o.resetOmciState()
+}
+
+// resetOmciState forgets what VOLTHA configured on the ONU via OMCI,
+// as it happens when an ONU reboots
+func (o *Onu) resetOmciState() {
+	o.HasGemPort = false
+	o.tid = 0x1
+	o.hpTid = 0x8000
+	o.seqNumber = 0
+	o.omciPm.reset()
+	o.omciAlarms.resetSeqNumber()
+	o.omciMib.reset()
+
+	// NOTE the omci-sim state (MIB upload counters, GemPort) is created again on the next MIB reset
+	omcisim.OnuOmciStateMapLock.Lock()
+	delete(omcisim.OnuOmciStateMap, omcisim.OnuKey{IntfId: o.omciSimIntfId(), OnuId: o.ID})
+	omcisim.OnuOmciStateMapLock.Unlock()
+}
+
+// omciSimIntfId is the interface ID the ONU is known by in omci-sim, see common.OmciSimIntfId
+func (o *Onu) omciSimIntfId() uint32 {
+	return common.OmciSimIntfId(o.PonPort.Olt.ID, o.PonPortID)
+}
+
+// openChannel creates the channel the ProcessOnuMessages Go routine reads from
+func (o *Onu) openChannel() {
+	o.channelLock.Lock()
+	defer o.channelLock.Unlock()
+	o.Channel = make(chan Message, 2048)
+	o.channelClosed = false
+}
+
+// closeChannel terminates the ProcessOnuMessages Go routine, the messages sent afterwards are dropped
+func (o *Onu) closeChannel() {
+	o.channelLock.Lock()
+	defer o.channelLock.Unlock()
+	if !o.channelClosed {
+		close(o.Channel)
+		o.channelClosed = true
+	}
+}
+
+func (o *Onu) getChannel() chan Message {
+	o.channelLock.RLock()
+	defer o.channelLock.RUnlock()
+	return o.Channel
+}
+
+// SendMessage queues a message for the ProcessOnuMessages Go routine,
+// it fails if the ONU went down in the meantime and its channel has been closed
+func (o *Onu) SendMessage(msg Message) error {
+	o.channelLock.RLock()
+	defer o.channelLock.RUnlock()
+	if o.channelClosed {
+		return errors.New(fmt.Sprintf("onu-%s-is-offline", o.Sn()))
+	}
+	o.Channel <- msg
+	return nil
+}
+
+// IsOffline returns true if the ONU is not processing messages,
+// because its PON port is disabled or because it is rebooting
+func (o *Onu) IsOffline() bool {
+	return o.InternalState.Is("pon_disabled") || o.InternalState.Is("rebooting")
+}
+
+// Reboot restarts the ONU: the ONU goes DOWN and stays silent for RebootDelay,
+// then it is discovered again and VOLTHA has to activate it and upload its MIB as for a new ONU.
+// The ONU messages are then processed with the given context and stream
+func (o *Onu) Reboot(ctx context.Context, stream openolt.Openolt_EnableIndicationServer, client openolt.OpenoltClient) error {
+	if !o.InternalState.Can("reboot") {
+		return errors.New(fmt.Sprintf("cannot-reboot-onu-%s-in-state-%s", o.Sn(), o.InternalState.Current()))
+	}
+
+	onuLogger.WithFields(log.Fields{
+		"IntfId": o.PonPortID,
+		"OnuId":  o.ID,
+		"OnuSn":  o.Sn(),
+	}).Infof("Simulating ONU reboot... (%s)", o.RebootDelay)
+
+	if o.OperState.Is("up") {
+		if err := o.OperState.Event("disable"); err != nil {
+			onuLogger.WithFields(log.Fields{
+				"IntfId": o.PonPortID,
+				"OnuId":  o.ID,
+				"OnuSn":  o.Sn(),
+			}).Errorf("Failed to transition ONU.OperState to disabled state: %s", err.Error())
+		}
+	}
+	if err := o.InternalState.Event("reboot"); err != nil {
+		return err
+	}
+	o.resetOmciState()
+	o.bootSoftwareImage()
+
+	go func() {
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(o.RebootDelay):
+		}
+
+		// NOTE the ONU may have been removed, or its PON port disabled, in the meantime
+		if !o.InternalState.Is("rebooting") {
+			return
+		}
+		if err := o.InternalState.Event("initialize"); err != nil {
+			onuLogger.WithFields(log.Fields{
+				"IntfId": o.PonPortID,
+				"OnuId":  o.ID,
+				"OnuSn":  o.Sn(),
+			}).Errorf("Error initializing ONU: %v", err)
+			return
+		}
+		go o.ProcessOnuMessages(ctx, stream, client)
+		if err := o.InternalState.Event("discover"); err != nil {
+			onuLogger.WithFields(log.Fields{
+				"IntfId": o.PonPortID,
+				"OnuId":  o.ID,
+				"OnuSn":  o.Sn(),
+			}).Errorf("Error discover ONU: %v", err)
+			return
+		}
+		onuLogger.WithFields(log.Fields{
+			"IntfId": o.PonPortID,
+			"OnuId":  o.ID,
+			"OnuSn":  o.Sn(),
+		}).Info("ONU reboot completed")
+	}()
+	return nil
+}
+
 func (o *Onu) logStateChange(src string, dst string) {
 	onuLogger.WithFields(log.Fields{
 		"OnuId":  o.ID,
@@ -253,6 +453,11 @@ func (o *Onu) ProcessOnuMessages(ctx context.Context, stream openolt.Openolt_Ena
 		"ponPort": o.PonPortID,
 	}).Debug("Starting ONU Indication Channel")
 
+	// NOTE BBR doesn't send any indication
+	if stream != nil {
+		stream = newOmciPmStream(o, stream)
+	}
+
 loop:
 	for {
 		select {
@@ -262,7 +467,7 @@ loop:
 				"onuSN": o.Sn(),
 			}).Tracef("ONU message handling canceled via context")
 			break loop
-		case message, ok := <-o.Channel:
+		case message, ok := <-o.getChannel():
 			if !ok || ctx.Err() != nil {
 				onuLogger.WithFields(log.Fields{
 					"onuID": o.ID,
@@ -285,19 +490,49 @@ loop:
 			case OnuIndication:
 				msg, _ := message.Data.(OnuIndicationMessage)
 				o.sendOnuIndication(msg, stream)
+			case DelayedOmciResponse:
+				msg, _ := message.Data.(DelayedOmciResponseMessage)
+				o.sendOmciIndication(msg.Pkt, stream)
+			case AutonomousOmci:
+				msg, _ := message.Data.(AutonomousOmciMessage)
+				o.sendOmciNotification(msg.Pkt, stream)
 			case OMCI:
 				msg, _ := message.Data.(OmciMessage)
 				o.handleOmciMessage(msg, stream)
+				if isOmciReboot(msg) || o.swImages.activationPending() {
+					// NOTE the response has been sent, the ONU goes down now
+					if err := o.Reboot(ctx, stream, client); err != nil {
+						onuLogger.WithFields(log.Fields{
+							"IntfId": o.PonPortID,
+							"OnuId":  o.ID,
+							"OnuSn":  o.Sn(),
+						}).Errorf("Cannot reboot ONU: %s", err.Error())
+						// the activated image would otherwise boot with the next unrelated reboot
+						o.cancelActivation()
+					}
+				}
 			case FlowUpdate:
 				msg, _ := message.Data.(OnuFlowUpdateMessage)
 				o.handleFlowUpdate(msg)
 			case StartEAPOL:
+				msg, _ := message.Data.(PacketMessage)
 				log.Infof("Receive StartEAPOL message on ONU Channel")
-				eapol.SendEapStart(o.ID, o.PonPortID, o.Sn(), o.PortNo, o.HwAddress, o.InternalState, stream)
+				uni, err := o.GetUniById(msg.UniID)
+				if err != nil {
+					onuLogger.Errorf("Cannot start EAPOL: %s", err.Error())
+					continue
+				}
+				eapol.SendEapStart(o.PonPort.Olt.ID, o.ID, o.PonPortID, o.Sn(), uni.PortNo, uni.HwAddress, uni.InternalState, stream)
 			case StartDHCP:
+				msg, _ := message.Data.(PacketMessage)
 				log.Infof("Receive StartDHCP message on ONU Channel")
+				uni, err := o.GetUniById(msg.UniID)
+				if err != nil {
+					onuLogger.Errorf("Cannot start DHCP: %s", err.Error())
+					continue
+				}
 				// FIXME use id, ponId as SendEapStart
-				dhcp.SendDHCPDiscovery(o.PonPortID, o.ID, o.Sn(), o.PortNo, o.InternalState, o.HwAddress, o.CTag, stream)
+				dhcp.SendDHCPDiscovery(o.PonPort.Olt.ID, o.PonPortID, o.ID, o.Sn(), uni.PortNo, uni.InternalState, uni.HwAddress, uni.CTag, stream)
 			case OnuPacketOut:
 
 				msg, _ := message.Data.(OnuPacketMessage)
@@ -305,15 +540,22 @@ loop:
 				log.WithFields(log.Fields{
 					"IntfId":  msg.IntfId,
 					"OnuId":   msg.OnuId,
+					"PortNo":  msg.PortNo,
 					"pktType": msg.Type,
 				}).Trace("Received OnuPacketOut Message")
 
+				uni := o.findUniForPacket(msg)
+
+				// NOTE the ONU drops the packets it has no client for
+				dropped := msg.Type != packetHandlers.EAPOL && msg.Type != packetHandlers.DHCP
+				o.countOmciPmFrame(false, uni, msg.GemPortId, msg.Packet.Data(), dropped)
+
 				if msg.Type == packetHandlers.EAPOL {
-					eapol.HandleNextPacket(msg.OnuId, msg.IntfId, o.Sn(), o.PortNo, o.InternalState, msg.Packet, stream, client)
+					eapol.HandleNextPacket(o.PonPort.Olt.ID, msg.OnuId, msg.IntfId, o.Sn(), uni.PortNo, uni.HwAddress, o.EapolCredentials, uni.InternalState, msg.Packet, stream, client)
 				} else if msg.Type == packetHandlers.DHCP {
 					// NOTE here we receive packets going from the DHCP Server to the ONU
 					// for now we expect them to be double-tagged, but ideally the should be single tagged
-					dhcp.HandleNextPacket(o.ID, o.PonPortID, o.Sn(), o.PortNo, o.HwAddress, o.CTag, o.InternalState, msg.Packet, stream)
+					dhcp.HandleNextPacket(o.PonPort.Olt.ID, o.ID, o.PonPortID, o.Sn(), uni.PortNo, uni.HwAddress, uni.CTag, uni.InternalState, msg.Packet, stream)
 				}
 			case OnuPacketIn:
 				// NOTE we only receive BBR packets here.
@@ -327,8 +569,9 @@ loop:
 					"pktType": msg.Type,
 				}).Trace("Received OnuPacketIn Message")
 
+				// NOTE BBR only emulates the first UNI, and the ONU state machine tracks it
 				if msg.Type == packetHandlers.EAPOL {
-					eapol.HandleNextPacket(msg.OnuId, msg.IntfId, o.Sn(), o.PortNo, o.InternalState, msg.Packet, stream, client)
+					eapol.HandleNextPacket(o.PonPort.Olt.ID, msg.OnuId, msg.IntfId, o.Sn(), o.UniPorts[0].PortNo, o.HwAddress, o.EapolCredentials, o.InternalState, msg.Packet, stream, client)
 				} else if msg.Type == packetHandlers.DHCP {
 					dhcp.HandleNextBbrPacket(o.ID, o.PonPortID, o.Sn(), o.STag, o.HwAddress, o.DoneChannel, msg.Packet, client)
 				}
@@ -357,32 +600,106 @@ func (o *Onu) processOmciMessage(message omcisim.OmciChMessage) {
 	switch message.Type {
 	case omcisim.GemPortAdded:
 		log.WithFields(log.Fields{
-			"OnuId":  message.Data.OnuId,
-			"IntfId": message.Data.IntfId,
+			"OnuId":  o.ID,
+			"IntfId": o.PonPortID,
+			"OnuSn":  o.Sn(),
 		}).Infof("GemPort Added")
 
 		// NOTE if we receive the GemPort but we don't have EAPOL flows
 		// go an intermediate state, otherwise start auth
-		if o.InternalState.Is("enabled") {
-			if err := o.InternalState.Event("add_gem_port"); err != nil {
-				log.Errorf("Can't go to gem_port_added: %v", err)
-			}
-		} else if o.InternalState.Is("eapol_flow_received") {
-			if o.Auth == true {
-				if err := o.InternalState.Event("start_auth"); err != nil {
-					log.Warnf("Can't go to auth_started: %v", err)
+		for _, uni := range o.UniPorts {
+			if uni.InternalState.Is("enabled") {
+				if err := uni.InternalState.Event("add_gem_port"); err != nil {
+					log.Errorf("Can't go to gem_port_added: %v", err)
+				}
+			} else if uni.InternalState.Is("eapol_flow_received") {
+				if o.Auth == true {
+					if err := uni.InternalState.Event("start_auth"); err != nil {
+						log.Warnf("Can't go to auth_started: %v", err)
+					}
+				} else {
+					onuLogger.WithFields(log.Fields{
+						"IntfId":       o.PonPortID,
+						"OnuId":        o.ID,
+						"SerialNumber": o.Sn(),
+						"UniId":        uni.ID,
+					}).Warn("Not starting authentication as Auth bit is not set in CLI parameters")
 				}
-			} else {
-				onuLogger.WithFields(log.Fields{
-					"IntfId":       o.PonPortID,
-					"OnuId":        o.ID,
-					"SerialNumber": o.Sn(),
-				}).Warn("Not starting authentication as Auth bit is not set in CLI parameters")
 			}
 		}
 	}
 }
 
+// setMibTemplate sets the MIB uploaded by the ONU, nil for the built-in one.
+// The ONU gets one UNI port per PPTP in the template, numUni ones with the built-in MIB
+func (o *Onu) setMibTemplate(template *common.MibTemplate, numUni int) {
+	o.MibTemplate = template
+	if template != nil && len(template.PptpInstances()) > 0 {
+		numUni = len(template.PptpInstances())
+	}
+	if numUni == len(o.UniPorts) {
+		return
+	}
+	o.UniPorts = []*UniPort{}
+	for i := 0; i < numUni; i++ {
+		o.UniPorts = append(o.UniPorts, CreateUniPort(o, uint32(i)))
+	}
+}
+
+// applyTopology overrides the generated values of the ONU (and of its UNIs) with the ones in the topology file
+func (o *Onu) applyTopology(t common.OnuTopology) error {
+	if t.SerialNumber != "" {
+		sn, err := common.OnuSnFromString(t.SerialNumber)
+		if err != nil {
+			return err
+		}
+		o.SerialNumber = sn
+	} else if t.VendorId != "" {
+		o.SerialNumber.VendorId = []byte(t.VendorId)
+	}
+	if t.MacAddress != "" {
+		mac, err := net.ParseMAC(t.MacAddress)
+		if err != nil {
+			return err
+		}
+		o.HwAddress = mac
+	}
+	if t.STag != 0 {
+		o.STag = t.STag
+	}
+	if t.CTag != 0 {
+		o.CTag = t.CTag
+	}
+	if t.Auth != nil {
+		o.Auth = *t.Auth
+	}
+	if t.Dhcp != nil {
+		o.Dhcp = *t.Dhcp
+	}
+	if t.EapolUsername != "" {
+		o.EapolCredentials.Username = t.EapolUsername
+	}
+	if t.EapolPassword != "" {
+		o.EapolCredentials.Password = t.EapolPassword
+	}
+
+	template, err := common.Options.GetMibTemplate(t.MibTemplate, string(o.SerialNumber.VendorId))
+	if err != nil {
+		return err
+	}
+	numUni := o.PonPort.Olt.NumUniPerOnu
+	if numUni < 1 {
+		numUni = 1
+	}
+	o.setMibTemplate(template, numUni)
+
+	for _, uni := range o.UniPorts {
+		uni.HwAddress = uniMacAddress(o.HwAddress, uni.ID)
+		uni.CTag = o.CTag + int(uni.ID)
+	}
+	return nil
+}
+
 func (o Onu) NewSN(oltid int, intfid uint32, onuid uint32) *openolt.SerialNumber {
 
 	sn := new(openolt.SerialNumber)
@@ -481,7 +798,10 @@ func (o *Onu) handleOmciMessage(msg OmciMessage, stream openolt.Openolt_EnableIn
 	}).Tracef("Received OMCI message")
 
 	var omciInd openolt.OmciIndication
-	respPkt, err := omcisim.OmciSim(o.PonPortID, o.ID, HexDecode(msg.omciMsg.Pkt))
+	reqPkt := HexDecode(msg.omciMsg.Pkt)
+	o.omciHistory.add(newOmciHistoryEntry(OmciRequest, reqPkt))
+
+	respPkt, err := o.omciResponse(reqPkt)
 	if err != nil {
 		onuLogger.WithFields(log.Fields{
 			"IntfId":       o.PonPortID,
@@ -491,10 +811,73 @@ func (o *Onu) handleOmciMessage(msg OmciMessage, stream openolt.Openolt_EnableIn
 		}).Errorf("Error handling OMCI message %v", msg)
 		return
 	}
+	if respPkt == nil {
+		// NOTE the request doesn't need to be acknowledged
+		return
+	}
 
+	if msg.impairment != nil {
+		if msg.impairment.drop {
+			onuLogger.WithFields(log.Fields{
+				"IntfId":       o.PonPortID,
+				"SerialNumber": o.Sn(),
+				"omciPacket":   msg.omciMsg.Pkt,
+			}).Debug("Dropping OMCI response")
+			entry := newOmciHistoryEntry(OmciResponse, respPkt)
+			entry.Dropped = true
+			o.omciHistory.add(entry)
+			return
+		}
+		respPkt = msg.impairment.apply(respPkt)
+		if msg.impairment.latency > 0 {
+			// NOTE the responses that are not delayed overtake this one,
+			// it goes through the ONU channel so that only ProcessOnuMessages sends on the stream
+			time.AfterFunc(msg.impairment.latency, func() {
+				if err := o.SendMessage(Message{Type: DelayedOmciResponse, Data: DelayedOmciResponseMessage{Pkt: respPkt}}); err != nil {
+					onuLogger.WithFields(log.Fields{
+						"IntfId":       o.PonPortID,
+						"SerialNumber": o.Sn(),
+					}).Debugf("Dropping delayed OMCI response: %v", err)
+				}
+			})
+			return
+		}
+	}
+	o.sendOmciIndication(respPkt, stream)
+}
+
+// sendOmciIndication sends an OMCI response to VOLTHA
+func (o *Onu) sendOmciIndication(respPkt []byte, stream openolt.Openolt_EnableIndicationServer) {
+	o.omciHistory.add(newOmciHistoryEntry(OmciResponse, respPkt))
+	o.sendOmciPkt(respPkt, stream)
+}
+
+// queueOmciNotification hands an autonomous OMCI message (Alarm Notification or Attribute Value Change)
+// to the ONU goroutine, so that it is not sent concurrently with the OMCI responses
+func (o *Onu) queueOmciNotification(pkt []byte) error {
+	return o.SendMessage(Message{
+		Type: AutonomousOmci,
+		Data: AutonomousOmciMessage{Pkt: pkt},
+	})
+}
+
+// sendOmciNotification sends an autonomous OMCI message to VOLTHA, the Alarm Notifications are numbered
+// when they are sent so that VOLTHA receives them in sequence
+func (o *Onu) sendOmciNotification(pkt []byte, stream openolt.Openolt_EnableIndicationServer) {
+	if omcisim.OmciMsgType(pkt[2]&0x1F) == omcisim.AlarmNotification {
+		o.omciAlarms.lock.Lock()
+		pkt[39] = o.omciAlarms.nextSeqNumber()
+		o.omciAlarms.lock.Unlock()
+	}
+	o.omciHistory.add(newOmciHistoryEntry(OmciNotification, pkt))
+	o.sendOmciPkt(pkt, stream)
+}
+
+func (o *Onu) sendOmciPkt(pkt []byte, stream openolt.Openolt_EnableIndicationServer) {
+	var omciInd openolt.OmciIndication
 	omciInd.IntfId = o.PonPortID
 	omciInd.OnuId = o.ID
-	omciInd.Pkt = respPkt
+	omciInd.Pkt = pkt
 
 	omci := &openolt.Indication_OmciInd{OmciInd: &omciInd}
 	if err := stream.Send(&openolt.Indication{Data: omci}); err != nil {
@@ -502,7 +885,6 @@ func (o *Onu) handleOmciMessage(msg OmciMessage, stream openolt.Openolt_EnableIn
 			"IntfId":       o.PonPortID,
 			"SerialNumber": o.Sn(),
 			"omciPacket":   omciInd.Pkt,
-			"msg":          msg,
 		}).Errorf("send omcisim indication failed: %v", err)
 		return
 	}
@@ -513,22 +895,61 @@ func (o *Onu) handleOmciMessage(msg OmciMessage, stream openolt.Openolt_EnableIn
 	}).Tracef("Sent OMCI message")
 }
 
-func (o *Onu) storePortNumber(portNo uint32) {
-	// NOTE this needed only as long as we don't support multiple UNIs
-	// we need to add support for multiple UNIs
-	// the action plan is:
-	// - refactor the omcisim-sim library to use https://github.com/cboling/omci instead of canned messages
-	// - change the library so that it reports a single UNI and remove this workaroung
-	// - add support for multiple UNIs in BBSim
-	if o.PortNo == 0 || portNo < o.PortNo {
-		onuLogger.WithFields(log.Fields{
-			"IntfId":       o.PonPortID,
-			"OnuId":        o.ID,
-			"SerialNumber": o.Sn(),
-			"OnuPortNo":    o.PortNo,
-			"FlowPortNo":   portNo,
-		}).Debug("Storing ONU portNo")
-		o.PortNo = portNo
+// isOmciReboot returns true if the OMCI message is a Reboot request
+func isOmciReboot(msg OmciMessage) bool {
+	pkt := HexDecode(msg.omciMsg.Pkt)
+	if len(pkt) < 3 {
+		return false
+	}
+	return omcisim.OmciMsgType(pkt[2]&0x1F) == omcisim.Reboot
+}
+
+// GetUniByPortNo returns the UNI with the given port number, the port number is set by VOLTHA
+func (o *Onu) GetUniByPortNo(portNo uint32) (*UniPort, error) {
+	for _, uni := range o.UniPorts {
+		if portNo != 0 && uni.PortNo == portNo {
+			return uni, nil
+		}
+	}
+	return nil, errors.New(fmt.Sprintf("cannot-find-uni-with-port-no-%d-on-onu-%s", portNo, o.Sn()))
+}
+
+// GetUniById returns the UNI with the given ID
+func (o *Onu) GetUniById(id uint32) (*UniPort, error) {
+	for _, uni := range o.UniPorts {
+		if uni.ID == id {
+			return uni, nil
+		}
+	}
+	return nil, errors.New(fmt.Sprintf("cannot-find-uni-%d-on-onu-%s", id, o.Sn()))
+}
+
+// findUniForPacket returns the UNI a packet coming from VOLTHA is directed to,
+// the PortNo is checked first, then the destination MAC address.
+// If neither matches we assume the packet is for the first UNI.
+func (o *Onu) findUniForPacket(msg OnuPacketMessage) *UniPort {
+	for _, uni := range o.UniPorts {
+		if msg.PortNo != 0 && uni.PortNo == msg.PortNo {
+			return uni
+		}
+	}
+	if dstMac, err := packetHandlers.GetDstMacAddressFromPacket(msg.Packet); err == nil {
+		for _, uni := range o.UniPorts {
+			if uni.HwAddress.String() == dstMac.String() {
+				return uni
+			}
+		}
+	}
+	return o.UniPorts[0]
+}
+
+// removeFlow drops a flow key from the ONU, the caller is expected to hold the OLT flowsLock
+func (o *Onu) removeFlow(key FlowKey) {
+	for i, k := range o.Flows {
+		if k == key {
+			o.Flows = append(o.Flows[:i], o.Flows[i+1:]...)
+			return
+		}
 	}
 }
 
@@ -553,30 +974,32 @@ func (o *Onu) handleFlowUpdate(msg OnuFlowUpdateMessage) {
 		"UniID":     msg.Flow.UniId,
 	}).Debug("ONU receives Flow")
 
-	if msg.Flow.UniId != 0 {
-		// as of now BBSim only support a single UNI, so ignore everything that is not targeted to it
+	uni, err := o.GetUniById(uint32(msg.Flow.UniId))
+	if err != nil {
+		// ignore everything that is targeted to a UNI we are not emulating
 		onuLogger.WithFields(log.Fields{
 			"IntfId":       o.PonPortID,
 			"OnuId":        o.ID,
 			"SerialNumber": o.Sn(),
-		}).Debug("Ignoring flow as it's not for the first UNI")
+			"UniId":        msg.Flow.UniId,
+		}).Debug("Ignoring flow as the UNI does not exist")
 		return
 	}
 
 	if msg.Flow.Classifier.EthType == uint32(layers.EthernetTypeEAPOL) && msg.Flow.Classifier.OVid == 4091 {
 		// NOTE storing the PortNO, it's needed when sending PacketIndications
-		o.storePortNumber(uint32(msg.Flow.PortNo))
+		uni.storePortNumber(uint32(msg.Flow.PortNo))
 
 		// NOTE if we receive the EAPOL flows but we don't have GemPorts
 		// go an intermediate state, otherwise start auth
-		if o.InternalState.Is("enabled") {
-			if err := o.InternalState.Event("receive_eapol_flow"); err != nil {
+		if uni.InternalState.Is("enabled") {
+			if err := uni.InternalState.Event("receive_eapol_flow"); err != nil {
 				log.Warnf("Can't go to eapol_flow_received: %v", err)
 			}
-		} else if o.InternalState.Is("gem_port_added") {
+		} else if uni.InternalState.Is("gem_port_added") {
 
 			if o.Auth == true {
-				if err := o.InternalState.Event("start_auth"); err != nil {
+				if err := uni.InternalState.Event("start_auth"); err != nil {
 					log.Warnf("Can't go to auth_started: %v", err)
 				}
 			} else {
@@ -593,11 +1016,11 @@ func (o *Onu) handleFlowUpdate(msg OnuFlowUpdateMessage) {
 		msg.Flow.Classifier.DstPort == uint32(67) {
 
 		// keep track that we received the DHCP Flows so that we can transition the state to dhcp_started
-		o.DhcpFlowReceived = true
+		uni.DhcpFlowReceived = true
 
 		if o.Dhcp == true {
 			// NOTE we are receiving multiple DHCP flows but we shouldn't call the transition multiple times
-			if err := o.InternalState.Event("start_dhcp"); err != nil {
+			if err := uni.InternalState.Event("start_dhcp"); err != nil {
 				log.Errorf("Can't go to dhcp_started: %v", err)
 			}
 		} else {
@@ -695,13 +1118,16 @@ func (o *Onu) handleOmci(msg OmciIndicationMessage, client openolt.OpenoltClient
 		mibUpload, _ := omcilib.CreateMibUploadRequest(o.getNextTid(false))
 		sendOmciMsg(mibUpload, o.PonPortID, o.ID, o.SerialNumber, "mibUpload", client)
 	case omci.MibUploadResponseType:
+		if layer := packet.Layer(omci.LayerTypeMibUploadResponse); layer != nil {
+			o.mibUploads = layer.(*omci.MibUploadResponse).NumberOfCommands
+		}
 		mibUploadNext, _ := omcilib.CreateMibUploadNextRequest(o.getNextTid(false), o.seqNumber)
 		sendOmciMsg(mibUploadNext, o.PonPortID, o.ID, o.SerialNumber, "mibUploadNext", client)
 	case omci.MibUploadNextResponseType:
 		o.seqNumber++
 
-		if o.seqNumber > 290 {
-			// NOTE we are done with the MIB Upload (290 is the number of messages the omci-sim library will respond to)
+		if o.seqNumber >= o.mibUploads {
+			// NOTE we are done with the MIB Upload (the number of messages depends on the MIB of the ONU)
 			galEnet, _ := omcilib.CreateGalEnetRequest(o.getNextTid(false))
 			sendOmciMsg(galEnet, o.PonPortID, o.ID, o.SerialNumber, "CreateGalEnetRequest", client)
 		} else {
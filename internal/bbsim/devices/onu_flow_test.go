@@ -51,7 +51,7 @@ func Test_HandleFlowUpdateEapolFromGem(t *testing.T) {
 
 	onu := createMockOnu(1, 1, 900, 900, true, false)
 
-	onu.InternalState = fsm.NewFSM(
+	onu.UniPorts[0].InternalState = fsm.NewFSM(
 		"gem_port_added",
 		fsm.Events{
 			{Name: "start_auth", Src: []string{"eapol_flow_received", "gem_port_added"}, Dst: "auth_started"},
@@ -83,16 +83,16 @@ func Test_HandleFlowUpdateEapolFromGem(t *testing.T) {
 	}
 
 	onu.handleFlowUpdate(msg)
-	assert.Equal(t, onu.InternalState.Current(), "auth_started")
+	assert.Equal(t, onu.UniPorts[0].InternalState.Current(), "auth_started")
 }
 
-// validates that when an ONU receives an EAPOL flow for UNI that is not 0
+// validates that when an ONU receives an EAPOL flow for a UNI that does not exist
 // no action is taken
 func Test_HandleFlowUpdateEapolFromGemIgnore(t *testing.T) {
 
 	onu := createMockOnu(1, 1, 900, 900, false, false)
 
-	onu.InternalState = fsm.NewFSM(
+	onu.UniPorts[0].InternalState = fsm.NewFSM(
 		"gem_port_added",
 		fsm.Events{
 			{Name: "start_auth", Src: []string{"eapol_flow_received", "gem_port_added"}, Dst: "auth_started"},
@@ -124,7 +124,7 @@ func Test_HandleFlowUpdateEapolFromGemIgnore(t *testing.T) {
 	}
 
 	onu.handleFlowUpdate(msg)
-	assert.Equal(t, onu.InternalState.Current(), "gem_port_added")
+	assert.Equal(t, onu.UniPorts[0].InternalState.Current(), "gem_port_added")
 }
 
 // validates that when an ONU receives an EAPOL flow for UNI 0
@@ -133,7 +133,7 @@ func Test_HandleFlowUpdateEapolFromEnabled(t *testing.T) {
 
 	onu := createMockOnu(1, 1, 900, 900, false, false)
 
-	onu.InternalState = fsm.NewFSM(
+	onu.UniPorts[0].InternalState = fsm.NewFSM(
 		"enabled",
 		fsm.Events{
 			{Name: "receive_eapol_flow", Src: []string{"enabled", "gem_port_added"}, Dst: "eapol_flow_received"},
@@ -165,16 +165,16 @@ func Test_HandleFlowUpdateEapolFromEnabled(t *testing.T) {
 	}
 
 	onu.handleFlowUpdate(msg)
-	assert.Equal(t, onu.InternalState.Current(), "eapol_flow_received")
+	assert.Equal(t, onu.UniPorts[0].InternalState.Current(), "eapol_flow_received")
 }
 
-// validates that when an ONU receives an EAPOL flow for UNI that is not 0
+// validates that when an ONU receives an EAPOL flow for a UNI that does not exist
 // no action is taken
 func Test_HandleFlowUpdateEapolFromEnabledIgnore(t *testing.T) {
 
 	onu := createMockOnu(1, 1, 900, 900, false, false)
 
-	onu.InternalState = fsm.NewFSM(
+	onu.UniPorts[0].InternalState = fsm.NewFSM(
 		"enabled",
 		fsm.Events{
 			{Name: "receive_eapol_flow", Src: []string{"enabled", "gem_port_added"}, Dst: "eapol_flow_received"},
@@ -206,7 +206,7 @@ func Test_HandleFlowUpdateEapolFromEnabledIgnore(t *testing.T) {
 	}
 
 	onu.handleFlowUpdate(msg)
-	assert.Equal(t, onu.InternalState.Current(), "enabled")
+	assert.Equal(t, onu.UniPorts[0].InternalState.Current(), "enabled")
 }
 
 // validates that when an ONU receives an EAPOL flow for UNI 0
@@ -214,7 +214,7 @@ func Test_HandleFlowUpdateEapolFromEnabledIgnore(t *testing.T) {
 func Test_HandleFlowUpdateEapolNoAuth(t *testing.T) {
 	onu := createMockOnu(1, 1, 900, 900, false, false)
 
-	onu.InternalState = fsm.NewFSM(
+	onu.UniPorts[0].InternalState = fsm.NewFSM(
 		"gem_port_added",
 		fsm.Events{
 			{Name: "start_auth", Src: []string{"eapol_flow_received", "gem_port_added"}, Dst: "auth_started"},
@@ -246,13 +246,13 @@ func Test_HandleFlowUpdateEapolNoAuth(t *testing.T) {
 	}
 
 	onu.handleFlowUpdate(msg)
-	assert.Equal(t, onu.InternalState.Current(), "gem_port_added")
+	assert.Equal(t, onu.UniPorts[0].InternalState.Current(), "gem_port_added")
 }
 
 func Test_HandleFlowUpdateDhcp(t *testing.T) {
 	onu := createMockOnu(1, 1, 900, 900, false, true)
 
-	onu.InternalState = fsm.NewFSM(
+	onu.UniPorts[0].InternalState = fsm.NewFSM(
 		"eap_response_success_received",
 		fsm.Events{
 			{Name: "start_dhcp", Src: []string{"eap_response_success_received"}, Dst: "dhcp_started"},
@@ -285,14 +285,14 @@ func Test_HandleFlowUpdateDhcp(t *testing.T) {
 	}
 
 	onu.handleFlowUpdate(msg)
-	assert.Equal(t, onu.InternalState.Current(), "dhcp_started")
-	assert.Equal(t, onu.DhcpFlowReceived, true)
+	assert.Equal(t, onu.UniPorts[0].InternalState.Current(), "dhcp_started")
+	assert.Equal(t, onu.UniPorts[0].DhcpFlowReceived, true)
 }
 
 func Test_HandleFlowUpdateDhcpNoDhcp(t *testing.T) {
 	onu := createMockOnu(1, 1, 900, 900, false, false)
 
-	onu.InternalState = fsm.NewFSM(
+	onu.UniPorts[0].InternalState = fsm.NewFSM(
 		"eap_response_success_received",
 		fsm.Events{
 			{Name: "start_dhcp", Src: []string{"eap_response_success_received"}, Dst: "dhcp_started"},
@@ -325,6 +325,46 @@ func Test_HandleFlowUpdateDhcpNoDhcp(t *testing.T) {
 	}
 
 	onu.handleFlowUpdate(msg)
-	assert.Equal(t, onu.InternalState.Current(), "eap_response_success_received")
-	assert.Equal(t, onu.DhcpFlowReceived, true)
+	assert.Equal(t, onu.UniPorts[0].InternalState.Current(), "eap_response_success_received")
+	assert.Equal(t, onu.UniPorts[0].DhcpFlowReceived, true)
+}
+
+// validates that when an ONU with multiple UNIs receives an EAPOL flow
+// only the targeted UNI stores the PortNo and transitions to eapol_flow_received
+func Test_HandleFlowUpdateEapolSecondUni(t *testing.T) {
+	onu := createMockOnu(1, 1, 900, 900, false, false)
+	onu.UniPorts = append(onu.UniPorts, CreateUniPort(onu, 1))
+
+	for _, uni := range onu.UniPorts {
+		uni.InternalState.SetState("enabled")
+	}
+
+	flow := openolt.Flow{
+		AccessIntfId:  int32(onu.PonPortID),
+		OnuId:         int32(onu.ID),
+		UniId:         int32(1),
+		FlowId:        uint32(onu.ID),
+		FlowType:      "downstream",
+		AllocId:       int32(0),
+		NetworkIntfId: int32(0),
+		Classifier: &openolt.Classifier{
+			EthType: uint32(layers.EthernetTypeEAPOL),
+			OVid:    4091,
+		},
+		Action:   &openolt.Action{},
+		Priority: int32(100),
+		PortNo:   uint32(17),
+	}
+
+	msg := OnuFlowUpdateMessage{
+		PonPortID: 1,
+		OnuID:     1,
+		Flow:      &flow,
+	}
+
+	onu.handleFlowUpdate(msg)
+	assert.Equal(t, onu.UniPorts[0].InternalState.Current(), "enabled")
+	assert.Equal(t, onu.UniPorts[0].PortNo, uint32(0))
+	assert.Equal(t, onu.UniPorts[1].InternalState.Current(), "eapol_flow_received")
+	assert.Equal(t, onu.UniPorts[1].PortNo, uint32(17))
 }
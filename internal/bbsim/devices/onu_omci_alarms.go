@@ -0,0 +1,287 @@
+/*
+ * Copyright 2018-present Open Networking Foundation
+
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+
+ * http://www.apache.org/licenses/LICENSE-2.0
+
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package devices
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/opencord/bbsim/internal/common"
+	omcisim "github.com/opencord/omci-sim"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	omciOnuGClass = 0x0100
+	omciAniGClass = 0x0107
+
+	omciAniGInstance = 0x8001
+)
+
+// omciAlarmDef is the ME class and the alarm number (see G.988) of an alarm that can be raised via the API
+type omciAlarmDef struct {
+	class uint16
+	alarm uint8
+}
+
+var omciAlarmDefs = map[string]omciAlarmDef{
+	common.OmciAlarmPptpLanLos:            {omciPptpClass, 0},
+	common.OmciAlarmAniGLowRxPower:        {omciAniGClass, 0},
+	common.OmciAlarmAniGHighRxPower:       {omciAniGClass, 1},
+	common.OmciAlarmAniGSignalFail:        {omciAniGClass, 2},
+	common.OmciAlarmAniGSignalDegrade:     {omciAniGClass, 3},
+	common.OmciAlarmAniGLowTxPower:        {omciAniGClass, 4},
+	common.OmciAlarmAniGHighTxPower:       {omciAniGClass, 5},
+	common.OmciAlarmAniGLaserBiasCurrent:  {omciAniGClass, 6},
+	common.OmciAlarmOnuGEquipment:         {omciOnuGClass, 0},
+	common.OmciAlarmOnuGPowering:          {omciOnuGClass, 1},
+	common.OmciAlarmOnuGSelfTestFailure:   {omciOnuGClass, 6},
+	common.OmciAlarmOnuGTemperatureYellow: {omciOnuGClass, 8},
+	common.OmciAlarmOnuGTemperatureRed:    {omciOnuGClass, 9},
+}
+
+// omciAvcDef is the ME class and the attribute number of an operational state
+// whose changes are reported via an Attribute Value Change
+type omciAvcDef struct {
+	class uint16
+	attr  uint8
+}
+
+var omciAvcDefs = map[string]omciAvcDef{
+	common.OmciAvcUniOperState: {omciPptpClass, 6},
+	common.OmciAvcOnuOperState: {omciOnuGClass, 8},
+}
+
+func (b *omciAlarmBitmap) clear(alarm uint8) {
+	b[alarm/8] &^= 0x80 >> (alarm % 8)
+}
+
+func (b *omciAlarmBitmap) isSet(alarm uint8) bool {
+	return b[alarm/8]&(0x80>>(alarm%8)) != 0
+}
+
+// omciActiveAlarms is the alarm bitmap of a ME in a Get All Alarms snapshot
+type omciActiveAlarms struct {
+	omciMeKey
+	alarms omciAlarmBitmap
+}
+
+// omciAlarms holds the alarms raised via the API and the alarm sequence number.
+// NOTE the threshold crossing alerts are numbered too, but they are not reported by Get All Alarms
+type omciAlarms struct {
+	lock      sync.Mutex
+	seqNumber uint8 // the sequence number of the last Alarm Notification, from 1 to 255
+	active    map[omciMeKey]omciAlarmBitmap
+	snapshot  []omciActiveAlarms // taken by Get All Alarms and read by Get All Alarms Next
+}
+
+func newOmciAlarms() *omciAlarms {
+	return &omciAlarms{
+		active: make(map[omciMeKey]omciAlarmBitmap),
+	}
+}
+
+// nextSeqNumber returns the sequence number of the next Alarm Notification, it must be called with the lock held
+func (a *omciAlarms) nextSeqNumber() uint8 {
+	// NOTE the sequence number goes from 1 to 255, 0 is never used
+	if a.seqNumber == 255 {
+		a.seqNumber = 0
+	}
+	a.seqNumber++
+	return a.seqNumber
+}
+
+// resetSeqNumber restarts the alarm sequence numbers, as it happens on a MIB reset
+func (a *omciAlarms) resetSeqNumber() {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	a.seqNumber = 0
+}
+
+// getAllAlarms takes a snapshot of the active alarms and returns the number of
+// Get All Alarms Next commands needed to upload it. The alarm sequence number is reset
+func (a *omciAlarms) getAllAlarms() uint16 {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	a.seqNumber = 0
+	a.snapshot = make([]omciActiveAlarms, 0, len(a.active))
+	for key, alarms := range a.active {
+		a.snapshot = append(a.snapshot, omciActiveAlarms{key, alarms})
+	}
+	sort.Slice(a.snapshot, func(i, j int) bool {
+		if a.snapshot[i].class != a.snapshot[j].class {
+			return a.snapshot[i].class < a.snapshot[j].class
+		}
+		return a.snapshot[i].instance < a.snapshot[j].instance
+	})
+	return uint16(len(a.snapshot))
+}
+
+// getAllAlarmsNext returns the entry of the snapshot with the given sequence number
+func (a *omciAlarms) getAllAlarmsNext(cmd uint16) (omciActiveAlarms, bool) {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	if int(cmd) >= len(a.snapshot) {
+		return omciActiveAlarms{}, false
+	}
+	return a.snapshot[cmd], true
+}
+
+// alarmsOmciResponse answers the Get All Alarms and Get All Alarms Next requests with the alarms raised on the ONU
+func (o *Onu) alarmsOmciResponse(request []byte) ([]byte, error) {
+	switch omcisim.OmciMsgType(request[2] & 0x1F) {
+	case omcisim.GetAllAlarms:
+		// NOTE the alarm retrieval mode (byte 8) is ignored, BBSim doesn't support the ARC
+		count := o.omciAlarms.getAllAlarms()
+		return newOmciResponse(request, byte(count>>8), byte(count&0xFF)), nil
+	case omcisim.GetAllAlarmsNext:
+		cmd := uint16(request[8])<<8 | uint16(request[9])
+		entry, ok := o.omciAlarms.getAllAlarmsNext(cmd)
+		if !ok {
+			// the ME class and instance are 0 when the command is out of range
+			return newOmciResponse(request), nil
+		}
+		contents := []byte{
+			byte(entry.class >> 8), byte(entry.class & 0xFF),
+			byte(entry.instance >> 8), byte(entry.instance & 0xFF),
+		}
+		return newOmciResponse(request, append(contents, entry.alarms[:]...)...), nil
+	}
+	return nil, errors.New(fmt.Sprintf("unexpected-omci-alarms-request-%x", request[2]))
+}
+
+// sendOmciAlarm queues an Alarm Notification for VOLTHA, the ONU goroutine sets its sequence number
+func (o *Onu) sendOmciAlarm(class uint16, instance uint16, alarms omciAlarmBitmap) error {
+	return o.queueOmciNotification(newOmciAlarmNotification(class, instance, alarms, 0))
+}
+
+// setOmciAlarm raises or clears an alarm, VOLTHA is notified only if the alarm changes
+func (o *Onu) setOmciAlarm(def omciAlarmDef, instance uint16, raise bool) error {
+	o.omciAlarms.lock.Lock()
+	key := omciMeKey{def.class, instance}
+	alarms := o.omciAlarms.active[key]
+	if alarms.isSet(def.alarm) == raise {
+		o.omciAlarms.lock.Unlock()
+		return errors.New(fmt.Sprintf("omci-alarm-%d-of-me-%d-%d-is-already-%s", def.alarm, def.class, instance, onOff(raise)))
+	}
+	if raise {
+		alarms.set(def.alarm)
+	} else {
+		alarms.clear(def.alarm)
+	}
+	if alarms.isEmpty() {
+		delete(o.omciAlarms.active, key)
+	} else {
+		o.omciAlarms.active[key] = alarms
+	}
+	// NOTE the lock is released before queueing the notification as the ONU goroutine takes it to number the notification
+	o.omciAlarms.lock.Unlock()
+
+	return o.sendOmciAlarm(def.class, instance, alarms)
+}
+
+// pptpInstance returns the instance ID of the PPTP of a UNI, the same as in the MIB upload
+func (o *Onu) pptpInstance(uniId uint32) uint16 {
+	if o.MibTemplate != nil {
+		if instances := o.MibTemplate.PptpInstances(); int(uniId) < len(instances) {
+			return instances[uniId]
+		}
+	}
+	return 0x0101 + uint16(uniId)
+}
+
+func onOff(on bool) string {
+	if on {
+		return common.AlarmStatusOn
+	}
+	return common.AlarmStatusOff
+}
+
+// newOmciAvc builds an autonomous Attribute Value Change (in the baseline message format) for a single attribute
+func newOmciAvc(class uint16, instance uint16, attr uint8, value ...byte) []byte {
+	pkt := make([]byte, 48)
+	pkt[2] = byte(omcisim.AttributeValueChange)
+	pkt[3] = 0x0a
+	pkt[4] = byte(class >> 8)
+	pkt[5] = byte(class & 0xFF)
+	pkt[6] = byte(instance >> 8)
+	pkt[7] = byte(instance & 0xFF)
+	mask := uint16(0x8000) >> (attr - 1)
+	pkt[8] = byte(mask >> 8)
+	pkt[9] = byte(mask & 0xFF)
+	copy(pkt[10:40], value)
+	return pkt
+}
+
+// SendOmciAlarm raises or clears an OMCI alarm, or reports the change of an operational state,
+// on the ONU ME the alarm type refers to (the PPTP of the UNI for the UNI alarms).
+// For the operational states "on" means enabled
+func (o *Onu) SendOmciAlarm(alarmType string, uniId uint32, status string) error {
+	if err := common.ValidateAlarmStatus(status); err != nil {
+		return err
+	}
+	if !o.InternalState.Is("enabled") {
+		return errors.New(fmt.Sprintf("onu-%s-is-not-enabled", o.Sn()))
+	}
+
+	t, err := common.GetOmciAlarmType(alarmType)
+	if err != nil {
+		return err
+	}
+
+	var class uint16
+	if def, ok := omciAlarmDefs[t]; ok {
+		class = def.class
+	} else {
+		class = omciAvcDefs[t].class
+	}
+
+	var instance uint16
+	switch class {
+	case omciPptpClass:
+		if _, err := o.GetUniById(uniId); err != nil {
+			return err
+		}
+		instance = o.pptpInstance(uniId)
+	case omciAniGClass:
+		instance = omciAniGInstance
+	}
+
+	onuLogger.WithFields(log.Fields{
+		"IntfId":     o.PonPortID,
+		"OnuId":      o.ID,
+		"OnuSn":      o.Sn(),
+		"AlarmType":  t,
+		"MeInstance": instance,
+		"Status":     status,
+	}).Info("Sending OMCI alarm")
+
+	if def, ok := omciAlarmDefs[t]; ok {
+		return o.setOmciAlarm(def, instance, status == common.AlarmStatusOn)
+	}
+
+	// the operational state is 0 when the ME is enabled and 1 when it is disabled
+	state := byte(1)
+	if status == common.AlarmStatusOn {
+		state = 0
+	}
+	return o.queueOmciNotification(newOmciAvc(class, instance, omciAvcDefs[t].attr, state))
+}
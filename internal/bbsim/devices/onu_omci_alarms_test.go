@@ -0,0 +1,217 @@
+/*
+ * Copyright 2018-present Open Networking Foundation
+
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+
+ * http://www.apache.org/licenses/LICENSE-2.0
+
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package devices
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/opencord/bbsim/internal/common"
+	omcisim "github.com/opencord/omci-sim"
+	"github.com/opencord/voltha-protos/v2/go/openolt"
+	"google.golang.org/grpc"
+	"gotest.tools/assert"
+)
+
+// sendQueuedOmciNotifications sends the notifications queued on the ONU channel, as the ONU goroutine does
+func sendQueuedOmciNotifications(t *testing.T, onu *Onu, stream openolt.Openolt_EnableIndicationServer) {
+	for len(onu.Channel) > 0 {
+		msg := <-onu.Channel
+		assert.Equal(t, msg.Type, AutonomousOmci)
+		onu.sendOmciNotification(msg.Data.(AutonomousOmciMessage).Pkt, stream)
+	}
+}
+
+func Test_Onu_SendOmciAlarm(t *testing.T) {
+	onu := createTestOnu()
+	stream := &mockOmciStream{}
+
+	err := onu.SendOmciAlarm("pptp_lan_los", 0, common.AlarmStatusOn)
+	assert.Error(t, err, "onu-BBSM00000101-is-not-enabled")
+
+	onu.InternalState.SetState("enabled")
+
+	err = onu.SendOmciAlarm("pptp_lan_los", 0, common.AlarmStatusOn)
+	assert.NilError(t, err)
+	err = onu.SendOmciAlarm(common.OmciAlarmAniGLowRxPower, 0, common.AlarmStatusOn)
+	assert.NilError(t, err)
+	err = onu.SendOmciAlarm(common.OmciAlarmPptpLanLos, 0, common.AlarmStatusOn)
+	assert.Error(t, err, "omci-alarm-0-of-me-11-257-is-already-on")
+	err = onu.SendOmciAlarm(common.OmciAlarmPptpLanLos, 4, common.AlarmStatusOn)
+	assert.Error(t, err, "cannot-find-uni-4-on-onu-BBSM00000101")
+	err = onu.SendOmciAlarm("Unknown", 0, common.AlarmStatusOn)
+	assert.Error(t, err, "unknown-alarm-type-Unknown")
+
+	// the notifications are sent by the ONU goroutine
+	assert.Equal(t, len(stream.Omci), 0)
+	sendQueuedOmciNotifications(t, onu, stream)
+	assert.Equal(t, len(stream.Omci), 2)
+	lanLos := stream.Omci[0]
+	assert.Equal(t, lanLos[2], byte(omcisim.AlarmNotification))
+	assert.Equal(t, uint16(lanLos[4])<<8|uint16(lanLos[5]), uint16(omciPptpClass))
+	assert.Equal(t, uint16(lanLos[6])<<8|uint16(lanLos[7]), uint16(0x0101))
+	assert.Equal(t, lanLos[8], byte(0x80))
+	assert.Equal(t, lanLos[39], byte(1))
+	lowRxPower := stream.Omci[1]
+	assert.Equal(t, uint16(lowRxPower[4])<<8|uint16(lowRxPower[5]), uint16(omciAniGClass))
+	assert.Equal(t, uint16(lowRxPower[6])<<8|uint16(lowRxPower[7]), uint16(omciAniGInstance))
+	assert.Equal(t, lowRxPower[39], byte(2))
+
+	// the notifications are in the OMCI history
+	entries := onu.GetOmciHistory()
+	assert.Equal(t, entries[0].Direction, OmciNotification)
+	assert.Equal(t, entries[0].MessageType, "AlarmNotification")
+	assert.Equal(t, entries[0].Result, "")
+
+	// clearing an alarm sends the alarms that are still raised on the ME
+	err = onu.SendOmciAlarm(common.OmciAlarmPptpLanLos, 0, common.AlarmStatusOff)
+	assert.NilError(t, err)
+	sendQueuedOmciNotifications(t, onu, stream)
+	assert.Equal(t, stream.Omci[2][8], byte(0x00))
+	assert.Equal(t, stream.Omci[2][39], byte(3))
+}
+
+type mockOmciChanStream struct {
+	grpc.ServerStream
+	channel chan []byte
+}
+
+func (s *mockOmciChanStream) Send(ind *openolt.Indication) error {
+	if omciInd := ind.GetOmciInd(); omciInd != nil {
+		s.channel <- omciInd.Pkt
+	}
+	return nil
+}
+
+func Test_Onu_SendOmciAlarm_from_onu_goroutine(t *testing.T) {
+	onu := createTestOnu()
+	onu.InternalState.SetState("enabled")
+	stream := &mockOmciChanStream{
+		channel: make(chan []byte, 10),
+	}
+	ctx, cancel := context.WithCancel(context.TODO())
+	defer cancel()
+	go onu.ProcessOnuMessages(ctx, stream, nil)
+
+	assert.NilError(t, onu.SendOmciAlarm(common.OmciAlarmPptpLanLos, 0, common.AlarmStatusOn))
+	assert.NilError(t, onu.SendOmciAlarm(common.OmciAvcOnuOperState, 0, common.AlarmStatusOff))
+	assert.NilError(t, onu.SendOmciAlarm(common.OmciAlarmPptpLanLos, 0, common.AlarmStatusOff))
+
+	expected := []struct {
+		msgType   omcisim.OmciMsgType
+		seqNumber byte
+	}{
+		{omcisim.AlarmNotification, 1},
+		{omcisim.AttributeValueChange, 0},
+		{omcisim.AlarmNotification, 2},
+	}
+	for _, e := range expected {
+		select {
+		case pkt := <-stream.channel:
+			assert.Equal(t, pkt[2], byte(e.msgType))
+			assert.Equal(t, pkt[39], e.seqNumber)
+		case <-time.After(1 * time.Second):
+			t.Fatal("the ONU has not sent the notification")
+		}
+	}
+}
+
+func Test_Onu_OmciAvc(t *testing.T) {
+	onu := createTestOnu()
+	onu.InternalState.SetState("enabled")
+	stream := &mockOmciStream{}
+
+	err := onu.SendOmciAlarm(common.OmciAvcUniOperState, 0, common.AlarmStatusOff)
+	assert.NilError(t, err)
+	err = onu.SendOmciAlarm(common.OmciAvcOnuOperState, 0, common.AlarmStatusOn)
+	assert.NilError(t, err)
+
+	sendQueuedOmciNotifications(t, onu, stream)
+	assert.Equal(t, len(stream.Omci), 2)
+	uni := stream.Omci[0]
+	assert.Equal(t, uni[2], byte(omcisim.AttributeValueChange))
+	assert.Equal(t, uint16(uni[6])<<8|uint16(uni[7]), uint16(0x0101))
+	// the operational state is attribute 6 of the PPTP
+	assert.Equal(t, uni[8], byte(0x04))
+	assert.Equal(t, uni[9], byte(0x00))
+	assert.Equal(t, uni[10], byte(1))
+	onuG := stream.Omci[1]
+	assert.Equal(t, uint16(onuG[4])<<8|uint16(onuG[5]), uint16(omciOnuGClass))
+	assert.Equal(t, onuG[8], byte(0x01))
+	assert.Equal(t, onuG[10], byte(0))
+
+	// the attribute value changes are not numbered
+	_ = onu.SendOmciAlarm(common.OmciAlarmOnuGEquipment, 0, common.AlarmStatusOn)
+	sendQueuedOmciNotifications(t, onu, stream)
+	assert.Equal(t, stream.Omci[2][39], byte(1))
+}
+
+func Test_Onu_OmciGetAllAlarms(t *testing.T) {
+	onu := createTestOnu()
+	onu.InternalState.SetState("enabled")
+	stream := &mockOmciStream{}
+
+	resp, err := onu.omciResponse(omciRequest(omcisim.GetAllAlarms, 0x0002, 0))
+	assert.NilError(t, err)
+	assert.Equal(t, resp[2], byte(0x20|omcisim.GetAllAlarms))
+	assert.Equal(t, resp[8], byte(0))
+	assert.Equal(t, resp[9], byte(0))
+
+	_ = onu.SendOmciAlarm(common.OmciAlarmAniGSignalDegrade, 0, common.AlarmStatusOn)
+	_ = onu.SendOmciAlarm(common.OmciAlarmPptpLanLos, 0, common.AlarmStatusOn)
+	sendQueuedOmciNotifications(t, onu, stream)
+	assert.Equal(t, stream.Omci[1][39], byte(2))
+
+	resp, _ = onu.omciResponse(omciRequest(omcisim.GetAllAlarms, 0x0002, 0))
+	assert.Equal(t, resp[9], byte(2))
+
+	// the MEs are uploaded in order
+	resp, err = onu.omciResponse(omciRequest(omcisim.GetAllAlarmsNext, 0x0002, 0, 0x00, 0x00))
+	assert.NilError(t, err)
+	assert.Equal(t, uint16(resp[8])<<8|uint16(resp[9]), uint16(omciPptpClass))
+	assert.Equal(t, uint16(resp[10])<<8|uint16(resp[11]), uint16(0x0101))
+	assert.Equal(t, resp[12], byte(0x80))
+	resp, _ = onu.omciResponse(omciRequest(omcisim.GetAllAlarmsNext, 0x0002, 0, 0x00, 0x01))
+	assert.Equal(t, uint16(resp[8])<<8|uint16(resp[9]), uint16(omciAniGClass))
+	assert.Equal(t, resp[12], byte(0x10))
+	resp, _ = onu.omciResponse(omciRequest(omcisim.GetAllAlarmsNext, 0x0002, 0, 0x00, 0x02))
+	assert.Equal(t, uint16(resp[8])<<8|uint16(resp[9]), uint16(0))
+
+	// Get All Alarms restarts the sequence numbers
+	_ = onu.SendOmciAlarm(common.OmciAlarmAniGSignalDegrade, 0, common.AlarmStatusOff)
+	sendQueuedOmciNotifications(t, onu, stream)
+	assert.Equal(t, stream.Omci[2][39], byte(1))
+
+	// as does a MIB reset, that keeps the alarms
+	_, _ = onu.omciResponse(omciRequest(omcisim.MibReset, 0x0002, 0))
+	_ = onu.SendOmciAlarm(common.OmciAlarmAniGSignalDegrade, 0, common.AlarmStatusOn)
+	sendQueuedOmciNotifications(t, onu, stream)
+	assert.Equal(t, stream.Omci[3][39], byte(1))
+	resp, _ = onu.omciResponse(omciRequest(omcisim.GetAllAlarms, 0x0002, 0))
+	assert.Equal(t, resp[9], byte(2))
+}
+
+func Test_OmciAlarms_seqNumber(t *testing.T) {
+	alarms := newOmciAlarms()
+	for i := 0; i < 255; i++ {
+		alarms.nextSeqNumber()
+	}
+	assert.Equal(t, alarms.seqNumber, uint8(255))
+	// 0 is skipped
+	assert.Equal(t, alarms.nextSeqNumber(), uint8(1))
+}
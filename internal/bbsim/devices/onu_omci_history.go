@@ -0,0 +1,143 @@
+/*
+ * Copyright 2018-present Open Networking Foundation
+
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+
+ * http://www.apache.org/licenses/LICENSE-2.0
+
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package devices
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	omcisim "github.com/opencord/omci-sim"
+)
+
+// the number of OMCI messages each ONU remembers
+const omciHistorySize = 256
+
+// the direction of the OMCI messages in the history
+const (
+	OmciRequest      = "request"
+	OmciResponse     = "response"
+	OmciNotification = "notification" // the Alarm Notifications and Attribute Value Changes the ONU sends autonomously
+)
+
+// the names of the OMCI result codes
+var omciResultNames = map[uint8]string{
+	0: "Success",
+	1: "ProcessingError",
+	2: "NotSupported",
+	3: "ParameterError",
+	4: "UnknownEntity",
+	5: "UnknownInstance",
+	6: "DeviceBusy",
+	7: "InstanceExists",
+	9: "AttributeFailure",
+}
+
+// OmciHistoryEntry is an OMCI message exchanged by an ONU
+type OmciHistoryEntry struct {
+	Time          time.Time
+	Direction     string // OmciRequest, OmciResponse or OmciNotification
+	TransactionId uint16
+	MessageType   string
+	MeClass       uint16
+	MeInstance    uint16
+	Result        string // only set for the responses that carry a result code
+	Dropped       bool   // the response has been dropped by an OmciFaultRule
+}
+
+// omciHistory is a ring buffer of the last OMCI messages of an ONU
+type omciHistory struct {
+	lock    sync.RWMutex
+	entries []OmciHistoryEntry
+	next    int
+}
+
+func newOmciHistory() *omciHistory {
+	return &omciHistory{
+		entries: make([]OmciHistoryEntry, 0, omciHistorySize),
+	}
+}
+
+func omciMsgTypeName(msgType omcisim.OmciMsgType) string {
+	name := msgType.PrettyPrint()
+	if name == string(msgType) {
+		return fmt.Sprintf("Unknown(%d)", msgType)
+	}
+	return name
+}
+
+// newOmciHistoryEntry decodes the header of an OMCI message (in the baseline message format)
+func newOmciHistoryEntry(direction string, pkt []byte) OmciHistoryEntry {
+	entry := OmciHistoryEntry{
+		Time:      time.Now(),
+		Direction: direction,
+	}
+	if len(pkt) < 8 {
+		entry.MessageType = "Invalid"
+		return entry
+	}
+
+	msgType := omcisim.OmciMsgType(pkt[2] & 0x1F)
+	entry.TransactionId = uint16(pkt[0])<<8 | uint16(pkt[1])
+	entry.MessageType = omciMsgTypeName(msgType)
+	entry.MeClass = uint16(pkt[4])<<8 | uint16(pkt[5])
+	entry.MeInstance = uint16(pkt[6])<<8 | uint16(pkt[7])
+
+	if direction == OmciResponse && len(pkt) > 8 && omciHasResultCode(msgType) {
+		if name, ok := omciResultNames[pkt[8]]; ok {
+			entry.Result = name
+		} else {
+			entry.Result = fmt.Sprintf("Unknown(%d)", pkt[8])
+		}
+	}
+	return entry
+}
+
+// add stores an entry, overwriting the oldest one if the history is full
+func (h *omciHistory) add(entry OmciHistoryEntry) {
+	if h == nil {
+		return
+	}
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	if len(h.entries) < omciHistorySize {
+		h.entries = append(h.entries, entry)
+		return
+	}
+	h.entries[h.next] = entry
+	h.next = (h.next + 1) % omciHistorySize
+}
+
+// get returns the entries from the oldest to the newest one
+func (h *omciHistory) get() []OmciHistoryEntry {
+	if h == nil {
+		return []OmciHistoryEntry{}
+	}
+	h.lock.RLock()
+	defer h.lock.RUnlock()
+
+	res := make([]OmciHistoryEntry, 0, len(h.entries))
+	res = append(res, h.entries[h.next:]...)
+	res = append(res, h.entries[:h.next]...)
+	return res
+}
+
+// GetOmciHistory returns the last OMCI requests and responses of the ONU, from the oldest to the newest one
+func (o *Onu) GetOmciHistory() []OmciHistoryEntry {
+	return o.omciHistory.get()
+}
@@ -0,0 +1,71 @@
+/*
+ * Copyright 2018-present Open Networking Foundation
+
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+
+ * http://www.apache.org/licenses/LICENSE-2.0
+
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package devices
+
+import (
+	"testing"
+
+	omcilib "github.com/opencord/bbsim/internal/common/omci"
+	"github.com/opencord/voltha-protos/v2/go/openolt"
+	"gotest.tools/assert"
+)
+
+func Test_OmciHistory_ring_buffer(t *testing.T) {
+	h := newOmciHistory()
+
+	for i := 0; i < omciHistorySize+10; i++ {
+		h.add(OmciHistoryEntry{TransactionId: uint16(i)})
+	}
+
+	// only the last entries are kept, from the oldest to the newest one
+	entries := h.get()
+	assert.Equal(t, len(entries), omciHistorySize)
+	assert.Equal(t, entries[0].TransactionId, uint16(10))
+	assert.Equal(t, entries[omciHistorySize-1].TransactionId, uint16(omciHistorySize+9))
+}
+
+func Test_Onu_OmciHistory(t *testing.T) {
+	onu := createTestOnu()
+	stream := &mockStream{
+		Calls:   make(map[int]*openolt.OnuDiscIndication),
+		channel: make(chan int, 10),
+	}
+
+	req, _ := omcilib.CreateMibResetRequest(0x0102)
+	onu.handleOmciMessage(OmciMessage{omciMsg: &openolt.OmciMsg{Pkt: req}}, stream)
+	req, _ = omcilib.CreateMibUploadRequest(0x0103)
+	onu.handleOmciMessage(OmciMessage{omciMsg: &openolt.OmciMsg{Pkt: req}, impairment: &omciImpairment{drop: true}}, stream)
+
+	entries := onu.GetOmciHistory()
+	assert.Equal(t, len(entries), 4)
+
+	assert.Equal(t, entries[0].Direction, OmciRequest)
+	assert.Equal(t, entries[0].TransactionId, uint16(0x0102))
+	assert.Equal(t, entries[0].MessageType, "MibReset")
+	assert.Equal(t, entries[0].MeClass, uint16(2))
+	assert.Equal(t, entries[0].Result, "")
+
+	assert.Equal(t, entries[1].Direction, OmciResponse)
+	assert.Equal(t, entries[1].TransactionId, uint16(0x0102))
+	assert.Equal(t, entries[1].Result, "Success")
+	assert.Equal(t, entries[1].Dropped, false)
+
+	// the MIB upload response does not carry a result code
+	assert.Equal(t, entries[3].MessageType, "MibUpload")
+	assert.Equal(t, entries[3].Result, "")
+	assert.Equal(t, entries[3].Dropped, true)
+}
@@ -0,0 +1,214 @@
+/*
+ * Copyright 2018-present Open Networking Foundation
+
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+
+ * http://www.apache.org/licenses/LICENSE-2.0
+
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package devices
+
+import (
+	"errors"
+	"fmt"
+
+	omcisim "github.com/opencord/omci-sim"
+)
+
+// the omci-sim library always reports 4 PPTPs (commands 9 to 12) and 4 UNI-Gs (commands 22 to 25)
+// in the MIB Upload, here we replace them with one entry per UNI port configured on the ONU
+const (
+	omciSimMibUploads     = uint16(omcisim.NumMibUploadsHigherByte)<<8 | uint16(omcisim.NumMibUploadsLowerByte)
+	omciSimFirstPptp      = 9
+	omciSimFirstTcont     = 13
+	omciSimFirstUniG      = 22
+	omciSimFirstGemPortPm = 26
+	omciSimNumPptp        = 4
+	omciSimNumUniG        = 4
+
+	omciPptpClass = 0x000b
+	omciUniGClass = 0x0108
+)
+
+var pptpMibUploadPkt = []byte{
+	0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0x00, 0x00,
+	0x00, 0x0b, 0x01, 0x01, 0xff, 0xfe, 0x00, 0x2f,
+	0x00, 0x00, 0x00, 0x00, 0x03, 0x05, 0xee, 0x00,
+	0x00, 0x00, 0x02, 0x00, 0x00, 0x00, 0x00, 0x00,
+	0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+	0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+}
+
+var uniGMibUploadPkt = []byte{
+	0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0x00, 0x00,
+	0x01, 0x08, 0x01, 0x01, 0xf8, 0x00, 0x00, 0x00,
+	0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+	0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+	0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+	0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+}
+
+// mibUploadCount returns the number of MIB Upload Next messages the ONU responds to
+func mibUploadCount(numUni int) uint16 {
+	return omciSimMibUploads - omciSimNumPptp - omciSimNumUniG + uint16(2*numUni)
+}
+
+// newOmciResponse builds the response to an OMCI request (in the baseline message format),
+// contents usually start with the result code
+func newOmciResponse(request []byte, contents ...byte) []byte {
+	resp := make([]byte, 48)
+	copy(resp, request[:8])
+	resp[2] = 0x2<<4 | request[2]&0x1F
+	copy(resp[8:], contents)
+	return resp
+}
+
+// omciAlarmBitmap holds the alarms of a ME, alarm number 0 is the most significant bit of the first byte
+type omciAlarmBitmap [28]byte
+
+func (b *omciAlarmBitmap) set(alarm uint8) {
+	b[alarm/8] |= 0x80 >> (alarm % 8)
+}
+
+func (b *omciAlarmBitmap) isEmpty() bool {
+	return *b == omciAlarmBitmap{}
+}
+
+// newOmciAlarmNotification builds an autonomous Alarm Notification (in the baseline message format)
+func newOmciAlarmNotification(class uint16, instance uint16, alarms omciAlarmBitmap, seqNumber uint8) []byte {
+	pkt := make([]byte, 48)
+	pkt[2] = byte(omcisim.AlarmNotification)
+	pkt[3] = 0x0a
+	pkt[4] = byte(class >> 8)
+	pkt[5] = byte(class & 0xFF)
+	pkt[6] = byte(instance >> 8)
+	pkt[7] = byte(instance & 0xFF)
+	copy(pkt[8:36], alarms[:])
+	pkt[39] = seqNumber
+	return pkt
+}
+
+// omciResponse answers an OMCI request (see omciMeResponse) and keeps the MIB of the ONU up to date (see omciMib)
+func (o *Onu) omciResponse(request []byte) ([]byte, error) {
+	resp, err := o.omciMeResponse(request)
+	if err == nil && resp != nil && len(request) >= 10 {
+		o.omciMib.update(request, resp)
+		o.omciPm.trackBridgePort(request, resp)
+	}
+	return resp, err
+}
+
+// omciMeResponse wraps the omci-sim library, adapts the MIB Upload to the number of UNIs on the ONU
+// (or replaces it with the MIB template of the ONU)
+// and handles the software upgrade (see swImageOmciResponse), the PM history data (see pmOmciResponse),
+// the alarms (see alarmsOmciResponse) and the MIB data sync (see ontDataOmciResponse)
+func (o *Onu) omciMeResponse(request []byte) ([]byte, error) {
+	if len(request) < 10 {
+		return omcisim.OmciSim(o.omciSimIntfId(), o.ID, request)
+	}
+
+	numUni := len(o.UniPorts)
+	class := uint16(request[4])<<8 | uint16(request[5])
+	msgType := omcisim.OmciMsgType(request[2] & 0x1F)
+
+	if class == omciOntDataClass && (msgType == omcisim.Get || msgType == omcisim.Set) {
+		return o.ontDataOmciResponse(request), nil
+	}
+
+	switch msgType {
+	case omcisim.Create, omcisim.Delete, omcisim.Set, omcisim.GetCurrentData:
+		if isOmciPmClass(class) {
+			return o.pmOmciResponse(request)
+		}
+	case omcisim.MibReset:
+		o.omciPm.reset()
+		o.omciAlarms.resetSeqNumber()
+	case omcisim.GetAllAlarms, omcisim.GetAllAlarmsNext:
+		return o.alarmsOmciResponse(request)
+	case omcisim.SynchronizeTime:
+		o.omciPm.synchronize()
+	case omcisim.StartSoftwareDownload, omcisim.DownloadSection, omcisim.EndSoftwareDownload,
+		omcisim.ActivateSoftware, omcisim.CommitSoftware:
+		return o.swImageOmciResponse(request)
+	case omcisim.Get:
+		if class == omciSoftwareImageClass {
+			return o.swImageOmciResponse(request)
+		}
+		if isOmciPmClass(class) {
+			return o.pmOmciResponse(request)
+		}
+	case omcisim.MibUpload:
+		if o.MibTemplate != nil {
+			count := o.MibTemplate.MibUploads()
+			return newOmciResponse(request, byte(count>>8), byte(count&0xFF)), nil
+		}
+		resp, err := omcisim.OmciSim(o.omciSimIntfId(), o.ID, request)
+		if err != nil {
+			return resp, err
+		}
+		count := mibUploadCount(numUni)
+		resp[8] = byte(count >> 8)
+		resp[9] = byte(count & 0xFF)
+		return resp, nil
+	case omcisim.MibUploadNext:
+		cmd := uint16(request[8])<<8 | uint16(request[9])
+		if o.MibTemplate != nil {
+			contents, ok := o.MibTemplate.MibUploadNext(cmd)
+			if !ok {
+				return nil, errors.New(fmt.Sprintf("mib-upload-next-command-%d-out-of-range-%d", cmd, o.MibTemplate.MibUploads()))
+			}
+			return newOmciResponse(request, contents...), nil
+		}
+		n := uint16(numUni)
+
+		var class uint16
+		var template []byte
+		var omciSimCmd uint16
+
+		switch {
+		case cmd < omciSimFirstPptp:
+			omciSimCmd = cmd
+		case cmd < omciSimFirstPptp+n:
+			class, template = omciPptpClass, pptpMibUploadPkt
+			cmd = cmd - omciSimFirstPptp
+		case cmd < omciSimFirstPptp+n+(omciSimFirstUniG-omciSimFirstTcont):
+			omciSimCmd = cmd - omciSimFirstPptp - n + omciSimFirstTcont
+		case cmd < omciSimFirstPptp+2*n+(omciSimFirstUniG-omciSimFirstTcont):
+			class, template = omciUniGClass, uniGMibUploadPkt
+			cmd = cmd - omciSimFirstPptp - n - (omciSimFirstUniG - omciSimFirstTcont)
+		default:
+			omciSimCmd = cmd - omciSimFirstPptp - 2*n - (omciSimFirstUniG - omciSimFirstTcont) + omciSimFirstGemPortPm
+		}
+
+		if template != nil {
+			// one entity per UNI, the instance ID is 0x01 followed by the 1-based UNI number
+			resp := make([]byte, len(template))
+			copy(resp, template)
+			resp[0] = request[0]
+			resp[1] = request[1]
+			resp[2] = 0x2<<4 | byte(omcisim.MibUploadNext)
+			resp[3] = request[3]
+			resp[8] = byte(class >> 8)
+			resp[9] = byte(class & 0xFF)
+			resp[10] = 0x01
+			resp[11] = byte(cmd + 1)
+			return resp, nil
+		}
+
+		req := make([]byte, len(request))
+		copy(req, request)
+		req[8] = byte(omciSimCmd >> 8)
+		req[9] = byte(omciSimCmd & 0xFF)
+		return omcisim.OmciSim(o.omciSimIntfId(), o.ID, req)
+	}
+
+	return omcisim.OmciSim(o.omciSimIntfId(), o.ID, request)
+}
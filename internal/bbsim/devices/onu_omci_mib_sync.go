@@ -0,0 +1,149 @@
+/*
+ * Copyright 2018-present Open Networking Foundation
+
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+
+ * http://www.apache.org/licenses/LICENSE-2.0
+
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package devices
+
+import (
+	"sort"
+	"sync"
+
+	me "github.com/cboling/omci/generated"
+	omcisim "github.com/opencord/omci-sim"
+)
+
+const omciOntDataClass = 0x0002
+
+// OmciMeInstance is a ME instance in the MIB of an ONU
+type OmciMeInstance struct {
+	Class    uint16
+	Instance uint16
+	Name     string // the name of the ME class, empty if unknown
+}
+
+// omciMib holds the ME instances of the ONU MIB and the MIB data sync counter (the only attribute of the ONT Data ME).
+// The MIB contains the MEs reported by the MIB upload and the ones created by VOLTHA, it survives the
+// reconnections of VOLTHA so that the adapter can resynchronize the MIB without uploading it again
+type omciMib struct {
+	lock      sync.RWMutex
+	dataSync  uint8
+	instances map[omciMeKey]bool
+}
+
+func newOmciMib() *omciMib {
+	return &omciMib{
+		instances: make(map[omciMeKey]bool),
+	}
+}
+
+// reset empties the MIB and clears the MIB data sync counter, as it happens on a MIB reset
+func (m *omciMib) reset() {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.dataSync = 0
+	m.instances = make(map[omciMeKey]bool)
+}
+
+// nextDataSync increments the MIB data sync counter, it must be called with the lock held
+func (m *omciMib) nextDataSync() {
+	// NOTE the counter goes from 1 to 255, 0 is only used after a MIB reset
+	if m.dataSync == 255 {
+		m.dataSync = 0
+	}
+	m.dataSync++
+}
+
+// update tracks the changes of the MIB made by a request, given the response of the ONU
+func (m *omciMib) update(request []byte, resp []byte) {
+	class := uint16(request[4])<<8 | uint16(request[5])
+	key := omciMeKey{class, uint16(request[6])<<8 | uint16(request[7])}
+	success := resp[8] == 0
+
+	switch omcisim.OmciMsgType(request[2] & 0x1F) {
+	case omcisim.MibReset:
+		if success {
+			m.reset()
+		}
+	case omcisim.MibUploadNext:
+		uploaded := omciMeKey{uint16(resp[8])<<8 | uint16(resp[9]), uint16(resp[10])<<8 | uint16(resp[11])}
+		if uploaded.class != 0 {
+			m.lock.Lock()
+			m.instances[uploaded] = true
+			m.lock.Unlock()
+		}
+	case omcisim.Create:
+		if success {
+			m.lock.Lock()
+			m.instances[key] = true
+			m.nextDataSync()
+			m.lock.Unlock()
+		}
+	case omcisim.Delete:
+		if success {
+			m.lock.Lock()
+			delete(m.instances, key)
+			m.nextDataSync()
+			m.lock.Unlock()
+		}
+	case omcisim.Set:
+		// NOTE setting the MIB data sync attribute doesn't change it any further
+		if success && class != omciOntDataClass {
+			m.lock.Lock()
+			m.nextDataSync()
+			m.lock.Unlock()
+		}
+	}
+}
+
+// ontDataOmciResponse answers the Get and Set requests on the ONT Data ME, whose only attribute is the MIB data sync
+func (o *Onu) ontDataOmciResponse(request []byte) []byte {
+	o.omciMib.lock.Lock()
+	defer o.omciMib.lock.Unlock()
+
+	mask := uint16(request[8])<<8 | uint16(request[9])
+	if omcisim.OmciMsgType(request[2]&0x1F) == omcisim.Set {
+		if mask&0x8000 != 0 {
+			o.omciMib.dataSync = request[10]
+		}
+		return newOmciResponse(request, omciResultSuccess)
+	}
+
+	if mask&0x8000 == 0 {
+		return newOmciResponse(request, omciResultSuccess)
+	}
+	return newOmciResponse(request, omciResultSuccess, 0x80, 0x00, o.omciMib.dataSync)
+}
+
+// GetOmciMib returns the MIB data sync counter and the ME instances in the MIB of the ONU, sorted by class and instance
+func (o *Onu) GetOmciMib() (uint8, []OmciMeInstance) {
+	o.omciMib.lock.RLock()
+	defer o.omciMib.lock.RUnlock()
+
+	instances := make([]OmciMeInstance, 0, len(o.omciMib.instances))
+	for key := range o.omciMib.instances {
+		instance := OmciMeInstance{Class: key.class, Instance: key.instance}
+		if entity, err := me.LoadManagedEntityDefinition(me.ClassID(key.class)); err == nil {
+			instance.Name = entity.GetName()
+		}
+		instances = append(instances, instance)
+	}
+	sort.Slice(instances, func(i, j int) bool {
+		if instances[i].Class != instances[j].Class {
+			return instances[i].Class < instances[j].Class
+		}
+		return instances[i].Instance < instances[j].Instance
+	})
+	return o.omciMib.dataSync, instances
+}
@@ -0,0 +1,104 @@
+/*
+ * Copyright 2018-present Open Networking Foundation
+
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+
+ * http://www.apache.org/licenses/LICENSE-2.0
+
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package devices
+
+import (
+	"testing"
+
+	"github.com/opencord/bbsim/internal/common"
+	omcisim "github.com/opencord/omci-sim"
+	"gotest.tools/assert"
+)
+
+const omciMacBridgeServiceProfileClass = 0x002d
+
+func omciMibDataSync(t *testing.T, onu *Onu) uint8 {
+	resp, err := onu.omciResponse(omciRequest(omcisim.Get, omciOntDataClass, 0, 0x80, 0x00))
+	assert.NilError(t, err)
+	assert.Equal(t, resp[8], byte(omciResultSuccess))
+	assert.Equal(t, resp[9], byte(0x80))
+	return resp[11]
+}
+
+func Test_Onu_MibDataSync(t *testing.T) {
+	onu := createTestOnu()
+	_, err := onu.omciResponse(omciRequest(omcisim.MibReset, omciOntDataClass, 0))
+	assert.NilError(t, err)
+	assert.Equal(t, omciMibDataSync(t, onu), uint8(0))
+
+	// create, set and delete increment the counter
+	_, _ = onu.omciResponse(omciRequest(omcisim.Create, omciMacBridgeServiceProfileClass, 1))
+	assert.Equal(t, omciMibDataSync(t, onu), uint8(1))
+	_, _ = onu.omciResponse(omciRequest(omcisim.Set, omciMacBridgeServiceProfileClass, 1, 0x80, 0x00, 0x01))
+	assert.Equal(t, omciMibDataSync(t, onu), uint8(2))
+	_, _ = onu.omciResponse(omciRequest(omcisim.Delete, omciMacBridgeServiceProfileClass, 1))
+	assert.Equal(t, omciMibDataSync(t, onu), uint8(3))
+
+	// a get doesn't
+	_, _ = onu.omciResponse(omciRequest(omcisim.Get, omciPptpClass, 0x0101, 0x80, 0x00))
+	assert.Equal(t, omciMibDataSync(t, onu), uint8(3))
+
+	// the OLT can set the counter
+	_, err = onu.omciResponse(omciRequest(omcisim.Set, omciOntDataClass, 0, 0x80, 0x00, 0xff))
+	assert.NilError(t, err)
+	assert.Equal(t, omciMibDataSync(t, onu), uint8(255))
+
+	// 0 is skipped
+	_, _ = onu.omciResponse(omciRequest(omcisim.Create, omciMacBridgeServiceProfileClass, 1))
+	assert.Equal(t, omciMibDataSync(t, onu), uint8(1))
+
+	_, _ = onu.omciResponse(omciRequest(omcisim.MibReset, omciOntDataClass, 0))
+	assert.Equal(t, omciMibDataSync(t, onu), uint8(0))
+}
+
+func Test_Onu_GetOmciMib(t *testing.T) {
+	template, err := common.LoadMibTemplate("two-uni", "../../../examples/mib-template.yaml")
+	assert.NilError(t, err)
+
+	onu := createTestOnu()
+	onu.setMibTemplate(template, 1)
+
+	_, _ = onu.omciResponse(omciRequest(omcisim.MibReset, omciOntDataClass, 0))
+	_, _ = onu.omciResponse(omciRequest(omcisim.MibUpload, omciOntDataClass, 0))
+	for cmd := uint16(0); cmd < template.MibUploads(); cmd++ {
+		mibUploadNext(t, onu, cmd)
+	}
+
+	dataSync, instances := onu.GetOmciMib()
+	assert.Equal(t, dataSync, uint8(0))
+	assert.Equal(t, len(instances), len(template.Mes))
+	assert.Equal(t, instances[0].Class, uint16(omciOntDataClass))
+	assert.Equal(t, instances[0].Name, "OnuData")
+
+	// the MEs created by the OLT are added to the MIB, the deleted ones are removed
+	_, _ = onu.omciResponse(omciRequest(omcisim.Create, omciMacBridgeServiceProfileClass, 1))
+	_, _ = onu.omciResponse(omciRequest(omcisim.Create, omciMacBridgeServiceProfileClass, 2))
+	_, _ = onu.omciResponse(omciRequest(omcisim.Delete, omciMacBridgeServiceProfileClass, 1))
+
+	dataSync, instances = onu.GetOmciMib()
+	assert.Equal(t, dataSync, uint8(3))
+	assert.Equal(t, len(instances), len(template.Mes)+1)
+	// sorted by class, after the circuit packs and the PPTPs
+	assert.Equal(t, instances[5].Class, uint16(omciMacBridgeServiceProfileClass))
+	assert.Equal(t, instances[5].Instance, uint16(2))
+
+	// the MIB is kept until the next MIB reset, or until the ONU reboots
+	onu.resetOmciState()
+	dataSync, instances = onu.GetOmciMib()
+	assert.Equal(t, dataSync, uint8(0))
+	assert.Equal(t, len(instances), 0)
+}
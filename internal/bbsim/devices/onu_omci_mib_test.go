@@ -0,0 +1,112 @@
+/*
+ * Copyright 2018-present Open Networking Foundation
+
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+
+ * http://www.apache.org/licenses/LICENSE-2.0
+
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package devices
+
+import (
+	"testing"
+
+	"github.com/opencord/bbsim/internal/common"
+	omcilib "github.com/opencord/bbsim/internal/common/omci"
+	omcisim "github.com/opencord/omci-sim"
+	"gotest.tools/assert"
+)
+
+func mibUploadNext(t *testing.T, onu *Onu, seq uint16) []byte {
+	req, _ := omcilib.CreateMibUploadNextRequest(1, seq)
+	resp, err := onu.omciResponse(HexDecode(req))
+	assert.NilError(t, err)
+	return resp
+}
+
+func Test_Onu_MibUpload_count(t *testing.T) {
+	onu := createTestOnu()
+	onu.UniPorts = append(onu.UniPorts, CreateUniPort(onu, 1))
+
+	req, _ := omcilib.CreateMibUploadRequest(1)
+	resp, err := onu.omciResponse(HexDecode(req))
+	assert.NilError(t, err)
+
+	// 291 entries from omci-sim, without its 4 PPTPs and 4 UNI-Gs, plus one of each per UNI
+	assert.Equal(t, uint16(resp[8])<<8|uint16(resp[9]), uint16(287))
+}
+
+func Test_Onu_MibUploadNext_unis(t *testing.T) {
+	onu := createTestOnu()
+	onu.UniPorts = append(onu.UniPorts, CreateUniPort(onu, 1))
+
+	// the entries before the PPTPs are not changed
+	resp := mibUploadNext(t, onu, 8)
+	assert.Equal(t, resp[9], byte(0x06))
+
+	// one PPTP per UNI
+	for i := uint16(0); i < 2; i++ {
+		resp = mibUploadNext(t, onu, 9+i)
+		assert.Equal(t, resp[2], byte(0x2e))
+		assert.Equal(t, uint16(resp[8])<<8|uint16(resp[9]), uint16(0x000b))
+		assert.Equal(t, resp[11], byte(i+1))
+	}
+
+	// followed by the T-CONTs and the ANI-G
+	resp = mibUploadNext(t, onu, 11)
+	assert.Equal(t, uint16(resp[8])<<8|uint16(resp[9]), uint16(0x0106))
+	resp = mibUploadNext(t, onu, 19)
+	assert.Equal(t, uint16(resp[8])<<8|uint16(resp[9]), uint16(0x0107))
+
+	// one UNI-G per UNI
+	for i := uint16(0); i < 2; i++ {
+		resp = mibUploadNext(t, onu, 20+i)
+		assert.Equal(t, uint16(resp[8])<<8|uint16(resp[9]), uint16(0x0108))
+		assert.Equal(t, resp[11], byte(i+1))
+	}
+
+	// the last entry is the same as the last one in omci-sim
+	last, _ := omcilib.CreateMibUploadNextRequest(1, 290)
+	expected, _ := omcisim.OmciSim(onu.omciSimIntfId(), onu.ID, HexDecode(last))
+	resp = mibUploadNext(t, onu, 286)
+	assert.DeepEqual(t, resp, expected)
+}
+
+func Test_Onu_MibTemplate(t *testing.T) {
+	template, err := common.LoadMibTemplate("two-uni", "../../../examples/mib-template.yaml")
+	assert.NilError(t, err)
+
+	onu := createTestOnu()
+	onu.setMibTemplate(template, 1)
+
+	// one UNI per PPTP in the template
+	assert.Equal(t, len(onu.UniPorts), 2)
+	assert.Equal(t, onu.pptpInstance(1), uint16(0x0102))
+
+	req, _ := omcilib.CreateMibUploadRequest(1)
+	resp, err := onu.omciResponse(HexDecode(req))
+	assert.NilError(t, err)
+	assert.Equal(t, uint16(resp[8])<<8|uint16(resp[9]), template.MibUploads())
+
+	resp = mibUploadNext(t, onu, 3)
+	contents, _ := template.MibUploadNext(3)
+	assert.Equal(t, resp[2], byte(0x2e))
+	assert.DeepEqual(t, resp[8:8+len(contents)], contents)
+
+	req, _ = omcilib.CreateMibUploadNextRequest(1, template.MibUploads())
+	_, err = onu.omciResponse(HexDecode(req))
+	assert.ErrorContains(t, err, "out-of-range")
+
+	// back to the built-in MIB
+	onu.setMibTemplate(nil, 1)
+	assert.Equal(t, len(onu.UniPorts), 1)
+	assert.Equal(t, onu.pptpInstance(0), uint16(0x0101))
+}
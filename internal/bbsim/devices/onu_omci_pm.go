@@ -0,0 +1,621 @@
+/*
+ * Copyright 2018-present Open Networking Foundation
+
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+
+ * http://www.apache.org/licenses/LICENSE-2.0
+
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package devices
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	omcisim "github.com/opencord/omci-sim"
+	"github.com/opencord/voltha-protos/v2/go/openolt"
+	log "github.com/sirupsen/logrus"
+)
+
+// the Performance Monitoring history data MEs the ONU keeps the 15-minute interval counters of,
+// and the Threshold Data MEs they refer to
+const (
+	omciEthernetPmClass      = 0x0018 // Ethernet PM history data
+	omciFecPmClass           = 0x0138 // FEC PM history data
+	omciEthFrameDownPmClass  = 0x0141 // Ethernet frame PM history data downstream
+	omciEthFrameUpPmClass    = 0x0142 // Ethernet frame PM history data upstream
+	omciGemPortPmClass       = 0x0155 // GEM port network CTP PM history data
+	omciThresholdData1Class  = 0x0111
+	omciThresholdData2Class  = 0x0112
+	omciThresholdDataSize    = 7   // the number of thresholds in each Threshold Data ME
+	omciFecCodeWordPayload   = 216 // the payload of a downstream FEC code word, RS(248,216)
+	omciResultInstanceExists = 7
+
+	omciMacBridgePortClass = 0x002f // MAC bridge port configuration data
+	omciTpTypePptpUni      = 1      // the termination point of a MAC bridge port is a PPTP Ethernet UNI
+)
+
+// omciPmClass describes a PM history data ME class: the size in bytes of each attribute
+// (the first two are always the interval end time and the threshold data 1/2 ID)
+// and the threshold crossing alerts of its counters
+type omciPmClass struct {
+	sizes []int
+	tcas  []omciPmTca
+}
+
+// omciPmTca raises the alarm when the counter in attribute attr exceeds the threshold value
+// number threshold (1 to 7 are in Threshold Data 1, 8 to 14 in Threshold Data 2)
+type omciPmTca struct {
+	attr      int
+	alarm     uint8
+	threshold int
+}
+
+var omciPmClasses = map[uint16]omciPmClass{
+	omciEthernetPmClass: {
+		// NOTE the ONU doesn't emulate any Ethernet error, these counters stay at 0
+		sizes: []int{1, 2, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4},
+	},
+	omciFecPmClass: {
+		sizes: []int{1, 2, 4, 4, 4, 4, 2},
+		tcas:  []omciPmTca{{3, 0, 1}, {4, 1, 2}, {5, 2, 3}, {7, 4, 4}},
+	},
+	omciEthFrameDownPmClass: {
+		sizes: []int{1, 2, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4},
+		tcas:  []omciPmTca{{3, 0, 1}, {8, 1, 2}, {9, 2, 3}, {10, 3, 4}},
+	},
+	omciEthFrameUpPmClass: {
+		sizes: []int{1, 2, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4},
+		tcas:  []omciPmTca{{3, 0, 1}, {8, 1, 2}, {9, 2, 3}, {10, 3, 4}},
+	},
+	omciGemPortPmClass: {
+		sizes: []int{1, 2, 4, 4, 8, 8, 4},
+		tcas:  []omciPmTca{{7, 1, 1}},
+	},
+}
+
+var omciThresholdDataSizes = []int{4, 4, 4, 4, 4, 4, 4}
+
+type omciMeKey struct {
+	class    uint16
+	instance uint16
+}
+
+// omciPmMe is an instance of a PM history data ME, the counters are indexed by attribute number
+type omciPmMe struct {
+	thresholdDataId uint16
+	current         []uint64 // the counters of the current interval
+	history         []uint64 // the counters of the last completed interval
+	tcas            omciAlarmBitmap
+}
+
+// omciPm holds the PM MEs created by VOLTHA, they are deleted by a MIB reset or a reboot
+type omciPm struct {
+	lock        sync.Mutex
+	intervalEnd uint8 // the number of the last completed interval
+	mes         map[omciMeKey]*omciPmMe
+	thresholds  map[omciMeKey][]uint32
+	bridgePorts map[uint16]uint16 // the PPTP UNI instance of the MAC bridge ports on the UNI side, by bridge port instance
+}
+
+// omciPmFrame is a frame the ONU has exchanged on the PON, its size includes the FCS
+type omciPmFrame struct {
+	upstream  bool
+	dropped   bool
+	size      uint64
+	broadcast bool
+	multicast bool
+	pptp      uint16 // the PPTP UNI instance of the UNI the frame goes through, 0 if not known
+	gemPort   uint32 // the GEM port the frame goes through, 0 if not known
+}
+
+// omciTca is a change in the threshold crossing alerts of a PM ME
+type omciTca struct {
+	class    uint16
+	instance uint16
+	alarms   omciAlarmBitmap
+}
+
+func newOmciPm() *omciPm {
+	return &omciPm{
+		mes:         make(map[omciMeKey]*omciPmMe),
+		thresholds:  make(map[omciMeKey][]uint32),
+		bridgePorts: make(map[uint16]uint16),
+	}
+}
+
+// omciPptpInstance returns the instance of the PPTP Ethernet UNI ME of a UNI, as reported by the MIB upload
+func omciPptpInstance(uni *UniPort) uint16 {
+	return 0x0100 | uint16(uni.ID+1)
+}
+
+func newOmciPmFrame(upstream bool, pkt []byte, dropped bool) omciPmFrame {
+	// NOTE BBSim doesn't pad the frames, nor it adds the FCS
+	size := uint64(len(pkt) + 4)
+	if size < 64 {
+		size = 64
+	}
+	f := omciPmFrame{
+		upstream: upstream,
+		dropped:  dropped,
+		size:     size,
+	}
+	if len(pkt) >= 6 {
+		f.broadcast = pkt[0]&pkt[1]&pkt[2]&pkt[3]&pkt[4]&pkt[5] == 0xFF
+		f.multicast = !f.broadcast && pkt[0]&0x01 != 0
+	}
+	return f
+}
+
+// reset deletes all the PM and Threshold Data MEs
+func (p *omciPm) reset() {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	p.intervalEnd = 0
+	p.mes = make(map[omciMeKey]*omciPmMe)
+	p.thresholds = make(map[omciMeKey][]uint32)
+	p.bridgePorts = make(map[uint16]uint16)
+}
+
+// trackBridgePort keeps track of the MAC bridge ports VOLTHA creates on the UNIs, given the response of the ONU,
+// so that the Ethernet frame PM history data MEs (whose instance is the one of the bridge port) only count
+// the frames of their UNI
+func (p *omciPm) trackBridgePort(request []byte, resp []byte) {
+	if len(request) < 14 || uint16(request[4])<<8|uint16(request[5]) != omciMacBridgePortClass || resp[8] != omciResultSuccess {
+		return
+	}
+	instance := uint16(request[6])<<8 | uint16(request[7])
+
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	switch omcisim.OmciMsgType(request[2] & 0x1F) {
+	case omcisim.Create:
+		// NOTE the attributes set by create are the bridge ID pointer, the port number, the TP type and the TP pointer
+		if request[11] == omciTpTypePptpUni {
+			p.bridgePorts[instance] = uint16(request[12])<<8 | uint16(request[13])
+		}
+	case omcisim.Delete:
+		delete(p.bridgePorts, instance)
+	}
+}
+
+// synchronize starts a new interval numbered 0, as requested by a Synchronize Time message.
+// The TCAs are cleared without notification
+func (p *omciPm) synchronize() {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	p.intervalEnd = 0
+	for _, me := range p.mes {
+		me.current = make([]uint64, len(me.current))
+		me.tcas = omciAlarmBitmap{}
+	}
+}
+
+// endInterval moves the counters of the current interval to the history
+// and returns the TCAs that are cleared with the new interval
+func (p *omciPm) endInterval() []omciTca {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	p.intervalEnd++
+
+	cleared := []omciTca{}
+	for key, me := range p.mes {
+		me.history = me.current
+		me.current = make([]uint64, len(me.history))
+		if !me.tcas.isEmpty() {
+			me.tcas = omciAlarmBitmap{}
+			cleared = append(cleared, omciTca{class: key.class, instance: key.instance})
+		}
+	}
+	return cleared
+}
+
+// threshold returns a threshold value (1 to 14) of a Threshold Data ID, 0 if not set
+func (p *omciPm) threshold(id uint16, n int) uint32 {
+	class := uint16(omciThresholdData1Class)
+	if n > omciThresholdDataSize {
+		class = omciThresholdData2Class
+	}
+	values, ok := p.thresholds[omciMeKey{class, id}]
+	if !ok {
+		return 0
+	}
+	return values[(n-1)%omciThresholdDataSize]
+}
+
+// countFrame accounts a frame in the PM MEs and returns the TCAs it raises
+func (p *omciPm) countFrame(f omciPmFrame) []omciTca {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	// NOTE the Ethernet frame PM MEs count the frames of the UNI of their bridge port,
+	// the GEM port PM MEs the frames of their GEM port and the FEC PM MEs all the frames
+	raised := []omciTca{}
+	for key, me := range p.mes {
+		c := me.current
+		switch key.class {
+		case omciEthFrameDownPmClass, omciEthFrameUpPmClass:
+			if f.upstream != (key.class == omciEthFrameUpPmClass) {
+				continue
+			}
+			if pptp, ok := p.bridgePorts[key.instance]; !ok || f.pptp == 0 || pptp != f.pptp {
+				continue
+			}
+			if f.dropped {
+				c[3]++
+				break
+			}
+			c[4] += f.size
+			c[5]++
+			if f.broadcast {
+				c[6]++
+			}
+			if f.multicast {
+				c[7]++
+			}
+			switch {
+			case f.size <= 64:
+				c[11]++
+			case f.size <= 127:
+				c[12]++
+			case f.size <= 255:
+				c[13]++
+			case f.size <= 511:
+				c[14]++
+			case f.size <= 1023:
+				c[15]++
+			case f.size <= 1518:
+				c[16]++
+			default:
+				c[10]++
+			}
+		case omciGemPortPmClass:
+			if f.gemPort == 0 || uint32(key.instance) != f.gemPort {
+				continue
+			}
+			if f.upstream {
+				c[3]++
+				c[6] += f.size
+			} else {
+				c[4]++
+				c[5] += f.size
+			}
+		case omciFecPmClass:
+			if !f.upstream {
+				c[6] += (f.size + omciFecCodeWordPayload - 1) / omciFecCodeWordPayload
+			}
+		}
+
+		if me.thresholdDataId == 0 {
+			continue
+		}
+		alarms := me.tcas
+		for _, tca := range omciPmClasses[key.class].tcas {
+			threshold := p.threshold(me.thresholdDataId, tca.threshold)
+			if threshold > 0 && c[tca.attr] > uint64(threshold) {
+				alarms.set(tca.alarm)
+			}
+		}
+		if alarms != me.tcas {
+			me.tcas = alarms
+			raised = append(raised, omciTca{class: key.class, instance: key.instance, alarms: alarms})
+		}
+	}
+	return raised
+}
+
+// omciGetResponse answers a Get request with the attributes of a ME, sizes are the sizes in bytes of the attributes.
+// The attributes that don't fit in the response are reported as failed
+func omciGetResponse(request []byte, sizes []int, value func(attr int) uint64) []byte {
+	mask := uint16(request[8])<<8 | uint16(request[9])
+
+	resp := newOmciResponse(request, omciResultSuccess)
+	pos := 11
+	var returned, unsupported, failed uint16
+	for attr := 1; attr <= 16; attr++ {
+		bit := uint16(0x8000) >> uint(attr-1)
+		if mask&bit == 0 {
+			continue
+		}
+		if attr > len(sizes) {
+			unsupported |= bit
+			continue
+		}
+		size := sizes[attr-1]
+		if pos+size > 36 {
+			failed |= bit
+			continue
+		}
+		v := value(attr)
+		for i := 0; i < size; i++ {
+			resp[pos+i] = byte(v >> uint(8*(size-1-i)))
+		}
+		pos += size
+		returned |= bit
+	}
+	resp[9] = byte(returned >> 8)
+	resp[10] = byte(returned & 0xFF)
+	if unsupported|failed != 0 {
+		resp[8] = omciResultAttributeFailure
+		resp[36] = byte(unsupported >> 8)
+		resp[37] = byte(unsupported & 0xFF)
+		resp[38] = byte(failed >> 8)
+		resp[39] = byte(failed & 0xFF)
+	}
+	return resp
+}
+
+// omciSetAttributes decodes the attributes of a Set request, set is called for each of them
+// and returns false if the attribute can't be written. It returns the result and the attribute execution mask
+func omciSetAttributes(contents []byte, sizes []int, set func(attr int, value uint64) bool) (byte, uint16) {
+	mask := uint16(contents[0])<<8 | uint16(contents[1])
+	pos := 2
+	var failed uint16
+	for attr := 1; attr <= 16; attr++ {
+		bit := uint16(0x8000) >> uint(attr-1)
+		if mask&bit == 0 {
+			continue
+		}
+		if attr > len(sizes) || pos+sizes[attr-1] > len(contents) {
+			return omciResultParameterError, 0
+		}
+		v := uint64(0)
+		for _, b := range contents[pos : pos+sizes[attr-1]] {
+			v = v<<8 | uint64(b)
+		}
+		pos += sizes[attr-1]
+		if !set(attr, v) {
+			failed |= bit
+		}
+	}
+	if failed != 0 {
+		return omciResultAttributeFailure, failed
+	}
+	return omciResultSuccess, 0
+}
+
+// isOmciPmClass returns true if the ME class is a PM history data or a Threshold Data ME the ONU handles
+func isOmciPmClass(class uint16) bool {
+	if class == omciThresholdData1Class || class == omciThresholdData2Class {
+		return true
+	}
+	_, ok := omciPmClasses[class]
+	return ok
+}
+
+// pmOmciResponse handles the Create, Delete, Set, Get and Get Current Data requests
+// on the PM history data and Threshold Data MEs
+func (o *Onu) pmOmciResponse(request []byte) ([]byte, error) {
+	if len(request) < 40 {
+		return nil, errors.New(fmt.Sprintf("invalid-omci-pm-request-length-%d", len(request)))
+	}
+
+	p := o.omciPm
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	key := omciMeKey{
+		class:    uint16(request[4])<<8 | uint16(request[5]),
+		instance: uint16(request[6])<<8 | uint16(request[7]),
+	}
+	contents := request[8:40]
+	msgType := omcisim.OmciMsgType(request[2] & 0x1F)
+
+	logger := onuLogger.WithFields(log.Fields{
+		"IntfId":     o.PonPortID,
+		"OnuId":      o.ID,
+		"OnuSn":      o.Sn(),
+		"MeClass":    key.class,
+		"MeInstance": key.instance,
+	})
+
+	if key.class == omciThresholdData1Class || key.class == omciThresholdData2Class {
+		values, exists := p.thresholds[key]
+		switch msgType {
+		case omcisim.Create:
+			if exists {
+				return newOmciResponse(request, omciResultInstanceExists), nil
+			}
+			values = make([]uint32, omciThresholdDataSize)
+			for i := range values {
+				values[i] = uint32(contents[4*i])<<24 | uint32(contents[4*i+1])<<16 | uint32(contents[4*i+2])<<8 | uint32(contents[4*i+3])
+			}
+			p.thresholds[key] = values
+			logger.WithFields(log.Fields{
+				"Thresholds": values,
+			}).Debug("Threshold Data created")
+			return newOmciResponse(request, omciResultSuccess), nil
+		case omcisim.Delete:
+			if !exists {
+				return newOmciResponse(request, omciResultUnknownInstance), nil
+			}
+			delete(p.thresholds, key)
+			return newOmciResponse(request, omciResultSuccess), nil
+		}
+		if !exists {
+			return newOmciResponse(request, omciResultUnknownInstance), nil
+		}
+		switch msgType {
+		case omcisim.Set:
+			result, failed := omciSetAttributes(contents, omciThresholdDataSizes, func(attr int, v uint64) bool {
+				values[attr-1] = uint32(v)
+				return true
+			})
+			logger.WithFields(log.Fields{
+				"Thresholds": values,
+			}).Debug("Threshold Data updated")
+			return newOmciResponse(request, result, 0, 0, byte(failed>>8), byte(failed&0xFF)), nil
+		case omcisim.Get:
+			return omciGetResponse(request, omciThresholdDataSizes, func(attr int) uint64 {
+				return uint64(values[attr-1])
+			}), nil
+		}
+		return newOmciResponse(request, omciResultParameterError), nil
+	}
+
+	class := omciPmClasses[key.class]
+	me, exists := p.mes[key]
+	switch msgType {
+	case omcisim.Create:
+		if exists {
+			return newOmciResponse(request, omciResultInstanceExists), nil
+		}
+		p.mes[key] = &omciPmMe{
+			thresholdDataId: uint16(contents[0])<<8 | uint16(contents[1]),
+			current:         make([]uint64, len(class.sizes)+1),
+			history:         make([]uint64, len(class.sizes)+1),
+		}
+		logger.Debug("PM history data created")
+		return newOmciResponse(request, omciResultSuccess), nil
+	case omcisim.Delete:
+		if !exists {
+			return newOmciResponse(request, omciResultUnknownInstance), nil
+		}
+		delete(p.mes, key)
+		return newOmciResponse(request, omciResultSuccess), nil
+	}
+	if !exists {
+		return newOmciResponse(request, omciResultUnknownInstance), nil
+	}
+
+	switch msgType {
+	case omcisim.Set:
+		// NOTE the threshold data ID is the only attribute that can be written
+		result, failed := omciSetAttributes(contents, class.sizes, func(attr int, v uint64) bool {
+			if attr != 2 {
+				return false
+			}
+			me.thresholdDataId = uint16(v)
+			return true
+		})
+		return newOmciResponse(request, result, 0, 0, byte(failed>>8), byte(failed&0xFF)), nil
+	case omcisim.Get, omcisim.GetCurrentData:
+		// Get returns the counters of the last completed interval, Get Current Data the ones of the current interval
+		counters := me.history
+		if msgType == omcisim.GetCurrentData {
+			counters = me.current
+		}
+		return omciGetResponse(request, class.sizes, func(attr int) uint64 {
+			switch attr {
+			case 1:
+				return uint64(p.intervalEnd)
+			case 2:
+				return uint64(me.thresholdDataId)
+			}
+			return counters[attr]
+		}), nil
+	}
+	return newOmciResponse(request, omciResultParameterError), nil
+}
+
+// countOmciPmFrame accounts a frame the ONU has sent (upstream) or received on a UNI and a GEM port
+// in the PM history data MEs and notifies VOLTHA about the threshold crossing alerts.
+// The UNI can be nil and the GEM port 0 if they are not known
+func (o *Onu) countOmciPmFrame(upstream bool, uni *UniPort, gemPort uint32, pkt []byte, dropped bool) {
+	f := newOmciPmFrame(upstream, pkt, dropped)
+	if uni != nil {
+		f.pptp = omciPptpInstance(uni)
+	}
+	f.gemPort = gemPort
+	for _, tca := range o.omciPm.countFrame(f) {
+		onuLogger.WithFields(log.Fields{
+			"IntfId":     o.PonPortID,
+			"OnuId":      o.ID,
+			"OnuSn":      o.Sn(),
+			"MeClass":    tca.class,
+			"MeInstance": tca.instance,
+		}).Info("Threshold crossing alert raised")
+		o.sendOmciTca(tca)
+	}
+}
+
+// endOmciPmInterval ends the current 15-minute interval and clears the threshold crossing alerts
+func (o *Onu) endOmciPmInterval() {
+	cleared := o.omciPm.endInterval()
+	if !o.InternalState.Is("enabled") {
+		return
+	}
+	for _, tca := range cleared {
+		o.sendOmciTca(tca)
+	}
+}
+
+func (o *Onu) sendOmciTca(tca omciTca) {
+	if err := o.sendOmciAlarm(tca.class, tca.instance, tca.alarms); err != nil {
+		onuLogger.WithFields(log.Fields{
+			"IntfId":     o.PonPortID,
+			"OnuId":      o.ID,
+			"OnuSn":      o.Sn(),
+			"MeClass":    tca.class,
+			"MeInstance": tca.instance,
+		}).Warnf("Cannot send the threshold crossing alert: %s", err.Error())
+	}
+}
+
+// omciPmStream counts the packets the ONU sends to VOLTHA in the PM history data MEs
+type omciPmStream struct {
+	openolt.Openolt_EnableIndicationServer
+	onu *Onu
+}
+
+// newOmciPmStream wraps the stream the ONU sends its indications on, it is not wrapped twice
+// as the ONU messages are processed again with the same stream after a reboot
+func newOmciPmStream(onu *Onu, stream openolt.Openolt_EnableIndicationServer) *omciPmStream {
+	if s, ok := stream.(*omciPmStream); ok {
+		stream = s.Openolt_EnableIndicationServer
+	}
+	return &omciPmStream{
+		Openolt_EnableIndicationServer: stream,
+		onu:                            onu,
+	}
+}
+
+func (s *omciPmStream) Send(ind *openolt.Indication) error {
+	if err := s.Openolt_EnableIndicationServer.Send(ind); err != nil {
+		return err
+	}
+	if pktInd, ok := ind.Data.(*openolt.Indication_PktInd); ok {
+		uni, _ := s.onu.GetUniByPortNo(pktInd.PktInd.PortNo)
+		s.onu.countOmciPmFrame(true, uni, pktInd.PktInd.GemportId, pktInd.PktInd.Pkt, false)
+	}
+	return nil
+}
+
+// processOmciPmIntervals ends the PM intervals of all the ONUs every OmciPmInterval seconds
+func (o *OltDevice) processOmciPmIntervals(ctx context.Context, wg *sync.WaitGroup) {
+	defer wg.Done()
+
+	interval := o.Options.OmciPmInterval
+	if interval <= 0 {
+		oltLogger.Debug("OMCI PM intervals are disabled")
+		return
+	}
+
+	ticker := time.NewTicker(time.Duration(interval) * time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			oltLogger.Debug("OMCI PM intervals processing canceled via context")
+			return
+		case <-ticker.C:
+			for _, pon := range o.Pons {
+				for _, onu := range pon.GetOnus() {
+					onu.endOmciPmInterval()
+				}
+			}
+		}
+	}
+}
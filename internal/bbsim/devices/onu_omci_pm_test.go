@@ -0,0 +1,238 @@
+/*
+ * Copyright 2018-present Open Networking Foundation
+
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+
+ * http://www.apache.org/licenses/LICENSE-2.0
+
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package devices
+
+import (
+	"testing"
+
+	omcisim "github.com/opencord/omci-sim"
+	"github.com/opencord/voltha-protos/v2/go/openolt"
+	"google.golang.org/grpc"
+	"gotest.tools/assert"
+)
+
+type mockOmciStream struct {
+	grpc.ServerStream
+	Omci [][]byte
+	Pkts []*openolt.PacketIndication
+}
+
+func (s *mockOmciStream) Send(ind *openolt.Indication) error {
+	if omciInd := ind.GetOmciInd(); omciInd != nil {
+		s.Omci = append(s.Omci, omciInd.Pkt)
+	}
+	if pktInd := ind.GetPktInd(); pktInd != nil {
+		s.Pkts = append(s.Pkts, pktInd)
+	}
+	return nil
+}
+
+// omciRequest builds an OMCI request (in the baseline message format)
+func omciRequest(msgType omcisim.OmciMsgType, class uint16, instance uint16, contents ...byte) []byte {
+	pkt := make([]byte, 48)
+	pkt[1] = 0x01
+	pkt[2] = 0x40 | byte(msgType)
+	pkt[3] = 0x0a
+	pkt[4] = byte(class >> 8)
+	pkt[5] = byte(class & 0xFF)
+	pkt[6] = byte(instance >> 8)
+	pkt[7] = byte(instance & 0xFF)
+	copy(pkt[8:], contents)
+	return pkt
+}
+
+func omciPmCounter(resp []byte, pos int) uint32 {
+	return uint32(resp[pos])<<24 | uint32(resp[pos+1])<<16 | uint32(resp[pos+2])<<8 | uint32(resp[pos+3])
+}
+
+// createOmciBridgePort creates a MAC bridge port on the PPTP UNI of a UNI
+func createOmciBridgePort(t *testing.T, onu *Onu, instance uint16, uni *UniPort) {
+	pptp := omciPptpInstance(uni)
+	resp, err := onu.omciResponse(omciRequest(omcisim.Create, omciMacBridgePortClass, instance,
+		0x00, 0x01, byte(uni.ID+1), omciTpTypePptpUni, byte(pptp>>8), byte(pptp&0xFF)))
+	assert.NilError(t, err)
+	assert.Equal(t, resp[8], byte(omciResultSuccess))
+}
+
+func Test_Onu_OmciPm_counters(t *testing.T) {
+	onu := createTestOnu()
+	uni := onu.UniPorts[0]
+	createOmciBridgePort(t, onu, 0x0101, uni)
+
+	resp, err := onu.omciResponse(omciRequest(omcisim.Create, omciEthFrameUpPmClass, 0x0101, 0x00, 0x00))
+	assert.NilError(t, err)
+	assert.Equal(t, resp[8], byte(omciResultSuccess))
+	resp, _ = onu.omciResponse(omciRequest(omcisim.Create, omciEthFrameUpPmClass, 0x0101, 0x00, 0x00))
+	assert.Equal(t, resp[8], byte(omciResultInstanceExists))
+
+	pkt := make([]byte, 100)
+	pkt[0] = 0xFF
+	onu.countOmciPmFrame(true, uni, 1024, pkt, false)
+	onu.countOmciPmFrame(true, uni, 1024, make([]byte, 20), false)
+	// downstream frames are not counted upstream
+	onu.countOmciPmFrame(false, uni, 1024, pkt, false)
+
+	// interval end time, octets and packets
+	get := omciRequest(omcisim.Get, omciEthFrameUpPmClass, 0x0101, 0x98, 0x00)
+	current := omciRequest(omcisim.GetCurrentData, omciEthFrameUpPmClass, 0x0101, 0x98, 0x00)
+
+	resp, err = onu.omciResponse(current)
+	assert.NilError(t, err)
+	assert.Equal(t, resp[8], byte(omciResultSuccess))
+	assert.Equal(t, resp[9], byte(0x98))
+	assert.Equal(t, resp[11], byte(0))
+	assert.Equal(t, omciPmCounter(resp, 12), uint32(104+64))
+	assert.Equal(t, omciPmCounter(resp, 16), uint32(2))
+
+	// Get reports the last completed interval
+	resp, _ = onu.omciResponse(get)
+	assert.Equal(t, omciPmCounter(resp, 16), uint32(0))
+
+	onu.endOmciPmInterval()
+
+	resp, _ = onu.omciResponse(get)
+	assert.Equal(t, resp[11], byte(1))
+	assert.Equal(t, omciPmCounter(resp, 12), uint32(104+64))
+	assert.Equal(t, omciPmCounter(resp, 16), uint32(2))
+	resp, _ = onu.omciResponse(current)
+	assert.Equal(t, omciPmCounter(resp, 16), uint32(0))
+
+	// a MIB reset deletes the PM MEs
+	_, err = onu.omciResponse(omciRequest(omcisim.MibReset, 0x0002, 0))
+	assert.NilError(t, err)
+	resp, _ = onu.omciResponse(get)
+	assert.Equal(t, resp[8], byte(omciResultUnknownInstance))
+	assert.Equal(t, len(onu.omciPm.bridgePorts), 0)
+}
+
+func Test_Onu_OmciPm_counters_per_uni_and_gem_port(t *testing.T) {
+	onu := createTestOnu()
+	uni0 := onu.UniPorts[0]
+	uni1 := CreateUniPort(onu, 1)
+	onu.UniPorts = append(onu.UniPorts, uni1)
+	createOmciBridgePort(t, onu, 0x0101, uni0)
+	createOmciBridgePort(t, onu, 0x0102, uni1)
+
+	_, _ = onu.omciResponse(omciRequest(omcisim.Create, omciEthFrameUpPmClass, 0x0101, 0x00, 0x00))
+	_, _ = onu.omciResponse(omciRequest(omcisim.Create, omciEthFrameUpPmClass, 0x0102, 0x00, 0x00))
+	_, _ = onu.omciResponse(omciRequest(omcisim.Create, omciGemPortPmClass, 1024, 0x00, 0x00))
+	_, _ = onu.omciResponse(omciRequest(omcisim.Create, omciGemPortPmClass, 1025, 0x00, 0x00))
+
+	onu.countOmciPmFrame(true, uni0, 1024, make([]byte, 100), false)
+	onu.countOmciPmFrame(true, uni0, 1024, make([]byte, 100), false)
+	onu.countOmciPmFrame(true, uni1, 1025, make([]byte, 100), false)
+	// a frame whose UNI and GEM port are not known is not counted per UNI or GEM port
+	onu.countOmciPmFrame(true, nil, 0, make([]byte, 100), false)
+
+	ethPackets := func(instance uint16) uint32 {
+		resp, _ := onu.omciResponse(omciRequest(omcisim.GetCurrentData, omciEthFrameUpPmClass, instance, 0x08, 0x00))
+		return omciPmCounter(resp, 11)
+	}
+	gemFrames := func(instance uint16) uint32 {
+		resp, _ := onu.omciResponse(omciRequest(omcisim.GetCurrentData, omciGemPortPmClass, instance, 0x20, 0x00))
+		return omciPmCounter(resp, 11)
+	}
+	assert.Equal(t, ethPackets(0x0101), uint32(2))
+	assert.Equal(t, ethPackets(0x0102), uint32(1))
+	assert.Equal(t, gemFrames(1024), uint32(2))
+	assert.Equal(t, gemFrames(1025), uint32(1))
+
+	// once the bridge port is deleted its frames are not counted anymore
+	_, _ = onu.omciResponse(omciRequest(omcisim.Delete, omciMacBridgePortClass, 0x0102))
+	onu.countOmciPmFrame(true, uni1, 1025, make([]byte, 100), false)
+	assert.Equal(t, ethPackets(0x0102), uint32(1))
+	assert.Equal(t, gemFrames(1025), uint32(2))
+}
+
+func Test_Onu_OmciPm_get_too_many_attributes(t *testing.T) {
+	onu := createTestOnu()
+	_, _ = onu.omciResponse(omciRequest(omcisim.Create, omciEthFrameDownPmClass, 1, 0x00, 0x00))
+
+	// 7 counters do not fit in a baseline message
+	resp, err := onu.omciResponse(omciRequest(omcisim.Get, omciEthFrameDownPmClass, 1, 0x3F, 0x80))
+	assert.NilError(t, err)
+	assert.Equal(t, resp[8], byte(omciResultAttributeFailure))
+	assert.Equal(t, resp[9], byte(0x3F))
+	assert.Equal(t, resp[10], byte(0x00))
+	assert.Equal(t, resp[38], byte(0x00))
+	assert.Equal(t, resp[39], byte(0x80))
+}
+
+func Test_Onu_OmciPm_threshold_crossing_alert(t *testing.T) {
+	onu := createTestOnu()
+	onu.InternalState.SetState("enabled")
+	stream := &mockOmciStream{}
+
+	// the drop events threshold is set to 1 after the creation
+	thresholds := make([]byte, 28)
+	resp, err := onu.omciResponse(omciRequest(omcisim.Create, omciThresholdData1Class, 5, thresholds...))
+	assert.NilError(t, err)
+	assert.Equal(t, resp[8], byte(omciResultSuccess))
+	resp, _ = onu.omciResponse(omciRequest(omcisim.Set, omciThresholdData1Class, 5, 0x80, 0x00, 0x00, 0x00, 0x00, 0x01))
+	assert.Equal(t, resp[8], byte(omciResultSuccess))
+	resp, _ = onu.omciResponse(omciRequest(omcisim.Get, omciThresholdData1Class, 5, 0x80, 0x00))
+	assert.Equal(t, omciPmCounter(resp, 11), uint32(1))
+
+	uni := onu.UniPorts[0]
+	createOmciBridgePort(t, onu, 1, uni)
+	_, _ = onu.omciResponse(omciRequest(omcisim.Create, omciEthFrameDownPmClass, 1, 0x00, 0x05))
+
+	onu.countOmciPmFrame(false, uni, 0, make([]byte, 64), true)
+	assert.Equal(t, len(onu.Channel), 0)
+	onu.countOmciPmFrame(false, uni, 0, make([]byte, 64), true)
+	onu.countOmciPmFrame(false, uni, 0, make([]byte, 64), true)
+	sendQueuedOmciNotifications(t, onu, stream)
+
+	// the TCA is raised only once
+	assert.Equal(t, len(stream.Omci), 1)
+	notification := stream.Omci[0]
+	assert.Equal(t, notification[2], byte(omcisim.AlarmNotification))
+	assert.Equal(t, uint16(notification[4])<<8|uint16(notification[5]), uint16(omciEthFrameDownPmClass))
+	assert.Equal(t, notification[8], byte(0x80))
+	assert.Equal(t, notification[39], byte(1))
+
+	// and cleared at the end of the interval
+	onu.endOmciPmInterval()
+	sendQueuedOmciNotifications(t, onu, stream)
+	assert.Equal(t, len(stream.Omci), 2)
+	assert.Equal(t, stream.Omci[1][8], byte(0x00))
+	assert.Equal(t, stream.Omci[1][39], byte(2))
+
+	resp, _ = onu.omciResponse(omciRequest(omcisim.Get, omciEthFrameDownPmClass, 1, 0x20, 0x00))
+	assert.Equal(t, omciPmCounter(resp, 11), uint32(3))
+}
+
+func Test_Onu_OmciPm_stream(t *testing.T) {
+	onu := createTestOnu()
+	_, _ = onu.omciResponse(omciRequest(omcisim.Create, omciGemPortPmClass, 1024, 0x00, 0x00))
+
+	inner := &mockOmciStream{}
+	stream := newOmciPmStream(onu, newOmciPmStream(onu, inner))
+	assert.Equal(t, stream.Openolt_EnableIndicationServer, inner)
+
+	err := stream.Send(&openolt.Indication{Data: &openolt.Indication_PktInd{PktInd: &openolt.PacketIndication{
+		IntfType:  "pon",
+		GemportId: 1024,
+		Pkt:       make([]byte, 200),
+	}}})
+	assert.NilError(t, err)
+	assert.Equal(t, len(inner.Pkts), 1)
+
+	// transmitted GEM frames
+	resp, _ := onu.omciResponse(omciRequest(omcisim.GetCurrentData, omciGemPortPmClass, 1024, 0x20, 0x00))
+	assert.Equal(t, omciPmCounter(resp, 11), uint32(1))
+}
@@ -0,0 +1,238 @@
+/*
+ * Copyright 2018-present Open Networking Foundation
+
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+
+ * http://www.apache.org/licenses/LICENSE-2.0
+
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package devices
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/opencord/bbsim/internal/common"
+	log "github.com/sirupsen/logrus"
+)
+
+// AddOnu plugs a new ONU in a PON port of the OLT, the ONU gets the first free ONU ID on the PON.
+// The values that are not set in the OnuTopology are generated as for the ONUs created with the OLT.
+// If the OLT is enabled the ONU is discovered right away, otherwise when the OLT is enabled.
+func (o *OltDevice) AddOnu(ponId uint32, t common.OnuTopology) (*Onu, error) {
+	o.onusLock.Lock()
+	defer o.onusLock.Unlock()
+
+	pon, err := o.GetPonById(ponId)
+	if err != nil {
+		return nil, err
+	}
+
+	id, err := pon.getFreeOnuId()
+	if err != nil {
+		return nil, err
+	}
+
+	// NOTE the ONU only uses the OLT ID, the number of UNIs, the options and the discovery delay,
+	// pass new structs so that the locks of the running OLT are not copied
+	onu := CreateONU(
+		OltDevice{ID: o.ID, NumUniPerOnu: o.NumUniPerOnu, Options: o.Options},
+		PonPort{ID: pon.ID, Olt: OltDevice{ID: o.ID, Delay: o.Delay, NumUniPerOnu: o.NumUniPerOnu}},
+		id, o.sTag, o.nextCTag, o.auth, o.dhcp,
+	)
+	if err := onu.applyTopology(t); err != nil {
+		return nil, err
+	}
+
+	if _, err := o.FindOnuBySn(onu.Sn()); err == nil {
+		return nil, errors.New(fmt.Sprintf("onu-%s-already-exists", onu.Sn()))
+	}
+	if _, err := o.FindUniByMacAddress(onu.HwAddress); err == nil {
+		return nil, errors.New(fmt.Sprintf("mac-address-%s-already-in-use", onu.HwAddress))
+	}
+
+	pon.addOnu(onu)
+
+	if err := o.plugOnu(onu, pon); err != nil {
+		// the ONU is not started, disconnect it so that its ID and MAC address can be used again
+		pon.removeOnu(onu)
+		return nil, err
+	}
+
+	// each UNI gets its own C-Tag, unless the C-Tag is set
+	if t.CTag == 0 {
+		o.nextCTag = o.nextCTag + len(onu.UniPorts)
+	}
+
+	onuLogger.WithFields(log.Fields{
+		"IntfId": onu.PonPortID,
+		"OnuId":  onu.ID,
+		"OnuSn":  onu.Sn(),
+	}).Info("ONU plugged in")
+	return onu, nil
+}
+
+// RemoveOnu unplugs an ONU from the OLT, if VOLTHA knows about the ONU
+// a DyingGasp and a LOS alarm are sent before the ONU goes down
+func (o *OltDevice) RemoveOnu(serialNumber string) error {
+	o.onusLock.Lock()
+	defer o.onusLock.Unlock()
+
+	onu, err := o.FindOnuBySn(serialNumber)
+	if err != nil {
+		return err
+	}
+	pon, err := o.GetPonById(onu.PonPortID)
+	if err != nil {
+		return err
+	}
+
+	o.unplugOnu(onu)
+	pon.removeOnu(onu)
+
+	onuLogger.WithFields(log.Fields{
+		"IntfId": onu.PonPortID,
+		"OnuId":  onu.ID,
+		"OnuSn":  onu.Sn(),
+	}).Info("ONU removed")
+	return nil
+}
+
+// MoveOnu unplugs an ONU and plugs it in another PON port of the OLT,
+// the ONU keeps its serial number, MAC address and tags and gets the first free ONU ID on the new PON
+func (o *OltDevice) MoveOnu(serialNumber string, ponId uint32) (*Onu, error) {
+	o.onusLock.Lock()
+	defer o.onusLock.Unlock()
+
+	onu, err := o.FindOnuBySn(serialNumber)
+	if err != nil {
+		return nil, err
+	}
+	src, err := o.GetPonById(onu.PonPortID)
+	if err != nil {
+		return nil, err
+	}
+	dst, err := o.GetPonById(ponId)
+	if err != nil {
+		return nil, err
+	}
+	if src.ID == dst.ID {
+		return nil, errors.New(fmt.Sprintf("onu-%s-is-already-on-pon-%d", serialNumber, ponId))
+	}
+
+	id, err := dst.getFreeOnuId()
+	if err != nil {
+		return nil, err
+	}
+
+	o.unplugOnu(onu)
+	src.removeOnu(onu)
+
+	onuLogger.WithFields(log.Fields{
+		"IntfId":    onu.PonPortID,
+		"OnuId":     onu.ID,
+		"OnuSn":     onu.Sn(),
+		"NewIntfId": dst.ID,
+		"NewOnuId":  id,
+	}).Info("Moving ONU")
+
+	srcId := onu.ID
+	onu.ID = id
+	onu.PonPortID = dst.ID
+	onu.PonPort.ID = dst.ID
+	dst.addOnu(onu)
+
+	if err := o.plugOnu(onu, dst); err != nil {
+		// put the ONU back on the source PON, it is started again if possible
+		dst.removeOnu(onu)
+		onu.ID = srcId
+		onu.PonPortID = src.ID
+		onu.PonPort.ID = src.ID
+		src.addOnu(onu)
+		if err := o.plugOnu(onu, src); err != nil {
+			onuLogger.WithFields(log.Fields{
+				"IntfId": onu.PonPortID,
+				"OnuId":  onu.ID,
+				"OnuSn":  onu.Sn(),
+				"err":    err,
+			}).Error("Can't plug the ONU back in")
+		}
+		return nil, err
+	}
+	return onu, nil
+}
+
+// plugOnu starts an ONU that has been connected to a PON port,
+// as it happens for all the ONUs when the OLT is enabled
+func (o *OltDevice) plugOnu(onu *Onu, pon *PonPort) error {
+	if err := onu.InternalState.Event("initialize"); err != nil {
+		return err
+	}
+
+	// NOTE the ONUs in the initialized state are discovered when the OLT is enabled
+	if !o.InternalState.Is("enabled") || o.enableStream == nil {
+		return nil
+	}
+
+	if pon.OperState.Is("down") {
+		// the ONU is rediscovered when the PON is enabled
+		return onu.InternalState.Event("pon_disabled")
+	}
+
+	go onu.ProcessOnuMessages(o.enableContext, o.enableStream, nil)
+	return onu.InternalState.Event("discover")
+}
+
+// unplugOnu stops an ONU as if its fiber was disconnected
+// and removes the configuration VOLTHA installed on it
+func (o *OltDevice) unplugOnu(onu *Onu) {
+	// NOTE VOLTHA only knows about the ONU if it has been activated
+	if o.InternalState.Is("enabled") && onu.InternalState.Is("enabled") {
+		for _, alarmType := range []string{common.OnuAlarmDyingGasp, common.OnuAlarmLos} {
+			alarm, _ := createOnuAlarm(onu, alarmType, common.AlarmStatusOn)
+			o.channel <- Message{
+				Type: AlarmIndication,
+				Data: AlarmIndicationMessage{
+					AlarmType: alarmType,
+					Alarm:     alarm,
+				},
+			}
+		}
+	}
+
+	if onu.OperState.Is("up") {
+		if err := onu.OperState.Event("disable"); err != nil {
+			onuLogger.WithFields(log.Fields{
+				"IntfId": onu.PonPortID,
+				"OnuId":  onu.ID,
+				"OnuSn":  onu.Sn(),
+			}).Errorf("Failed to transition ONU.OperState to disabled state: %s", err.Error())
+		}
+	}
+
+	// NOTE the pon_disabled state sends the ONU DOWN indication and stops the ONU message processing,
+	// a rebooting ONU is already down and must not come back
+	if onu.InternalState.Is("rebooting") {
+		onu.InternalState.SetState("pon_disabled")
+	} else if onu.InternalState.Can("pon_disabled") {
+		if err := onu.InternalState.Event("pon_disabled"); err != nil {
+			onuLogger.WithFields(log.Fields{
+				"IntfId": onu.PonPortID,
+				"OnuId":  onu.ID,
+				"OnuSn":  onu.Sn(),
+			}).Errorf("Failed to transition ONU to pon_disabled state: %s", err.Error())
+		}
+	}
+
+	o.clearOnuFlows(onu)
+	o.clearOnuTrafficConfig(onu)
+	onu.resetConfig()
+}
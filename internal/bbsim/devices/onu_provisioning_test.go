@@ -0,0 +1,224 @@
+/*
+ * Copyright 2018-present Open Networking Foundation
+
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+
+ * http://www.apache.org/licenses/LICENSE-2.0
+
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package devices
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/opencord/bbsim/internal/common"
+	"github.com/opencord/voltha-protos/v2/go/openolt"
+	"gotest.tools/assert"
+)
+
+// createProvisioningOlt creates a mock OLT, the caller removes it from the OLTs with unregisterOlt
+func createProvisioningOlt(t *testing.T, id int) *OltDevice {
+	options := common.OltConfig{
+		ID:          id,
+		PonPorts:    2,
+		OnusPonPort: 2,
+		UnisPerOnu:  1,
+	}
+	olt, err := CreateOLT(options, 900, 900, false, false, 0, true)
+	assert.NilError(t, err)
+	return olt
+}
+
+// enableProvisioningOlt emulates an OLT that has been enabled by VOLTHA
+func enableProvisioningOlt(olt *OltDevice) *mockStream {
+	stream := &mockStream{
+		Calls:   make(map[int]*openolt.OnuDiscIndication),
+		channel: make(chan int, 10),
+	}
+	olt.channel = make(chan Message, 10)
+	olt.enableStream = stream
+	olt.enableContext, olt.enableContextCancel = context.WithCancel(context.TODO())
+	olt.InternalState.SetState("enabled")
+	for _, pon := range olt.Pons {
+		pon.OperState.SetState("up")
+	}
+	return stream
+}
+
+func Test_Olt_AddOnu(t *testing.T) {
+	olt := createProvisioningOlt(t, 20)
+	defer unregisterOlt(olt)
+
+	onu, err := olt.AddOnu(1, common.OnuTopology{})
+	assert.NilError(t, err)
+
+	// the ONU gets the first free ID, the S-Tag of the OLT and the next C-Tag
+	assert.Equal(t, onu.ID, uint32(3))
+	assert.Equal(t, onu.PonPortID, uint32(1))
+	assert.Equal(t, onu.Sn(), "BBSM00140103")
+	assert.Equal(t, onu.STag, 920)
+	assert.Equal(t, onu.CTag, 904)
+	assert.Equal(t, len(olt.Pons[1].Onus), 3)
+	assert.Equal(t, olt.Pons[1].NumOnu, 3)
+
+	// the OLT is not enabled, the ONU is discovered when it is
+	assert.Equal(t, onu.InternalState.Current(), "initialized")
+
+	onu, err = olt.AddOnu(1, common.OnuTopology{SerialNumber: "ABCD00000001", CTag: 100})
+	assert.NilError(t, err)
+	assert.Equal(t, onu.ID, uint32(4))
+	assert.Equal(t, onu.CTag, 100)
+
+	// the C-Tag set for the previous ONU is not taken from the free ones
+	onu, err = olt.AddOnu(0, common.OnuTopology{})
+	assert.NilError(t, err)
+	assert.Equal(t, onu.CTag, 905)
+}
+
+func Test_Olt_AddOnu_Errors(t *testing.T) {
+	olt := createProvisioningOlt(t, 21)
+	defer unregisterOlt(olt)
+
+	_, err := olt.AddOnu(2, common.OnuTopology{})
+	assert.Error(t, err, "Cannot find PonPort with id 2 in OLT 21")
+
+	_, err = olt.AddOnu(0, common.OnuTopology{SerialNumber: "BBSM00150001"})
+	assert.Error(t, err, "onu-BBSM00150001-already-exists")
+
+	_, err = olt.AddOnu(0, common.OnuTopology{MacAddress: olt.Pons[0].Onus[0].HwAddress.String()})
+	assert.Error(t, err, "mac-address-2e:60:85:13:00:01-already-in-use")
+
+	assert.Equal(t, len(olt.Pons[0].Onus), 2)
+}
+
+func Test_Olt_AddOnu_Discovered(t *testing.T) {
+	olt := createProvisioningOlt(t, 22)
+	defer unregisterOlt(olt)
+	stream := enableProvisioningOlt(olt)
+	defer olt.enableContextCancel()
+
+	onu, err := olt.AddOnu(0, common.OnuTopology{})
+	assert.NilError(t, err)
+	assert.Equal(t, onu.InternalState.Current(), "discovered")
+
+	select {
+	case <-stream.channel:
+		assert.Equal(t, stream.Calls[1].SerialNumber, onu.SerialNumber)
+		assert.Equal(t, stream.Calls[1].IntfId, uint32(0))
+	case <-time.After(1 * time.Second):
+		t.Fatal("the ONU has not been discovered")
+	}
+}
+
+func Test_Olt_RemoveOnu(t *testing.T) {
+	olt := createProvisioningOlt(t, 23)
+	defer unregisterOlt(olt)
+	enableProvisioningOlt(olt)
+	defer olt.enableContextCancel()
+
+	onu := olt.Pons[0].Onus[0]
+	onu.InternalState.SetState("enabled")
+	onu.OperState.SetState("up")
+	onu.Channel = make(chan Message, 10)
+
+	err := olt.RemoveOnu(onu.Sn())
+	assert.NilError(t, err)
+
+	assert.Equal(t, len(olt.Pons[0].Onus), 1)
+	assert.Equal(t, olt.Pons[0].NumOnu, 1)
+	assert.Equal(t, onu.OperState.Current(), "down")
+	assert.Equal(t, onu.InternalState.Current(), "pon_disabled")
+
+	// VOLTHA is notified with a Dying Gasp and a LOS
+	msg := <-olt.channel
+	assert.Equal(t, msg.Data.(AlarmIndicationMessage).AlarmType, common.OnuAlarmDyingGasp)
+	msg = <-olt.channel
+	assert.Equal(t, msg.Data.(AlarmIndicationMessage).AlarmType, common.OnuAlarmLos)
+	los := msg.Data.(AlarmIndicationMessage).Alarm.GetOnuAlarmInd()
+	assert.Equal(t, los.OnuId, onu.ID)
+	assert.Equal(t, los.LosStatus, "on")
+
+	_, err = olt.FindOnuBySn(onu.Sn())
+	assert.Error(t, err, "cannot-find-onu-by-serial-number-BBSM00170001")
+
+	err = olt.RemoveOnu(onu.Sn())
+	assert.Error(t, err, "cannot-find-onu-by-serial-number-BBSM00170001")
+}
+
+func Test_Olt_MoveOnu(t *testing.T) {
+	olt := createProvisioningOlt(t, 24)
+	defer unregisterOlt(olt)
+	stream := enableProvisioningOlt(olt)
+	defer olt.enableContextCancel()
+
+	onu := olt.Pons[0].Onus[0]
+	sn := onu.Sn()
+	mac := onu.HwAddress.String()
+
+	// the ONU ID 1 is free on PON 1
+	olt.Pons[1].removeOnu(olt.Pons[1].Onus[0])
+
+	moved, err := olt.MoveOnu(sn, 1)
+	assert.NilError(t, err)
+	assert.Equal(t, moved, onu)
+
+	assert.Equal(t, len(olt.Pons[0].Onus), 1)
+	assert.Equal(t, len(olt.Pons[1].Onus), 2)
+	assert.Equal(t, onu.PonPortID, uint32(1))
+	assert.Equal(t, onu.ID, uint32(1))
+	assert.Equal(t, onu.Sn(), sn)
+	assert.Equal(t, onu.HwAddress.String(), mac)
+
+	found, err := olt.FindOnuById(1, 1)
+	assert.NilError(t, err)
+	assert.Equal(t, found, onu)
+
+	// the ONU is rediscovered on the new PON
+	assert.Equal(t, onu.InternalState.Current(), "discovered")
+	select {
+	case <-stream.channel:
+		assert.Equal(t, stream.Calls[1].SerialNumber, onu.SerialNumber)
+		assert.Equal(t, stream.Calls[1].IntfId, uint32(1))
+	case <-time.After(1 * time.Second):
+		t.Fatal("the ONU has not been rediscovered")
+	}
+
+	_, err = olt.MoveOnu(sn, 1)
+	assert.Error(t, err, "onu-"+sn+"-is-already-on-pon-1")
+}
+
+func Test_Olt_MoveOnu_Rollback(t *testing.T) {
+	olt := createProvisioningOlt(t, 25)
+	defer unregisterOlt(olt)
+	enableProvisioningOlt(olt)
+	defer olt.enableContextCancel()
+
+	onu := olt.Pons[0].Onus[0]
+	olt.Pons[1].removeOnu(olt.Pons[1].Onus[0])
+
+	// an ONU that is being activated can't be plugged in again
+	onu.InternalState.SetState("eapol_flow_sent")
+
+	_, err := olt.MoveOnu(onu.Sn(), 1)
+	assert.ErrorContains(t, err, "event initialize inappropriate in current state eapol_flow_sent")
+
+	// the ONU is back on the source PON with its own ID
+	assert.Equal(t, len(olt.Pons[0].Onus), 2)
+	assert.Equal(t, len(olt.Pons[1].Onus), 1)
+	assert.Equal(t, onu.PonPortID, uint32(0))
+	assert.Equal(t, onu.ID, uint32(1))
+
+	found, err := olt.FindOnuBySn(onu.Sn())
+	assert.NilError(t, err)
+	assert.Equal(t, found, onu)
+}
@@ -17,8 +17,11 @@
 package devices
 
 import (
+	"context"
 	"testing"
+	"time"
 
+	"github.com/opencord/voltha-protos/v2/go/openolt"
 	"gotest.tools/assert"
 )
 
@@ -31,113 +34,129 @@ func Test_Onu_StateMachine_enable(t *testing.T) {
 	assert.Equal(t, onu.InternalState.Current(), "enabled")
 }
 
-func Test_Onu_StateMachine_eapol_start_eap_flow(t *testing.T) {
+func Test_Onu_StateMachine_enable_unis(t *testing.T) {
 	onu := createTestOnu()
+	onu.UniPorts = append(onu.UniPorts, CreateUniPort(onu, 1))
 
-	onu.InternalState.SetState("enabled")
+	onu.InternalState.SetState("discovered")
+	onu.InternalState.Event("enable")
+	for _, uni := range onu.UniPorts {
+		assert.Equal(t, uni.InternalState.Current(), "enabled")
+	}
 
-	// TODO we need to add a check so that you can't go from eapol_flow_received
-	// to auth_started without passing through gem_port_added
-	// (see start_dhcp for an example)
+	onu.UniPorts[1].InternalState.SetState("dhcp_ack_received")
 
-	assert.Equal(t, onu.InternalState.Current(), "enabled")
-	onu.InternalState.Event("receive_eapol_flow")
-	assert.Equal(t, onu.InternalState.Current(), "eapol_flow_received")
-	onu.InternalState.Event("add_gem_port")
-	assert.Equal(t, onu.InternalState.Current(), "gem_port_added")
-	onu.InternalState.Event("start_auth")
-	assert.Equal(t, onu.InternalState.Current(), "auth_started")
+	onu.InternalState.Event("disable")
+	assert.Equal(t, onu.InternalState.Current(), "disabled")
+	for _, uni := range onu.UniPorts {
+		assert.Equal(t, uni.InternalState.Current(), "disabled")
+	}
 }
 
-func Test_Onu_StateMachine_eapol_start_gem_port(t *testing.T) {
+func Test_Onu_StateMachine_reboot(t *testing.T) {
 	onu := createTestOnu()
-
+	onu.RebootDelay = 1 * time.Hour
+	onu.HasGemPort = true
 	onu.InternalState.SetState("enabled")
-
-	// TODO we need to add a check so that you can't go from gem_port_added
-	// to auth_started without passing through eapol_flow_received
-	// (see start_dhcp for an example)
-
-	assert.Equal(t, onu.InternalState.Current(), "enabled")
-	onu.InternalState.Event("add_gem_port")
-	assert.Equal(t, onu.InternalState.Current(), "gem_port_added")
-	onu.InternalState.Event("receive_eapol_flow")
-	assert.Equal(t, onu.InternalState.Current(), "eapol_flow_received")
-	onu.InternalState.Event("start_auth")
-	assert.Equal(t, onu.InternalState.Current(), "auth_started")
+	onu.OperState.SetState("up")
+
+	ctx, cancel := context.WithCancel(context.TODO())
+	defer cancel()
+
+	err := onu.Reboot(ctx, nil, nil)
+	assert.NilError(t, err)
+	assert.Equal(t, onu.InternalState.Current(), "rebooting")
+	assert.Equal(t, onu.OperState.Current(), "down")
+	assert.Equal(t, onu.HasGemPort, false)
+	assert.Equal(t, onu.IsOffline(), true)
+
+	// VOLTHA is told the ONU went down, then the ONU stops processing messages
+	msg := <-onu.Channel
+	assert.Equal(t, msg.Type, OnuIndication)
+	assert.Equal(t, msg.Data.(OnuIndicationMessage).OperState, DOWN)
+	_, ok := <-onu.Channel
+	assert.Equal(t, ok, false)
 }
 
-func Test_Onu_StateMachine_eapol_states(t *testing.T) {
+func Test_Onu_StateMachine_reboot_rediscovery(t *testing.T) {
 	onu := createTestOnu()
+	onu.RebootDelay = 10 * time.Millisecond
+	onu.InternalState.SetState("discovered")
 
-	onu.InternalState.SetState("auth_started")
-
-	assert.Equal(t, onu.InternalState.Current(), "auth_started")
-	onu.InternalState.Event("eap_start_sent")
-	assert.Equal(t, onu.InternalState.Current(), "eap_start_sent")
-	onu.InternalState.Event("eap_response_identity_sent")
-	assert.Equal(t, onu.InternalState.Current(), "eap_response_identity_sent")
-	onu.InternalState.Event("eap_response_challenge_sent")
-	assert.Equal(t, onu.InternalState.Current(), "eap_response_challenge_sent")
-	onu.InternalState.Event("eap_response_success_received")
-	assert.Equal(t, onu.InternalState.Current(), "eap_response_success_received")
-
-	// test that we can retrigger EAPOL
-	states := []string{"eap_start_sent", "eap_response_identity_sent", "eap_response_challenge_sent", "eap_response_success_received", "auth_failed", "dhcp_ack_received", "dhcp_failed"}
-	for _, state := range states {
-		onu.InternalState.SetState(state)
-		err := onu.InternalState.Event("start_auth")
-		assert.Equal(t, err, nil)
-		assert.Equal(t, onu.InternalState.Current(), "auth_started")
+	stream := &mockStream{
+		Calls:   make(map[int]*openolt.OnuDiscIndication),
+		channel: make(chan int, 10),
 	}
-}
+	ctx, cancel := context.WithCancel(context.TODO())
+	defer cancel()
 
-func Test_Onu_StateMachine_dhcp_start(t *testing.T) {
-	onu := createTestOnu()
-	onu.DhcpFlowReceived = true
+	err := onu.Reboot(ctx, stream, nil)
+	assert.NilError(t, err)
 
-	onu.InternalState.SetState("eap_response_success_received")
-	assert.Equal(t, onu.InternalState.Current(), "eap_response_success_received")
+	select {
+	case <-stream.channel:
+		assert.Equal(t, stream.Calls[1].SerialNumber, onu.SerialNumber)
+	case <-time.After(1 * time.Second):
+		t.Fatal("the ONU has not been discovered after the reboot")
+	}
+	assert.Equal(t, onu.InternalState.Current(), "discovered")
+}
 
-	onu.InternalState.Event("start_dhcp")
+func Test_Onu_StateMachine_reboot_not_discovered(t *testing.T) {
+	onu := createTestOnu()
 
-	assert.Equal(t, onu.InternalState.Current(), "dhcp_started")
+	err := onu.Reboot(context.TODO(), nil, nil)
+	assert.Error(t, err, "cannot-reboot-onu-BBSM00000101-in-state-initialized")
 }
 
-func Test_Onu_StateMachine_dhcp_start_error(t *testing.T) {
+func Test_Onu_SendMessage_offline(t *testing.T) {
 	onu := createTestOnu()
+	onu.InternalState.SetState("discovered")
 
-	onu.InternalState.SetState("eap_response_success_received")
-	assert.Equal(t, onu.InternalState.Current(), "eap_response_success_received")
+	assert.NilError(t, onu.SendMessage(Message{Type: OMCI}))
+	msg := <-onu.Channel
+	assert.Equal(t, msg.Type, OMCI)
 
-	err := onu.InternalState.Event("start_dhcp")
+	// the messages sent once the ONU went down are dropped
+	onu.InternalState.Event("pon_disabled")
+	assert.Error(t, onu.SendMessage(Message{Type: OMCI}), "onu-BBSM00000101-is-offline")
 
-	assert.Equal(t, onu.InternalState.Current(), "eap_response_success_received")
-	assert.Equal(t, err.Error(), "transition canceled with error: cannot-go-to-dhcp-started-as-dhcp-flow-is-missing")
+	// the ONU gets a new channel when it comes back
+	onu.InternalState.Event("initialize")
+	assert.NilError(t, onu.SendMessage(Message{Type: OMCI}))
+	msg = <-onu.Channel
+	assert.Equal(t, msg.Type, OMCI)
 }
 
-func Test_Onu_StateMachine_dhcp_states(t *testing.T) {
+func Test_Onu_SendMessage_concurrent_reboot(t *testing.T) {
 	onu := createTestOnu()
-
-	onu.DhcpFlowReceived = false
-
-	onu.InternalState.SetState("dhcp_started")
-
-	assert.Equal(t, onu.InternalState.Current(), "dhcp_started")
-	onu.InternalState.Event("dhcp_discovery_sent")
-	assert.Equal(t, onu.InternalState.Current(), "dhcp_discovery_sent")
-	onu.InternalState.Event("dhcp_request_sent")
-	assert.Equal(t, onu.InternalState.Current(), "dhcp_request_sent")
-	onu.InternalState.Event("dhcp_ack_received")
-	assert.Equal(t, onu.InternalState.Current(), "dhcp_ack_received")
-
-	// test that we can retrigger DHCP
-	onu.DhcpFlowReceived = true
-	states := []string{"eap_response_success_received", "dhcp_discovery_sent", "dhcp_request_sent", "dhcp_ack_received", "dhcp_failed"}
-	for _, state := range states {
-		onu.InternalState.SetState(state)
-		err := onu.InternalState.Event("start_dhcp")
-		assert.Equal(t, err, nil)
-		assert.Equal(t, onu.InternalState.Current(), "dhcp_started")
+	onu.RebootDelay = 1 * time.Hour
+	ctx, cancel := context.WithCancel(context.TODO())
+	defer cancel()
+
+	// the messages sent while the ONU reboots never hit the closed channel
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 1000; i++ {
+			_ = onu.SendMessage(Message{Type: OMCI})
+		}
+	}()
+	for i := 0; i < 100; i++ {
+		onu.InternalState.SetState("discovered")
+		assert.NilError(t, onu.Reboot(ctx, nil, nil))
+		onu.InternalState.Event("initialize")
+		for len(onu.getChannel()) > 0 {
+			<-onu.getChannel()
+		}
 	}
+	<-done
+}
+
+func Test_Onu_isOmciReboot(t *testing.T) {
+	reboot := OmciMessage{omciMsg: &openolt.OmciMsg{Pkt: []byte("0001590a01000000")}}
+	assert.Equal(t, isOmciReboot(reboot), true)
+
+	mibReset := OmciMessage{omciMsg: &openolt.OmciMsg{Pkt: []byte("00014f0a00020000")}}
+	assert.Equal(t, isOmciReboot(mibReset), false)
 }
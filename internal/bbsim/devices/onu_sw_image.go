@@ -0,0 +1,439 @@
+/*
+ * Copyright 2018-present Open Networking Foundation
+
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+
+ * http://www.apache.org/licenses/LICENSE-2.0
+
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package devices
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	omcisim "github.com/opencord/omci-sim"
+	log "github.com/sirupsen/logrus"
+)
+
+// the ONU has two instances of the Software Image ME, the image in instance 0 is active and committed at startup
+const (
+	omciSoftwareImageClass = 0x0007
+	numSoftwareImages      = 2
+	swImageVersionLen      = 14
+	swImageSectionLen      = 31 // the image bytes carried by a Download Section message
+	defaultSwImageVersion  = "BBSM_IMG_00001"
+)
+
+// the attributes of the Software Image ME BBSim supports in the Get requests
+const (
+	swImageVersionAttr   = 0x8000
+	swImageCommittedAttr = 0x4000
+	swImageActiveAttr    = 0x2000
+	swImageValidAttr     = 0x1000
+	swImageSupportedAttr = swImageVersionAttr | swImageCommittedAttr | swImageActiveAttr | swImageValidAttr
+)
+
+// the OMCI result codes used in the responses to the software download messages
+const (
+	omciResultSuccess          = 0
+	omciResultProcessingError  = 1
+	omciResultParameterError   = 3
+	omciResultUnknownInstance  = 5
+	omciResultAttributeFailure = 9
+)
+
+// SwImageFault is a failure the ONU simulates during the software upgrade
+type SwImageFault string
+
+const (
+	SwImageFaultNone     SwImageFault = "none"
+	SwImageFaultCrc      SwImageFault = "crc-mismatch" // End Software Download fails as if the CRC of the image didn't match
+	SwImageFaultActivate SwImageFault = "activate"     // the activated image doesn't boot, the ONU comes back with the previous one
+)
+
+// ParseSwImageFault returns the SwImageFault with the given name, an empty name means SwImageFaultNone
+func ParseSwImageFault(name string) (SwImageFault, error) {
+	switch SwImageFault(name) {
+	case "", SwImageFaultNone:
+		return SwImageFaultNone, nil
+	case SwImageFaultCrc, SwImageFaultActivate:
+		return SwImageFault(name), nil
+	}
+	return SwImageFaultNone, errors.New(fmt.Sprintf("invalid-sw-image-fault-%s", name))
+}
+
+// SoftwareImage is an instance of the Software Image ME of an ONU
+type SoftwareImage struct {
+	Instance    uint8
+	Version     string
+	IsCommitted bool
+	IsActive    bool
+	IsValid     bool
+}
+
+// swImageDownload is a download in progress
+type swImageDownload struct {
+	instance   uint8
+	size       uint32
+	windowSize int
+	image      []byte           // the sections of the windows that have been acknowledged
+	window     map[uint8][]byte // the sections of the current window, by section number
+}
+
+// softwareImages holds the Software Image MEs of an ONU, they survive the ONU reboots
+type softwareImages struct {
+	lock       sync.Mutex
+	images     [numSoftwareImages]SoftwareImage
+	download   *swImageDownload
+	activating int // the instance that becomes active with the next reboot, -1 if none
+	fault      SwImageFault
+}
+
+func newSoftwareImages() *softwareImages {
+	s := &softwareImages{
+		activating: -1,
+		fault:      SwImageFaultNone,
+	}
+	for i := range s.images {
+		s.images[i].Instance = uint8(i)
+	}
+	s.images[0].Version = defaultSwImageVersion
+	s.images[0].IsCommitted = true
+	s.images[0].IsActive = true
+	s.images[0].IsValid = true
+	return s
+}
+
+// crc32Aal5 computes the CRC-32 of ITU-T I.363.5 (AAL5) that OMCI uses to verify the software images
+func crc32Aal5(data []byte) uint32 {
+	crc := uint32(0xFFFFFFFF)
+	for _, b := range data {
+		crc ^= uint32(b) << 24
+		for i := 0; i < 8; i++ {
+			if crc&0x80000000 != 0 {
+				crc = crc<<1 ^ 0x04C11DB7
+			} else {
+				crc <<= 1
+			}
+		}
+	}
+	return ^crc
+}
+
+// swImageVersion returns the version an ONU reports for a downloaded image:
+// the printable string the image starts with (eg: a version header), up to 14 characters,
+// or the CRC of the image if it doesn't start with one
+func swImageVersion(image []byte, crc uint32) string {
+	n := 0
+	for n < len(image) && n < swImageVersionLen && image[n] >= 0x21 && image[n] <= 0x7E {
+		n++
+	}
+	if n == 0 {
+		return fmt.Sprintf("%08X", crc)
+	}
+	return string(image[:n])
+}
+
+// swImageOmciResponse handles the software upgrade messages and the Get requests on the Software Image ME.
+// A nil response means that the request is not acknowledged (the Download Sections within a window)
+func (o *Onu) swImageOmciResponse(request []byte) ([]byte, error) {
+	if len(request) < 40 {
+		return nil, errors.New(fmt.Sprintf("invalid-omci-sw-image-request-length-%d", len(request)))
+	}
+
+	s := o.swImages
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	class := uint16(request[4])<<8 | uint16(request[5])
+	instance := uint16(request[6])<<8 | uint16(request[7])
+	contents := request[8:40]
+
+	if class != omciSoftwareImageClass {
+		return newOmciResponse(request, omciResultParameterError), nil
+	}
+	if instance >= numSoftwareImages {
+		return newOmciResponse(request, omciResultUnknownInstance), nil
+	}
+	image := &s.images[instance]
+
+	logger := onuLogger.WithFields(log.Fields{
+		"IntfId":     o.PonPortID,
+		"OnuId":      o.ID,
+		"OnuSn":      o.Sn(),
+		"MeInstance": instance,
+	})
+
+	switch omcisim.OmciMsgType(request[2] & 0x1F) {
+	case omcisim.StartSoftwareDownload:
+		windowSize := int(contents[0]) + 1
+		size := uint32(contents[1])<<24 | uint32(contents[2])<<16 | uint32(contents[3])<<8 | uint32(contents[4])
+
+		result := byte(omciResultSuccess)
+		if image.IsActive || size == 0 {
+			result = omciResultParameterError
+		} else {
+			// NOTE the image being overwritten is not valid anymore
+			s.images[instance] = SoftwareImage{Instance: uint8(instance)}
+			s.download = &swImageDownload{
+				instance:   uint8(instance),
+				size:       size,
+				windowSize: windowSize,
+				image:      []byte{},
+				window:     make(map[uint8][]byte),
+			}
+			logger.WithFields(log.Fields{
+				"ImageSize":  size,
+				"WindowSize": windowSize,
+			}).Info("Software download started")
+		}
+		return newOmciResponse(request, result, byte(windowSize-1), 1, request[6], request[7], result), nil
+
+	case omcisim.DownloadSection:
+		section := contents[0]
+		ackRequested := request[2]&0x40 != 0
+
+		d := s.download
+		if d == nil || d.instance != uint8(instance) {
+			if !ackRequested {
+				return nil, nil
+			}
+			return newOmciResponse(request, omciResultParameterError, section), nil
+		}
+
+		data := make([]byte, swImageSectionLen)
+		copy(data, contents[1:])
+		d.window[section] = data
+		if !ackRequested {
+			return nil, nil
+		}
+
+		// the window is complete if all the sections up to the last one have been received,
+		// otherwise it is discarded and VOLTHA sends it again
+		complete := int(section) < d.windowSize && len(d.window) == int(section)+1
+		for i := 0; complete && i <= int(section); i++ {
+			_, complete = d.window[uint8(i)]
+		}
+		result := byte(omciResultSuccess)
+		if complete {
+			for i := 0; i <= int(section); i++ {
+				d.image = append(d.image, d.window[uint8(i)]...)
+			}
+		} else {
+			logger.WithFields(log.Fields{
+				"Section":  section,
+				"Received": len(d.window),
+			}).Warn("Incomplete software download window")
+			result = omciResultProcessingError
+		}
+		d.window = make(map[uint8][]byte)
+		return newOmciResponse(request, result, section), nil
+
+	case omcisim.EndSoftwareDownload:
+		crc := uint32(contents[0])<<24 | uint32(contents[1])<<16 | uint32(contents[2])<<8 | uint32(contents[3])
+		size := uint32(contents[4])<<24 | uint32(contents[5])<<16 | uint32(contents[6])<<8 | uint32(contents[7])
+
+		d := s.download
+		s.download = nil
+
+		result := byte(omciResultSuccess)
+		switch {
+		case d == nil || d.instance != uint8(instance):
+			result = omciResultParameterError
+		case size != d.size || uint32(len(d.image)) < size:
+			logger.WithFields(log.Fields{
+				"ImageSize":  size,
+				"Downloaded": len(d.image),
+			}).Warn("Software download size mismatch")
+			result = omciResultProcessingError
+		case crc32Aal5(d.image[:size]) != crc || s.fault == SwImageFaultCrc:
+			logger.WithFields(log.Fields{
+				"Crc":         fmt.Sprintf("%08X", crc),
+				"ComputedCrc": fmt.Sprintf("%08X", crc32Aal5(d.image[:size])),
+				"Fault":       s.fault,
+			}).Warn("Software download CRC mismatch")
+			result = omciResultProcessingError
+		default:
+			image.Version = swImageVersion(d.image[:size], crc)
+			image.IsValid = true
+			logger.WithFields(log.Fields{
+				"Version": image.Version,
+			}).Info("Software download completed")
+		}
+		return newOmciResponse(request, result, 1, request[6], request[7], result), nil
+
+	case omcisim.ActivateSoftware:
+		result := byte(omciResultSuccess)
+		if !image.IsValid || s.download != nil {
+			result = omciResultParameterError
+		} else {
+			// NOTE the image becomes active when the ONU reboots, right after the response is sent
+			s.activating = int(instance)
+			logger.WithFields(log.Fields{
+				"Version": image.Version,
+			}).Info("Software image activated")
+		}
+		return newOmciResponse(request, result), nil
+
+	case omcisim.CommitSoftware:
+		result := byte(omciResultSuccess)
+		if !image.IsValid {
+			result = omciResultParameterError
+		} else {
+			for i := range s.images {
+				s.images[i].IsCommitted = i == int(instance)
+			}
+			logger.WithFields(log.Fields{
+				"Version": image.Version,
+			}).Info("Software image committed")
+		}
+		return newOmciResponse(request, result), nil
+
+	case omcisim.Get:
+		mask := uint16(contents[0])<<8 | uint16(contents[1])
+		supported := mask & swImageSupportedAttr
+
+		resp := newOmciResponse(request, omciResultSuccess, byte(supported>>8), byte(supported&0xFF))
+		pos := 11
+		if supported&swImageVersionAttr != 0 {
+			copy(resp[pos:pos+swImageVersionLen], image.Version)
+			pos += swImageVersionLen
+		}
+		for _, attr := range []struct {
+			mask  uint16
+			value bool
+		}{
+			{swImageCommittedAttr, image.IsCommitted},
+			{swImageActiveAttr, image.IsActive},
+			{swImageValidAttr, image.IsValid},
+		} {
+			if supported&attr.mask != 0 {
+				if attr.value {
+					resp[pos] = 1
+				}
+				pos++
+			}
+		}
+		// NOTE the product code and the image hash are reported as unsupported
+		if unsupported := mask &^ swImageSupportedAttr; unsupported != 0 {
+			resp[8] = omciResultAttributeFailure
+			resp[36] = byte(unsupported >> 8)
+			resp[37] = byte(unsupported & 0xFF)
+		}
+		return resp, nil
+	}
+	return newOmciResponse(request, omciResultParameterError), nil
+}
+
+// activationPending returns true if an image has been activated and the ONU has to reboot
+func (s *softwareImages) activationPending() bool {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.activating >= 0
+}
+
+// cancelActivation forgets the activated image if the ONU can't reboot, the active image doesn't change
+func (o *Onu) cancelActivation() {
+	s := o.swImages
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.activating < 0 {
+		return
+	}
+	onuLogger.WithFields(log.Fields{
+		"IntfId":     o.PonPortID,
+		"OnuId":      o.ID,
+		"OnuSn":      o.Sn(),
+		"MeInstance": s.activating,
+	}).Warn("Software image activation canceled")
+	s.activating = -1
+}
+
+// bootSoftwareImage is called when the ONU reboots: the downloads in progress are aborted
+// and the activated image (if any) becomes the active one
+func (o *Onu) bootSoftwareImage() {
+	s := o.swImages
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.download = nil
+	if s.activating < 0 {
+		return
+	}
+	activating := s.activating
+	s.activating = -1
+
+	logger := onuLogger.WithFields(log.Fields{
+		"IntfId":     o.PonPortID,
+		"OnuId":      o.ID,
+		"OnuSn":      o.Sn(),
+		"MeInstance": activating,
+		"Version":    s.images[activating].Version,
+	})
+	if s.fault == SwImageFaultActivate {
+		logger.Warn("Software image failed to boot, falling back to the previous one")
+		return
+	}
+	for i := range s.images {
+		s.images[i].IsActive = i == activating
+	}
+	logger.Info("Booting the activated software image")
+}
+
+// GetSoftwareImages returns the Software Image MEs of the ONU
+func (o *Onu) GetSoftwareImages() []SoftwareImage {
+	o.swImages.lock.Lock()
+	defer o.swImages.lock.Unlock()
+
+	res := make([]SoftwareImage, numSoftwareImages)
+	copy(res, o.swImages.images[:])
+	return res
+}
+
+// GetSoftwareDownload returns the bytes received and the size of the image being downloaded, 0 and 0 if none is
+func (o *Onu) GetSoftwareDownload() (uint32, uint32) {
+	o.swImages.lock.Lock()
+	defer o.swImages.lock.Unlock()
+
+	d := o.swImages.download
+	if d == nil {
+		return 0, 0
+	}
+	received := uint32(len(d.image))
+	if received > d.size {
+		received = d.size
+	}
+	return received, d.size
+}
+
+// GetSoftwareImageFault returns the failure the ONU simulates during the software upgrade
+func (o *Onu) GetSoftwareImageFault() SwImageFault {
+	o.swImages.lock.Lock()
+	defer o.swImages.lock.Unlock()
+	return o.swImages.fault
+}
+
+// SetSoftwareImageFault sets the failure the ONU simulates during the software upgrade, SwImageFaultNone to remove it
+func (o *Onu) SetSoftwareImageFault(fault SwImageFault) {
+	o.swImages.lock.Lock()
+	defer o.swImages.lock.Unlock()
+	o.swImages.fault = fault
+
+	onuLogger.WithFields(log.Fields{
+		"IntfId": o.PonPortID,
+		"OnuId":  o.ID,
+		"OnuSn":  o.Sn(),
+		"Fault":  fault,
+	}).Info("Software image fault updated")
+}
@@ -0,0 +1,263 @@
+/*
+ * Copyright 2018-present Open Networking Foundation
+
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+
+ * http://www.apache.org/licenses/LICENSE-2.0
+
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package devices
+
+import (
+	"context"
+	"encoding/hex"
+	"testing"
+	"time"
+
+	omcisim "github.com/opencord/omci-sim"
+	"github.com/opencord/voltha-protos/v2/go/openolt"
+	"gotest.tools/assert"
+)
+
+// swImageRequest builds an OMCI request for the Software Image ME (in the baseline message format)
+func swImageRequest(msgType omcisim.OmciMsgType, ackRequested bool, instance uint16, contents ...byte) []byte {
+	pkt := make([]byte, 48)
+	pkt[1] = 0x01
+	pkt[2] = byte(msgType)
+	if ackRequested {
+		pkt[2] |= 0x40
+	}
+	pkt[3] = 0x0a
+	pkt[5] = omciSoftwareImageClass
+	pkt[6] = byte(instance >> 8)
+	pkt[7] = byte(instance & 0xFF)
+	copy(pkt[8:], contents)
+	return pkt
+}
+
+func be32(v uint32) []byte {
+	return []byte{byte(v >> 24), byte(v >> 16), byte(v >> 8), byte(v)}
+}
+
+// downloadSwImage downloads an image on an instance of the Software Image ME in windows of 2 sections
+// and returns the result of the End Software Download
+func downloadSwImage(t *testing.T, onu *Onu, instance uint16, image []byte) byte {
+	start := append([]byte{1}, be32(uint32(len(image)))...)
+	start = append(start, 1, 0, byte(instance))
+	resp, err := onu.omciResponse(swImageRequest(omcisim.StartSoftwareDownload, true, instance, start...))
+	assert.NilError(t, err)
+	assert.Equal(t, resp[8], byte(omciResultSuccess))
+	assert.Equal(t, resp[9], byte(1))
+
+	sections := (len(image) + swImageSectionLen - 1) / swImageSectionLen
+	for i := 0; i < sections; i++ {
+		end := (i + 1) * swImageSectionLen
+		if end > len(image) {
+			end = len(image)
+		}
+		section := byte(i % 2)
+		last := section == 1 || i == sections-1
+		contents := append([]byte{section}, image[i*swImageSectionLen:end]...)
+		resp, err := onu.omciResponse(swImageRequest(omcisim.DownloadSection, last, instance, contents...))
+		assert.NilError(t, err)
+		if !last {
+			assert.Assert(t, resp == nil)
+			continue
+		}
+		assert.Equal(t, resp[8], byte(omciResultSuccess))
+		assert.Equal(t, resp[9], section)
+	}
+
+	contents := append(be32(crc32Aal5(image)), be32(uint32(len(image)))...)
+	contents = append(contents, 1, 0, byte(instance))
+	resp, err = onu.omciResponse(swImageRequest(omcisim.EndSoftwareDownload, true, instance, contents...))
+	assert.NilError(t, err)
+	return resp[8]
+}
+
+func testSwImage() []byte {
+	image := []byte("BBSM_IMG_00002\n")
+	for i := 0; i < 100; i++ {
+		image = append(image, byte(i))
+	}
+	return image
+}
+
+func Test_crc32Aal5(t *testing.T) {
+	assert.Equal(t, crc32Aal5([]byte("123456789")), uint32(0xFC891918))
+}
+
+func Test_swImageVersion(t *testing.T) {
+	assert.Equal(t, swImageVersion([]byte("v2.1.0\x00\x01"), 0), "v2.1.0")
+	assert.Equal(t, swImageVersion([]byte("a-very-long-version-string"), 0), "a-very-long-ve")
+	assert.Equal(t, swImageVersion([]byte{0x00, 0x01}, 0xCAFE), "0000CAFE")
+}
+
+func Test_ParseSwImageFault(t *testing.T) {
+	fault, err := ParseSwImageFault("")
+	assert.NilError(t, err)
+	assert.Equal(t, fault, SwImageFaultNone)
+
+	fault, err = ParseSwImageFault("crc-mismatch")
+	assert.NilError(t, err)
+	assert.Equal(t, fault, SwImageFaultCrc)
+
+	_, err = ParseSwImageFault("foo")
+	assert.Error(t, err, "invalid-sw-image-fault-foo")
+}
+
+func Test_Onu_SoftwareUpgrade(t *testing.T) {
+	onu := createTestOnu()
+
+	images := onu.GetSoftwareImages()
+	assert.Equal(t, images[0].Version, defaultSwImageVersion)
+	assert.Equal(t, images[0].IsActive, true)
+	assert.Equal(t, images[1].IsValid, false)
+
+	assert.Equal(t, downloadSwImage(t, onu, 1, testSwImage()), byte(omciResultSuccess))
+	images = onu.GetSoftwareImages()
+	assert.Equal(t, images[1].Version, "BBSM_IMG_00002")
+	assert.Equal(t, images[1].IsValid, true)
+	assert.Equal(t, images[1].IsActive, false)
+
+	resp, err := onu.omciResponse(swImageRequest(omcisim.ActivateSoftware, true, 1))
+	assert.NilError(t, err)
+	assert.Equal(t, resp[8], byte(omciResultSuccess))
+	assert.Equal(t, onu.swImages.activationPending(), true)
+
+	// the image becomes active when the ONU reboots
+	onu.bootSoftwareImage()
+	assert.Equal(t, onu.swImages.activationPending(), false)
+
+	resp, err = onu.omciResponse(swImageRequest(omcisim.CommitSoftware, true, 1))
+	assert.NilError(t, err)
+	assert.Equal(t, resp[8], byte(omciResultSuccess))
+
+	images = onu.GetSoftwareImages()
+	assert.Equal(t, images[0].IsActive, false)
+	assert.Equal(t, images[0].IsCommitted, false)
+	assert.Equal(t, images[1].IsActive, true)
+	assert.Equal(t, images[1].IsCommitted, true)
+
+	// the new version is reported via OMCI
+	resp, err = onu.omciResponse(swImageRequest(omcisim.Get, true, 1, 0xF0, 0x00))
+	assert.NilError(t, err)
+	assert.Equal(t, resp[2], byte(0x20|omcisim.Get))
+	assert.Equal(t, resp[8], byte(omciResultSuccess))
+	assert.DeepEqual(t, resp[9:11], []byte{0xF0, 0x00})
+	assert.Equal(t, string(resp[11:25]), "BBSM_IMG_00002")
+	assert.DeepEqual(t, resp[25:28], []byte{1, 1, 1})
+}
+
+func Test_Onu_SoftwareUpgrade_errors(t *testing.T) {
+	onu := createTestOnu()
+
+	// the active image can't be overwritten
+	resp, err := onu.omciResponse(swImageRequest(omcisim.StartSoftwareDownload, true, 0, 0, 0, 0, 0, 10, 1, 0, 0))
+	assert.NilError(t, err)
+	assert.Equal(t, resp[8], byte(omciResultParameterError))
+
+	// there are only two images
+	resp, err = onu.omciResponse(swImageRequest(omcisim.StartSoftwareDownload, true, 2, 0, 0, 0, 0, 10, 1, 0, 2))
+	assert.NilError(t, err)
+	assert.Equal(t, resp[8], byte(omciResultUnknownInstance))
+
+	// an image that has not been downloaded can't be activated
+	resp, err = onu.omciResponse(swImageRequest(omcisim.ActivateSoftware, true, 1))
+	assert.NilError(t, err)
+	assert.Equal(t, resp[8], byte(omciResultParameterError))
+
+	// a window with a missing section is rejected
+	resp, err = onu.omciResponse(swImageRequest(omcisim.StartSoftwareDownload, true, 1, 3, 0, 0, 0, 100, 1, 0, 1))
+	assert.NilError(t, err)
+	assert.Equal(t, resp[8], byte(omciResultSuccess))
+	_, err = onu.omciResponse(swImageRequest(omcisim.DownloadSection, false, 1, 0))
+	assert.NilError(t, err)
+	resp, err = onu.omciResponse(swImageRequest(omcisim.DownloadSection, true, 1, 2))
+	assert.NilError(t, err)
+	assert.Equal(t, resp[8], byte(omciResultProcessingError))
+	assert.Equal(t, resp[9], byte(2))
+
+	// the attributes that are not supported are reported as such
+	resp, err = onu.omciResponse(swImageRequest(omcisim.Get, true, 0, 0x24, 0x00))
+	assert.NilError(t, err)
+	assert.Equal(t, resp[8], byte(omciResultAttributeFailure))
+	assert.DeepEqual(t, resp[9:11], []byte{0x20, 0x00})
+	assert.Equal(t, resp[11], byte(1))
+	assert.DeepEqual(t, resp[36:38], []byte{0x04, 0x00})
+}
+
+func Test_Onu_SoftwareUpgrade_faults(t *testing.T) {
+	onu := createTestOnu()
+
+	onu.SetSoftwareImageFault(SwImageFaultCrc)
+	assert.Equal(t, downloadSwImage(t, onu, 1, testSwImage()), byte(omciResultProcessingError))
+	assert.Equal(t, onu.GetSoftwareImages()[1].IsValid, false)
+
+	onu.SetSoftwareImageFault(SwImageFaultActivate)
+	assert.Equal(t, downloadSwImage(t, onu, 1, testSwImage()), byte(omciResultSuccess))
+	resp, err := onu.omciResponse(swImageRequest(omcisim.ActivateSoftware, true, 1))
+	assert.NilError(t, err)
+	assert.Equal(t, resp[8], byte(omciResultSuccess))
+
+	// the new image doesn't boot, the ONU comes back with the previous one
+	onu.bootSoftwareImage()
+	images := onu.GetSoftwareImages()
+	assert.Equal(t, images[0].IsActive, true)
+	assert.Equal(t, images[1].IsActive, false)
+}
+
+func Test_Onu_handleOmciMessage_noAck(t *testing.T) {
+	onu := createTestOnu()
+	stream := &mockStream{
+		Calls:   make(map[int]*openolt.OnuDiscIndication),
+		channel: make(chan int, 10),
+	}
+
+	// the download sections within a window are not acknowledged
+	req := swImageRequest(omcisim.DownloadSection, false, 1, 0)
+	msg := OmciMessage{omciMsg: &openolt.OmciMsg{Pkt: []byte(hex.EncodeToString(req))}}
+	onu.handleOmciMessage(msg, stream)
+	assert.Equal(t, stream.CallCount, 0)
+}
+
+func Test_Onu_SoftwareUpgrade_rebootFailure(t *testing.T) {
+	onu := createTestOnu()
+	stream := &mockStream{
+		Calls:   make(map[int]*openolt.OnuDiscIndication),
+		channel: make(chan int, 10),
+	}
+	assert.Equal(t, downloadSwImage(t, onu, 1, testSwImage()), byte(omciResultSuccess))
+
+	ctx, cancel := context.WithCancel(context.TODO())
+	defer cancel()
+	go onu.ProcessOnuMessages(ctx, stream, nil)
+
+	// the ONU is not activated yet, so it can't reboot to boot the image
+	req := swImageRequest(omcisim.ActivateSoftware, true, 1)
+	onu.Channel <- Message{Type: OMCI, Data: OmciMessage{omciMsg: &openolt.OmciMsg{Pkt: []byte(hex.EncodeToString(req))}}}
+
+	select {
+	case <-stream.channel:
+	case <-time.After(1 * time.Second):
+		t.Fatal("the activation has not been answered")
+	}
+	for i := 0; i < 100 && onu.swImages.activationPending(); i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	// the activation is canceled and the previous image is still the active one
+	assert.Equal(t, onu.swImages.activationPending(), false)
+	assert.Equal(t, onu.InternalState.Current(), "initialized")
+	images := onu.GetSoftwareImages()
+	assert.Equal(t, images[0].IsActive, true)
+	assert.Equal(t, images[1].IsActive, false)
+}
@@ -20,6 +20,7 @@ import (
 	"bytes"
 	"errors"
 	"fmt"
+	"sync"
 
 	"github.com/looplab/fsm"
 	"github.com/opencord/voltha-protos/v2/go/openolt"
@@ -32,15 +33,29 @@ type PonPort struct {
 	Onus   []*Onu
 	Olt    OltDevice
 
+	// onusLock protects the list of ONUs, that changes when ONUs are added, removed or moved on a running OLT
+	onusLock sync.RWMutex
+
 	// PON Attributes
 	OperState *fsm.FSM
 	Type      string
+	Stats     PacketStats
 
 	// NOTE do we need a state machine for the PON Ports?
 }
 
-func (p PonPort) GetOnuBySn(sn *openolt.SerialNumber) (*Onu, error) {
-	for _, onu := range p.Onus {
+// GetOnus returns the ONUs connected to the PON port,
+// the list is a copy so it can be iterated while ONUs are added or removed
+func (p *PonPort) GetOnus() []*Onu {
+	p.onusLock.RLock()
+	defer p.onusLock.RUnlock()
+	onus := make([]*Onu, len(p.Onus))
+	copy(onus, p.Onus)
+	return onus
+}
+
+func (p *PonPort) GetOnuBySn(sn *openolt.SerialNumber) (*Onu, error) {
+	for _, onu := range p.GetOnus() {
 		if bytes.Equal(onu.SerialNumber.VendorSpecific, sn.VendorSpecific) {
 			return onu, nil
 		}
@@ -48,11 +63,47 @@ func (p PonPort) GetOnuBySn(sn *openolt.SerialNumber) (*Onu, error) {
 	return nil, errors.New(fmt.Sprintf("Cannot find Onu with serial number %d in PonPort %d", sn, p.ID))
 }
 
-func (p PonPort) GetOnuById(id uint32) (*Onu, error) {
-	for _, onu := range p.Onus {
+func (p *PonPort) GetOnuById(id uint32) (*Onu, error) {
+	for _, onu := range p.GetOnus() {
 		if onu.ID == id {
 			return onu, nil
 		}
 	}
 	return nil, errors.New(fmt.Sprintf("Cannot find Onu with id %d in PonPort %d", id, p.ID))
 }
+
+// getFreeOnuId returns the lowest ONU ID that is not used on the PON port
+func (p *PonPort) getFreeOnuId() (uint32, error) {
+	used := map[uint32]bool{}
+	for _, onu := range p.GetOnus() {
+		used[onu.ID] = true
+	}
+	for id := uint32(onuIdStart); id <= onuIdEnd; id++ {
+		if !used[id] {
+			return id, nil
+		}
+	}
+	return 0, errors.New(fmt.Sprintf("no-free-onu-id-on-pon-%d", p.ID))
+}
+
+// addOnu connects an ONU to the PON port
+func (p *PonPort) addOnu(onu *Onu) {
+	p.onusLock.Lock()
+	defer p.onusLock.Unlock()
+	p.Onus = append(p.Onus, onu)
+	p.NumOnu = len(p.Onus)
+}
+
+// removeOnu disconnects an ONU from the PON port
+func (p *PonPort) removeOnu(onu *Onu) {
+	p.onusLock.Lock()
+	defer p.onusLock.Unlock()
+	onus := []*Onu{}
+	for _, o := range p.Onus {
+		if o != onu {
+			onus = append(onus, o)
+		}
+	}
+	p.Onus = onus
+	p.NumOnu = len(p.Onus)
+}
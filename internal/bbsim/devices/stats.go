@@ -0,0 +1,250 @@
+/*
+ * Copyright 2018-present Open Networking Foundation
+
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+
+ * http://www.apache.org/licenses/LICENSE-2.0
+
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package devices
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/gopacket"
+	"github.com/google/gopacket/layers"
+	"github.com/opencord/voltha-protos/v2/go/openolt"
+	log "github.com/sirupsen/logrus"
+)
+
+// PacketStats counts the packets going through an OLT port or matching a flow
+type PacketStats struct {
+	RxPackets uint64
+	RxBytes   uint64
+	TxPackets uint64
+	TxBytes   uint64
+}
+
+func (s *PacketStats) countRx(size int) {
+	atomic.AddUint64(&s.RxPackets, 1)
+	atomic.AddUint64(&s.RxBytes, uint64(size))
+}
+
+func (s *PacketStats) countTx(size int) {
+	atomic.AddUint64(&s.TxPackets, 1)
+	atomic.AddUint64(&s.TxBytes, uint64(size))
+}
+
+// Get returns a snapshot of the counters
+func (s *PacketStats) Get() PacketStats {
+	return PacketStats{
+		RxPackets: atomic.LoadUint64(&s.RxPackets),
+		RxBytes:   atomic.LoadUint64(&s.RxBytes),
+		TxPackets: atomic.LoadUint64(&s.TxPackets),
+		TxBytes:   atomic.LoadUint64(&s.TxBytes),
+	}
+}
+
+// packetEthType returns the EtherType of the payload, skipping the VLAN tags
+func packetEthType(pkt gopacket.Packet) uint32 {
+	var ethType layers.EthernetType
+	for _, l := range pkt.Layers() {
+		switch layer := l.(type) {
+		case *layers.Ethernet:
+			ethType = layer.EthernetType
+		case *layers.Dot1Q:
+			ethType = layer.Type
+		}
+	}
+	return uint32(ethType)
+}
+
+// flowMatches is a loose match between a packet and a flow, it is only used to account
+// the packets on the flows, an unknown interface or ONU (-1) matches any flow
+func flowMatches(flow openolt.Flow, direction string, intfId int32, onuId int32, ethType uint32) bool {
+	if flow.FlowType != direction {
+		return false
+	}
+	if intfId >= 0 && flow.AccessIntfId >= 0 && flow.AccessIntfId != intfId {
+		return false
+	}
+	if onuId >= 0 && flow.OnuId >= 0 && flow.OnuId != onuId {
+		return false
+	}
+	if flow.Classifier != nil && flow.Classifier.EthType != 0 && flow.Classifier.EthType != ethType {
+		return false
+	}
+	return true
+}
+
+// countFlowPacket accounts a packet on the first flow (lowest FlowId) it matches
+func (o *OltDevice) countFlowPacket(direction string, intfId int32, onuId int32, pkt gopacket.Packet) {
+	ethType := packetEthType(pkt)
+
+	o.flowsLock.Lock()
+	defer o.flowsLock.Unlock()
+
+	var key *FlowKey
+	for k, flow := range o.Flows {
+		if !flowMatches(flow, direction, intfId, onuId, ethType) {
+			continue
+		}
+		if key == nil || k.ID < key.ID {
+			matched := k
+			key = &matched
+		}
+	}
+	if key == nil {
+		return
+	}
+	o.countFlowStats(*key, len(pkt.Data()))
+}
+
+// countFlowStats accounts a packet on a flow, the caller is expected to hold the flowsLock.
+// The counters are seen from the PON: the packets of the upstream flows are received,
+// the ones of the downstream flows are transmitted
+func (o *OltDevice) countFlowStats(key FlowKey, size int) {
+	if o.flowStats == nil {
+		o.flowStats = make(map[FlowKey]*PacketStats)
+	}
+	stats, ok := o.flowStats[key]
+	if !ok {
+		stats = &PacketStats{}
+		o.flowStats[key] = stats
+	}
+	if key.Direction == "upstream" {
+		stats.countRx(size)
+	} else {
+		stats.countTx(size)
+	}
+}
+
+// GetFlowStats returns the counters of a flow
+func (o *OltDevice) GetFlowStats(key FlowKey) PacketStats {
+	o.flowsLock.RLock()
+	defer o.flowsLock.RUnlock()
+
+	if stats, ok := o.flowStats[key]; ok {
+		return stats.Get()
+	}
+	return PacketStats{}
+}
+
+func toPortStatistics(intfId uint32, stats PacketStats, timestamp uint32) *openolt.PortStatistics {
+	return &openolt.PortStatistics{
+		IntfId:         intfId,
+		RxBytes:        stats.RxBytes,
+		RxPackets:      stats.RxPackets,
+		RxUcastPackets: stats.RxPackets,
+		TxBytes:        stats.TxBytes,
+		TxPackets:      stats.TxPackets,
+		TxUcastPackets: stats.TxPackets,
+		Timestamp:      timestamp,
+	}
+}
+
+// ponStatsStream counts the packets the ONUs send to VOLTHA as received on their PON port
+type ponStatsStream struct {
+	openolt.Openolt_EnableIndicationServer
+	olt *OltDevice
+}
+
+func newPonStatsStream(olt *OltDevice, stream openolt.Openolt_EnableIndicationServer) *ponStatsStream {
+	return &ponStatsStream{
+		Openolt_EnableIndicationServer: stream,
+		olt:                            olt,
+	}
+}
+
+func (s *ponStatsStream) Send(ind *openolt.Indication) error {
+	if err := s.Openolt_EnableIndicationServer.Send(ind); err != nil {
+		return err
+	}
+	if pktInd := ind.GetPktInd(); pktInd != nil && pktInd.IntfType == "pon" {
+		if pon, err := s.olt.GetPonById(pktInd.IntfId); err == nil {
+			pon.Stats.countRx(len(pktInd.Pkt))
+		}
+	}
+	return nil
+}
+
+// sendStatistics reports the counters of all the ports and flows to VOLTHA
+func (o *OltDevice) sendStatistics(stream openolt.Openolt_EnableIndicationServer) {
+	timestamp := uint32(time.Now().Unix())
+
+	portStats := []*openolt.PortStatistics{}
+	for _, nni := range o.Nnis {
+		portStats = append(portStats, toPortStatistics(nniIntfIdPrefix|nni.ID, nni.Stats.Get(), timestamp))
+	}
+	for _, pon := range o.Pons {
+		portStats = append(portStats, toPortStatistics(ponIntfIdPrefix|pon.ID, pon.Stats.Get(), timestamp))
+	}
+
+	for _, stats := range portStats {
+		data := &openolt.Indication_PortStats{PortStats: stats}
+		if err := stream.Send(&openolt.Indication{Data: data}); err != nil {
+			oltLogger.Errorf("Failed to send PortStats indication: %v", err)
+			return
+		}
+	}
+
+	for _, flow := range o.GetFlows() {
+		stats := o.GetFlowStats(FlowKey{ID: flow.FlowId, Direction: flow.FlowType})
+		data := &openolt.Indication_FlowStats{FlowStats: &openolt.FlowStatistics{
+			FlowId:    flow.FlowId,
+			RxBytes:   stats.RxBytes,
+			RxPackets: stats.RxPackets,
+			TxBytes:   stats.TxBytes,
+			TxPackets: stats.TxPackets,
+			Timestamp: timestamp,
+		}}
+		if err := stream.Send(&openolt.Indication{Data: data}); err != nil {
+			oltLogger.Errorf("Failed to send FlowStats indication: %v", err)
+			return
+		}
+	}
+
+	oltLogger.WithFields(log.Fields{
+		"oltId": o.ID,
+		"ports": len(portStats),
+	}).Trace("Sent statistics")
+}
+
+// processStatistics periodically reports the port and flow statistics to VOLTHA
+func (o *OltDevice) processStatistics(ctx context.Context, wg *sync.WaitGroup) {
+	defer wg.Done()
+
+	interval := o.Options.PortStatsInterval
+	if interval <= 0 {
+		oltLogger.Debug("Periodic statistics are disabled")
+		return
+	}
+
+	ticker := time.NewTicker(time.Duration(interval) * time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			oltLogger.Debug("Statistics processing canceled via context")
+			return
+		case <-ticker.C:
+			// the statistics are sent by the OLT message loop, as all the other OLT indications
+			select {
+			case o.channel <- Message{Type: StatisticsIndication}:
+			case <-ctx.Done():
+			}
+		}
+	}
+}
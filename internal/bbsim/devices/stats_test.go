@@ -0,0 +1,263 @@
+/*
+ * Copyright 2018-present Open Networking Foundation
+
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+
+ * http://www.apache.org/licenses/LICENSE-2.0
+
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package devices
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/google/gopacket"
+	"github.com/google/gopacket/layers"
+	"github.com/looplab/fsm"
+	"github.com/opencord/voltha-protos/v2/go/openolt"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"gotest.tools/assert"
+)
+
+type mockStatsStream struct {
+	grpc.ServerStream
+	PortStats []*openolt.PortStatistics
+	FlowStats []*openolt.FlowStatistics
+}
+
+func (s *mockStatsStream) Send(ind *openolt.Indication) error {
+	if stats := ind.GetPortStats(); stats != nil {
+		s.PortStats = append(s.PortStats, stats)
+	}
+	if stats := ind.GetFlowStats(); stats != nil {
+		s.FlowStats = append(s.FlowStats, stats)
+	}
+	return nil
+}
+
+func createTestPacket(t *testing.T, ethType layers.EthernetType, vlans ...uint16) []byte {
+	eth := &layers.Ethernet{
+		SrcMAC:       net.HardwareAddr{0x0e, 0x00, 0x00, 0x00, 0x00, 0x01},
+		DstMAC:       net.HardwareAddr{0x0e, 0x00, 0x00, 0x00, 0x00, 0x02},
+		EthernetType: ethType,
+	}
+	pktLayers := []gopacket.SerializableLayer{eth}
+	for i, vlan := range vlans {
+		if i == 0 {
+			eth.EthernetType = layers.EthernetTypeDot1Q
+		}
+		tag := &layers.Dot1Q{VLANIdentifier: vlan, Type: ethType}
+		if i < len(vlans)-1 {
+			tag.Type = layers.EthernetTypeDot1Q
+		}
+		pktLayers = append(pktLayers, tag)
+	}
+	pktLayers = append(pktLayers, gopacket.Payload([]byte{0x01, 0x02}))
+
+	buffer := gopacket.NewSerializeBuffer()
+	err := gopacket.SerializeLayers(buffer, gopacket.SerializeOptions{}, pktLayers...)
+	assert.NilError(t, err)
+	return buffer.Bytes()
+}
+
+func Test_PacketEthType(t *testing.T) {
+	pkt := gopacket.NewPacket(createTestPacket(t, layers.EthernetTypeEAPOL), layers.LayerTypeEthernet, gopacket.Default)
+	assert.Equal(t, packetEthType(pkt), uint32(0x888e))
+
+	// the VLAN tags are skipped
+	pkt = gopacket.NewPacket(createTestPacket(t, layers.EthernetTypeIPv4, 900, 901), layers.LayerTypeEthernet, gopacket.Default)
+	assert.Equal(t, packetEthType(pkt), uint32(0x0800))
+}
+
+func Test_Olt_CountFlowPacket(t *testing.T) {
+	olt := createMockOlt(1, 2)
+
+	eapol := FlowKey{ID: 1, Direction: "downstream"}
+	olt.Flows[eapol] = openolt.Flow{FlowId: 1, FlowType: "downstream", AccessIntfId: 0, OnuId: 1,
+		Classifier: &openolt.Classifier{EthType: 0x888e}}
+	data := FlowKey{ID: 2, Direction: "downstream"}
+	olt.Flows[data] = openolt.Flow{FlowId: 2, FlowType: "downstream", AccessIntfId: 0, OnuId: 1,
+		Classifier: &openolt.Classifier{}}
+	other := FlowKey{ID: 3, Direction: "downstream"}
+	olt.Flows[other] = openolt.Flow{FlowId: 3, FlowType: "downstream", AccessIntfId: 0, OnuId: 0,
+		Classifier: &openolt.Classifier{}}
+
+	raw := createTestPacket(t, layers.EthernetTypeIPv4)
+	olt.countFlowPacket("downstream", 0, 1, gopacket.NewPacket(raw, layers.LayerTypeEthernet, gopacket.Default))
+
+	assert.Equal(t, olt.GetFlowStats(eapol), PacketStats{})
+	assert.Equal(t, olt.GetFlowStats(other), PacketStats{})
+	assert.Equal(t, olt.GetFlowStats(data), PacketStats{
+		TxPackets: 1,
+		TxBytes:   uint64(len(raw)),
+	})
+
+	// the packets of the upstream flows are received
+	up := FlowKey{ID: 4, Direction: "upstream"}
+	olt.Flows[up] = openolt.Flow{FlowId: 4, FlowType: "upstream", AccessIntfId: 0, OnuId: 1,
+		Classifier: &openolt.Classifier{}}
+	olt.countFlowPacket("upstream", 0, 1, gopacket.NewPacket(raw, layers.LayerTypeEthernet, gopacket.Default))
+	assert.Equal(t, olt.GetFlowStats(up), PacketStats{
+		RxPackets: 1,
+		RxBytes:   uint64(len(raw)),
+	})
+
+	// the counters are removed with the flow
+	_, err := olt.FlowRemove(context.TODO(), &openolt.Flow{FlowId: 2, FlowType: "downstream"})
+	assert.NilError(t, err)
+	assert.Equal(t, olt.GetFlowStats(data), PacketStats{})
+}
+
+func Test_Olt_OnuPacketOut_Stats(t *testing.T) {
+	olt := createMockOlt(1, 1)
+
+	raw := createTestPacket(t, layers.EthernetTypeEAPOL)
+	_, err := olt.OnuPacketOut(context.TODO(), &openolt.OnuPacket{IntfId: 0, OnuId: 0, Pkt: raw})
+	assert.NilError(t, err)
+
+	stats := olt.Pons[0].Stats.Get()
+	assert.Equal(t, stats.TxPackets, uint64(1))
+	assert.Equal(t, stats.TxBytes, uint64(len(raw)))
+	assert.Equal(t, stats.RxPackets, uint64(0))
+}
+
+func Test_Olt_OnuPacketOut_UnknownOnu(t *testing.T) {
+	olt := createMockOlt(1, 1)
+
+	raw := createTestPacket(t, layers.EthernetTypeEAPOL)
+	_, err := olt.OnuPacketOut(context.TODO(), &openolt.OnuPacket{IntfId: 1, OnuId: 0, Pkt: raw})
+	assert.Equal(t, status.Code(err), codes.NotFound)
+	_, err = olt.OnuPacketOut(context.TODO(), &openolt.OnuPacket{IntfId: 0, OnuId: 5, Pkt: raw})
+	assert.Equal(t, status.Code(err), codes.NotFound)
+
+	// nothing is counted
+	assert.Equal(t, olt.Pons[0].Stats.Get(), PacketStats{})
+}
+
+func Test_Olt_SendStatistics(t *testing.T) {
+	olt := createMockOlt(2, 1)
+	olt.Nnis = []*NniPort{{ID: 0}}
+	olt.Nnis[0].Stats.countRx(100)
+	olt.Flows[FlowKey{ID: 1, Direction: "upstream"}] = openolt.Flow{FlowId: 1, FlowType: "upstream"}
+
+	stream := &mockStatsStream{}
+	olt.sendStatistics(stream)
+
+	assert.Equal(t, len(stream.PortStats), 3)
+	assert.Equal(t, stream.PortStats[0].IntfId, uint32(1048576))
+	assert.Equal(t, stream.PortStats[0].RxPackets, uint64(1))
+	assert.Equal(t, stream.PortStats[0].RxBytes, uint64(100))
+	assert.Equal(t, stream.PortStats[1].IntfId, uint32(0x20000000))
+	assert.Equal(t, stream.PortStats[2].IntfId, uint32(0x20000001))
+
+	assert.Equal(t, len(stream.FlowStats), 1)
+	assert.Equal(t, stream.FlowStats[0].FlowId, uint32(1))
+}
+
+func Test_Olt_CollectStatistics(t *testing.T) {
+	olt := createMockOlt(1, 1)
+	olt.InternalState = fsm.NewFSM("enabled", fsm.Events{}, fsm.Callbacks{})
+	olt.channel = make(chan Message, 10)
+
+	// the statistics are sent by the OLT message loop
+	_, err := olt.CollectStatistics(context.TODO(), &openolt.Empty{})
+	assert.NilError(t, err)
+	msg := <-olt.channel
+	assert.Equal(t, msg.Type, StatisticsIndication)
+}
+
+func Test_Olt_CollectStatistics_NotEnabled(t *testing.T) {
+	olt := createMockOlt(1, 1)
+	olt.InternalState = fsm.NewFSM("disabled", fsm.Events{}, fsm.Callbacks{})
+
+	_, err := olt.CollectStatistics(context.TODO(), &openolt.Empty{})
+	assert.Equal(t, err.Error(), "olt-0-is-not-enabled")
+}
+
+func Test_Olt_PonStatsStream(t *testing.T) {
+	olt := createMockOlt(2, 1)
+	stream := newPonStatsStream(&olt, &mockStatsStream{})
+
+	raw := createTestPacket(t, layers.EthernetTypeEAPOL)
+	err := stream.Send(&openolt.Indication{Data: &openolt.Indication_PktInd{PktInd: &openolt.PacketIndication{
+		IntfType: "pon",
+		IntfId:   1,
+		Pkt:      raw,
+	}}})
+	assert.NilError(t, err)
+
+	// the packets trapped on the NNI are not counted on the PON ports
+	err = stream.Send(&openolt.Indication{Data: &openolt.Indication_PktInd{PktInd: &openolt.PacketIndication{
+		IntfType: "nni",
+		IntfId:   0,
+		Pkt:      raw,
+	}}})
+	assert.NilError(t, err)
+
+	assert.Equal(t, olt.Pons[0].Stats.Get(), PacketStats{})
+	assert.Equal(t, olt.Pons[1].Stats.Get(), PacketStats{
+		RxPackets: 1,
+		RxBytes:   uint64(len(raw)),
+	})
+}
+
+func Test_Olt_SendStatistics_DataPlane(t *testing.T) {
+	olt := createMockOlt(1, 1)
+	onu := olt.Pons[0].Onus[0]
+	uni := onu.UniPorts[0]
+	nni := &NniPort{ID: 0, nniVeth: "nni"}
+	olt.Nnis = []*NniPort{nni}
+
+	_writeOnVeth := writeOnVeth
+	defer func() { writeOnVeth = _writeOnVeth }()
+	writeOnVeth = func(vethName string, data []byte) error {
+		return nil
+	}
+
+	olt.Flows[FlowKey{ID: 1, Direction: "downstream"}] = openolt.Flow{
+		AccessIntfId: 0, OnuId: int32(onu.ID), UniId: 0, FlowId: 1, FlowType: "downstream",
+		Classifier: &openolt.Classifier{OVid: 900, IVid: 901},
+		Action:     &openolt.Action{Cmd: &openolt.ActionCmd{RemoveOuterTag: true}},
+		Priority:   1000,
+	}
+	olt.Flows[FlowKey{ID: 2, Direction: "upstream"}] = openolt.Flow{
+		AccessIntfId: 0, OnuId: int32(onu.ID), UniId: 0, FlowId: 2, FlowType: "upstream", NetworkIntfId: 0,
+		Classifier: &openolt.Classifier{OVid: 901},
+		Action:     &openolt.Action{Cmd: &openolt.ActionCmd{AddOuterTag: true}, OVid: 900},
+		Priority:   1000,
+	}
+
+	pktStream := &mockPktStream{
+		channel: make(chan *openolt.PacketIndication, 10),
+	}
+	downstream := newTestPacket(t, layers.EthernetTypeIPv4, 900, 901)
+	olt.forwardNniPacket(nni, downstream, pktStream)
+	upstream := newTestPacket(t, layers.EthernetTypeIPv4, 901)
+	assert.NilError(t, olt.forwardUniPacket(uni, upstream, pktStream))
+	// the packets without a flow are counted too, the OLT drops them
+	untagged := newTestPacket(t, layers.EthernetTypeIPv4)
+	assert.Assert(t, olt.forwardUniPacket(uni, untagged, pktStream) != nil)
+
+	// the data plane traffic is reported on the PON port
+	stream := &mockStatsStream{}
+	olt.sendStatistics(stream)
+	assert.Equal(t, len(stream.PortStats), 2)
+	ponStats := stream.PortStats[1]
+	assert.Equal(t, ponStats.IntfId, uint32(0x20000000))
+	assert.Equal(t, ponStats.TxPackets, uint64(1))
+	assert.Equal(t, ponStats.TxBytes, uint64(len(downstream.Data())))
+	assert.Equal(t, ponStats.RxPackets, uint64(2))
+	assert.Equal(t, ponStats.RxBytes, uint64(len(upstream.Data())+len(untagged.Data())))
+}
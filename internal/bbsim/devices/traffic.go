@@ -0,0 +1,312 @@
+/*
+ * Copyright 2018-present Open Networking Foundation
+
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+
+ * http://www.apache.org/licenses/LICENSE-2.0
+
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package devices
+
+import (
+	"context"
+
+	"github.com/opencord/voltha-protos/v2/go/openolt"
+	"github.com/opencord/voltha-protos/v2/go/tech_profile"
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// getTechProfileUni returns the UNI a set of schedulers or queues is installed on
+func (o *OltDevice) getTechProfileUni(intfId uint32, onuId uint32, uniId uint32) (*UniPort, error) {
+	onu, err := o.FindOnuById(intfId, onuId)
+	if err != nil {
+		return nil, status.Errorf(codes.NotFound, err.Error())
+	}
+	uni, err := onu.GetUniById(uniId)
+	if err != nil {
+		return nil, status.Errorf(codes.NotFound, err.Error())
+	}
+	return uni, nil
+}
+
+// findScheduler returns the UNI on a PON using an alloc-id in a given direction
+func (o *OltDevice) findScheduler(intfId uint32, direction tech_profile.Direction, allocId uint32) (*UniPort, int) {
+	pon, err := o.GetPonById(intfId)
+	if err != nil {
+		return nil, -1
+	}
+	for _, onu := range pon.GetOnus() {
+		for _, uni := range onu.UniPorts {
+			for i, ts := range uni.TrafficSchedulers {
+				if ts.Direction == direction && ts.AllocId == allocId {
+					return uni, i
+				}
+			}
+		}
+	}
+	return nil, -1
+}
+
+// findQueue returns the UNI on a PON using a gemport in a given direction
+func (o *OltDevice) findQueue(intfId uint32, direction tech_profile.Direction, gemportId uint32) (*UniPort, int) {
+	pon, err := o.GetPonById(intfId)
+	if err != nil {
+		return nil, -1
+	}
+	for _, onu := range pon.GetOnus() {
+		for _, uni := range onu.UniPorts {
+			for i, tq := range uni.TrafficQueues {
+				if tq.Direction == direction && tq.GemportId == gemportId {
+					return uni, i
+				}
+			}
+		}
+	}
+	return nil, -1
+}
+
+func hasScheduler(uni *UniPort, direction tech_profile.Direction) bool {
+	for _, ts := range uni.TrafficSchedulers {
+		if ts.Direction == direction {
+			return true
+		}
+	}
+	return false
+}
+
+func hasQueue(uni *UniPort, direction tech_profile.Direction) bool {
+	for _, tq := range uni.TrafficQueues {
+		if tq.Direction == direction {
+			return true
+		}
+	}
+	return false
+}
+
+func (o *OltDevice) CreateTrafficSchedulers(_ context.Context, tss *tech_profile.TrafficSchedulers) (*openolt.Empty, error) {
+	oltLogger.WithFields(log.Fields{
+		"IntfId":     tss.IntfId,
+		"OnuId":      tss.OnuId,
+		"UniId":      tss.UniId,
+		"Schedulers": len(tss.TrafficScheds),
+	}).Debug("Received CreateTrafficSchedulers call from VOLTHA")
+
+	uni, err := o.getTechProfileUni(tss.IntfId, tss.OnuId, tss.UniId)
+	if err != nil {
+		return new(openolt.Empty), err
+	}
+
+	o.trafficLock.Lock()
+	defer o.trafficLock.Unlock()
+
+	// validate all the schedulers before storing any of them
+	for i, ts := range tss.TrafficScheds {
+		if ts.AllocId < allocIdStart || ts.AllocId > allocIdEnd {
+			return new(openolt.Empty), status.Errorf(codes.InvalidArgument, "alloc-id %d is out of range [%d, %d]", ts.AllocId, allocIdStart, allocIdEnd)
+		}
+		if owner, _ := o.findScheduler(tss.IntfId, ts.Direction, ts.AllocId); owner != nil {
+			return new(openolt.Empty), status.Errorf(codes.AlreadyExists, "%s alloc-id %d is already in use by UNI %d on ONU %s",
+				ts.Direction, ts.AllocId, owner.ID, owner.Onu.Sn())
+		}
+		for _, other := range tss.TrafficScheds[:i] {
+			if other.Direction == ts.Direction && other.AllocId == ts.AllocId {
+				return new(openolt.Empty), status.Errorf(codes.AlreadyExists, "%s alloc-id %d is duplicated in the request", ts.Direction, ts.AllocId)
+			}
+		}
+	}
+
+	uni.TrafficSchedulers = append(uni.TrafficSchedulers, tss.TrafficScheds...)
+
+	oltLogger.WithFields(log.Fields{
+		"IntfId": tss.IntfId,
+		"OnuId":  tss.OnuId,
+		"UniId":  tss.UniId,
+		"OnuSn":  uni.Onu.Sn(),
+	}).Info("Created TrafficSchedulers")
+	return new(openolt.Empty), nil
+}
+
+func (o *OltDevice) RemoveTrafficSchedulers(_ context.Context, tss *tech_profile.TrafficSchedulers) (*openolt.Empty, error) {
+	oltLogger.WithFields(log.Fields{
+		"IntfId":     tss.IntfId,
+		"OnuId":      tss.OnuId,
+		"UniId":      tss.UniId,
+		"Schedulers": len(tss.TrafficScheds),
+	}).Debug("Received RemoveTrafficSchedulers call from VOLTHA")
+
+	uni, err := o.getTechProfileUni(tss.IntfId, tss.OnuId, tss.UniId)
+	if err != nil {
+		return new(openolt.Empty), err
+	}
+
+	o.trafficLock.Lock()
+	defer o.trafficLock.Unlock()
+
+	for _, ts := range tss.TrafficScheds {
+		if owner, _ := o.findScheduler(tss.IntfId, ts.Direction, ts.AllocId); owner != uni {
+			return new(openolt.Empty), status.Errorf(codes.NotFound, "%s alloc-id %d is not installed on UNI %d on ONU %s",
+				ts.Direction, ts.AllocId, uni.ID, uni.Onu.Sn())
+		}
+		// NOTE VOLTHA removes the queues before the schedulers they are attached to
+		if hasQueue(uni, ts.Direction) {
+			return new(openolt.Empty), status.Errorf(codes.FailedPrecondition, "%s alloc-id %d still has queues on UNI %d on ONU %s",
+				ts.Direction, ts.AllocId, uni.ID, uni.Onu.Sn())
+		}
+	}
+
+	for _, ts := range tss.TrafficScheds {
+		// NOTE the scheduler may be listed twice in the request
+		if _, i := o.findScheduler(tss.IntfId, ts.Direction, ts.AllocId); i >= 0 {
+			uni.TrafficSchedulers = append(uni.TrafficSchedulers[:i], uni.TrafficSchedulers[i+1:]...)
+		}
+	}
+
+	oltLogger.WithFields(log.Fields{
+		"IntfId": tss.IntfId,
+		"OnuId":  tss.OnuId,
+		"UniId":  tss.UniId,
+		"OnuSn":  uni.Onu.Sn(),
+	}).Info("Removed TrafficSchedulers")
+	return new(openolt.Empty), nil
+}
+
+func (o *OltDevice) CreateTrafficQueues(_ context.Context, tqs *tech_profile.TrafficQueues) (*openolt.Empty, error) {
+	oltLogger.WithFields(log.Fields{
+		"IntfId": tqs.IntfId,
+		"OnuId":  tqs.OnuId,
+		"UniId":  tqs.UniId,
+		"Queues": len(tqs.TrafficQueues),
+	}).Debug("Received CreateTrafficQueues call from VOLTHA")
+
+	uni, err := o.getTechProfileUni(tqs.IntfId, tqs.OnuId, tqs.UniId)
+	if err != nil {
+		return new(openolt.Empty), err
+	}
+
+	o.trafficLock.Lock()
+	defer o.trafficLock.Unlock()
+
+	for i, tq := range tqs.TrafficQueues {
+		if tq.GemportId < gemportIdStart || tq.GemportId > gemportIdEnd {
+			return new(openolt.Empty), status.Errorf(codes.InvalidArgument, "gemport %d is out of range [%d, %d]", tq.GemportId, gemportIdStart, gemportIdEnd)
+		}
+		if !hasScheduler(uni, tq.Direction) {
+			return new(openolt.Empty), status.Errorf(codes.FailedPrecondition, "no %s scheduler on UNI %d on ONU %s for gemport %d",
+				tq.Direction, uni.ID, uni.Onu.Sn(), tq.GemportId)
+		}
+		if owner, _ := o.findQueue(tqs.IntfId, tq.Direction, tq.GemportId); owner != nil {
+			return new(openolt.Empty), status.Errorf(codes.AlreadyExists, "%s gemport %d is already in use by UNI %d on ONU %s",
+				tq.Direction, tq.GemportId, owner.ID, owner.Onu.Sn())
+		}
+		for _, other := range tqs.TrafficQueues[:i] {
+			if other.Direction == tq.Direction && other.GemportId == tq.GemportId {
+				return new(openolt.Empty), status.Errorf(codes.AlreadyExists, "%s gemport %d is duplicated in the request", tq.Direction, tq.GemportId)
+			}
+		}
+	}
+
+	uni.TrafficQueues = append(uni.TrafficQueues, tqs.TrafficQueues...)
+
+	oltLogger.WithFields(log.Fields{
+		"IntfId": tqs.IntfId,
+		"OnuId":  tqs.OnuId,
+		"UniId":  tqs.UniId,
+		"OnuSn":  uni.Onu.Sn(),
+	}).Info("Created TrafficQueues")
+	return new(openolt.Empty), nil
+}
+
+func (o *OltDevice) RemoveTrafficQueues(_ context.Context, tqs *tech_profile.TrafficQueues) (*openolt.Empty, error) {
+	oltLogger.WithFields(log.Fields{
+		"IntfId": tqs.IntfId,
+		"OnuId":  tqs.OnuId,
+		"UniId":  tqs.UniId,
+		"Queues": len(tqs.TrafficQueues),
+	}).Debug("Received RemoveTrafficQueues call from VOLTHA")
+
+	uni, err := o.getTechProfileUni(tqs.IntfId, tqs.OnuId, tqs.UniId)
+	if err != nil {
+		return new(openolt.Empty), err
+	}
+
+	o.trafficLock.Lock()
+	defer o.trafficLock.Unlock()
+
+	for _, tq := range tqs.TrafficQueues {
+		if owner, _ := o.findQueue(tqs.IntfId, tq.Direction, tq.GemportId); owner != uni {
+			return new(openolt.Empty), status.Errorf(codes.NotFound, "%s gemport %d is not installed on UNI %d on ONU %s",
+				tq.Direction, tq.GemportId, uni.ID, uni.Onu.Sn())
+		}
+	}
+
+	for _, tq := range tqs.TrafficQueues {
+		// NOTE the queue may be listed twice in the request
+		if _, i := o.findQueue(tqs.IntfId, tq.Direction, tq.GemportId); i >= 0 {
+			uni.TrafficQueues = append(uni.TrafficQueues[:i], uni.TrafficQueues[i+1:]...)
+		}
+	}
+
+	oltLogger.WithFields(log.Fields{
+		"IntfId": tqs.IntfId,
+		"OnuId":  tqs.OnuId,
+		"UniId":  tqs.UniId,
+		"OnuSn":  uni.Onu.Sn(),
+	}).Info("Removed TrafficQueues")
+	return new(openolt.Empty), nil
+}
+
+// GetTrafficSchedulers returns the schedulers (T-CONTs) installed on a UNI
+func (o *OltDevice) GetTrafficSchedulers(uni *UniPort) []*tech_profile.TrafficScheduler {
+	o.trafficLock.RLock()
+	defer o.trafficLock.RUnlock()
+
+	res := make([]*tech_profile.TrafficScheduler, len(uni.TrafficSchedulers))
+	copy(res, uni.TrafficSchedulers)
+	return res
+}
+
+// GetTrafficQueues returns the queues (GEM ports) installed on a UNI
+func (o *OltDevice) GetTrafficQueues(uni *UniPort) []*tech_profile.TrafficQueue {
+	o.trafficLock.RLock()
+	defer o.trafficLock.RUnlock()
+
+	res := make([]*tech_profile.TrafficQueue, len(uni.TrafficQueues))
+	copy(res, uni.TrafficQueues)
+	return res
+}
+
+// clearTrafficConfig removes all the schedulers and queues from the UNIs
+func (o *OltDevice) clearTrafficConfig() {
+	o.trafficLock.Lock()
+	defer o.trafficLock.Unlock()
+
+	for _, pon := range o.Pons {
+		for _, onu := range pon.GetOnus() {
+			for _, uni := range onu.UniPorts {
+				uni.TrafficSchedulers = []*tech_profile.TrafficScheduler{}
+				uni.TrafficQueues = []*tech_profile.TrafficQueue{}
+			}
+		}
+	}
+}
+
+// clearOnuTrafficConfig removes the schedulers and queues from the UNIs of an ONU
+func (o *OltDevice) clearOnuTrafficConfig(onu *Onu) {
+	o.trafficLock.Lock()
+	defer o.trafficLock.Unlock()
+
+	for _, uni := range onu.UniPorts {
+		uni.TrafficSchedulers = []*tech_profile.TrafficScheduler{}
+		uni.TrafficQueues = []*tech_profile.TrafficQueue{}
+	}
+}
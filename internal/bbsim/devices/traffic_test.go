@@ -0,0 +1,148 @@
+/*
+ * Copyright 2018-present Open Networking Foundation
+
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+
+ * http://www.apache.org/licenses/LICENSE-2.0
+
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package devices
+
+import (
+	"context"
+	"testing"
+
+	"github.com/opencord/voltha-protos/v2/go/tech_profile"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"gotest.tools/assert"
+)
+
+func createTestSchedulers(intfId uint32, onuId uint32, allocIds ...uint32) *tech_profile.TrafficSchedulers {
+	tss := &tech_profile.TrafficSchedulers{
+		IntfId: intfId,
+		OnuId:  onuId,
+		UniId:  0,
+	}
+	for _, id := range allocIds {
+		tss.TrafficScheds = append(tss.TrafficScheds, &tech_profile.TrafficScheduler{
+			Direction: tech_profile.Direction_UPSTREAM,
+			AllocId:   id,
+		})
+	}
+	return tss
+}
+
+func createTestQueues(intfId uint32, onuId uint32, gemportIds ...uint32) *tech_profile.TrafficQueues {
+	tqs := &tech_profile.TrafficQueues{
+		IntfId: intfId,
+		OnuId:  onuId,
+		UniId:  0,
+	}
+	for _, id := range gemportIds {
+		tqs.TrafficQueues = append(tqs.TrafficQueues, &tech_profile.TrafficQueue{
+			Direction: tech_profile.Direction_UPSTREAM,
+			GemportId: id,
+			PbitMap:   "0b11111111",
+		})
+	}
+	return tqs
+}
+
+func Test_Olt_CreateTrafficSchedulers(t *testing.T) {
+	olt := createMockOlt(1, 2)
+	onu := olt.Pons[0].Onus[0]
+
+	_, err := olt.CreateTrafficSchedulers(context.TODO(), createTestSchedulers(0, onu.ID, 1024))
+	assert.NilError(t, err)
+
+	schedulers := olt.GetTrafficSchedulers(onu.UniPorts[0])
+	assert.Equal(t, len(schedulers), 1)
+	assert.Equal(t, schedulers[0].AllocId, uint32(1024))
+}
+
+func Test_Olt_CreateTrafficSchedulers_Errors(t *testing.T) {
+	olt := createMockOlt(1, 2)
+	onu := olt.Pons[0].Onus[0]
+	other := olt.Pons[0].Onus[1]
+
+	_, err := olt.CreateTrafficSchedulers(context.TODO(), createTestSchedulers(0, onu.ID, 1024))
+	assert.NilError(t, err)
+
+	// the alloc-id is already used by another ONU on the same PON
+	_, err = olt.CreateTrafficSchedulers(context.TODO(), createTestSchedulers(0, other.ID, 1024))
+	assert.Equal(t, status.Code(err), codes.AlreadyExists)
+
+	// the alloc-id is listed twice
+	_, err = olt.CreateTrafficSchedulers(context.TODO(), createTestSchedulers(0, other.ID, 1025, 1025))
+	assert.Equal(t, status.Code(err), codes.AlreadyExists)
+
+	// the alloc-id is outside the range reported in GetDeviceInfo
+	_, err = olt.CreateTrafficSchedulers(context.TODO(), createTestSchedulers(0, other.ID, 100))
+	assert.Equal(t, status.Code(err), codes.InvalidArgument)
+
+	// the ONU does not exist
+	_, err = olt.CreateTrafficSchedulers(context.TODO(), createTestSchedulers(0, 64, 1026))
+	assert.Equal(t, status.Code(err), codes.NotFound)
+
+	// nothing is stored when the request is rejected
+	assert.Equal(t, len(olt.GetTrafficSchedulers(other.UniPorts[0])), 0)
+}
+
+func Test_Olt_CreateTrafficQueues(t *testing.T) {
+	olt := createMockOlt(1, 1)
+	onu := olt.Pons[0].Onus[0]
+
+	// a queue can't be created without a scheduler
+	_, err := olt.CreateTrafficQueues(context.TODO(), createTestQueues(0, onu.ID, 1024))
+	assert.Equal(t, status.Code(err), codes.FailedPrecondition)
+
+	_, err = olt.CreateTrafficSchedulers(context.TODO(), createTestSchedulers(0, onu.ID, 1024))
+	assert.NilError(t, err)
+
+	_, err = olt.CreateTrafficQueues(context.TODO(), createTestQueues(0, onu.ID, 1024, 1025))
+	assert.NilError(t, err)
+	assert.Equal(t, len(olt.GetTrafficQueues(onu.UniPorts[0])), 2)
+
+	_, err = olt.CreateTrafficQueues(context.TODO(), createTestQueues(0, onu.ID, 1025))
+	assert.Equal(t, status.Code(err), codes.AlreadyExists)
+
+	_, err = olt.CreateTrafficQueues(context.TODO(), createTestQueues(0, onu.ID, 1000))
+	assert.Equal(t, status.Code(err), codes.InvalidArgument)
+}
+
+func Test_Olt_RemoveTrafficSchedulersAndQueues(t *testing.T) {
+	olt := createMockOlt(1, 1)
+	onu := olt.Pons[0].Onus[0]
+
+	_, err := olt.CreateTrafficSchedulers(context.TODO(), createTestSchedulers(0, onu.ID, 1024))
+	assert.NilError(t, err)
+	_, err = olt.CreateTrafficQueues(context.TODO(), createTestQueues(0, onu.ID, 1024))
+	assert.NilError(t, err)
+
+	// the queues have to be removed first
+	_, err = olt.RemoveTrafficSchedulers(context.TODO(), createTestSchedulers(0, onu.ID, 1024))
+	assert.Equal(t, status.Code(err), codes.FailedPrecondition)
+
+	_, err = olt.RemoveTrafficQueues(context.TODO(), createTestQueues(0, onu.ID, 1024))
+	assert.NilError(t, err)
+	assert.Equal(t, len(olt.GetTrafficQueues(onu.UniPorts[0])), 0)
+
+	_, err = olt.RemoveTrafficQueues(context.TODO(), createTestQueues(0, onu.ID, 1024))
+	assert.Equal(t, status.Code(err), codes.NotFound)
+
+	_, err = olt.RemoveTrafficSchedulers(context.TODO(), createTestSchedulers(0, onu.ID, 1024))
+	assert.NilError(t, err)
+	assert.Equal(t, len(olt.GetTrafficSchedulers(onu.UniPorts[0])), 0)
+
+	_, err = olt.RemoveTrafficSchedulers(context.TODO(), createTestSchedulers(0, onu.ID, 1024))
+	assert.Equal(t, status.Code(err), codes.NotFound)
+}
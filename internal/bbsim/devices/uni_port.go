@@ -0,0 +1,167 @@
+/*
+ * Copyright 2018-present Open Networking Foundation
+
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+
+ * http://www.apache.org/licenses/LICENSE-2.0
+
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package devices
+
+import (
+	"errors"
+	"net"
+
+	"github.com/looplab/fsm"
+	"github.com/opencord/voltha-protos/v2/go/tech_profile"
+	log "github.com/sirupsen/logrus"
+)
+
+var uniLogger = log.WithFields(log.Fields{
+	"module": "UNI",
+})
+
+// UniPort is a subscriber facing port on an ONU,
+// each UNI runs its own EAPOL and DHCP clients
+type UniPort struct {
+	ID        uint32
+	Onu       *Onu
+	HwAddress net.HardwareAddr
+	CTag      int
+
+	// PortNo comes with flows and it's used when sending packetIndications
+	PortNo           uint32
+	DhcpFlowReceived bool
+
+	// NOTE this state machine is used to activate the EAPOL and DHCP clients
+	InternalState *fsm.FSM
+
+	// T-CONTs and GEM ports installed by VOLTHA via the tech profile,
+	// they are protected by the OLT trafficLock
+	TrafficSchedulers []*tech_profile.TrafficScheduler
+	TrafficQueues     []*tech_profile.TrafficQueue
+}
+
+// uniMacAddress returns the MAC address of a UNI,
+// the first UNI uses the ONU MAC Address so that single UNI setups are not affected
+func uniMacAddress(onuMac net.HardwareAddr, uniId uint32) net.HardwareAddr {
+	mac := make(net.HardwareAddr, len(onuMac))
+	copy(mac, onuMac)
+	mac[3] = mac[3] + byte(uniId)
+	return mac
+}
+
+func CreateUniPort(onu *Onu, id uint32) *UniPort {
+	u := UniPort{
+		ID:               id,
+		Onu:              onu,
+		HwAddress:        uniMacAddress(onu.HwAddress, id),
+		CTag:             onu.CTag + int(id),
+		PortNo:           0,
+		DhcpFlowReceived: false,
+	}
+
+	u.InternalState = fsm.NewFSM(
+		"created",
+		fsm.Events{
+			{Name: "enable", Src: []string{"created", "disabled"}, Dst: "enabled"},
+			{Name: "receive_eapol_flow", Src: []string{"enabled", "gem_port_added"}, Dst: "eapol_flow_received"},
+			{Name: "add_gem_port", Src: []string{"enabled", "eapol_flow_received"}, Dst: "gem_port_added"},
+			{Name: "disable", Src: []string{"enabled", "eapol_flow_received", "gem_port_added", "auth_started", "eap_start_sent", "eap_response_identity_sent", "eap_response_challenge_sent", "eap_response_success_received", "auth_failed", "dhcp_started", "dhcp_discovery_sent", "dhcp_request_sent", "dhcp_ack_received", "dhcp_failed"}, Dst: "disabled"},
+			// EAPOL
+			{Name: "start_auth", Src: []string{"eapol_flow_received", "gem_port_added", "eap_start_sent", "eap_response_identity_sent", "eap_response_challenge_sent", "eap_response_success_received", "auth_failed", "dhcp_ack_received", "dhcp_failed"}, Dst: "auth_started"},
+			{Name: "eap_start_sent", Src: []string{"auth_started"}, Dst: "eap_start_sent"},
+			{Name: "eap_response_identity_sent", Src: []string{"eap_start_sent"}, Dst: "eap_response_identity_sent"},
+			{Name: "eap_response_challenge_sent", Src: []string{"eap_response_identity_sent"}, Dst: "eap_response_challenge_sent"},
+			{Name: "eap_response_success_received", Src: []string{"eap_response_challenge_sent"}, Dst: "eap_response_success_received"},
+			{Name: "auth_failed", Src: []string{"auth_started", "eap_start_sent", "eap_response_identity_sent", "eap_response_challenge_sent"}, Dst: "auth_failed"},
+			// DHCP
+			{Name: "start_dhcp", Src: []string{"eap_response_success_received", "dhcp_discovery_sent", "dhcp_request_sent", "dhcp_ack_received", "dhcp_failed"}, Dst: "dhcp_started"},
+			{Name: "dhcp_discovery_sent", Src: []string{"dhcp_started"}, Dst: "dhcp_discovery_sent"},
+			{Name: "dhcp_request_sent", Src: []string{"dhcp_discovery_sent"}, Dst: "dhcp_request_sent"},
+			{Name: "dhcp_ack_received", Src: []string{"dhcp_request_sent"}, Dst: "dhcp_ack_received"},
+			{Name: "dhcp_failed", Src: []string{"dhcp_started", "dhcp_discovery_sent", "dhcp_request_sent"}, Dst: "dhcp_failed"},
+		},
+		fsm.Callbacks{
+			"enter_state": func(e *fsm.Event) {
+				u.logStateChange(e.Src, e.Dst)
+			},
+			"enter_auth_started": func(e *fsm.Event) {
+				msg := Message{
+					Type: StartEAPOL,
+					Data: PacketMessage{
+						PonPortID: u.Onu.PonPortID,
+						OnuID:     u.Onu.ID,
+						UniID:     u.ID,
+					},
+				}
+				u.Onu.SendMessage(msg)
+			},
+			"enter_auth_failed": func(e *fsm.Event) {
+				uniLogger.WithFields(log.Fields{
+					"OnuId":  u.Onu.ID,
+					"IntfId": u.Onu.PonPortID,
+					"OnuSn":  u.Onu.Sn(),
+					"UniId":  u.ID,
+				}).Errorf("UNI failed to authenticate!")
+			},
+			"before_start_dhcp": func(e *fsm.Event) {
+				if u.DhcpFlowReceived == false {
+					e.Cancel(errors.New("cannot-go-to-dhcp-started-as-dhcp-flow-is-missing"))
+				}
+			},
+			"enter_dhcp_started": func(e *fsm.Event) {
+				msg := Message{
+					Type: StartDHCP,
+					Data: PacketMessage{
+						PonPortID: u.Onu.PonPortID,
+						OnuID:     u.Onu.ID,
+						UniID:     u.ID,
+					},
+				}
+				u.Onu.SendMessage(msg)
+			},
+			"enter_dhcp_failed": func(e *fsm.Event) {
+				uniLogger.WithFields(log.Fields{
+					"OnuId":  u.Onu.ID,
+					"IntfId": u.Onu.PonPortID,
+					"OnuSn":  u.Onu.Sn(),
+					"UniId":  u.ID,
+				}).Errorf("UNI failed to DHCP!")
+			},
+		},
+	)
+
+	return &u
+}
+
+func (u *UniPort) logStateChange(src string, dst string) {
+	uniLogger.WithFields(log.Fields{
+		"OnuId":  u.Onu.ID,
+		"IntfId": u.Onu.PonPortID,
+		"OnuSn":  u.Onu.Sn(),
+		"UniId":  u.ID,
+	}).Debugf("Changing UNI InternalState from %s to %s", src, dst)
+}
+
+func (u *UniPort) storePortNumber(portNo uint32) {
+	if u.PortNo != portNo {
+		uniLogger.WithFields(log.Fields{
+			"IntfId":       u.Onu.PonPortID,
+			"OnuId":        u.Onu.ID,
+			"SerialNumber": u.Onu.Sn(),
+			"UniId":        u.ID,
+			"UniPortNo":    u.PortNo,
+			"FlowPortNo":   portNo,
+		}).Debug("Storing UNI portNo")
+		u.PortNo = portNo
+	}
+}
@@ -0,0 +1,146 @@
+/*
+ * Copyright 2018-present Open Networking Foundation
+
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+
+ * http://www.apache.org/licenses/LICENSE-2.0
+
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package devices
+
+import (
+	"testing"
+
+	"gotest.tools/assert"
+)
+
+func Test_Uni_StateMachine_eapol_start_eap_flow(t *testing.T) {
+	uni := createTestOnu().UniPorts[0]
+
+	uni.InternalState.SetState("enabled")
+
+	// TODO we need to add a check so that you can't go from eapol_flow_received
+	// to auth_started without passing through gem_port_added
+	// (see start_dhcp for an example)
+
+	assert.Equal(t, uni.InternalState.Current(), "enabled")
+	uni.InternalState.Event("receive_eapol_flow")
+	assert.Equal(t, uni.InternalState.Current(), "eapol_flow_received")
+	uni.InternalState.Event("add_gem_port")
+	assert.Equal(t, uni.InternalState.Current(), "gem_port_added")
+	uni.InternalState.Event("start_auth")
+	assert.Equal(t, uni.InternalState.Current(), "auth_started")
+}
+
+func Test_Uni_StateMachine_eapol_start_gem_port(t *testing.T) {
+	uni := createTestOnu().UniPorts[0]
+
+	uni.InternalState.SetState("enabled")
+
+	// TODO we need to add a check so that you can't go from gem_port_added
+	// to auth_started without passing through eapol_flow_received
+	// (see start_dhcp for an example)
+
+	assert.Equal(t, uni.InternalState.Current(), "enabled")
+	uni.InternalState.Event("add_gem_port")
+	assert.Equal(t, uni.InternalState.Current(), "gem_port_added")
+	uni.InternalState.Event("receive_eapol_flow")
+	assert.Equal(t, uni.InternalState.Current(), "eapol_flow_received")
+	uni.InternalState.Event("start_auth")
+	assert.Equal(t, uni.InternalState.Current(), "auth_started")
+}
+
+func Test_Uni_StateMachine_eapol_states(t *testing.T) {
+	uni := createTestOnu().UniPorts[0]
+
+	uni.InternalState.SetState("auth_started")
+
+	assert.Equal(t, uni.InternalState.Current(), "auth_started")
+	uni.InternalState.Event("eap_start_sent")
+	assert.Equal(t, uni.InternalState.Current(), "eap_start_sent")
+	uni.InternalState.Event("eap_response_identity_sent")
+	assert.Equal(t, uni.InternalState.Current(), "eap_response_identity_sent")
+	uni.InternalState.Event("eap_response_challenge_sent")
+	assert.Equal(t, uni.InternalState.Current(), "eap_response_challenge_sent")
+	uni.InternalState.Event("eap_response_success_received")
+	assert.Equal(t, uni.InternalState.Current(), "eap_response_success_received")
+
+	// test that we can retrigger EAPOL
+	states := []string{"eap_start_sent", "eap_response_identity_sent", "eap_response_challenge_sent", "eap_response_success_received", "auth_failed", "dhcp_ack_received", "dhcp_failed"}
+	for _, state := range states {
+		uni.InternalState.SetState(state)
+		err := uni.InternalState.Event("start_auth")
+		assert.Equal(t, err, nil)
+		assert.Equal(t, uni.InternalState.Current(), "auth_started")
+	}
+}
+
+func Test_Uni_StateMachine_dhcp_start(t *testing.T) {
+	uni := createTestOnu().UniPorts[0]
+	uni.DhcpFlowReceived = true
+
+	uni.InternalState.SetState("eap_response_success_received")
+	assert.Equal(t, uni.InternalState.Current(), "eap_response_success_received")
+
+	uni.InternalState.Event("start_dhcp")
+
+	assert.Equal(t, uni.InternalState.Current(), "dhcp_started")
+}
+
+func Test_Uni_StateMachine_dhcp_start_error(t *testing.T) {
+	uni := createTestOnu().UniPorts[0]
+
+	uni.InternalState.SetState("eap_response_success_received")
+	assert.Equal(t, uni.InternalState.Current(), "eap_response_success_received")
+
+	err := uni.InternalState.Event("start_dhcp")
+
+	assert.Equal(t, uni.InternalState.Current(), "eap_response_success_received")
+	assert.Equal(t, err.Error(), "transition canceled with error: cannot-go-to-dhcp-started-as-dhcp-flow-is-missing")
+}
+
+func Test_Uni_StateMachine_dhcp_states(t *testing.T) {
+	uni := createTestOnu().UniPorts[0]
+
+	uni.DhcpFlowReceived = false
+
+	uni.InternalState.SetState("dhcp_started")
+
+	assert.Equal(t, uni.InternalState.Current(), "dhcp_started")
+	uni.InternalState.Event("dhcp_discovery_sent")
+	assert.Equal(t, uni.InternalState.Current(), "dhcp_discovery_sent")
+	uni.InternalState.Event("dhcp_request_sent")
+	assert.Equal(t, uni.InternalState.Current(), "dhcp_request_sent")
+	uni.InternalState.Event("dhcp_ack_received")
+	assert.Equal(t, uni.InternalState.Current(), "dhcp_ack_received")
+
+	// test that we can retrigger DHCP
+	uni.DhcpFlowReceived = true
+	states := []string{"eap_response_success_received", "dhcp_discovery_sent", "dhcp_request_sent", "dhcp_ack_received", "dhcp_failed"}
+	for _, state := range states {
+		uni.InternalState.SetState(state)
+		err := uni.InternalState.Event("start_dhcp")
+		assert.Equal(t, err, nil)
+		assert.Equal(t, uni.InternalState.Current(), "dhcp_started")
+	}
+}
+
+func Test_Uni_MacAddress(t *testing.T) {
+	onu := createTestOnu()
+	onu.UniPorts = append(onu.UniPorts, CreateUniPort(onu, 1))
+
+	// the first UNI keeps the ONU MAC Address
+	assert.Equal(t, onu.UniPorts[0].HwAddress.String(), onu.HwAddress.String())
+	assert.Equal(t, onu.UniPorts[1].HwAddress.String(), "2e:60:70:14:01:01")
+
+	assert.Equal(t, onu.UniPorts[0].CTag, onu.CTag)
+	assert.Equal(t, onu.UniPorts[1].CTag, onu.CTag+1)
+}
@@ -24,6 +24,7 @@ import (
 	"github.com/google/gopacket/layers"
 	"github.com/looplab/fsm"
 	bbsim "github.com/opencord/bbsim/internal/bbsim/types"
+	"github.com/opencord/bbsim/internal/common"
 	omci "github.com/opencord/omci-sim"
 	"github.com/opencord/voltha-protos/v2/go/openolt"
 	log "github.com/sirupsen/logrus"
@@ -37,9 +38,21 @@ var eapolLogger = log.WithFields(log.Fields{
 var eapolVersion uint8 = 1
 var GetGemPortId = omci.GetGemPortId
 
+// Credentials are used by a UNI to authenticate (and by BBR to validate the challenge)
+type Credentials struct {
+	Username string
+	Password string
+}
+
+// DefaultCredentials are used by the ONUs that don't have credentials in the topology file
+var DefaultCredentials = Credentials{
+	Username: "user",
+	Password: "password",
+}
+
 func sendEapolPktIn(msg bbsim.ByteMsg, portNo uint32, stream openolt.Openolt_EnableIndicationServer) {
 	// FIXME unify sendDHCPPktIn and sendEapolPktIn methods
-	gemid, err := omci.GetGemPortId(msg.IntfId, msg.OnuId)
+	gemid, err := GetGemPortId(common.OmciSimIntfId(msg.OltId, msg.IntfId), msg.OnuId)
 	if err != nil {
 		eapolLogger.WithFields(log.Fields{
 			"OnuId":  msg.OnuId,
@@ -61,10 +74,10 @@ func sendEapolPktIn(msg bbsim.ByteMsg, portNo uint32, stream openolt.Openolt_Ena
 	}
 }
 
-func getMD5Data(eap *layers.EAP) []byte {
+func getMD5Data(eap *layers.EAP, password string) []byte {
 	i := byte(eap.Id)
 	C := []byte(eap.BaseLayer.Contents)[6:]
-	P := []byte{i, 0x70, 0x61, 0x73, 0x73, 0x77, 0x6f, 0x72, 0x64} // "password"
+	P := append([]byte{i}, []byte(password)...)
 	data := md5.Sum(append(P, C...))
 	ret := make([]byte, 16)
 	for j := 0; j < 16; j++ {
@@ -104,12 +117,12 @@ func createEAPIdentityRequest(eapId uint8) *layers.EAP {
 	return &eap
 }
 
-func createEAPIdentityResponse(eapId uint8) *layers.EAP {
+func createEAPIdentityResponse(eapId uint8, username string) *layers.EAP {
 	eap := layers.EAP{Code: layers.EAPCodeResponse,
 		Id:       eapId,
-		Length:   9,
+		Length:   uint16(5 + len(username)),
 		Type:     layers.EAPTypeIdentity,
-		TypeData: []byte{0x75, 0x73, 0x65, 0x72}}
+		TypeData: []byte(username)}
 	return &eap
 }
 
@@ -123,12 +136,12 @@ func createEAPSuccess(eapId uint8) *layers.EAP {
 	return &eap
 }
 
-func createEAPOLPkt(eap *layers.EAP, onuId uint32, intfId uint32) []byte {
+func createEAPOLPkt(eap *layers.EAP, macAddress net.HardwareAddr) []byte {
 	buffer := gopacket.NewSerializeBuffer()
 	options := gopacket.SerializeOptions{}
 
 	ethernetLayer := &layers.Ethernet{
-		SrcMAC:       net.HardwareAddr{0x2e, 0x60, 0x70, 0x13, byte(intfId), byte(onuId)},
+		SrcMAC:       macAddress,
 		DstMAC:       net.HardwareAddr{0x01, 0x80, 0xC2, 0x00, 0x00, 0x03},
 		EthernetType: layers.EthernetTypeEAPOL,
 	}
@@ -188,10 +201,10 @@ func updateAuthFailed(onuId uint32, ponPortId uint32, serialNumber string, onuSt
 	return nil
 }
 
-func SendEapStart(onuId uint32, ponPortId uint32, serialNumber string, portNo uint32, macAddress net.HardwareAddr, onuStateMachine *fsm.FSM, stream bbsim.Stream) error {
+func SendEapStart(oltId int, onuId uint32, ponPortId uint32, serialNumber string, portNo uint32, macAddress net.HardwareAddr, onuStateMachine *fsm.FSM, stream bbsim.Stream) error {
 
 	// send the packet (hacked together)
-	gemId, err := GetGemPortId(ponPortId, onuId)
+	gemId, err := GetGemPortId(common.OmciSimIntfId(oltId, ponPortId), onuId)
 	if err != nil {
 		eapolLogger.WithFields(log.Fields{
 			"OnuId":  onuId,
@@ -265,7 +278,7 @@ func SendEapStart(onuId uint32, ponPortId uint32, serialNumber string, portNo ui
 	return nil
 }
 
-func HandleNextPacket(onuId uint32, ponPortId uint32, serialNumber string, portNo uint32, onuStateMachine *fsm.FSM, pkt gopacket.Packet, stream openolt.Openolt_EnableIndicationServer, client openolt.OpenoltClient) {
+func HandleNextPacket(oltId int, onuId uint32, ponPortId uint32, serialNumber string, portNo uint32, macAddress net.HardwareAddr, credentials Credentials, onuStateMachine *fsm.FSM, pkt gopacket.Packet, stream openolt.Openolt_EnableIndicationServer, client openolt.OpenoltClient) {
 
 	eap, eapErr := extractEAP(pkt)
 
@@ -298,7 +311,7 @@ func HandleNextPacket(onuId uint32, ponPortId uint32, serialNumber string, portN
 
 	if eapol != nil && eapol.Type == layers.EAPOLTypeStart {
 		identityRequest := createEAPIdentityRequest(1)
-		pkt := createEAPOLPkt(identityRequest, onuId, ponPortId)
+		pkt := createEAPOLPkt(identityRequest, macAddress)
 
 		if err := sendEapolPktOut(client, ponPortId, onuId, pkt); err != nil {
 			log.WithFields(log.Fields{
@@ -317,10 +330,11 @@ func HandleNextPacket(onuId uint32, ponPortId uint32, serialNumber string, portN
 		}).Infof("Sent EAPIdentityRequest packet")
 		return
 	} else if eap.Code == layers.EAPCodeRequest && eap.Type == layers.EAPTypeIdentity {
-		reseap := createEAPIdentityResponse(eap.Id)
-		pkt := createEAPOLPkt(reseap, onuId, ponPortId)
+		reseap := createEAPIdentityResponse(eap.Id, credentials.Username)
+		pkt := createEAPOLPkt(reseap, macAddress)
 
 		msg := bbsim.ByteMsg{
+			OltId:  oltId,
 			IntfId: ponPortId,
 			OnuId:  onuId,
 			Bytes:  pkt,
@@ -342,10 +356,10 @@ func HandleNextPacket(onuId uint32, ponPortId uint32, serialNumber string, portN
 		}
 
 	} else if eap.Code == layers.EAPCodeResponse && eap.Type == layers.EAPTypeIdentity {
-		senddata := getMD5Data(eap)
+		senddata := getMD5Data(eap, credentials.Password)
 		senddata = append([]byte{0x10}, senddata...)
 		challengeRequest := createEAPChallengeRequest(eap.Id, senddata)
-		pkt := createEAPOLPkt(challengeRequest, onuId, ponPortId)
+		pkt := createEAPOLPkt(challengeRequest, macAddress)
 
 		if err := sendEapolPktOut(client, ponPortId, onuId, pkt); err != nil {
 			log.WithFields(log.Fields{
@@ -363,12 +377,13 @@ func HandleNextPacket(onuId uint32, ponPortId uint32, serialNumber string, portN
 		}).Infof("Sent EAPChallengeRequest packet")
 		return
 	} else if eap.Code == layers.EAPCodeRequest && eap.Type == layers.EAPTypeOTP {
-		senddata := getMD5Data(eap)
+		senddata := getMD5Data(eap, credentials.Password)
 		senddata = append([]byte{0x10}, senddata...)
 		sendeap := createEAPChallengeResponse(eap.Id, senddata)
-		pkt := createEAPOLPkt(sendeap, onuId, ponPortId)
+		pkt := createEAPOLPkt(sendeap, macAddress)
 
 		msg := bbsim.ByteMsg{
+			OltId:  oltId,
 			IntfId: ponPortId,
 			OnuId:  onuId,
 			Bytes:  pkt,
@@ -390,7 +405,7 @@ func HandleNextPacket(onuId uint32, ponPortId uint32, serialNumber string, portN
 		}
 	} else if eap.Code == layers.EAPCodeResponse && eap.Type == layers.EAPTypeOTP {
 		eapSuccess := createEAPSuccess(eap.Id)
-		pkt := createEAPOLPkt(eapSuccess, onuId, ponPortId)
+		pkt := createEAPOLPkt(eapSuccess, macAddress)
 
 		if err := sendEapolPktOut(client, ponPortId, onuId, pkt); err != nil {
 			log.WithFields(log.Fields{
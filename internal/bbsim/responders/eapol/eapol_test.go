@@ -73,7 +73,9 @@ func TestSendEapStartSuccess(t *testing.T) {
 	old := GetGemPortId
 	defer func() { GetGemPortId = old }()
 
+	// the ONUs of different OLTs have separate states in omci-sim
 	GetGemPortId = func(intfId uint32, onuId uint32) (uint16, error) {
+		assert.Equal(t, intfId, uint32(1)<<16|ponPortId)
 		return gemPortId, nil
 	}
 
@@ -82,7 +84,7 @@ func TestSendEapStartSuccess(t *testing.T) {
 		fail:  false,
 	}
 
-	if err := SendEapStart(onuId, ponPortId, serialNumber, portNo, macAddress, eapolStateMachine, stream); err != nil {
+	if err := SendEapStart(1, onuId, ponPortId, serialNumber, portNo, macAddress, eapolStateMachine, stream); err != nil {
 		t.Errorf("SendEapStart returned an error: %v", err)
 		t.Fail()
 	}
@@ -115,7 +117,7 @@ func TestSendEapStartFailNoGemPort(t *testing.T) {
 		fail:  false,
 	}
 
-	err := SendEapStart(onuId, ponPortId, serialNumber, portNo, macAddress, eapolStateMachine, stream)
+	err := SendEapStart(0, onuId, ponPortId, serialNumber, portNo, macAddress, eapolStateMachine, stream)
 	if err == nil {
 		t.Errorf("SendEapStart did not return an error")
 		t.Fail()
@@ -143,7 +145,7 @@ func TestSendEapStartFailStreamError(t *testing.T) {
 		fail:  true,
 	}
 
-	err := SendEapStart(onuId, ponPortId, serialNumber, portNo, macAddress, eapolStateMachine, stream)
+	err := SendEapStart(0, onuId, ponPortId, serialNumber, portNo, macAddress, eapolStateMachine, stream)
 	if err == nil {
 		t.Errorf("SendEapStart did not return an error")
 		t.Fail()
@@ -156,6 +158,12 @@ func TestSendEapStartFailStreamError(t *testing.T) {
 
 // TODO test eapol.HandleNextPacket
 
+func TestCreateEAPIdentityResponse(t *testing.T) {
+	eap := createEAPIdentityResponse(1, "subscriber")
+	assert.Equal(t, eap.Length, uint16(15))
+	assert.Equal(t, string(eap.TypeData), "subscriber")
+}
+
 func TestUpdateAuthFailed(t *testing.T) {
 
 	var onuId uint32 = 1
@@ -18,7 +18,11 @@ package sadis
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
 	"net/http"
+	"strconv"
 	"strings"
 	"sync"
 
@@ -33,7 +37,7 @@ var sadisLogger = log.WithFields(log.Fields{
 })
 
 type sadisServer struct {
-	olt *devices.OltDevice
+	olts []*devices.OltDevice
 }
 
 // bandwidthProfiles contains some dummy profiles
@@ -99,8 +103,8 @@ type SadisBWPEntry struct {
 }
 
 // GetSadisConfig returns a full SADIS configuration struct ready to be marshalled into JSON
-func GetSadisConfig(olt *devices.OltDevice) *SadisConfig {
-	sadisEntries, _ := GetSadisEntries(olt)
+func GetSadisConfig(olts []*devices.OltDevice) *SadisConfig {
+	sadisEntries, _ := GetSadisEntries(olts)
 	bwpEntries := getBWPEntries()
 
 	conf := &SadisConfig{}
@@ -110,11 +114,12 @@ func GetSadisConfig(olt *devices.OltDevice) *SadisConfig {
 	return conf
 }
 
-func GetSadisEntries(olt *devices.OltDevice) (*SadisEntries, error) {
-	solt, _ := GetOltEntry(olt)
-
+func GetSadisEntries(olts []*devices.OltDevice) (*SadisEntries, error) {
 	entries := []interface{}{}
-	entries = append(entries, solt)
+	for _, olt := range olts {
+		solt, _ := GetOltEntry(olt)
+		entries = append(entries, solt)
+	}
 
 	a := strings.Split(common.Options.BBSim.SadisRestAddress, ":")
 	port := a[len(a)-1]
@@ -133,12 +138,24 @@ func GetSadisEntries(olt *devices.OltDevice) (*SadisEntries, error) {
 	return sadis, nil
 }
 
+// getOltAddress returns the address of the OpenOLT server of an OLT, the OLTs listen on different ports.
+// If the server listens on all the interfaces the IP of the NNI interface is reported
+func getOltAddress(olt *devices.OltDevice) string {
+	host, port, err := net.SplitHostPort(olt.Options.OpenOltAddress)
+	if err != nil {
+		return olt.Options.OpenOltAddress
+	}
+	if ip := net.ParseIP(host); ip == nil || ip.IsUnspecified() {
+		host, _ = common.GetIPAddr("nni")
+	}
+	return net.JoinHostPort(host, port)
+}
+
 func GetOltEntry(olt *devices.OltDevice) (*SadisOltEntry, error) {
-	ip, _ := common.GetIPAddr("nni") // TODO verify which IP to report
 	solt := &SadisOltEntry{
 		ID:                 olt.SerialNumber,
-		HardwareIdentifier: common.Options.Olt.DeviceId,
-		IPAddress:          ip,
+		HardwareIdentifier: olt.Options.DeviceId,
+		IPAddress:          getOltAddress(olt),
 		NasID:              olt.SerialNumber,
 		UplinkPort:         1048576, // TODO currently assumes we only have on NNI port
 	}
@@ -147,9 +164,19 @@ func GetOltEntry(olt *devices.OltDevice) (*SadisOltEntry, error) {
 
 func GetOnuEntry(olt *devices.OltDevice, onu *devices.Onu, uniId string) (*SadisOnuEntry, error) {
 	uniSuffix := "-" + uniId
+
+	// the subscriber ID uses 1-based UNI numbers,
+	// fallback to the ONU C-Tag if the UNI does not exist
+	cTag := onu.CTag
+	if n, err := strconv.Atoi(uniId); err == nil && n > 0 {
+		if uni, err := onu.GetUniById(uint32(n - 1)); err == nil {
+			cTag = uni.CTag
+		}
+	}
+
 	sonu := &SadisOnuEntry{
 		ID:                         onu.Sn() + uniSuffix,
-		CTag:                       onu.CTag,
+		CTag:                       cTag,
 		STag:                       onu.STag,
 		NasPortID:                  onu.Sn() + uniSuffix,
 		CircuitID:                  onu.Sn() + uniSuffix,
@@ -182,7 +209,7 @@ func getBWPEntries() *BandwidthProfileEntries {
 func (s *sadisServer) ServeBaseConfig(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
-	sadisConf := GetSadisConfig(s.olt)
+	sadisConf := GetSadisConfig(s.olts)
 
 	sadisJSON, _ := json.Marshal(sadisConf)
 	sadisLogger.Tracef("SADIS JSON: %s", sadisJSON)
@@ -194,14 +221,17 @@ func (s *sadisServer) ServeBaseConfig(w http.ResponseWriter, r *http.Request) {
 func (s *sadisServer) ServeStaticConfig(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
-	sadisConf := GetSadisConfig(s.olt)
+	sadisConf := GetSadisConfig(s.olts)
 
 	sadisConf.Sadis.Integration.URL = ""
-	for i := range s.olt.Pons {
-		for _, onu := range s.olt.Pons[i].Onus {
-			// FIXME currently we only support one UNI per ONU
-			sonu, _ := GetOnuEntry(s.olt, onu, "1")
-			sadisConf.Sadis.Entries = append(sadisConf.Sadis.Entries, sonu)
+	for _, olt := range s.olts {
+		for i := range olt.Pons {
+			for _, onu := range olt.Pons[i].GetOnus() {
+				for _, uni := range onu.UniPorts {
+					sonu, _ := GetOnuEntry(olt, onu, strconv.Itoa(int(uni.ID+1)))
+					sadisConf.Sadis.Entries = append(sadisConf.Sadis.Entries, sonu)
+				}
+			}
 		}
 	}
 
@@ -219,17 +249,19 @@ func (s *sadisServer) ServeEntry(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 	vars := mux.Vars(r)
 
-	// check if the requested ID is for the OLT
-	if s.olt.SerialNumber == vars["ID"] {
-		sadisLogger.WithFields(log.Fields{
-			"OltSn": s.olt.SerialNumber,
-		}).Debug("Received SADIS OLT request")
+	// check if the requested ID is for one of the OLTs
+	for _, olt := range s.olts {
+		if olt.SerialNumber == vars["ID"] {
+			sadisLogger.WithFields(log.Fields{
+				"OltSn": olt.SerialNumber,
+			}).Debug("Received SADIS OLT request")
 
-		sadisConf, _ := GetOltEntry(s.olt)
+			sadisConf, _ := GetOltEntry(olt)
 
-		w.WriteHeader(http.StatusOK)
-		json.NewEncoder(w).Encode(sadisConf)
-		return
+			w.WriteHeader(http.StatusOK)
+			json.NewEncoder(w).Encode(sadisConf)
+			return
+		}
 	}
 
 	i := strings.Split(vars["ID"], "-") // split ID to get serial number and uni port
@@ -241,7 +273,7 @@ func (s *sadisServer) ServeEntry(w http.ResponseWriter, r *http.Request) {
 	}
 	sn, uni := i[0], i[len(i)-1]
 
-	onu, err := s.olt.FindOnuBySn(sn)
+	olt, onu, err := s.findOnuBySn(sn)
 	if err != nil {
 		w.WriteHeader(http.StatusNotFound)
 		w.Write([]byte("{}"))
@@ -258,7 +290,7 @@ func (s *sadisServer) ServeEntry(w http.ResponseWriter, r *http.Request) {
 		"OnuPortNo": uni,
 	}).Debug("Received SADIS request")
 
-	sadisConf, err := GetOnuEntry(s.olt, onu, uni)
+	sadisConf, err := GetOnuEntry(olt, onu, uni)
 
 	w.WriteHeader(http.StatusOK)
 	json.NewEncoder(w).Encode(sadisConf)
@@ -283,12 +315,22 @@ func (s *sadisServer) ServeBWPEntry(w http.ResponseWriter, r *http.Request) {
 	w.Write([]byte("{}"))
 }
 
-// StartRestServer starts REST server which returns a SADIS configuration for the currently simulated OLT
-func StartRestServer(olt *devices.OltDevice, wg *sync.WaitGroup) {
+// findOnuBySn looks for an ONU on all the OLTs, the ONU serial numbers contain the OLT ID so they are unique
+func (s *sadisServer) findOnuBySn(sn string) (*devices.OltDevice, *devices.Onu, error) {
+	for _, olt := range s.olts {
+		if onu, err := olt.FindOnuBySn(sn); err == nil {
+			return olt, onu, nil
+		}
+	}
+	return nil, nil, errors.New(fmt.Sprintf("cannot-find-onu-%s", sn))
+}
+
+// StartRestServer starts REST server which returns a SADIS configuration for the currently simulated OLTs
+func StartRestServer(olts []*devices.OltDevice, wg *sync.WaitGroup) {
 	addr := common.Options.BBSim.SadisRestAddress
 	sadisLogger.Infof("SADIS server listening on %s", addr)
 	s := &sadisServer{
-		olt: olt,
+		olts: olts,
 	}
 
 	router := mux.NewRouter().StrictSlash(true)
@@ -22,6 +22,7 @@ import (
 
 // deprecated, use OnuPacketOutMessage instead
 type ByteMsg struct {
+	OltId  int
 	IntfId uint32
 	OnuId  uint32
 	Bytes  []byte
@@ -0,0 +1,196 @@
+/*
+ * Copyright 2018-present Open Networking Foundation
+
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+
+ * http://www.apache.org/licenses/LICENSE-2.0
+
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package commands
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/jessevdk/go-flags"
+	pb "github.com/opencord/bbsim/api/bbsim"
+	"github.com/opencord/bbsim/internal/bbsimctl/config"
+	"github.com/opencord/bbsim/internal/common"
+	log "github.com/sirupsen/logrus"
+)
+
+type OnuAlarmTypeString string
+type OltAlarmTypeString string
+type OmciAlarmTypeString string
+type AlarmStatusString string
+
+type ONUAlarmRaise struct {
+	Args struct {
+		OnuSn     OnuSnString
+		AlarmType OnuAlarmTypeString
+	} `positional-args:"yes" required:"yes"`
+}
+
+type ONUAlarmClear struct {
+	Args struct {
+		OnuSn     OnuSnString
+		AlarmType OnuAlarmTypeString
+	} `positional-args:"yes" required:"yes"`
+}
+
+type ONUAlarmOptions struct {
+	Raise ONUAlarmRaise `command:"raise"`
+	Clear ONUAlarmClear `command:"clear"`
+}
+
+type ONUOmciAlarm struct {
+	UniID uint32 `long:"uni" description:"ID of the UNI the PPTP alarms and the UniOperState changes are sent for"`
+	Args  struct {
+		OnuSn     OnuSnString
+		AlarmType OmciAlarmTypeString
+		Status    AlarmStatusString
+	} `positional-args:"yes" required:"yes"`
+}
+
+type OltAlarmRaise struct {
+	Args struct {
+		AlarmType OltAlarmTypeString
+		IntfID    uint32
+	} `positional-args:"yes" required:"yes"`
+}
+
+type OltAlarmClear struct {
+	Args struct {
+		AlarmType OltAlarmTypeString
+		IntfID    uint32
+	} `positional-args:"yes" required:"yes"`
+}
+
+type OltAlarmOptions struct {
+	Raise OltAlarmRaise `command:"raise"`
+	Clear OltAlarmClear `command:"clear"`
+}
+
+func setOnuAlarm(onuSn OnuSnString, alarmType OnuAlarmTypeString, status string) error {
+	client, conn := connect()
+	defer conn.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), config.GlobalConfig.Grpc.Timeout)
+	defer cancel()
+	req := pb.AlarmRequest{
+		SerialNumber: string(onuSn),
+		AlarmType:    string(alarmType),
+		Status:       status,
+		OltID:        config.GlobalOptions.Olt,
+	}
+	res, err := client.SetOnuAlarm(ctx, &req)
+
+	if err != nil {
+		log.Fatalf("Cannot set alarm %s on ONU %s: %v", alarmType, onuSn, err)
+		return err
+	}
+
+	fmt.Println(fmt.Sprintf("[Status: %d] %s", res.StatusCode, res.Message))
+
+	return nil
+}
+
+func setOltAlarm(alarmType OltAlarmTypeString, intfId uint32, status string) error {
+	client, conn := connect()
+	defer conn.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), config.GlobalConfig.Grpc.Timeout)
+	defer cancel()
+	req := pb.OltAlarmRequest{
+		AlarmType:   string(alarmType),
+		InterfaceID: intfId,
+		Status:      status,
+		OltID:       config.GlobalOptions.Olt,
+	}
+	res, err := client.SetOltAlarm(ctx, &req)
+
+	if err != nil {
+		log.Fatalf("Cannot set alarm %s on interface %d: %v", alarmType, intfId, err)
+		return err
+	}
+
+	fmt.Println(fmt.Sprintf("[Status: %d] %s", res.StatusCode, res.Message))
+
+	return nil
+}
+
+func (options *ONUAlarmRaise) Execute(args []string) error {
+	return setOnuAlarm(options.Args.OnuSn, options.Args.AlarmType, common.AlarmStatusOn)
+}
+
+func (options *ONUAlarmClear) Execute(args []string) error {
+	return setOnuAlarm(options.Args.OnuSn, options.Args.AlarmType, common.AlarmStatusOff)
+}
+
+func (options *ONUOmciAlarm) Execute(args []string) error {
+	client, conn := connect()
+	defer conn.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), config.GlobalConfig.Grpc.Timeout)
+	defer cancel()
+	req := pb.OmciAlarmRequest{
+		SerialNumber: string(options.Args.OnuSn),
+		AlarmType:    string(options.Args.AlarmType),
+		UniID:        options.UniID,
+		Status:       string(options.Args.Status),
+		OltID:        config.GlobalOptions.Olt,
+	}
+	res, err := client.SendOnuOmciAlarm(ctx, &req)
+
+	if err != nil {
+		log.Fatalf("Cannot send OMCI alarm %s on ONU %s: %v", options.Args.AlarmType, options.Args.OnuSn, err)
+		return err
+	}
+
+	fmt.Println(fmt.Sprintf("[Status: %d] %s", res.StatusCode, res.Message))
+
+	return nil
+}
+
+func (options *OltAlarmRaise) Execute(args []string) error {
+	return setOltAlarm(options.Args.AlarmType, options.Args.IntfID, common.AlarmStatusOn)
+}
+
+func (options *OltAlarmClear) Execute(args []string) error {
+	return setOltAlarm(options.Args.AlarmType, options.Args.IntfID, common.AlarmStatusOff)
+}
+
+func completeAlarmType(types []string, match string) []flags.Completion {
+	list := make([]flags.Completion, 0)
+	for _, t := range types {
+		if strings.HasPrefix(strings.ToLower(t), strings.ToLower(match)) {
+			list = append(list, flags.Completion{Item: t})
+		}
+	}
+	return list
+}
+
+func (alarmType *OnuAlarmTypeString) Complete(match string) []flags.Completion {
+	return completeAlarmType(common.OnuAlarmTypes, match)
+}
+
+func (alarmType *OltAlarmTypeString) Complete(match string) []flags.Completion {
+	return completeAlarmType(common.OltAlarmTypes, match)
+}
+
+func (alarmType *OmciAlarmTypeString) Complete(match string) []flags.Completion {
+	return completeAlarmType(common.OmciAlarmTypes, match)
+}
+
+func (status *AlarmStatusString) Complete(match string) []flags.Completion {
+	return completeAlarmType([]string{common.AlarmStatusOn, common.AlarmStatusOff}, match)
+}
@@ -0,0 +1,247 @@
+/*
+ * Copyright 2018-present Open Networking Foundation
+
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+
+ * http://www.apache.org/licenses/LICENSE-2.0
+
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package commands
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/jessevdk/go-flags"
+	pb "github.com/opencord/bbsim/api/bbsim"
+	"github.com/opencord/bbsim/internal/bbsimctl/config"
+	"github.com/opencord/cordctl/pkg/format"
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+)
+
+const (
+	DEFAULT_FAULT_RULE_HEADER_FORMAT      = "table{{ .Method }}\t{{ .ErrorCode }}\t{{ .Rate }}\t{{ .LatencyMs }}\t{{ .JitterMs }}\t{{ .Drop }}"
+	DEFAULT_OMCI_FAULT_RULE_HEADER_FORMAT = "table{{ .SerialNumber }}\t{{ .MeClass }}\t{{ .DropNth }}\t{{ .DropEvery }}\t{{ .LatencyMs }}\t{{ .Corrupt }}\t{{ .WrongTid }}\t{{ .ResultCode }}"
+)
+
+type FaultCodeString string
+
+type FaultsList struct{}
+
+type FaultsAdd struct {
+	ErrorCode     FaultCodeString `long:"code" description:"gRPC code returned by the failing calls, eg: UNAVAILABLE"`
+	Rate          float32         `long:"rate" default:"1" description:"Fraction of the calls that fail, between 0 and 1"`
+	LatencyMs     uint32          `long:"latency" description:"Latency added to every call, in milliseconds"`
+	JitterMs      uint32          `long:"jitter" description:"Random delay up to jitter added to the latency, in milliseconds"`
+	Drop          bool            `long:"drop" description:"Never answer the failing calls, the caller gets a deadline exceeded"`
+	DropTimeoutMs uint32          `long:"drop-timeout" description:"Time after which the dropped calls fail with a deadline exceeded, in milliseconds (60000 if not set)"`
+	Args          struct {
+		Method string
+	} `positional-args:"yes" required:"yes"`
+}
+
+type FaultsRemove struct {
+	Args struct {
+		Method string
+	} `positional-args:"yes" required:"yes"`
+}
+
+type FaultsClear struct{}
+
+type OmciFaultsList struct{}
+
+type OmciFaultsAdd struct {
+	OnuSn      OnuSnString `long:"onu" description:"Serial Number of the ONU (all the ONUs if not set)"`
+	MeClass    uint16      `long:"me-class" description:"ME class of the requests whose responses are impaired (all of them if not set)"`
+	DropNth    uint32      `long:"drop-nth" description:"Drop only the Nth response, 1 drops the first one"`
+	DropEvery  uint32      `long:"drop-every" description:"Drop every Nth response, 1 drops all of them"`
+	LatencyMs  uint32      `long:"latency" description:"Delay the responses, in milliseconds"`
+	Corrupt    bool        `long:"corrupt" description:"Flip a random bit in the payload of the responses"`
+	WrongTid   bool        `long:"wrong-tid" description:"Answer with a transaction ID that does not match the request"`
+	ResultCode uint8       `long:"result-code" description:"Answer with this OMCI result code, eg: 6 (device busy)"`
+}
+
+type OmciFaultsClear struct{}
+
+type OmciFaultsOptions struct {
+	List  OmciFaultsList  `command:"list"`
+	Add   OmciFaultsAdd   `command:"add"`
+	Clear OmciFaultsClear `command:"clear"`
+}
+
+type faultsOptions struct {
+	List   FaultsList        `command:"list"`
+	Add    FaultsAdd         `command:"add"`
+	Remove FaultsRemove      `command:"remove"`
+	Clear  FaultsClear       `command:"clear"`
+	Omci   OmciFaultsOptions `command:"omci"`
+}
+
+func RegisterFaultsCommands(parser *flags.Parser) {
+	parser.AddCommand("faults", "Fault injection Commands", "Commands to inject faults in the OpenOLT calls of the OLT", &faultsOptions{})
+}
+
+func getFaultRules() []*pb.FaultRule {
+	client, conn := connect()
+	defer conn.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), config.GlobalConfig.Grpc.Timeout)
+	defer cancel()
+
+	res, err := client.GetFaultRules(ctx, newOltRequest())
+	if err != nil {
+		log.Fatalf("Cannot get the fault rules: %v", err)
+		return nil
+	}
+	return res.Rules
+}
+
+func setFaultRules(rules []*pb.FaultRule) error {
+	client, conn := connect()
+	defer conn.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), config.GlobalConfig.Grpc.Timeout)
+	defer cancel()
+
+	req := pb.FaultRules{
+		OltID: config.GlobalOptions.Olt,
+		Rules: rules,
+	}
+	res, err := client.SetFaultRules(ctx, &req)
+	if err != nil {
+		log.Fatalf("Cannot set the fault rules: %v", err)
+		return err
+	}
+
+	fmt.Println(fmt.Sprintf("[Status: %d] %s", res.StatusCode, res.Message))
+	return nil
+}
+
+// withoutFaultRule returns the rules that are not for the given method
+func withoutFaultRule(rules []*pb.FaultRule, method string) []*pb.FaultRule {
+	res := []*pb.FaultRule{}
+	for _, rule := range rules {
+		if rule.Method != method {
+			res = append(res, rule)
+		}
+	}
+	return res
+}
+
+func (options *FaultsList) Execute(args []string) error {
+	rules := getFaultRules()
+
+	tableFormat := format.Format(DEFAULT_FAULT_RULE_HEADER_FORMAT)
+	if err := tableFormat.Execute(os.Stdout, true, rules); err != nil {
+		log.Fatalf("Error while formatting fault rules table: %s", err)
+	}
+	return nil
+}
+
+func (options *FaultsAdd) Execute(args []string) error {
+	// NOTE a new rule for a method replaces the existing one
+	rules := withoutFaultRule(getFaultRules(), options.Args.Method)
+	rules = append(rules, &pb.FaultRule{
+		Method:        options.Args.Method,
+		ErrorCode:     string(options.ErrorCode),
+		Rate:          options.Rate,
+		LatencyMs:     options.LatencyMs,
+		JitterMs:      options.JitterMs,
+		Drop:          options.Drop,
+		DropTimeoutMs: options.DropTimeoutMs,
+	})
+	return setFaultRules(rules)
+}
+
+func (options *FaultsRemove) Execute(args []string) error {
+	return setFaultRules(withoutFaultRule(getFaultRules(), options.Args.Method))
+}
+
+func (options *FaultsClear) Execute(args []string) error {
+	return setFaultRules([]*pb.FaultRule{})
+}
+
+func getOmciFaultRules() []*pb.OmciFaultRule {
+	client, conn := connect()
+	defer conn.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), config.GlobalConfig.Grpc.Timeout)
+	defer cancel()
+
+	res, err := client.GetOmciFaultRules(ctx, newOltRequest())
+	if err != nil {
+		log.Fatalf("Cannot get the OMCI fault rules: %v", err)
+		return nil
+	}
+	return res.Rules
+}
+
+func setOmciFaultRules(rules []*pb.OmciFaultRule) error {
+	client, conn := connect()
+	defer conn.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), config.GlobalConfig.Grpc.Timeout)
+	defer cancel()
+
+	req := pb.OmciFaultRules{
+		OltID: config.GlobalOptions.Olt,
+		Rules: rules,
+	}
+	res, err := client.SetOmciFaultRules(ctx, &req)
+	if err != nil {
+		log.Fatalf("Cannot set the OMCI fault rules: %v", err)
+		return err
+	}
+
+	fmt.Println(fmt.Sprintf("[Status: %d] %s", res.StatusCode, res.Message))
+	return nil
+}
+
+func (options *OmciFaultsList) Execute(args []string) error {
+	rules := getOmciFaultRules()
+
+	tableFormat := format.Format(DEFAULT_OMCI_FAULT_RULE_HEADER_FORMAT)
+	if err := tableFormat.Execute(os.Stdout, true, rules); err != nil {
+		log.Fatalf("Error while formatting OMCI fault rules table: %s", err)
+	}
+	return nil
+}
+
+func (options *OmciFaultsAdd) Execute(args []string) error {
+	rules := append(getOmciFaultRules(), &pb.OmciFaultRule{
+		SerialNumber: string(options.OnuSn),
+		MeClass:      uint32(options.MeClass),
+		DropNth:      options.DropNth,
+		DropEvery:    options.DropEvery,
+		LatencyMs:    options.LatencyMs,
+		Corrupt:      options.Corrupt,
+		WrongTid:     options.WrongTid,
+		ResultCode:   uint32(options.ResultCode),
+	})
+	return setOmciFaultRules(rules)
+}
+
+func (options *OmciFaultsClear) Execute(args []string) error {
+	return setOmciFaultRules([]*pb.OmciFaultRule{})
+}
+
+func (code *FaultCodeString) Complete(match string) []flags.Completion {
+	list := make([]flags.Completion, 0)
+	for c := codes.Canceled; c <= codes.Unauthenticated; c++ {
+		if strings.HasPrefix(strings.ToLower(c.String()), strings.ToLower(match)) {
+			list = append(list, flags.Completion{Item: c.String()})
+		}
+	}
+	return list
+}
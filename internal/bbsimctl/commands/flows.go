@@ -0,0 +1,39 @@
+/*
+ * Portions copyright 2019-present Open Networking Foundation
+ * Original copyright 2019-present Ciena Corporation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package commands
+
+import (
+	"fmt"
+	pb "github.com/opencord/bbsim/api/bbsim"
+	"github.com/opencord/cordctl/pkg/format"
+	log "github.com/sirupsen/logrus"
+	"os"
+)
+
+const (
+	DEFAULT_FLOW_HEADER_FORMAT = "table{{ .FlowId }}\t{{ .FlowType }}\t{{ .AccessIntfId }}\t{{ .OnuId }}\t{{ .UniId }}\t{{ .PortNo }}\t{{ .GemportId }}\t{{ .Classifier.OVid }}\t{{ .Classifier.IVid }}\t{{ .Classifier.EthType }}\t{{ .Classifier.IpProto }}\t{{ .Classifier.SrcPort }}\t{{ .Classifier.DstPort }}"
+)
+
+func printFlows(flows *pb.Flows) {
+	fmt.Println(fmt.Sprintf("Number of flows: %d", flows.FlowCount))
+	fmt.Println()
+
+	tableFormat := format.Format(DEFAULT_FLOW_HEADER_FORMAT)
+	if err := tableFormat.Execute(os.Stdout, true, flows.Flows); err != nil {
+		log.Fatalf("Error while formatting flows table: %s", err)
+	}
+}
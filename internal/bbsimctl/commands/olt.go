@@ -32,8 +32,12 @@ import (
 const (
 	DEFAULT_OLT_DEVICE_HEADER_FORMAT = "table{{ .ID }}\t{{ .SerialNumber }}\t{{ .OperState }}\t{{ .InternalState }}"
 	DEFAULT_PORT_HEADER_FORMAT       = "table{{ .ID }}\t{{ .OperState }}"
+	DEFAULT_PORT_STATS_HEADER_FORMAT = "table{{ .PortType }}\t{{ .ID }}\t{{ .RxPackets }}\t{{ .RxBytes }}\t{{ .TxPackets }}\t{{ .TxBytes }}"
+	DEFAULT_FLOW_STATS_HEADER_FORMAT = "table{{ .FlowId }}\t{{ .FlowType }}\t{{ .RxPackets }}\t{{ .RxBytes }}\t{{ .TxPackets }}\t{{ .TxBytes }}"
 )
 
+type OltList struct{}
+
 type OltGet struct{}
 
 type OltNNIs struct{}
@@ -44,21 +48,49 @@ type OltShutdown struct{}
 
 type OltPoweron struct{}
 
-type OltReboot struct{}
+type OltReboot struct {
+	Mode      string `long:"mode" default:"soft" choice:"soft" choice:"hard" choice:"hang" description:"soft: the OLT reports itself down, hard: power cut, hang: the OLT stops answering until rebooted again"`
+	DyingGasp bool   `long:"dying-gasp" description:"Send a DyingGasp for each activated ONU before a hard reboot"`
+}
+
+type OltFlows struct{}
+
+type OltStats struct{}
+
+type OltHeartbeatStop struct{}
+
+type OltHeartbeatStart struct{}
+
+type OltHeartbeatOptions struct {
+	Stop  OltHeartbeatStop  `command:"stop"`
+	Start OltHeartbeatStart `command:"start"`
+}
 
 type oltOptions struct {
-	Get      OltGet      `command:"get"`
-	NNI      OltNNIs     `command:"nnis"`
-	PON      OltPONs     `command:"pons"`
-	Shutdown OltShutdown `command:"shutdown"`
-	Poweron  OltPoweron  `command:"poweron"`
-	Reboot   OltReboot   `command:"reboot"`
+	List      OltList             `command:"list"`
+	Get       OltGet              `command:"get"`
+	NNI       OltNNIs             `command:"nnis"`
+	PON       OltPONs             `command:"pons"`
+	Shutdown  OltShutdown         `command:"shutdown"`
+	Poweron   OltPoweron          `command:"poweron"`
+	Reboot    OltReboot           `command:"reboot"`
+	Flows     OltFlows            `command:"flows"`
+	Stats     OltStats            `command:"stats"`
+	Alarms    OltAlarmOptions     `command:"alarms"`
+	Heartbeat OltHeartbeatOptions `command:"heartbeat"`
 }
 
 func RegisterOltCommands(parser *flags.Parser) {
 	parser.AddCommand("olt", "OLT Commands", "Commands to query and manipulate the OLT device", &oltOptions{})
 }
 
+// newOltRequest returns a request for the OLT selected via the --olt option
+func newOltRequest() *pb.OltRequest {
+	return &pb.OltRequest{
+		OltID: config.GlobalOptions.Olt,
+	}
+}
+
 func getOLT() *pb.Olt {
 	conn, err := grpc.Dial(config.GlobalConfig.Server, grpc.WithInsecure())
 	if err != nil {
@@ -72,7 +104,7 @@ func getOLT() *pb.Olt {
 
 	ctx, cancel := context.WithTimeout(context.Background(), config.GlobalConfig.Grpc.Timeout)
 	defer cancel()
-	olt, err := c.GetOlt(ctx, &pb.Empty{})
+	olt, err := c.GetOlt(ctx, newOltRequest())
 	if err != nil {
 		log.Fatalf("could not get OLT: %v", err)
 		return nil
@@ -85,6 +117,25 @@ func printOltHeader(prefix string, o *pb.Olt) {
 	fmt.Println()
 }
 
+func (o *OltList) Execute(args []string) error {
+	client, conn := connect()
+	defer conn.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), config.GlobalConfig.Grpc.Timeout)
+	defer cancel()
+
+	olts, err := client.GetOlts(ctx, &pb.Empty{})
+	if err != nil {
+		log.Fatalf("could not get OLTs: %v", err)
+		return err
+	}
+
+	tableFormat := format.Format(DEFAULT_OLT_DEVICE_HEADER_FORMAT)
+	tableFormat.Execute(os.Stdout, true, olts.Items)
+
+	return nil
+}
+
 func (o *OltGet) Execute(args []string) error {
 	olt := getOLT()
 
@@ -124,7 +175,7 @@ func (o *OltShutdown) Execute(args []string) error {
 	ctx, cancel := context.WithTimeout(context.Background(), config.GlobalConfig.Grpc.Timeout)
 	defer cancel()
 
-	res, err := client.ShutdownOlt(ctx, &pb.Empty{})
+	res, err := client.ShutdownOlt(ctx, newOltRequest())
 
 	if err != nil {
 		log.Fatalf("Cannot shut down OLT: %v", err)
@@ -142,7 +193,7 @@ func (o *OltPoweron) Execute(args []string) error {
 	ctx, cancel := context.WithTimeout(context.Background(), config.GlobalConfig.Grpc.Timeout)
 	defer cancel()
 
-	res, err := client.PoweronOlt(ctx, &pb.Empty{})
+	res, err := client.PoweronOlt(ctx, newOltRequest())
 
 	if err != nil {
 		log.Fatalf("Cannot power on OLT: %v", err)
@@ -160,7 +211,12 @@ func (o *OltReboot) Execute(args []string) error {
 	ctx, cancel := context.WithTimeout(context.Background(), config.GlobalConfig.Grpc.Timeout)
 	defer cancel()
 
-	res, err := client.RebootOlt(ctx, &pb.Empty{})
+	req := pb.RebootRequest{
+		OltID:     config.GlobalOptions.Olt,
+		Mode:      o.Mode,
+		DyingGasp: o.DyingGasp,
+	}
+	res, err := client.RebootOlt(ctx, &req)
 
 	if err != nil {
 		log.Fatalf("Cannot reboot OLT: %v", err)
@@ -170,3 +226,89 @@ func (o *OltReboot) Execute(args []string) error {
 	fmt.Println(fmt.Sprintf("[Status: %d] %s", res.StatusCode, res.Message))
 	return nil
 }
+
+func (o *OltFlows) Execute(args []string) error {
+	client, conn := connect()
+	defer conn.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), config.GlobalConfig.Grpc.Timeout)
+	defer cancel()
+
+	res, err := client.ListOltFlows(ctx, newOltRequest())
+
+	if err != nil {
+		log.Fatalf("Cannot list OLT flows: %v", err)
+		return err
+	}
+
+	printFlows(res)
+	return nil
+}
+
+func (o *OltHeartbeatStop) Execute(args []string) error {
+	client, conn := connect()
+	defer conn.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), config.GlobalConfig.Grpc.Timeout)
+	defer cancel()
+
+	res, err := client.StopOltHeartbeat(ctx, newOltRequest())
+
+	if err != nil {
+		log.Fatalf("Cannot stop OLT heartbeat: %v", err)
+		return err
+	}
+
+	fmt.Println(fmt.Sprintf("[Status: %d] %s", res.StatusCode, res.Message))
+	return nil
+}
+
+func (o *OltHeartbeatStart) Execute(args []string) error {
+	client, conn := connect()
+	defer conn.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), config.GlobalConfig.Grpc.Timeout)
+	defer cancel()
+
+	res, err := client.StartOltHeartbeat(ctx, newOltRequest())
+
+	if err != nil {
+		log.Fatalf("Cannot start OLT heartbeat: %v", err)
+		return err
+	}
+
+	fmt.Println(fmt.Sprintf("[Status: %d] %s", res.StatusCode, res.Message))
+	return nil
+}
+
+func (o *OltStats) Execute(args []string) error {
+	client, conn := connect()
+	defer conn.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), config.GlobalConfig.Grpc.Timeout)
+	defer cancel()
+
+	res, err := client.GetOltStats(ctx, newOltRequest())
+
+	if err != nil {
+		log.Fatalf("Cannot get OLT stats: %v", err)
+		return err
+	}
+
+	fmt.Println("Port statistics:")
+	fmt.Println()
+	tableFormat := format.Format(DEFAULT_PORT_STATS_HEADER_FORMAT)
+	if err := tableFormat.Execute(os.Stdout, true, res.Ports); err != nil {
+		log.Fatalf("Error while formatting port stats table: %s", err)
+	}
+
+	fmt.Println()
+	fmt.Println("Flow statistics:")
+	fmt.Println()
+	tableFormat = format.Format(DEFAULT_FLOW_STATS_HEADER_FORMAT)
+	if err := tableFormat.Execute(os.Stdout, true, res.Flows); err != nil {
+		log.Fatalf("Error while formatting flow stats table: %s", err)
+	}
+
+	return nil
+}
@@ -19,6 +19,8 @@ package commands
 
 import (
 	"context"
+	"encoding/hex"
+	"encoding/json"
 	"fmt"
 	"github.com/jessevdk/go-flags"
 	pb "github.com/opencord/bbsim/api/bbsim"
@@ -32,6 +34,12 @@ import (
 
 const (
 	DEFAULT_ONU_DEVICE_HEADER_FORMAT = "table{{ .PonPortID }}\t{{ .ID }}\t{{ .PortNo }}\t{{ .SerialNumber }}\t{{ .HwAddress }}\t{{ .STag }}\t{{ .CTag }}\t{{ .OperState }}\t{{ .InternalState }}"
+	DEFAULT_TCONT_HEADER_FORMAT      = "table{{ .UniID }}\t{{ .Direction }}\t{{ .AllocID }}"
+	DEFAULT_GEMPORT_HEADER_FORMAT    = "table{{ .UniID }}\t{{ .Direction }}\t{{ .GemportID }}\t{{ .PbitMap }}\t{{ .Priority }}\t{{ .Weight }}"
+	DEFAULT_UNI_HEADER_FORMAT        = "table{{ .OnuSn }}\t{{ .OnuID }}\t{{ .ID }}\t{{ .PortNo }}\t{{ .HwAddress }}\t{{ .CTag }}\t{{ .InternalState }}"
+	DEFAULT_OMCI_HISTORY_FORMAT      = "table{{ .Time }}\t{{ .Direction }}\t{{ .TransactionId }}\t{{ .MessageType }}\t{{ .MeClass }}\t{{ .MeInstance }}\t{{ .Result }}\t{{ .Dropped }}"
+	DEFAULT_OMCI_MIB_FORMAT          = "table{{ .MeClass }}\t{{ .MeInstance }}\t{{ .Name }}"
+	DEFAULT_SW_IMAGE_HEADER_FORMAT   = "table{{ .Instance }}\t{{ .Version }}\t{{ .IsCommitted }}\t{{ .IsActive }}\t{{ .IsValid }}"
 )
 
 type OnuSnString string
@@ -55,6 +63,12 @@ type ONUPowerOn struct {
 	} `positional-args:"yes" required:"yes"`
 }
 
+type ONUReboot struct {
+	Args struct {
+		OnuSn OnuSnString
+	} `positional-args:"yes" required:"yes"`
+}
+
 type ONUEapolRestart struct {
 	Args struct {
 		OnuSn OnuSnString
@@ -67,13 +81,107 @@ type ONUDhcpRestart struct {
 	} `positional-args:"yes" required:"yes"`
 }
 
+type ONUFlows struct {
+	Args struct {
+		OnuSn OnuSnString
+	} `positional-args:"yes" required:"yes"`
+}
+
+type ONUUnis struct {
+	Args struct {
+		OnuSn OnuSnString
+	} `positional-args:"yes" required:"yes"`
+}
+
+type ONUTConts struct {
+	Args struct {
+		OnuSn OnuSnString
+	} `positional-args:"yes" required:"yes"`
+}
+
+type ONUOmciHistory struct {
+	Json bool `long:"json" description:"Print the history as JSON"`
+	Args struct {
+		OnuSn OnuSnString
+	} `positional-args:"yes" required:"yes"`
+}
+
+type ONUMib struct {
+	Args struct {
+		OnuSn OnuSnString
+	} `positional-args:"yes" required:"yes"`
+}
+
+type ONUImages struct {
+	Args struct {
+		OnuSn OnuSnString
+	} `positional-args:"yes" required:"yes"`
+}
+
+type SwImageFaultString string
+
+type ONUImageFault struct {
+	Args struct {
+		OnuSn OnuSnString
+		Fault SwImageFaultString
+	} `positional-args:"yes" required:"yes"`
+}
+
+type ONUAdd struct {
+	SerialNumber  string `long:"sn" description:"Serial Number of the ONU, eg: ABCD00000001 (generated if not set)"`
+	HwAddress     string `long:"mac" description:"MAC Address of the first UNI (generated if not set)"`
+	STag          int32  `long:"s-tag" description:"S-Tag of the ONU (the OLT one if not set)"`
+	CTag          int32  `long:"c-tag" description:"C-Tag of the first UNI (the next free one if not set)"`
+	EapolUsername string `long:"eapol-username" description:"EAPOL username"`
+	EapolPassword string `long:"eapol-password" description:"EAPOL password"`
+	MibTemplate   string `long:"mib-template" description:"MIB template the ONU uploads (the one of the vendor ID if not set)"`
+	Args          struct {
+		PonPortID uint32
+	} `positional-args:"yes" required:"yes"`
+}
+
+type ONURemove struct {
+	Args struct {
+		OnuSn OnuSnString
+	} `positional-args:"yes" required:"yes"`
+}
+
+type ONUMove struct {
+	Args struct {
+		OnuSn     OnuSnString
+		PonPortID uint32
+	} `positional-args:"yes" required:"yes"`
+}
+
+type ONUSendPacket struct {
+	Args struct {
+		OnuSn  OnuSnString
+		UniID  uint32
+		Packet string // the Ethernet frame in hex, eg: ffffffffffff0e000000000181000385...
+	} `positional-args:"yes" required:"yes"`
+}
+
 type ONUOptions struct {
 	List         ONUList         `command:"list"`
 	Get          ONUGet          `command:"get"`
 	ShutDown     ONUShutDown     `command:"shutdown"`
 	PowerOn      ONUPowerOn      `command:"poweron"`
+	Reboot       ONUReboot       `command:"reboot"`
 	RestartEapol ONUEapolRestart `command:"auth_restart"`
 	RestartDchp  ONUDhcpRestart  `command:"dhcp_restart"`
+	Flows        ONUFlows        `command:"flows"`
+	Unis         ONUUnis         `command:"unis"`
+	TConts       ONUTConts       `command:"tconts"`
+	OmciHistory  ONUOmciHistory  `command:"omci-history"`
+	Mib          ONUMib          `command:"mib"`
+	Images       ONUImages       `command:"images"`
+	ImageFault   ONUImageFault   `command:"image-fault"`
+	Alarms       ONUAlarmOptions `command:"alarms"`
+	OmciAlarm    ONUOmciAlarm    `command:"omci-alarm"`
+	Add          ONUAdd          `command:"add"`
+	Remove       ONURemove       `command:"remove"`
+	Move         ONUMove         `command:"move"`
+	SendPacket   ONUSendPacket   `command:"send-packet"`
 }
 
 func RegisterONUCommands(parser *flags.Parser) {
@@ -99,7 +207,7 @@ func getONUs() *pb.ONUs {
 	ctx, cancel := context.WithTimeout(context.Background(), config.GlobalConfig.Grpc.Timeout)
 	defer cancel()
 
-	onus, err := client.GetONUs(ctx, &pb.Empty{})
+	onus, err := client.GetONUs(ctx, newOltRequest())
 	if err != nil {
 		log.Fatalf("could not get OLT: %v", err)
 		return nil
@@ -127,6 +235,7 @@ func (options *ONUGet) Execute(args []string) error {
 	defer cancel()
 	req := pb.ONURequest{
 		SerialNumber: string(options.Args.OnuSn),
+		OltID:        config.GlobalOptions.Olt,
 	}
 	res, err := client.GetONU(ctx, &req)
 
@@ -152,6 +261,7 @@ func (options *ONUShutDown) Execute(args []string) error {
 	defer cancel()
 	req := pb.ONURequest{
 		SerialNumber: string(options.Args.OnuSn),
+		OltID:        config.GlobalOptions.Olt,
 	}
 	res, err := client.ShutdownONU(ctx, &req)
 
@@ -173,6 +283,7 @@ func (options *ONUPowerOn) Execute(args []string) error {
 	defer cancel()
 	req := pb.ONURequest{
 		SerialNumber: string(options.Args.OnuSn),
+		OltID:        config.GlobalOptions.Olt,
 	}
 	res, err := client.PoweronONU(ctx, &req)
 
@@ -186,6 +297,28 @@ func (options *ONUPowerOn) Execute(args []string) error {
 	return nil
 }
 
+func (options *ONUReboot) Execute(args []string) error {
+	client, conn := connect()
+	defer conn.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), config.GlobalConfig.Grpc.Timeout)
+	defer cancel()
+	req := pb.ONURequest{
+		SerialNumber: string(options.Args.OnuSn),
+		OltID:        config.GlobalOptions.Olt,
+	}
+	res, err := client.RebootONU(ctx, &req)
+
+	if err != nil {
+		log.Fatalf("Cannot reboot ONU %s: %v", options.Args.OnuSn, err)
+		return err
+	}
+
+	fmt.Println(fmt.Sprintf("[Status: %d] %s", res.StatusCode, res.Message))
+
+	return nil
+}
+
 func (options *ONUEapolRestart) Execute(args []string) error {
 	client, conn := connect()
 	defer conn.Close()
@@ -194,6 +327,7 @@ func (options *ONUEapolRestart) Execute(args []string) error {
 	defer cancel()
 	req := pb.ONURequest{
 		SerialNumber: string(options.Args.OnuSn),
+		OltID:        config.GlobalOptions.Olt,
 	}
 	res, err := client.RestartEapol(ctx, &req)
 
@@ -215,6 +349,7 @@ func (options *ONUDhcpRestart) Execute(args []string) error {
 	defer cancel()
 	req := pb.ONURequest{
 		SerialNumber: string(options.Args.OnuSn),
+		OltID:        config.GlobalOptions.Olt,
 	}
 	res, err := client.RestartDhcp(ctx, &req)
 
@@ -228,6 +363,320 @@ func (options *ONUDhcpRestart) Execute(args []string) error {
 	return nil
 }
 
+func (options *ONUFlows) Execute(args []string) error {
+	client, conn := connect()
+	defer conn.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), config.GlobalConfig.Grpc.Timeout)
+	defer cancel()
+	req := pb.ONURequest{
+		SerialNumber: string(options.Args.OnuSn),
+		OltID:        config.GlobalOptions.Olt,
+	}
+	res, err := client.ListOnuFlows(ctx, &req)
+
+	if err != nil {
+		log.Fatalf("Cannot list flows for ONU %s: %v", options.Args.OnuSn, err)
+		return err
+	}
+
+	printFlows(res)
+	return nil
+}
+
+func (options *ONUUnis) Execute(args []string) error {
+	client, conn := connect()
+	defer conn.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), config.GlobalConfig.Grpc.Timeout)
+	defer cancel()
+	req := pb.ONURequest{
+		SerialNumber: string(options.Args.OnuSn),
+		OltID:        config.GlobalOptions.Olt,
+	}
+	res, err := client.GetONU(ctx, &req)
+
+	if err != nil {
+		log.Fatalf("Cannot get UNIs for ONU %s: %v", options.Args.OnuSn, err)
+		return err
+	}
+
+	tableFormat := format.Format(DEFAULT_UNI_HEADER_FORMAT)
+	if err := tableFormat.Execute(os.Stdout, true, res.Unis); err != nil {
+		log.Fatalf("Error while formatting UNIs table: %s", err)
+	}
+
+	return nil
+}
+
+func (options *ONUTConts) Execute(args []string) error {
+	client, conn := connect()
+	defer conn.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), config.GlobalConfig.Grpc.Timeout)
+	defer cancel()
+	req := pb.ONURequest{
+		SerialNumber: string(options.Args.OnuSn),
+		OltID:        config.GlobalOptions.Olt,
+	}
+	res, err := client.GetOnuTConts(ctx, &req)
+
+	if err != nil {
+		log.Fatalf("Cannot get T-CONTs for ONU %s: %v", options.Args.OnuSn, err)
+		return err
+	}
+
+	fmt.Println("T-CONTs:")
+	fmt.Println()
+	tableFormat := format.Format(DEFAULT_TCONT_HEADER_FORMAT)
+	if err := tableFormat.Execute(os.Stdout, true, res.TConts); err != nil {
+		log.Fatalf("Error while formatting T-CONTs table: %s", err)
+	}
+
+	fmt.Println()
+	fmt.Println("GEM ports:")
+	fmt.Println()
+	tableFormat = format.Format(DEFAULT_GEMPORT_HEADER_FORMAT)
+	if err := tableFormat.Execute(os.Stdout, true, res.GemPorts); err != nil {
+		log.Fatalf("Error while formatting GEM ports table: %s", err)
+	}
+
+	return nil
+}
+
+func (options *ONUOmciHistory) Execute(args []string) error {
+	client, conn := connect()
+	defer conn.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), config.GlobalConfig.Grpc.Timeout)
+	defer cancel()
+	req := pb.ONURequest{
+		SerialNumber: string(options.Args.OnuSn),
+		OltID:        config.GlobalOptions.Olt,
+	}
+	res, err := client.GetOnuOmciHistory(ctx, &req)
+
+	if err != nil {
+		log.Fatalf("Cannot get the OMCI history of ONU %s: %v", options.Args.OnuSn, err)
+		return err
+	}
+
+	if options.Json {
+		out, err := json.MarshalIndent(res.Entries, "", "  ")
+		if err != nil {
+			log.Fatalf("Error while formatting the OMCI history: %s", err)
+		}
+		fmt.Println(string(out))
+		return nil
+	}
+
+	tableFormat := format.Format(DEFAULT_OMCI_HISTORY_FORMAT)
+	if err := tableFormat.Execute(os.Stdout, true, res.Entries); err != nil {
+		log.Fatalf("Error while formatting OMCI history table: %s", err)
+	}
+
+	return nil
+}
+
+func (options *ONUMib) Execute(args []string) error {
+	client, conn := connect()
+	defer conn.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), config.GlobalConfig.Grpc.Timeout)
+	defer cancel()
+	req := pb.ONURequest{
+		SerialNumber: string(options.Args.OnuSn),
+		OltID:        config.GlobalOptions.Olt,
+	}
+	res, err := client.GetOnuMib(ctx, &req)
+
+	if err != nil {
+		log.Fatalf("Cannot get the MIB of ONU %s: %v", options.Args.OnuSn, err)
+		return err
+	}
+
+	fmt.Printf("MIB data sync: %d\n\n", res.MibDataSync)
+	tableFormat := format.Format(DEFAULT_OMCI_MIB_FORMAT)
+	if err := tableFormat.Execute(os.Stdout, true, res.Instances); err != nil {
+		log.Fatalf("Error while formatting MIB table: %s", err)
+	}
+
+	return nil
+}
+
+func (options *ONUImages) Execute(args []string) error {
+	client, conn := connect()
+	defer conn.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), config.GlobalConfig.Grpc.Timeout)
+	defer cancel()
+	req := pb.ONURequest{
+		SerialNumber: string(options.Args.OnuSn),
+		OltID:        config.GlobalOptions.Olt,
+	}
+	res, err := client.GetOnuSoftwareImages(ctx, &req)
+
+	if err != nil {
+		log.Fatalf("Cannot get the software images of ONU %s: %v", options.Args.OnuSn, err)
+		return err
+	}
+
+	tableFormat := format.Format(DEFAULT_SW_IMAGE_HEADER_FORMAT)
+	if err := tableFormat.Execute(os.Stdout, true, res.Images); err != nil {
+		log.Fatalf("Error while formatting software images table: %s", err)
+	}
+
+	if res.DownloadSize > 0 {
+		fmt.Println(fmt.Sprintf("Downloading: %d/%d bytes", res.DownloadedBytes, res.DownloadSize))
+	}
+	fmt.Println(fmt.Sprintf("Fault: %s", res.Fault))
+
+	return nil
+}
+
+func (options *ONUImageFault) Execute(args []string) error {
+	client, conn := connect()
+	defer conn.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), config.GlobalConfig.Grpc.Timeout)
+	defer cancel()
+	req := pb.SoftwareImageFaultRequest{
+		SerialNumber: string(options.Args.OnuSn),
+		Fault:        string(options.Args.Fault),
+		OltID:        config.GlobalOptions.Olt,
+	}
+	res, err := client.SetOnuSoftwareImageFault(ctx, &req)
+
+	if err != nil {
+		log.Fatalf("Cannot set the software image fault of ONU %s: %v", options.Args.OnuSn, err)
+		return err
+	}
+
+	fmt.Println(fmt.Sprintf("[Status: %d] %s", res.StatusCode, res.Message))
+
+	return nil
+}
+
+func (fault *SwImageFaultString) Complete(match string) []flags.Completion {
+	list := make([]flags.Completion, 0)
+	for _, f := range []string{"none", "crc-mismatch", "activate"} {
+		if strings.HasPrefix(f, match) {
+			list = append(list, flags.Completion{Item: f})
+		}
+	}
+	return list
+}
+
+func (options *ONUAdd) Execute(args []string) error {
+	client, conn := connect()
+	defer conn.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), config.GlobalConfig.Grpc.Timeout)
+	defer cancel()
+	req := pb.CreateOnuRequest{
+		OltID:         config.GlobalOptions.Olt,
+		PonPortID:     options.Args.PonPortID,
+		SerialNumber:  options.SerialNumber,
+		HwAddress:     options.HwAddress,
+		STag:          options.STag,
+		CTag:          options.CTag,
+		EapolUsername: options.EapolUsername,
+		EapolPassword: options.EapolPassword,
+		MibTemplate:   options.MibTemplate,
+	}
+	res, err := client.CreateOnu(ctx, &req)
+
+	if err != nil {
+		log.Fatalf("Cannot add ONU on PON %d: %v", options.Args.PonPortID, err)
+		return err
+	}
+
+	tableFormat := format.Format(DEFAULT_ONU_DEVICE_HEADER_FORMAT)
+	if err := tableFormat.Execute(os.Stdout, true, []*pb.ONU{res}); err != nil {
+		log.Fatalf("Error while formatting ONUs table: %s", err)
+	}
+
+	return nil
+}
+
+func (options *ONURemove) Execute(args []string) error {
+	client, conn := connect()
+	defer conn.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), config.GlobalConfig.Grpc.Timeout)
+	defer cancel()
+	req := pb.ONURequest{
+		SerialNumber: string(options.Args.OnuSn),
+		OltID:        config.GlobalOptions.Olt,
+	}
+	res, err := client.DeleteOnu(ctx, &req)
+
+	if err != nil {
+		log.Fatalf("Cannot remove ONU %s: %v", options.Args.OnuSn, err)
+		return err
+	}
+
+	fmt.Println(fmt.Sprintf("[Status: %d] %s", res.StatusCode, res.Message))
+
+	return nil
+}
+
+func (options *ONUMove) Execute(args []string) error {
+	client, conn := connect()
+	defer conn.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), config.GlobalConfig.Grpc.Timeout)
+	defer cancel()
+	req := pb.MoveOnuRequest{
+		SerialNumber: string(options.Args.OnuSn),
+		PonPortID:    options.Args.PonPortID,
+		OltID:        config.GlobalOptions.Olt,
+	}
+	res, err := client.MoveOnu(ctx, &req)
+
+	if err != nil {
+		log.Fatalf("Cannot move ONU %s to PON %d: %v", options.Args.OnuSn, options.Args.PonPortID, err)
+		return err
+	}
+
+	tableFormat := format.Format(DEFAULT_ONU_DEVICE_HEADER_FORMAT)
+	if err := tableFormat.Execute(os.Stdout, true, []*pb.ONU{res}); err != nil {
+		log.Fatalf("Error while formatting ONUs table: %s", err)
+	}
+
+	return nil
+}
+
+func (options *ONUSendPacket) Execute(args []string) error {
+	pkt, err := hex.DecodeString(strings.NewReplacer(":", "", " ", "").Replace(options.Args.Packet))
+	if err != nil {
+		log.Fatalf("Invalid packet %s: %v", options.Args.Packet, err)
+		return err
+	}
+
+	client, conn := connect()
+	defer conn.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), config.GlobalConfig.Grpc.Timeout)
+	defer cancel()
+	req := pb.UniPacketRequest{
+		SerialNumber: string(options.Args.OnuSn),
+		UniID:        options.Args.UniID,
+		Packet:       pkt,
+		OltID:        config.GlobalOptions.Olt,
+	}
+	res, err := client.SendUniPacket(ctx, &req)
+
+	if err != nil {
+		log.Fatalf("Cannot send packet from UNI %d of ONU %s: %v", options.Args.UniID, options.Args.OnuSn, err)
+		return err
+	}
+
+	fmt.Println(fmt.Sprintf("[Status: %d] %s", res.StatusCode, res.Message))
+
+	return nil
+}
+
 func (onuSn *OnuSnString) Complete(match string) []flags.Completion {
 	client, conn := connect()
 	defer conn.Close()
@@ -235,7 +684,7 @@ func (onuSn *OnuSnString) Complete(match string) []flags.Completion {
 	ctx, cancel := context.WithTimeout(context.Background(), config.GlobalConfig.Grpc.Timeout)
 	defer cancel()
 
-	onus, err := client.GetONUs(ctx, &pb.Empty{})
+	onus, err := client.GetONUs(ctx, newOltRequest())
 	if err != nil {
 		log.Fatalf("could not get ONUs: %v", err)
 		return nil
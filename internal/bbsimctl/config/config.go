@@ -38,7 +38,8 @@ var GlobalOptions struct {
 	Config string `short:"c" long:"config" env:"BBSIMCTL_CONFIG" value-name:"FILE" default:"" description:"Location of client config file"`
 	Server string `short:"s" long:"server" default:"" value-name:"SERVER:PORT" description:"IP/Host and port of XOS"`
 	//Protoset string `long:"protoset" value-name:"FILENAME" description:"Load protobuf definitions from protoset instead of reflection api"`
-	Debug bool `short:"d" long:"debug" description:"Enable debug mode"`
+	Debug bool  `short:"d" long:"debug" description:"Enable debug mode"`
+	Olt   int32 `long:"olt" default:"0" value-name:"OLT_ID" description:"ID of the OLT to manage, when BBSim emulates more than one OLT"`
 }
 
 type GrpcConfigSpec struct {
@@ -0,0 +1,162 @@
+/*
+ * Copyright 2018-present Open Networking Foundation
+
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+
+ * http://www.apache.org/licenses/LICENSE-2.0
+
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package common
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ONU alarm types that can be raised via the API
+const (
+	OnuAlarmLos                  = "Los"
+	OnuAlarmLob                  = "Lob"
+	OnuAlarmLopcMiss             = "LopcMiss"
+	OnuAlarmLopcMicError         = "LopcMicError"
+	OnuAlarmLofi                 = "Lofi"
+	OnuAlarmLoami                = "Loami"
+	OnuAlarmDyingGasp            = "DyingGasp"
+	OnuAlarmStartupFailure       = "StartupFailure"
+	OnuAlarmSignalDegrade        = "SignalDegrade"
+	OnuAlarmDriftOfWindow        = "DriftOfWindow"
+	OnuAlarmLossOfOmciChannel    = "LossOfOmciChannel"
+	OnuAlarmSignalsFailure       = "SignalsFailure"
+	OnuAlarmTransmissionWarning  = "TransmissionInterferenceWarning"
+	OnuAlarmActivationFailure    = "ActivationFailure"
+	OnuAlarmLossOfKeySyncFailure = "LossOfKeySyncFailure"
+	OnuAlarmProcessingError      = "ProcessingError"
+)
+
+// OLT alarm types that can be raised via the API
+const (
+	OltAlarmPonLos = "PonLos"
+	OltAlarmNniLos = "NniLos"
+)
+
+// OMCI alarms and attribute value changes the ONUs can send via the API
+const (
+	OmciAlarmPptpLanLos            = "PptpLanLos"
+	OmciAlarmAniGLowRxPower        = "AniGLowRxPower"
+	OmciAlarmAniGHighRxPower       = "AniGHighRxPower"
+	OmciAlarmAniGSignalFail        = "AniGSignalFail"
+	OmciAlarmAniGSignalDegrade     = "AniGSignalDegrade"
+	OmciAlarmAniGLowTxPower        = "AniGLowTxPower"
+	OmciAlarmAniGHighTxPower       = "AniGHighTxPower"
+	OmciAlarmAniGLaserBiasCurrent  = "AniGLaserBiasCurrent"
+	OmciAlarmOnuGEquipment         = "OnuGEquipment"
+	OmciAlarmOnuGPowering          = "OnuGPowering"
+	OmciAlarmOnuGSelfTestFailure   = "OnuGSelfTestFailure"
+	OmciAlarmOnuGTemperatureYellow = "OnuGTemperatureYellow"
+	OmciAlarmOnuGTemperatureRed    = "OnuGTemperatureRed"
+	OmciAvcUniOperState            = "UniOperState"
+	OmciAvcOnuOperState            = "OnuOperState"
+)
+
+// Alarm status
+const (
+	AlarmStatusOn  = "on"
+	AlarmStatusOff = "off"
+)
+
+var OnuAlarmTypes = []string{
+	OnuAlarmLos,
+	OnuAlarmLob,
+	OnuAlarmLopcMiss,
+	OnuAlarmLopcMicError,
+	OnuAlarmLofi,
+	OnuAlarmLoami,
+	OnuAlarmDyingGasp,
+	OnuAlarmStartupFailure,
+	OnuAlarmSignalDegrade,
+	OnuAlarmDriftOfWindow,
+	OnuAlarmLossOfOmciChannel,
+	OnuAlarmSignalsFailure,
+	OnuAlarmTransmissionWarning,
+	OnuAlarmActivationFailure,
+	OnuAlarmLossOfKeySyncFailure,
+	OnuAlarmProcessingError,
+}
+
+var OltAlarmTypes = []string{
+	OltAlarmPonLos,
+	OltAlarmNniLos,
+}
+
+var OmciAlarmTypes = []string{
+	OmciAlarmPptpLanLos,
+	OmciAlarmAniGLowRxPower,
+	OmciAlarmAniGHighRxPower,
+	OmciAlarmAniGSignalFail,
+	OmciAlarmAniGSignalDegrade,
+	OmciAlarmAniGLowTxPower,
+	OmciAlarmAniGHighTxPower,
+	OmciAlarmAniGLaserBiasCurrent,
+	OmciAlarmOnuGEquipment,
+	OmciAlarmOnuGPowering,
+	OmciAlarmOnuGSelfTestFailure,
+	OmciAlarmOnuGTemperatureYellow,
+	OmciAlarmOnuGTemperatureRed,
+	OmciAvcUniOperState,
+	OmciAvcOnuOperState,
+}
+
+// alarmAliases contains the names used by the legacy API
+var alarmAliases = map[string]string{
+	"lossofploam": OnuAlarmLopcMiss,
+}
+
+func normalizeAlarmName(name string) string {
+	return strings.ToLower(strings.NewReplacer("_", "", "-", "", " ", "").Replace(name))
+}
+
+func findAlarmType(name string, types []string) (string, error) {
+	n := normalizeAlarmName(name)
+	if alias, ok := alarmAliases[n]; ok {
+		n = normalizeAlarmName(alias)
+	}
+	for _, t := range types {
+		if normalizeAlarmName(t) == n {
+			return t, nil
+		}
+	}
+	return "", errors.New(fmt.Sprintf("unknown-alarm-type-%s", name))
+}
+
+// GetOnuAlarmType returns the ONU alarm type matching the name,
+// the match is case insensitive and ignores separators (e.g. "signal_degrade" is "SignalDegrade")
+func GetOnuAlarmType(name string) (string, error) {
+	return findAlarmType(name, OnuAlarmTypes)
+}
+
+// GetOltAlarmType returns the OLT alarm type matching the name
+func GetOltAlarmType(name string) (string, error) {
+	return findAlarmType(name, OltAlarmTypes)
+}
+
+// GetOmciAlarmType returns the OMCI alarm (or attribute value change) type matching the name
+func GetOmciAlarmType(name string) (string, error) {
+	return findAlarmType(name, OmciAlarmTypes)
+}
+
+// ValidateAlarmStatus checks that an alarm status is either "on" or "off"
+func ValidateAlarmStatus(status string) error {
+	if status != AlarmStatusOn && status != AlarmStatusOff {
+		return errors.New(fmt.Sprintf("invalid-alarm-status-%s", status))
+	}
+	return nil
+}
@@ -17,6 +17,9 @@
 package common
 
 import (
+	"encoding/hex"
+	"errors"
+	"fmt"
 	"net"
 	"strconv"
 
@@ -31,6 +34,32 @@ func OnuSnToString(sn *openolt.SerialNumber) string {
 	return s
 }
 
+// OnuSnFromString parses a serial number in the form returned by OnuSnToString (eg: BBSM00000001)
+func OnuSnFromString(s string) (*openolt.SerialNumber, error) {
+	if len(s) != 12 {
+		return nil, errors.New(fmt.Sprintf("invalid-onu-serial-number-%s", s))
+	}
+	vendorSpecific, err := hex.DecodeString(s[4:])
+	if err != nil {
+		return nil, errors.New(fmt.Sprintf("invalid-onu-serial-number-%s", s))
+	}
+	return &openolt.SerialNumber{
+		VendorId:       []byte(s[:4]),
+		VendorSpecific: vendorSpecific,
+	}, nil
+}
+
+// OmciSimIntfId returns the interface ID an ONU is known by in omci-sim, that keys the state of the ONUs
+// by interface and ONU ID only: the OLT ID is in the upper bits so that the ONUs of different OLTs don't share a state
+func OmciSimIntfId(oltId int, ponId uint32) uint32 {
+	return uint32(oltId)<<16 | ponId
+}
+
+// SplitOmciSimIntfId returns the OLT and PON IDs of an interface ID returned by OmciSimIntfId
+func SplitOmciSimIntfId(intfId uint32) (int, uint32) {
+	return int(intfId >> 16), intfId & 0xFFFF
+}
+
 // GetIPAddr returns the IPv4 address of an interface. 0.0.0.0 is returned if the IP cannot be determined.
 func GetIPAddr(ifname string) (string, error) {
 	ip := "0.0.0.0"
@@ -0,0 +1,215 @@
+/*
+ * Copyright 2018-present Open Networking Foundation
+
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+
+ * http://www.apache.org/licenses/LICENSE-2.0
+
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package common
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	// the attribute values of a MIB Upload Next response (in the baseline message format) fit in 26 bytes
+	mibUploadMaxAttrBytes = 26
+	mibTemplatePptpClass  = 0x000b
+)
+
+// MibTemplateConfig is an entry of the mib_templates option of the bbsim section
+type MibTemplateConfig struct {
+	Name      string   `yaml:"name"`
+	File      string   `yaml:"file"`
+	VendorIds []string `yaml:"vendor_ids"` // the ONUs with these vendor IDs upload the template, unless the topology sets a different one
+}
+
+// MibTemplate is the MIB uploaded by an ONU model, it is loaded from a YAML (or JSON) file.
+// The ONUs without a template upload the MIB built in the omci-sim library
+type MibTemplate struct {
+	Name string          `yaml:"-"`
+	Mes  []MibTemplateMe `yaml:"mes"`
+
+	uploads [][]byte
+}
+
+// MibTemplateMe is a ME instance of the MIB, the MEs are uploaded in the order they are listed
+type MibTemplateMe struct {
+	Class    uint16 `yaml:"class"`
+	Instance uint16 `yaml:"instance"`
+	// Attributes maps the attribute number (from 1 to 16) to its value in hex, eg: "0101".
+	// The value must be as long as the attribute, the attributes that are not listed are not uploaded
+	Attributes map[string]string `yaml:"attributes"`
+}
+
+// LoadMibTemplate loads a MIB template file and prepares the MIB Upload Next responses
+func LoadMibTemplate(name string, filename string) (*MibTemplate, error) {
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	template := &MibTemplate{Name: name}
+	if err := yaml.UnmarshalStrict(data, template); err != nil {
+		return nil, err
+	}
+
+	if len(template.Mes) == 0 {
+		return nil, errors.New(fmt.Sprintf("empty-mib-template-%s", name))
+	}
+	for _, me := range template.Mes {
+		uploads, err := me.mibUploads()
+		if err != nil {
+			return nil, err
+		}
+		template.uploads = append(template.uploads, uploads...)
+	}
+	if len(template.uploads) > 0xFFFF {
+		return nil, errors.New(fmt.Sprintf("too-many-mes-in-mib-template-%s", name))
+	}
+	return template, nil
+}
+
+// mibUploads encodes the ME in one or more MIB Upload Next responses, splitting the attributes
+// (in order) when they don't fit in a single message
+func (me MibTemplateMe) mibUploads() ([][]byte, error) {
+	attrs := make([]int, 0, len(me.Attributes))
+	values := make(map[int][]byte, len(me.Attributes))
+	for key, value := range me.Attributes {
+		attr, err := strconv.Atoi(key)
+		if err != nil || attr < 1 || attr > 16 {
+			return nil, errors.New(fmt.Sprintf("invalid-attribute-%s-of-me-%d-%d", key, me.Class, me.Instance))
+		}
+		v, err := hex.DecodeString(strings.TrimPrefix(strings.Replace(value, " ", "", -1), "0x"))
+		if err != nil || len(v) == 0 || len(v) > mibUploadMaxAttrBytes {
+			return nil, errors.New(fmt.Sprintf("invalid-value-of-attribute-%d-of-me-%d-%d", attr, me.Class, me.Instance))
+		}
+		attrs = append(attrs, attr)
+		values[attr] = v
+	}
+	sort.Ints(attrs)
+
+	header := func() []byte {
+		return []byte{
+			byte(me.Class >> 8), byte(me.Class & 0xFF),
+			byte(me.Instance >> 8), byte(me.Instance & 0xFF),
+			0x00, 0x00, // attribute mask
+		}
+	}
+
+	// a ME without attributes is uploaded with an empty mask
+	uploads := [][]byte{header()}
+	size := 0
+	for _, attr := range attrs {
+		current := uploads[len(uploads)-1]
+		if size+len(values[attr]) > mibUploadMaxAttrBytes {
+			current = header()
+			uploads = append(uploads, current)
+			size = 0
+		}
+		mask := uint16(current[4])<<8 | uint16(current[5]) | uint16(0x8000)>>uint(attr-1)
+		current[4] = byte(mask >> 8)
+		current[5] = byte(mask & 0xFF)
+		uploads[len(uploads)-1] = append(current, values[attr]...)
+		size += len(values[attr])
+	}
+	return uploads, nil
+}
+
+// MibUploads returns the number of MIB Upload Next messages needed to upload the template
+func (t *MibTemplate) MibUploads() uint16 {
+	return uint16(len(t.uploads))
+}
+
+// MibUploadNext returns the contents of a MIB Upload Next response
+// (the ME class and instance, the attribute mask and the attribute values), false if the command is out of range
+func (t *MibTemplate) MibUploadNext(cmd uint16) ([]byte, bool) {
+	if int(cmd) >= len(t.uploads) {
+		return nil, false
+	}
+	return t.uploads[cmd], true
+}
+
+// PptpInstances returns the instance IDs of the Physical Path Termination Points (one per UNI) in the template
+func (t *MibTemplate) PptpInstances() []uint16 {
+	instances := []uint16{}
+	for _, me := range t.Mes {
+		if me.Class == mibTemplatePptpClass {
+			instances = append(instances, me.Instance)
+		}
+	}
+	return instances
+}
+
+// loadMibTemplates loads the templates listed in the mib_templates option
+func loadMibTemplates(configs []MibTemplateConfig) (map[string]*MibTemplate, error) {
+	templates := map[string]*MibTemplate{}
+	vendorIds := map[string]bool{}
+
+	for _, c := range configs {
+		if c.Name == "" {
+			return nil, errors.New(fmt.Sprintf("mib-template-%s-has-no-name", c.File))
+		}
+		if _, ok := templates[c.Name]; ok {
+			return nil, errors.New(fmt.Sprintf("duplicate-mib-template-%s", c.Name))
+		}
+		for _, vendorId := range c.VendorIds {
+			if len(vendorId) != 4 {
+				return nil, errors.New(fmt.Sprintf("invalid-onu-vendor-id-%s", vendorId))
+			}
+			if vendorIds[vendorId] {
+				return nil, errors.New(fmt.Sprintf("duplicate-mib-template-vendor-id-%s", vendorId))
+			}
+			vendorIds[vendorId] = true
+		}
+
+		template, err := LoadMibTemplate(c.Name, c.File)
+		if err != nil {
+			return nil, err
+		}
+		templates[c.Name] = template
+	}
+	return templates, nil
+}
+
+// GetMibTemplate returns the MIB template of an ONU: the one with the given name if set,
+// otherwise the one of its vendor ID. It returns nil if the ONU uploads the built-in MIB
+func (c *BBSimYamlConfig) GetMibTemplate(name string, vendorId string) (*MibTemplate, error) {
+	if name != "" {
+		if c != nil {
+			if template, ok := c.MibTemplates[name]; ok {
+				return template, nil
+			}
+		}
+		return nil, errors.New(fmt.Sprintf("cannot-find-mib-template-%s", name))
+	}
+
+	if c == nil {
+		return nil, nil
+	}
+	for _, t := range c.BBSim.MibTemplates {
+		for _, v := range t.VendorIds {
+			if v == vendorId {
+				return c.MibTemplates[t.Name], nil
+			}
+		}
+	}
+	return nil, nil
+}
@@ -0,0 +1,140 @@
+/*
+ * Copyright 2018-present Open Networking Foundation
+
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+
+ * http://www.apache.org/licenses/LICENSE-2.0
+
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package common
+
+import (
+	"os"
+	"testing"
+
+	"gotest.tools/assert"
+)
+
+func Test_LoadMibTemplate(t *testing.T) {
+	filename := writeTestTopology(t, `
+mes:
+  - class: 2
+    instance: 0
+    attributes:
+      1: "00"
+  - class: 0x0006
+    instance: 0x0101
+    attributes:
+      1: "2f"
+      3: "49534b5471e80080"
+      9: "20202020202020202020 20202020202020202020"
+  - {class: 0x000b, instance: 0x0102}
+`)
+	defer os.Remove(filename)
+
+	template, err := LoadMibTemplate("test", filename)
+	assert.NilError(t, err)
+	assert.Equal(t, template.Name, "test")
+
+	// the circuit pack doesn't fit in a single message
+	assert.Equal(t, template.MibUploads(), uint16(4))
+
+	contents, ok := template.MibUploadNext(0)
+	assert.Equal(t, ok, true)
+	assert.DeepEqual(t, contents, []byte{0x00, 0x02, 0x00, 0x00, 0x80, 0x00, 0x00})
+
+	contents, _ = template.MibUploadNext(1)
+	assert.DeepEqual(t, contents[:6], []byte{0x00, 0x06, 0x01, 0x01, 0xa0, 0x00})
+	assert.Equal(t, len(contents), 6+1+8)
+
+	contents, _ = template.MibUploadNext(2)
+	assert.DeepEqual(t, contents[:6], []byte{0x00, 0x06, 0x01, 0x01, 0x00, 0x80})
+	assert.Equal(t, len(contents), 6+20)
+
+	contents, _ = template.MibUploadNext(3)
+	assert.DeepEqual(t, contents, []byte{0x00, 0x0b, 0x01, 0x02, 0x00, 0x00})
+
+	_, ok = template.MibUploadNext(4)
+	assert.Equal(t, ok, false)
+
+	assert.DeepEqual(t, template.PptpInstances(), []uint16{0x0102})
+}
+
+func Test_LoadMibTemplate_Json(t *testing.T) {
+	filename := writeTestTopology(t, `{"mes": [{"class": 11, "instance": 257, "attributes": {"6": "0x00", "7": "03"}}]}`)
+	defer os.Remove(filename)
+
+	template, err := LoadMibTemplate("test", filename)
+	assert.NilError(t, err)
+
+	contents, _ := template.MibUploadNext(0)
+	assert.DeepEqual(t, contents, []byte{0x00, 0x0b, 0x01, 0x01, 0x06, 0x00, 0x00, 0x03})
+}
+
+func Test_LoadMibTemplate_Example(t *testing.T) {
+	template, err := LoadMibTemplate("two-uni", "../../examples/mib-template.yaml")
+	assert.NilError(t, err)
+	assert.DeepEqual(t, template.PptpInstances(), []uint16{0x0101, 0x0102})
+}
+
+func Test_LoadMibTemplate_Invalid(t *testing.T) {
+	tests := []struct {
+		data string
+		err  string
+	}{
+		{`mes: []`, "empty-mib-template-test"},
+		{`{"mes": [{"class": 11, "instance": 257, "attributes": {"17": "00"}}]}`, "invalid-attribute-17-of-me-11-257"},
+		{`{"mes": [{"class": 11, "instance": 257, "attributes": {"name": "00"}}]}`, "invalid-attribute-name-of-me-11-257"},
+		{`{"mes": [{"class": 11, "instance": 257, "attributes": {"1": "0g"}}]}`, "invalid-value-of-attribute-1-of-me-11-257"},
+		{`{"mes": [{"class": 11, "instance": 257, "attributes": {"1": ""}}]}`, "invalid-value-of-attribute-1-of-me-11-257"},
+	}
+
+	for _, test := range tests {
+		filename := writeTestTopology(t, test.data)
+		_, err := LoadMibTemplate("test", filename)
+		os.Remove(filename)
+		assert.Error(t, err, test.err)
+	}
+}
+
+func Test_GetMibTemplate(t *testing.T) {
+	filename := writeTestTopology(t, `{"mes": [{"class": 11, "instance": 257}]}`)
+	defer os.Remove(filename)
+
+	_, err := loadMibTemplates([]MibTemplateConfig{
+		{Name: "a", File: filename, VendorIds: []string{"ABCD"}},
+		{Name: "b", File: filename, VendorIds: []string{"ABCD"}},
+	})
+	assert.Error(t, err, "duplicate-mib-template-vendor-id-ABCD")
+
+	conf := getDefaultOps()
+	conf.BBSim.MibTemplates = []MibTemplateConfig{
+		{Name: "a", File: filename, VendorIds: []string{"ABCD"}},
+		{Name: "b", File: filename},
+	}
+	conf.MibTemplates, err = loadMibTemplates(conf.BBSim.MibTemplates)
+	assert.NilError(t, err)
+
+	template, err := conf.GetMibTemplate("", "ABCD")
+	assert.NilError(t, err)
+	assert.Equal(t, template.Name, "a")
+
+	template, err = conf.GetMibTemplate("b", "ABCD")
+	assert.NilError(t, err)
+	assert.Equal(t, template.Name, "b")
+
+	template, err = conf.GetMibTemplate("", "BBSM")
+	assert.NilError(t, err)
+	assert.Assert(t, template == nil)
+
+	_, err = conf.GetMibTemplate("c", "BBSM")
+	assert.Error(t, err, "cannot-find-mib-template-c")
+}
@@ -17,10 +17,13 @@
 package common
 
 import (
+	"errors"
 	"flag"
 	"fmt"
 	"io/ioutil"
 	"net"
+	"os"
+	"strconv"
 
 	"gopkg.in/yaml.v2"
 )
@@ -37,6 +40,16 @@ type BBSimYamlConfig struct {
 	BBSim BBSimConfig
 	Olt   OltConfig
 	BBR   BBRConfig
+
+	// OltList contains the OLTs to emulate as found in the configuration file,
+	// each entry only overrides the values of the olt section
+	OltList []yaml.MapSlice `yaml:"olts"`
+	// Olts is the configuration of each OLT, it is populated by GetBBSimOpts
+	Olts []OltConfig `yaml:"-"`
+	// Topology is loaded from the file set in bbsim.topology, nil if not set
+	Topology *TopologyConfig `yaml:"-"`
+	// MibTemplates are loaded from the files set in bbsim.mib_templates, indexed by name
+	MibTemplates map[string]*MibTemplate `yaml:"-"`
 }
 
 type OltConfig struct {
@@ -49,9 +62,18 @@ type OltConfig struct {
 	PonPorts           uint32 `yaml:"pon_ports"`
 	NniPorts           uint32 `yaml:"nni_ports"`
 	OnusPonPort        uint32 `yaml:"onus_per_port"`
+	UnisPerOnu         uint32 `yaml:"unis_per_onu"`
 	Technology         string `yaml:"technology"`
 	ID                 int    `yaml:"id"`
+	SerialNumber       string `yaml:"serial_number"`
+	OpenOltAddress     string `yaml:"openolt_address"`
 	OltRebootDelay     int    `yaml:"reboot_delay"`
+	OnuRebootDelay     int    `yaml:"onu_reboot_delay"`
+	PortStatsInterval  int    `yaml:"port_stats_interval"`
+	OmciPmInterval     int    `yaml:"omci_pm_interval"`
+
+	// Topology lists the PONs and ONUs of this OLT one by one, nil if the OLT is not in the topology file
+	Topology *OltTopology `yaml:"-"`
 }
 
 type BBSimConfig struct {
@@ -70,6 +92,9 @@ type BBSimConfig struct {
 	LegacyRestApiAddress string  `yaml:"legacy_rest_api_address"`
 	SadisRestAddress     string  `yaml:"sadis_rest_address"`
 	SadisServer          bool    `yaml:"sadis_server"`
+	Topology             string  `yaml:"topology"`
+
+	MibTemplates []MibTemplateConfig `yaml:"mib_templates"`
 }
 
 type BBRConfig struct {
@@ -88,7 +113,7 @@ func init() {
 func getDefaultOps() *BBSimYamlConfig {
 
 	c := &BBSimYamlConfig{
-		BBSimConfig{
+		BBSim: BBSimConfig{
 			STag:                 900,
 			CTagInit:             900,
 			EnableDhcp:           false,
@@ -104,7 +129,7 @@ func getDefaultOps() *BBSimYamlConfig {
 			SadisRestAddress:     ":50074",
 			SadisServer:          true,
 		},
-		OltConfig{
+		Olt: OltConfig{
 			Vendor:             "BBSim",
 			Model:              "asfvolt16",
 			HardwareVersion:    "emulated",
@@ -113,11 +138,15 @@ func getDefaultOps() *BBSimYamlConfig {
 			PonPorts:           1,
 			NniPorts:           1,
 			OnusPonPort:        1,
+			UnisPerOnu:         1,
 			Technology:         "XGS-PON",
 			ID:                 0,
 			OltRebootDelay:     10,
+			OnuRebootDelay:     5,
+			PortStatsInterval:  20,
+			OmciPmInterval:     900,
 		},
-		BBRConfig{
+		BBR: BBRConfig{
 			LogLevel:  "debug",
 			LogCaller: false,
 		},
@@ -140,6 +169,29 @@ func LoadBBSimConf(filename string) (*BBSimYamlConfig, error) {
 		fmt.Printf("Error parsing YAML file: %s\n", err)
 	}
 
+	if yamlConfig.BBSim.Topology != "" {
+		topology, err := LoadTopology(yamlConfig.BBSim.Topology)
+		if err != nil {
+			fmt.Printf("Cannot load topology file: %s\n", err)
+			return yamlConfig, err
+		}
+		yamlConfig.Topology = topology
+	}
+
+	if len(yamlConfig.BBSim.MibTemplates) > 0 {
+		templates, err := loadMibTemplates(yamlConfig.BBSim.MibTemplates)
+		if err != nil {
+			fmt.Printf("Cannot load MIB templates: %s\n", err)
+			return yamlConfig, err
+		}
+		yamlConfig.MibTemplates = templates
+	}
+
+	if err := yamlConfig.Topology.validateMibTemplates(yamlConfig.MibTemplates); err != nil {
+		fmt.Printf("Invalid topology file: %s\n", err)
+		return yamlConfig, err
+	}
+
 	return yamlConfig, nil
 }
 
@@ -151,9 +203,10 @@ func GetBBSimOpts() *BBSimYamlConfig {
 	nni := flag.Int("nni", int(conf.Olt.NniPorts), "Number of NNI ports per OLT device to be emulated")
 	pon := flag.Int("pon", int(conf.Olt.PonPorts), "Number of PON ports per OLT device to be emulated")
 	onu := flag.Int("onu", int(conf.Olt.OnusPonPort), "Number of ONU devices per PON port to be emulated")
+	uni := flag.Int("uni", int(conf.Olt.UnisPerOnu), "Number of UNI ports per ONU device to be emulated")
 
 	s_tag := flag.Int("s_tag", conf.BBSim.STag, "S-Tag initial value")
-	c_tag_init := flag.Int("c_tag", conf.BBSim.CTagInit, "C-Tag starting value, each ONU will get a sequential one (targeting 1024 ONUs per BBSim instance the range is big enough)")
+	c_tag_init := flag.Int("c_tag", conf.BBSim.CTagInit, "C-Tag starting value, each UNI will get a sequential one (targeting 1024 ONUs per BBSim instance the range is big enough)")
 
 	auth := flag.Bool("auth", conf.BBSim.EnableAuth, "Set this flag if you want authentication to start automatically")
 	dhcp := flag.Bool("dhcp", conf.BBSim.EnableDhcp, "Set this flag if you want DHCP to start automatically")
@@ -171,6 +224,7 @@ func GetBBSimOpts() *BBSimYamlConfig {
 	conf.Olt.NniPorts = uint32(*nni)
 	conf.Olt.PonPorts = uint32(*pon)
 	conf.Olt.OnusPonPort = uint32(*onu)
+	conf.Olt.UnisPerOnu = uint32(*uni)
 	conf.BBSim.STag = int(*s_tag)
 	conf.BBSim.CTagInit = int(*c_tag_init)
 	conf.BBSim.CpuProfile = profileCpu
@@ -182,13 +236,124 @@ func GetBBSimOpts() *BBSimYamlConfig {
 
 	// update device id if not set
 	if conf.Olt.DeviceId == "" {
-		conf.Olt.DeviceId = net.HardwareAddr{0xA, 0xA, 0xA, 0xA, 0xA, byte(conf.Olt.ID)}.String()
+		conf.Olt.DeviceId = defaultDeviceId(conf.Olt.ID)
+	}
+
+	olts, err := getOltConfigs(conf)
+	if err != nil {
+		fmt.Printf("Invalid OLT configuration: %s\n", err)
+		os.Exit(1)
 	}
+	conf.Olts = olts
 
 	Options = conf
 	return conf
 }
 
+func defaultDeviceId(oltId int) string {
+	return net.HardwareAddr{0xA, 0xA, 0xA, 0xA, 0xA, byte(oltId)}.String()
+}
+
+// getOltConfigs returns the configuration of the OLTs to emulate,
+// if the olts list is empty a single OLT is created from the olt section.
+// Each OLT in the list inherits the values of the olt section, the ID defaults to the position
+// in the list and the OpenOLT port is incremented for each OLT
+func getOltConfigs(conf *BBSimYamlConfig) ([]OltConfig, error) {
+	if len(conf.OltList) == 0 {
+		olt := conf.Olt
+		if olt.OpenOltAddress == "" {
+			olt.OpenOltAddress = conf.BBSim.OpenOltAddress
+		}
+		return applyTopology(conf, []OltConfig{olt})
+	}
+
+	olts := []OltConfig{}
+	ids := map[int]bool{}
+	addresses := map[string]bool{}
+	for i, entry := range conf.OltList {
+		olt := conf.Olt
+		olt.ID = i
+		olt.DeviceId = ""
+		olt.OpenOltAddress = ""
+
+		// apply the values of the entry on top of the olt section
+		out, err := yaml.Marshal(entry)
+		if err != nil {
+			return nil, err
+		}
+		if err := yaml.Unmarshal(out, &olt); err != nil {
+			return nil, err
+		}
+
+		if olt.DeviceId == "" {
+			olt.DeviceId = defaultDeviceId(olt.ID)
+		}
+		if olt.OpenOltAddress == "" {
+			address, err := incrementPort(conf.BBSim.OpenOltAddress, i)
+			if err != nil {
+				return nil, err
+			}
+			olt.OpenOltAddress = address
+		}
+
+		if ids[olt.ID] {
+			return nil, errors.New(fmt.Sprintf("duplicate-olt-id-%d", olt.ID))
+		}
+		if addresses[olt.OpenOltAddress] {
+			return nil, errors.New(fmt.Sprintf("duplicate-openolt-address-%s", olt.OpenOltAddress))
+		}
+		ids[olt.ID] = true
+		addresses[olt.OpenOltAddress] = true
+
+		olts = append(olts, olt)
+	}
+	return applyTopology(conf, olts)
+}
+
+// applyTopology sets the topology of each OLT,
+// every OLT and PON in the topology has to exist in the configuration
+func applyTopology(conf *BBSimYamlConfig, olts []OltConfig) ([]OltConfig, error) {
+	if conf.BBSim.Topology != "" && conf.Topology == nil {
+		return nil, errors.New(fmt.Sprintf("cannot-load-topology-%s", conf.BBSim.Topology))
+	}
+	if conf.Topology == nil {
+		return olts, nil
+	}
+
+	for _, t := range conf.Topology.Olts {
+		found := false
+		for i := range olts {
+			if olts[i].ID != t.ID {
+				continue
+			}
+			for _, pon := range t.Pons {
+				if pon.ID >= olts[i].PonPorts {
+					return nil, errors.New(fmt.Sprintf("topology-pon-%d-out-of-range-on-olt-%d", pon.ID, t.ID))
+				}
+			}
+			olts[i].Topology = conf.Topology.getOlt(t.ID)
+			found = true
+		}
+		if !found {
+			return nil, errors.New(fmt.Sprintf("topology-olt-%d-not-found", t.ID))
+		}
+	}
+	return olts, nil
+}
+
+// incrementPort adds an offset to the port of an address in the host:port form
+func incrementPort(address string, offset int) (string, error) {
+	host, port, err := net.SplitHostPort(address)
+	if err != nil {
+		return "", err
+	}
+	p, err := strconv.Atoi(port)
+	if err != nil {
+		return "", err
+	}
+	return net.JoinHostPort(host, strconv.Itoa(p+offset)), nil
+}
+
 func GetBBROpts() BBRCliOptions {
 
 	bbsimIp := flag.String("bbsimIp", "127.0.0.1", "BBSim IP")
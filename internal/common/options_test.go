@@ -0,0 +1,111 @@
+/*
+ * Copyright 2018-present Open Networking Foundation
+
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+
+ * http://www.apache.org/licenses/LICENSE-2.0
+
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package common
+
+import (
+	"testing"
+
+	"gopkg.in/yaml.v2"
+	"gotest.tools/assert"
+)
+
+func loadTestConf(t *testing.T, data string) *BBSimYamlConfig {
+	conf := getDefaultOps()
+	err := yaml.Unmarshal([]byte(data), conf)
+	assert.NilError(t, err)
+	return conf
+}
+
+func Test_GetOltConfigs_Single(t *testing.T) {
+	conf := loadTestConf(t, `
+olt:
+  pon_ports: 2
+`)
+
+	olts, err := getOltConfigs(conf)
+	assert.NilError(t, err)
+	assert.Equal(t, len(olts), 1)
+	assert.Equal(t, olts[0].ID, 0)
+	assert.Equal(t, olts[0].PonPorts, uint32(2))
+	assert.Equal(t, olts[0].OpenOltAddress, ":50060")
+}
+
+func Test_GetOltConfigs_List(t *testing.T) {
+	conf := loadTestConf(t, `
+olt:
+  pon_ports: 2
+  onus_per_port: 4
+olts:
+  - serial_number: BBSIM_OLT_A
+  - id: 5
+    pon_ports: 8
+    openolt_address: ":60000"
+    device_id: 0a:0a:0a:0a:0a:aa
+`)
+
+	olts, err := getOltConfigs(conf)
+	assert.NilError(t, err)
+	assert.Equal(t, len(olts), 2)
+
+	// the first OLT inherits the olt section
+	assert.Equal(t, olts[0].ID, 0)
+	assert.Equal(t, olts[0].SerialNumber, "BBSIM_OLT_A")
+	assert.Equal(t, olts[0].PonPorts, uint32(2))
+	assert.Equal(t, olts[0].OnusPonPort, uint32(4))
+	assert.Equal(t, olts[0].OpenOltAddress, ":50060")
+	assert.Equal(t, olts[0].DeviceId, "0a:0a:0a:0a:0a:00")
+
+	assert.Equal(t, olts[1].ID, 5)
+	assert.Equal(t, olts[1].PonPorts, uint32(8))
+	assert.Equal(t, olts[1].OnusPonPort, uint32(4))
+	assert.Equal(t, olts[1].OpenOltAddress, ":60000")
+	assert.Equal(t, olts[1].DeviceId, "0a:0a:0a:0a:0a:aa")
+}
+
+func Test_GetOltConfigs_DefaultAddresses(t *testing.T) {
+	conf := loadTestConf(t, `
+olts:
+  - id: 0
+  - id: 1
+  - id: 2
+`)
+
+	olts, err := getOltConfigs(conf)
+	assert.NilError(t, err)
+	assert.Equal(t, olts[0].OpenOltAddress, ":50060")
+	assert.Equal(t, olts[1].OpenOltAddress, ":50061")
+	assert.Equal(t, olts[2].OpenOltAddress, ":50062")
+	assert.Equal(t, olts[2].DeviceId, "0a:0a:0a:0a:0a:02")
+}
+
+func Test_GetOltConfigs_Duplicates(t *testing.T) {
+	conf := loadTestConf(t, `
+olts:
+  - id: 1
+  - id: 1
+`)
+	_, err := getOltConfigs(conf)
+	assert.Error(t, err, "duplicate-olt-id-1")
+
+	conf = loadTestConf(t, `
+olts:
+  - openolt_address: ":50070"
+  - openolt_address: ":50070"
+`)
+	_, err = getOltConfigs(conf)
+	assert.Error(t, err, "duplicate-openolt-address-:50070")
+}
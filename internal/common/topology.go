@@ -0,0 +1,188 @@
+/*
+ * Copyright 2018-present Open Networking Foundation
+
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+
+ * http://www.apache.org/licenses/LICENSE-2.0
+
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package common
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net"
+
+	"gopkg.in/yaml.v2"
+)
+
+// TopologyConfig lists the PONs and ONUs of the emulated OLTs one by one,
+// it is loaded from the file set in the topology option of the bbsim section.
+// As JSON is a subset of YAML the file can be written in either format.
+type TopologyConfig struct {
+	Olts []OltTopology `yaml:"olts"`
+}
+
+type OltTopology struct {
+	ID   int           `yaml:"id"`
+	Pons []PonTopology `yaml:"pons"`
+}
+
+// PonTopology describes the ONUs on a PON port, the ONU IDs are assigned
+// sequentially (starting from 1) in the order the ONUs are listed
+type PonTopology struct {
+	ID   uint32        `yaml:"id"`
+	Onus []OnuTopology `yaml:"onus"`
+}
+
+// OnuTopology contains the per-ONU overrides,
+// the values that are not set are generated as for the ONUs that are not in the topology
+type OnuTopology struct {
+	SerialNumber  string `yaml:"serial_number"` // eg: ABCD00000001, takes precedence over vendor_id
+	VendorId      string `yaml:"vendor_id"`     // 4 characters, replaces BBSM in the generated serial number
+	MacAddress    string `yaml:"mac_address"`   // MAC Address of the first UNI, the other UNIs get sequential ones
+	STag          int    `yaml:"s_tag"`
+	CTag          int    `yaml:"c_tag"` // C-Tag of the first UNI, the other UNIs get sequential ones
+	Auth          *bool  `yaml:"auth"`
+	Dhcp          *bool  `yaml:"dhcp"`
+	EapolUsername string `yaml:"eapol_username"`
+	EapolPassword string `yaml:"eapol_password"`
+	MibTemplate   string `yaml:"mib_template"` // name of one of the mib_templates, overrides the template of the vendor ID
+}
+
+// LoadTopology loads and validates a topology file
+func LoadTopology(filename string) (*TopologyConfig, error) {
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	topology := &TopologyConfig{}
+	if err := yaml.UnmarshalStrict(data, topology); err != nil {
+		return nil, err
+	}
+
+	if err := topology.validate(); err != nil {
+		return nil, err
+	}
+	return topology, nil
+}
+
+func (t *TopologyConfig) validate() error {
+	olts := map[int]bool{}
+	serialNumbers := map[string]bool{}
+	macAddresses := map[string]bool{}
+
+	for _, olt := range t.Olts {
+		if olts[olt.ID] {
+			return errors.New(fmt.Sprintf("duplicate-topology-olt-%d", olt.ID))
+		}
+		olts[olt.ID] = true
+
+		pons := map[uint32]bool{}
+		for _, pon := range olt.Pons {
+			if pons[pon.ID] {
+				return errors.New(fmt.Sprintf("duplicate-topology-pon-%d-on-olt-%d", pon.ID, olt.ID))
+			}
+			pons[pon.ID] = true
+
+			for _, onu := range pon.Onus {
+				if err := onu.Validate(); err != nil {
+					return err
+				}
+				if onu.SerialNumber != "" {
+					if serialNumbers[onu.SerialNumber] {
+						return errors.New(fmt.Sprintf("duplicate-onu-serial-number-%s", onu.SerialNumber))
+					}
+					serialNumbers[onu.SerialNumber] = true
+				}
+				if onu.MacAddress != "" {
+					mac, _ := net.ParseMAC(onu.MacAddress)
+					if macAddresses[mac.String()] {
+						return errors.New(fmt.Sprintf("duplicate-onu-mac-address-%s", onu.MacAddress))
+					}
+					macAddresses[mac.String()] = true
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// Validate checks the values of an ONU
+func (t OnuTopology) Validate() error {
+	if t.SerialNumber != "" {
+		if _, err := OnuSnFromString(t.SerialNumber); err != nil {
+			return err
+		}
+	}
+	if t.VendorId != "" && len(t.VendorId) != 4 {
+		return errors.New(fmt.Sprintf("invalid-onu-vendor-id-%s", t.VendorId))
+	}
+	if t.MacAddress != "" {
+		mac, err := net.ParseMAC(t.MacAddress)
+		if err != nil || len(mac) != 6 {
+			return errors.New(fmt.Sprintf("invalid-onu-mac-address-%s", t.MacAddress))
+		}
+	}
+	if t.STag < 0 || t.STag > 4095 {
+		return errors.New(fmt.Sprintf("invalid-onu-s-tag-%d", t.STag))
+	}
+	if t.CTag < 0 || t.CTag > 4095 {
+		return errors.New(fmt.Sprintf("invalid-onu-c-tag-%d", t.CTag))
+	}
+	return nil
+}
+
+// validateMibTemplates checks that the MIB templates set on the ONUs are loaded
+func (t *TopologyConfig) validateMibTemplates(templates map[string]*MibTemplate) error {
+	if t == nil {
+		return nil
+	}
+	for _, olt := range t.Olts {
+		for _, pon := range olt.Pons {
+			for _, onu := range pon.Onus {
+				if _, ok := templates[onu.MibTemplate]; onu.MibTemplate != "" && !ok {
+					return errors.New(fmt.Sprintf("cannot-find-mib-template-%s", onu.MibTemplate))
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// getOlt returns the topology of an OLT, nil if the OLT is not in the topology
+func (t *TopologyConfig) getOlt(oltId int) *OltTopology {
+	if t == nil {
+		return nil
+	}
+	for i := range t.Olts {
+		if t.Olts[i].ID == oltId {
+			return &t.Olts[i]
+		}
+	}
+	return nil
+}
+
+// GetOnus returns the ONUs listed for a PON port, false if the PON port
+// is not in the topology and the ONUs are generated from onus_per_port
+func (t *OltTopology) GetOnus(ponId uint32) ([]OnuTopology, bool) {
+	if t == nil {
+		return nil, false
+	}
+	for _, pon := range t.Pons {
+		if pon.ID == ponId {
+			return pon.Onus, true
+		}
+	}
+	return nil, false
+}
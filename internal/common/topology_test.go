@@ -0,0 +1,120 @@
+/*
+ * Copyright 2018-present Open Networking Foundation
+
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+
+ * http://www.apache.org/licenses/LICENSE-2.0
+
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package common
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"gotest.tools/assert"
+)
+
+func writeTestTopology(t *testing.T, data string) string {
+	f, err := ioutil.TempFile("", "topology")
+	assert.NilError(t, err)
+	_, err = f.WriteString(data)
+	assert.NilError(t, err)
+	assert.NilError(t, f.Close())
+	return f.Name()
+}
+
+func Test_LoadTopology_Yaml(t *testing.T) {
+	filename := writeTestTopology(t, `
+olts:
+  - id: 0
+    pons:
+      - id: 1
+        onus:
+          - serial_number: ABCD00000001
+            mac_address: 00:11:22:33:44:55
+            s_tag: 100
+            c_tag: 200
+            auth: false
+            eapol_username: subscriber
+          - vendor_id: EFGH
+`)
+	defer os.Remove(filename)
+
+	topology, err := LoadTopology(filename)
+	assert.NilError(t, err)
+
+	onus, found := topology.getOlt(0).GetOnus(1)
+	assert.Equal(t, found, true)
+	assert.Equal(t, len(onus), 2)
+	assert.Equal(t, onus[0].SerialNumber, "ABCD00000001")
+	assert.Equal(t, onus[0].STag, 100)
+	assert.Equal(t, onus[0].CTag, 200)
+	assert.Equal(t, *onus[0].Auth, false)
+	assert.Assert(t, onus[0].Dhcp == nil)
+	assert.Equal(t, onus[0].EapolUsername, "subscriber")
+	assert.Equal(t, onus[1].VendorId, "EFGH")
+
+	_, found = topology.getOlt(0).GetOnus(0)
+	assert.Equal(t, found, false)
+	assert.Assert(t, topology.getOlt(1) == nil)
+}
+
+func Test_LoadTopology_Json(t *testing.T) {
+	filename := writeTestTopology(t, `{"olts": [{"id": 0, "pons": [{"id": 0, "onus": [{"serial_number": "ABCD00000001", "c_tag": 10}]}]}]}`)
+	defer os.Remove(filename)
+
+	topology, err := LoadTopology(filename)
+	assert.NilError(t, err)
+
+	onus, _ := topology.getOlt(0).GetOnus(0)
+	assert.Equal(t, onus[0].CTag, 10)
+}
+
+func Test_LoadTopology_Invalid(t *testing.T) {
+	tests := map[string]string{
+		"invalid-onu-serial-number-ABCD0001":       `{"olts": [{"pons": [{"onus": [{"serial_number": "ABCD0001"}]}]}]}`,
+		"duplicate-onu-serial-number-ABCD00000001": `{"olts": [{"pons": [{"onus": [{"serial_number": "ABCD00000001"}, {"serial_number": "ABCD00000001"}]}]}]}`,
+		"invalid-onu-mac-address-foo":              `{"olts": [{"pons": [{"onus": [{"mac_address": "foo"}]}]}]}`,
+		"invalid-onu-c-tag-5000":                   `{"olts": [{"pons": [{"onus": [{"c_tag": 5000}]}]}]}`,
+		"duplicate-topology-pon-0-on-olt-0":        `{"olts": [{"pons": [{"id": 0}, {"id": 0}]}]}`,
+	}
+
+	for expected, data := range tests {
+		filename := writeTestTopology(t, data)
+		_, err := LoadTopology(filename)
+		os.Remove(filename)
+		assert.Error(t, err, expected)
+	}
+}
+
+func Test_GetOltConfigs_Topology(t *testing.T) {
+	conf := loadTestConf(t, `
+olt:
+  pon_ports: 2
+`)
+	conf.Topology = &TopologyConfig{
+		Olts: []OltTopology{{ID: 0, Pons: []PonTopology{{ID: 1}}}},
+	}
+
+	olts, err := getOltConfigs(conf)
+	assert.NilError(t, err)
+	assert.Equal(t, olts[0].Topology.ID, 0)
+
+	conf.Topology.Olts[0].Pons[0].ID = 2
+	_, err = getOltConfigs(conf)
+	assert.Error(t, err, "topology-pon-2-out-of-range-on-olt-0")
+
+	conf.Topology.Olts[0].ID = 3
+	_, err = getOltConfigs(conf)
+	assert.Error(t, err, "topology-olt-3-not-found")
+}